@@ -0,0 +1,99 @@
+package logx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultAnyMaxBytes is the serialized size above which structured values
+// are summarized, unless AnyBudget.MaxBytes is set.
+const DefaultAnyMaxBytes = 16 << 10
+
+// AnyBudget bounds the size of the structured values of fields, such as
+// the structs, maps and slices passed to Any, so that accidentally logging
+// a huge payload degrades to a small summary instead of a multi-megabyte
+// entry. Scalar values such as strings and numbers are not affected.
+//
+// A value is written in one of three forms, depending on its JSON size:
+//
+//	{"order":{"id":"o-1","items":[...]}}                                                  // full, at most MaxBytes
+//	{"order":{"type":"*shop.Order","bytes":2483101,"sha256":"9f2c..."}}                   // summarized
+//	{"order":{"type":"*shop.Order","bytes":2483101,"sha256":"9f2c...","ref":"01J9..."}}  // stored by reference
+//
+// Slices, arrays and maps add their "length" to the summary. Values
+// encoding themselves, such as zapcore.ObjectMarshalers, are written as
+// they encode.
+type AnyBudget struct {
+	// MaxBytes is the largest serialized size written in full.
+	// Default: DefaultAnyMaxBytes
+	MaxBytes int
+
+	// Store, if set, receives the JSON of every value over MaxBytes under
+	// a unique reference ID, e.g. to keep it in object storage, and the
+	// summary carries the ID as "ref". If Store fails, the summary is
+	// written without it.
+	// Default: nil (summarize only)
+	Store func(ref string, data []byte) error
+}
+
+// anySummary is the summarized form of an oversized value.
+type anySummary struct {
+	Type   string `json:"type"`
+	Length int    `json:"length,omitempty"`
+	Bytes  int    `json:"bytes"`
+	SHA256 string `json:"sha256"`
+	Ref    string `json:"ref,omitempty"`
+}
+
+// apply returns value, or its summary if it is a structured value whose
+// JSON exceeds the budget.
+func (b *AnyBudget) apply(value interface{}) interface{} {
+	if b == nil || value == nil {
+		return value
+	}
+	switch value.(type) {
+	case zapcore.ObjectMarshaler, zapcore.ArrayMarshaler, zapcore.Field:
+		return value
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return value
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return value
+	}
+	maxBytes := b.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultAnyMaxBytes
+	}
+	data, err := json.Marshal(value)
+	if err != nil || len(data) <= maxBytes {
+		return value
+	}
+	sum := sha256.Sum256(data)
+	summary := anySummary{
+		Type:   reflect.TypeOf(value).String(),
+		Bytes:  len(data),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		summary.Length = v.Len()
+	}
+	if b.Store != nil {
+		ref := NewEventID()
+		if b.Store(ref, data) == nil {
+			summary.Ref = ref
+		}
+	}
+	return summary
+}
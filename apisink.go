@@ -0,0 +1,384 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchConfig configures how an APISink batches entries.
+type BatchConfig struct {
+	// MaxEntries is the number of entries that triggers a send.
+	// Default: 100
+	MaxEntries int
+
+	// FlushInterval is how often pending entries are sent when fewer than
+	// MaxEntries are queued.
+	// Default: 5s
+	FlushInterval time.Duration
+
+	// Client sends the requests.
+	// Default: an http.Client with a 10s timeout
+	Client *http.Client
+
+	// OnError is called when a batch cannot be sent or an entry cannot be
+	// decoded. Without a spool, failed batches are dropped, not retried. It
+	// must not log to the same sink.
+	// Default: nil (errors are ignored, except by Sync)
+	OnError func(err error)
+
+	// SpoolPath, if set, journals every entry to this file before it is
+	// sent, and records the last acknowledged entry in a checkpoint file
+	// next to it (SpoolPath + CheckpointSuffix). Failed batches are retried
+	// from the spool at the next flush instead of being dropped, and after
+	// a crash or restart, shipping resumes right after the last
+	// acknowledged entry, found by its event ID when Config.EventID is
+	// enabled. The spool is truncated whenever it is fully shipped.
+	// Default: "" (no spool)
+	SpoolPath string
+
+	// DeadLetterPath, if set, is a file the entries of failed batches are
+	// appended to, one JSON line each, instead of being dropped, so that
+	// they can be sent later with ReplayDeadLetters. With a spool, failed
+	// batches are retried instead.
+	// Default: "" (failed batches are dropped)
+	DeadLetterPath string
+}
+
+// APISink sends entries to a vendor log API in batches. It is used as the
+// Writer of a JSON sink; entries are decoded from the sink's encoding and
+// converted to the vendor's format. Batches are sent when full, every
+// FlushInterval, on Sync and on Close.
+//
+// Batches are sent one at a time. While a batch is being sent, a Write that
+// fills the next batch waits for it, so a slow or throttled API slows down
+// logging instead of buffering without bound; combine with Config.Async to
+// shed load through its watermarks instead.
+//
+// APISink is created by the vendor constructors, such as NewHoneycombSink
+// and NewNewRelicSink.
+type APISink struct {
+	config BatchConfig
+	format apiFormat
+	keys   atomic.Pointer[entryKeys] // Envelope of the entries, bound by the logger
+
+	mu           sync.Mutex
+	pending      []apiEntry
+	pendingBytes int
+	spool        *spool       // Journal of unacknowledged entries, nil if disabled
+	deadLetters  *deadLetters // Entries of failed batches, nil if disabled
+	sending      sync.Mutex   // Serializes sends
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// apiEntry is an entry queued by an APISink: its fields, decoded from the
+// sink's JSON encoding, the encoding itself and the envelope it follows.
+type apiEntry struct {
+	fields map[string]interface{}
+	raw    []byte
+	keys   *entryKeys
+}
+
+// apiFormat describes how an APISink talks to a vendor API.
+type apiFormat struct {
+	maxEntries int                                           // Entries per request allowed by the API, 0 for no limit
+	maxBytes   int                                           // Encoded bytes per request, 0 for no limit
+	request    func(batch []apiEntry) (*http.Request, error) // Builds the request for a batch
+	response   func(resp *http.Response, body []byte) error  // Checks a successful response, may be nil
+	keep       func(entry apiEntry) bool                     // Selects the entries sent, nil for all
+}
+
+// newAPISink starts an APISink sending batches in format. Batches are
+// capped at the limits of the format. Entries left in the spool by a
+// previous process are sent at the first flush.
+func newAPISink(config BatchConfig, format apiFormat) (*APISink, error) {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 100
+	}
+	if format.maxEntries > 0 && config.MaxEntries > format.maxEntries {
+		config.MaxEntries = format.maxEntries
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	s := &APISink{
+		config:      config,
+		format:      format,
+		deadLetters: newDeadLetters(config.DeadLetterPath),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	s.keys.Store(defaultEntryKeys)
+	if config.SpoolPath != "" {
+		var err error
+		if s.spool, err = openSpool(config.SpoolPath); err != nil {
+			return nil, err
+		}
+	}
+	go s.run()
+	return s, nil
+}
+
+// bindKeys sets the envelope of the entries written by the logger the sink
+// is given to.
+func (s *APISink) bindKeys(keys *entryKeys) {
+	s.keys.Store(keys)
+}
+
+// Write decodes the encoded entries in p and queues them, sending the batch
+// once it is full. It only fails if p cannot be decoded.
+func (s *APISink) Write(p []byte) (int, error) {
+	var entries []apiEntry
+	keys := s.keys.Load()
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var fields map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			err = fmt.Errorf("failed to decode entry: %w", err)
+			s.report(err)
+			return 0, err
+		}
+		entry := apiEntry{fields: fields, raw: append([]byte(nil), line...), keys: keys}
+		if s.format.keep != nil && !s.format.keep(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if s.spool != nil {
+		s.mu.Lock()
+		err := s.spool.append(entries)
+		full := s.spool.unsent >= s.config.MaxEntries
+		s.mu.Unlock()
+		if err != nil {
+			s.report(err)
+			return 0, err
+		}
+		if full {
+			s.sendSpool(true)
+		}
+		return len(p), nil
+	}
+
+	var batches [][]apiEntry
+	s.mu.Lock()
+	for _, entry := range entries {
+		if s.format.maxBytes > 0 && len(s.pending) > 0 && s.pendingBytes+len(entry.raw) > s.format.maxBytes {
+			batches = append(batches, s.pending)
+			s.pending, s.pendingBytes = nil, 0
+		}
+		s.pending = append(s.pending, entry)
+		s.pendingBytes += len(entry.raw)
+		if len(s.pending) >= s.config.MaxEntries {
+			batches = append(batches, s.pending)
+			s.pending, s.pendingBytes = nil, 0
+		}
+	}
+	s.mu.Unlock()
+	for _, batch := range batches {
+		s.send(batch)
+	}
+	return len(p), nil
+}
+
+// flush sends the pending entries, if any.
+func (s *APISink) flush() error {
+	if s.spool != nil {
+		return s.sendSpool(false)
+	}
+	s.mu.Lock()
+	batch := s.pending
+	s.pending, s.pendingBytes = nil, 0
+	s.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	return s.send(batch)
+}
+
+// send posts a batch, after any batch being sent, and reports any failure.
+// The entries of a failed batch are appended to the dead-letter file, if
+// any.
+func (s *APISink) send(batch []apiEntry) error {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	err := s.post(batch)
+	if err != nil {
+		s.report(err)
+		if s.deadLetters != nil {
+			lines := make([][]byte, len(batch))
+			for i, entry := range batch {
+				lines[i] = entry.raw
+			}
+			if err := s.deadLetters.append(lines); err != nil {
+				s.report(err)
+			}
+		}
+	}
+	return err
+}
+
+// sendSpool sends the unacknowledged entries of the spool in order,
+// advancing the checkpoint after each acknowledged batch. With full set,
+// only full batches are sent. It stops at the first failure; the batch is
+// retried by the next call.
+func (s *APISink) sendSpool(full bool) error {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	for {
+		s.mu.Lock()
+		start, end, unsent := s.spool.checkpoint.Offset, s.spool.size, s.spool.unsent
+		s.mu.Unlock()
+		if unsent == 0 || full && unsent < s.config.MaxEntries {
+			return nil
+		}
+		batch, next, lines := s.spool.read(start, end, s.config.MaxEntries, s.format.maxBytes)
+		if lines == 0 {
+			return nil
+		}
+		if len(batch) > 0 {
+			if err := s.post(batch); err != nil {
+				s.report(err)
+				return err
+			}
+		}
+		var lastID string
+		if len(batch) > 0 {
+			lastID = fieldValue(batch[len(batch)-1].fields, EventIDKey)
+		}
+		s.mu.Lock()
+		err := s.spool.ack(next, lines, lastID)
+		s.mu.Unlock()
+		if err != nil {
+			s.report(err)
+			return err
+		}
+	}
+}
+
+// Checkpoint returns how far the spool has been shipped. It returns the
+// zero checkpoint if the sink has no spool.
+func (s *APISink) Checkpoint() ShippingCheckpoint {
+	if s.spool == nil {
+		return ShippingCheckpoint{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spool.checkpoint
+}
+
+// post builds and sends the request for a batch. Entries read back from
+// the spool follow the current envelope.
+func (s *APISink) post(batch []apiEntry) error {
+	for i := range batch {
+		if batch[i].keys == nil {
+			batch[i].keys = s.keys.Load()
+		}
+	}
+	req, err := s.format.request(batch)
+	if err != nil {
+		return err
+	}
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %d entries to %s: %w", len(batch), req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send %d entries to %s: %s", len(batch), req.URL.Host, resp.Status)
+	}
+	if s.format.response != nil {
+		if err := s.format.response(resp, body); err != nil {
+			return fmt.Errorf("failed to send entries to %s: %w", req.URL.Host, err)
+		}
+	}
+	return nil
+}
+
+// report passes err to OnError, if set.
+func (s *APISink) report(err error) {
+	if s.config.OnError != nil {
+		s.config.OnError(err)
+	}
+}
+
+// run sends pending entries every FlushInterval until Close.
+func (s *APISink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Sync sends the pending entries and returns the error of the send, if any.
+func (s *APISink) Sync() error {
+	return s.flush()
+}
+
+// Close stops the periodic sends and sends the pending entries. Entries
+// written after Close are only sent by Sync or when a batch fills up.
+func (s *APISink) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	err := s.flush()
+	if s.spool != nil {
+		s.sending.Lock()
+		s.spool.file.Sync()
+		s.sending.Unlock()
+	}
+	return err
+}
+
+// newJSONRequest builds a POST request with a JSON body.
+func newJSONRequest(url string, body interface{}, headers map[string]string) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// mapAttributes renames the keys of entry found in renames, in place.
+func mapAttributes(entry map[string]interface{}, renames map[string]string) {
+	for from, to := range renames {
+		if value, ok := entry[from]; ok && from != to {
+			delete(entry, from)
+			entry[to] = value
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package logx
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AsyncConfig enables asynchronous writing. Encoded entries are placed on a
+// bounded queue and written to their outputs by a background goroutine, so
+// logging calls never wait for slow outputs. When the queue is full, new
+// entries are dropped.
+type AsyncConfig struct {
+	// QueueSize is the maximum number of queued entries.
+	// Default: 8192
+	QueueSize int
+
+	// HighWatermark is the queue fill ratio, between 0 and 1, at which
+	// OnHighWatermark is called.
+	// Default: 0.8
+	HighWatermark float64
+
+	// LowWatermark is the queue fill ratio at which OnLowWatermark is
+	// called once the high watermark has been reached.
+	// Default: 0.5
+	LowWatermark float64
+
+	// OnHighWatermark is called when the queue depth rises to the high
+	// watermark, so that the application can shed load, e.g. by lowering
+	// the log level. It is called from a logging goroutine and must not
+	// block.
+	OnHighWatermark func(depth, capacity int)
+
+	// OnLowWatermark is called when the queue depth falls back to the low
+	// watermark. It is called from the background writer and must not
+	// block.
+	OnLowWatermark func(depth, capacity int)
+
+	// WALPath, if set, backs the queue with a memory-mapped write-ahead
+	// file. Every queued entry is journaled before it is accepted, and
+	// entries still pending when the process crashes are written to their
+	// outputs the next time a logger is created with the same WALPath,
+	// followed by a Warn entry reporting how many were recovered. Entries
+	// that do not fit in the file are queued without being journaled.
+	// Default: "" (no write-ahead file)
+	WALPath string
+
+	// WALSize is the size of the write-ahead file in bytes. It bounds how
+	// many queued bytes are protected against a crash.
+	// Default: 1 MiB
+	WALSize int
+}
+
+// asyncItem is a queued write, or a flush request if done is set.
+type asyncItem struct {
+	output    zapcore.WriteSyncer
+	data      []byte
+	walOffset int // Offset of the journaled record, -1 if not journaled
+	done      chan struct{}
+}
+
+// asyncQueue is the queue shared by every output of an asynchronous
+// logger. A single writer goroutine preserves the order of entries.
+type asyncQueue struct {
+	config  AsyncConfig
+	queue   chan asyncItem
+	high    int
+	low     int
+	above   atomic.Bool   // Whether the high watermark was reached
+	dropped atomic.Uint64 // Entries dropped because the queue was full
+
+	wal       *wal                           // Write-ahead log, nil if disabled
+	recovered []walRecord                    // Entries pending in the log at startup
+	outputs   map[string]zapcore.WriteSyncer // Outputs by key, for recovery
+
+	mu      sync.RWMutex // Guards stopped against concurrent enqueues
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newAsyncQueue starts the writer goroutine for config, or returns nil if
+// config is nil. Entries pending in the write-ahead log are kept for
+// recoverWAL.
+func newAsyncQueue(config *AsyncConfig) (*asyncQueue, error) {
+	if config == nil {
+		return nil, nil
+	}
+	c := *config
+	if c.QueueSize <= 0 {
+		c.QueueSize = 8192
+	}
+	if c.HighWatermark <= 0 || c.HighWatermark > 1 {
+		c.HighWatermark = 0.8
+	}
+	if c.LowWatermark <= 0 {
+		c.LowWatermark = 0.5
+	}
+	if c.LowWatermark >= c.HighWatermark {
+		c.LowWatermark = c.HighWatermark / 2
+	}
+	if c.WALSize <= 0 {
+		c.WALSize = 1 << 20
+	}
+	q := &asyncQueue{
+		config: c,
+		queue:  make(chan asyncItem, c.QueueSize),
+		high:   int(c.HighWatermark * float64(c.QueueSize)),
+		low:    int(c.LowWatermark * float64(c.QueueSize)),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if q.high < 1 {
+		q.high = 1
+	}
+	if c.WALPath != "" {
+		var err error
+		q.wal, q.recovered, err = openWAL(c.WALPath, c.WALSize)
+		if err != nil {
+			return nil, err
+		}
+		q.outputs = make(map[string]zapcore.WriteSyncer)
+	}
+	go q.run()
+	return q, nil
+}
+
+// register records the output identified by key, so that entries recovered
+// from the write-ahead log can be written back to it.
+func (q *asyncQueue) register(key string, output zapcore.WriteSyncer) {
+	if q.outputs != nil {
+		q.outputs[key] = output
+	}
+}
+
+// recoverWAL writes the entries left pending in the write-ahead log by a
+// previous process to their outputs and returns how many were written.
+// Entries for outputs that no longer exist go to the default output.
+func (q *asyncQueue) recoverWAL() (int, error) {
+	records := q.recovered
+	q.recovered = nil
+	written := make(map[zapcore.WriteSyncer]bool)
+	count := 0
+	for _, record := range records {
+		output, ok := q.outputs[record.key]
+		if !ok {
+			output, ok = q.outputs[DefaultSinkName]
+		}
+		if !ok {
+			continue
+		}
+		if _, err := output.Write(record.data); err != nil {
+			return count, fmt.Errorf("failed to write recovered entry: %w", err)
+		}
+		written[output] = true
+		count++
+	}
+	for output := range written {
+		output.Sync()
+	}
+	if q.wal != nil {
+		q.wal.discard()
+	}
+	return count, nil
+}
+
+// enqueue queues a copy of p for the output identified by key, dropping it
+// if the queue is full. After the queue is closed, p is written
+// synchronously.
+func (q *asyncQueue) enqueue(key string, output zapcore.WriteSyncer, p []byte) {
+	q.mu.RLock()
+	if q.stopped {
+		q.mu.RUnlock()
+		output.Write(p)
+		return
+	}
+	walOffset := -1
+	if q.wal != nil {
+		walOffset = q.wal.append(key, p)
+	}
+	select {
+	case q.queue <- asyncItem{output: output, data: append([]byte(nil), p...), walOffset: walOffset}:
+	default:
+		q.dropped.Add(1)
+		if walOffset >= 0 {
+			q.wal.done(walOffset)
+		}
+	}
+	q.mu.RUnlock()
+
+	if depth := len(q.queue); depth >= q.high && q.above.CompareAndSwap(false, true) {
+		if q.config.OnHighWatermark != nil {
+			q.config.OnHighWatermark(depth, cap(q.queue))
+		}
+	}
+}
+
+// run writes queued entries until the queue is closed, then drains it.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for {
+		select {
+		case item := <-q.queue:
+			q.process(item)
+		case <-q.stop:
+			for {
+				select {
+				case item := <-q.queue:
+					q.process(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// process writes a single item and reports the low watermark.
+func (q *asyncQueue) process(item asyncItem) {
+	if item.done != nil {
+		close(item.done)
+		return
+	}
+	item.output.Write(item.data)
+	if item.walOffset >= 0 {
+		q.wal.done(item.walOffset)
+	}
+	if depth := len(q.queue); depth <= q.low && q.above.CompareAndSwap(true, false) {
+		if q.config.OnLowWatermark != nil {
+			q.config.OnLowWatermark(depth, cap(q.queue))
+		}
+	}
+}
+
+// flush waits until every entry queued before the call has been written.
+func (q *asyncQueue) flush() {
+	q.mu.RLock()
+	if q.stopped {
+		q.mu.RUnlock()
+		return
+	}
+	done := make(chan struct{})
+	q.queue <- asyncItem{done: done}
+	q.mu.RUnlock()
+	<-done
+}
+
+// backpressure returns the queue fill ratio between 0 and 1.
+func (q *asyncQueue) backpressure() float64 {
+	return float64(len(q.queue)) / float64(cap(q.queue))
+}
+
+// close writes the remaining entries, stops the writer goroutine and
+// releases the write-ahead log.
+func (q *asyncQueue) close() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.stopped = true
+		q.mu.Unlock()
+		close(q.stop)
+		<-q.done
+		if q.wal != nil {
+			q.wal.close()
+		}
+	})
+}
+
+// asyncWriteSyncer queues writes to an output on an asyncQueue. The key
+// identifies the output across restarts for write-ahead log recovery.
+type asyncWriteSyncer struct {
+	queue  *asyncQueue
+	key    string
+	output zapcore.WriteSyncer
+}
+
+// Write queues p. It never fails; entries that do not fit are dropped.
+func (w *asyncWriteSyncer) Write(p []byte) (int, error) {
+	w.queue.enqueue(w.key, w.output, p)
+	return len(p), nil
+}
+
+// Sync waits for queued entries to be written and syncs the output.
+func (w *asyncWriteSyncer) Sync() error {
+	w.queue.flush()
+	return w.output.Sync()
+}
+
+// Backpressure returns how full the asynchronous write queue is, from 0
+// (empty) to 1 (full, new entries are being dropped). Applications can use
+// it to shed their own load before entries are lost. It returns 0 for
+// synchronous loggers.
+//
+// Example:
+//
+//	if logger.Backpressure() > 0.5 {
+//	    logger.SetLevel(logx.WarnLevel)
+//	}
+func (l *Logger) Backpressure() float64 {
+	if l.shared.async == nil {
+		return 0
+	}
+	return l.shared.async.backpressure()
+}
+
+// Close flushes the logger, stops its background goroutines, such as the
+// asynchronous writer and the level schedule, and closes its output files,
+// releasing their sidecar indexes and FileLock claims. Entries logged after
+// Close are not written to the closed files. Close applies to the logger
+// and every logger sharing its state.
+func (l *Logger) Close() error {
+	l.shared.schedule.close()
+	err := l.Sync()
+	if l.shared.async != nil {
+		l.shared.async.close()
+	}
+	// Wait for entries being written before closing the files.
+	l.shared.reload.Lock()
+	defer l.shared.reload.Unlock()
+	if closeErr := l.shared.files.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package logx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditManifestSuffix is appended to the name of a daily audit file, without
+// its .jsonl extension, to name its manifest.
+const AuditManifestSuffix = ".manifest.json"
+
+// AuditExporterConfig configures an AuditExporter.
+type AuditExporterConfig struct {
+	// Dir is the directory the daily files and manifests are written to.
+	// It is created if it does not exist.
+	Dir string
+
+	// Prefix starts the name of every file, e.g. audit-2024-05-01.jsonl.
+	// Default: "audit"
+	Prefix string
+
+	// Location sets the day boundaries.
+	// Default: UTC
+	Location *time.Location
+
+	// IDKey is the field whose value is recorded as the first and last ID
+	// of each day. Enable Config.EventID to give every entry a unique ID.
+	// Default: EventIDKey
+	IDKey string
+}
+
+// AuditManifest summarizes a daily audit file for retention audits and
+// chain-of-custody reports.
+type AuditManifest struct {
+	File      string    `json:"file"`               // Base name of the audit file
+	Date      string    `json:"date"`               // Day covered, as YYYY-MM-DD
+	Entries   int64     `json:"entries"`            // Number of entries
+	Bytes     int64     `json:"bytes"`              // Size of the file
+	FirstID   string    `json:"first_id,omitempty"` // IDKey of the first entry
+	LastID    string    `json:"last_id,omitempty"`  // IDKey of the last entry
+	SHA256    string    `json:"sha256"`             // Hex SHA-256 of the file
+	UpdatedAt time.Time `json:"updated_at"`         // When the manifest was written
+}
+
+// AuditExporter is an append-only writer for an audit sink. It writes
+// entries to one JSONL file per day and keeps a manifest next to each file
+// with its entry count, first and last IDs and checksum. Manifests are
+// rewritten on Sync, when the day changes and on Close. Files are never
+// truncated; an exporter reopening the current day's file continues its
+// manifest.
+//
+// Use it as the Writer of a JSON sink:
+//
+//	exporter, err := logx.NewAuditExporter(logx.AuditExporterConfig{Dir: "/var/log/app/audit"})
+//	config.Sinks = []logx.SinkConfig{{Name: "audit", Writer: exporter}}
+//	defer exporter.Close()
+type AuditExporter struct {
+	config AuditExporterConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	manifest AuditManifest
+	hash     hash.Hash
+}
+
+// NewAuditExporter creates the directory in config and returns an exporter
+// writing to it.
+func NewAuditExporter(config AuditExporterConfig) (*AuditExporter, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("audit exporter requires a directory")
+	}
+	if config.Prefix == "" {
+		config.Prefix = "audit"
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.IDKey == "" {
+		config.IDKey = EventIDKey
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	return &AuditExporter{config: config}, nil
+}
+
+// Write appends the encoded entries in p to the current day's file.
+func (a *AuditExporter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	date := time.Now().In(a.config.Location).Format("2006-01-02")
+	if a.file == nil || a.manifest.Date != date {
+		if err := a.openLocked(date); err != nil {
+			return 0, err
+		}
+	}
+	n, err := a.file.Write(p)
+	a.hash.Write(p[:n])
+	a.manifest.Bytes += int64(n)
+	for _, line := range bytes.Split(p[:n], []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			a.recordLocked(line)
+		}
+	}
+	return n, err
+}
+
+// recordLocked counts an entry and tracks its ID.
+func (a *AuditExporter) recordLocked(line []byte) {
+	a.manifest.Entries++
+	id := auditID(line, a.config.IDKey)
+	if id == "" {
+		return
+	}
+	if a.manifest.FirstID == "" {
+		a.manifest.FirstID = id
+	}
+	a.manifest.LastID = id
+}
+
+// auditID returns the value of key in the JSON entry, or "" if absent.
+func auditID(line []byte, key string) string {
+	var fields map[string]interface{}
+	if json.Unmarshal(line, &fields) != nil {
+		return ""
+	}
+	if v, ok := fields[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// openLocked finishes the current day, if any, and opens the file for
+// date, reading back what a previous exporter already wrote to it.
+func (a *AuditExporter) openLocked(date string) error {
+	if a.file != nil {
+		if err := a.closeLocked(); err != nil {
+			return err
+		}
+	}
+	name := a.config.Prefix + "-" + date + ".jsonl"
+	path := filepath.Join(a.config.Dir, name)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	a.file = file
+	a.hash = sha256.New()
+	a.manifest = AuditManifest{File: name, Date: date}
+	reader := bufio.NewReader(io.TeeReader(file, a.hash))
+	for {
+		line, err := reader.ReadBytes('\n')
+		a.manifest.Bytes += int64(len(line))
+		if len(bytes.TrimSpace(line)) > 0 {
+			a.recordLocked(line)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audit file: %w", err)
+		}
+	}
+}
+
+// writeManifestLocked atomically replaces the manifest of the current day.
+func (a *AuditExporter) writeManifestLocked() error {
+	a.manifest.SHA256 = hex.EncodeToString(a.hash.Sum(nil))
+	a.manifest.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(a.config.Dir, a.config.Prefix+"-"+a.manifest.Date+AuditManifestSuffix)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write audit manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write audit manifest: %w", err)
+	}
+	return nil
+}
+
+// Sync flushes the current day's file to disk and rewrites its manifest.
+func (a *AuditExporter) Sync() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	return a.writeManifestLocked()
+}
+
+// Close syncs the current day's file, writes its manifest and closes it.
+// Entries written after Close reopen the file.
+func (a *AuditExporter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	return a.closeLocked()
+}
+
+// closeLocked finishes the current day's file.
+func (a *AuditExporter) closeLocked() error {
+	err := a.file.Sync()
+	if manifestErr := a.writeManifestLocked(); err == nil {
+		err = manifestErr
+	}
+	if closeErr := a.file.Close(); err == nil {
+		err = closeErr
+	}
+	a.file = nil
+	return err
+}
+
+// VerifyAuditManifest checks the audit file described by the manifest at
+// manifestPath against it. It returns the manifest and an error describing
+// the first mismatch, if any. Entries appended after the manifest was
+// written are reported as a size mismatch.
+func VerifyAuditManifest(manifestPath string) (*AuditManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest AuditManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s: invalid audit manifest: %w", manifestPath, err)
+	}
+	content, err := os.ReadFile(filepath.Join(filepath.Dir(manifestPath), manifest.File))
+	if err != nil {
+		return &manifest, err
+	}
+	if int64(len(content)) != manifest.Bytes {
+		return &manifest, fmt.Errorf("%s: size is %d bytes, manifest records %d", manifest.File, len(content), manifest.Bytes)
+	}
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != manifest.SHA256 {
+		return &manifest, fmt.Errorf("%s: checksum does not match the manifest", manifest.File)
+	}
+	var entries int64
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			entries++
+		}
+	}
+	if entries != manifest.Entries {
+		return &manifest, fmt.Errorf("%s: has %d entries, manifest records %d", manifest.File, entries, manifest.Entries)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,96 @@
+package logx
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// BannerConfig enables a startup banner: a single structured entry written
+// when the logger is created, summarizing the application version, the
+// effective logging configuration and the host. It gives aggregators a
+// reliable process-start marker with "event":"startup".
+type BannerConfig struct {
+	// Version is the application version.
+	// Default: the main module version from the build information
+	Version string
+
+	// Fields are added to the banner, e.g. the service name or commit.
+	Fields []Field
+}
+
+// writeBanner writes the startup banner for config. It is written at
+// InfoLevel, or at the logger's level if that is higher, so that it is
+// never filtered out.
+func (l *Logger) writeBanner(config *Config) {
+	level := InfoLevel
+	if config.Level > level {
+		level = config.Level
+	}
+	fields := []Field{
+		String(EventKey, EventStartup),
+		String("version", bannerVersion(config.Banner.Version)),
+		Any("config", configSummary(config)),
+		Any("host", hostSummary()),
+	}
+	fields = append(fields, config.Banner.Fields...)
+	l.emit(&Entry{Time: time.Now(), Level: level, Message: "Logger started", Fields: fields})
+}
+
+// bannerVersion returns version, or the main module version if empty.
+func bannerVersion(version string) string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
+
+// configSummary describes the effective configuration.
+func configSummary(config *Config) map[string]interface{} {
+	output := config.OutputPath
+	switch {
+	case config.OutputWriter != nil && !(config.Development && config.DevDual):
+		output = "writer"
+	case (output == "" || config.Development && !config.DevDual) && config.SplitStdStreams:
+		output = "stdout/stderr"
+	case output == "" || config.Development && !config.DevDual:
+		output = "stdout"
+	}
+	sinks := make([]string, 0, len(config.Sinks)+1)
+	sinks = append(sinks, DefaultSinkName)
+	for _, sc := range config.Sinks {
+		sinks = append(sinks, sc.Name)
+	}
+	summary := map[string]interface{}{
+		"level":       config.Level.String(),
+		"output":      output,
+		"development": config.Development,
+		"sinks":       sinks,
+		"processors":  len(config.Processors),
+		"async":       config.Async != nil,
+	}
+	if config.TimeZone != "" {
+		summary["time_zone"] = config.TimeZone
+	}
+	if config.FieldProfile != nil {
+		summary["field_profile"] = config.FieldProfile.Name
+	}
+	return summary
+}
+
+// hostSummary describes the host and process.
+func hostSummary() map[string]interface{} {
+	hostname, _ := os.Hostname()
+	return map[string]interface{}{
+		"hostname":   hostname,
+		"pid":        os.Getpid(),
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"cpus":       runtime.NumCPU(),
+	}
+}
@@ -0,0 +1,210 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultBodyMaxBytes is the size above which a body is logged by its hash
+// only, unless BodyConfig.MaxBytes is set.
+const DefaultBodyMaxBytes = 4096
+
+// Reasons a body's content is omitted, recorded in the "omitted" key.
+const (
+	BodyOmittedBinary  = "binary"
+	BodyOmittedSize    = "size"
+	BodyOmittedInvalid = "invalid" // JSON bodies that cannot be decoded, and so masked
+)
+
+// BodyConfig controls how request and response bodies are logged by
+// BodyConfig.Field and Body.
+type BodyConfig struct {
+	// MaxBytes is the largest body whose content is logged. Larger bodies
+	// are logged by size and SHA-256 hash only, since a truncated JSON
+	// body could no longer be masked.
+	// Default: DefaultBodyMaxBytes
+	MaxBytes int
+
+	// Paths are masked in JSON bodies, as in Payload, in addition to the
+	// sensitive keys masked at any depth.
+	// Default: nil
+	Paths []string
+
+	// TextTypes are additional media types logged as text, e.g.
+	// "application/vnd.custom". Text types, JSON, XML and form bodies are
+	// always logged; other types are treated as binary.
+	// Default: nil
+	TextTypes []string
+}
+
+// bodyValue is the value of a body field.
+type bodyValue struct {
+	ContentType string      `json:"content_type,omitempty"`
+	Size        int         `json:"size"`
+	Body        interface{} `json:"body,omitempty"`
+	SHA256      string      `json:"sha256,omitempty"`
+	Omitted     string      `json:"omitted,omitempty"` // One of the BodyOmitted reasons
+}
+
+// Body creates a field describing an HTTP request or response body with the
+// default BodyConfig; see BodyConfig.Field.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+//	logger.Info("Request received", logx.Body("request_body", body, r.Header.Get("Content-Type")))
+func Body(key string, body []byte, contentType string) Field {
+	return BodyConfig{}.Field(key, body, contentType)
+}
+
+// Field creates a field describing a request or response body of the given
+// Content-Type, so that every service logs bodies the same safe way. The
+// value is an object with the content type, the size in bytes and:
+//
+//   - for JSON bodies, the decoded body with sensitive keys and Paths masked;
+//   - for form bodies, the decoded values with sensitive keys masked;
+//   - for other text bodies, the body as a string;
+//   - for binary bodies, bodies larger than MaxBytes and invalid JSON
+//     bodies, the SHA-256 hash of the body and the reason its content is
+//     omitted.
+//
+// Without a content type, it is detected from the body.
+//
+// Example:
+//
+//	bodies := logx.BodyConfig{MaxBytes: 16 << 10, Paths: []string{"card.number"}}
+//	logger.Info("Charge created",
+//	    bodies.Field("request_body", reqBody, "application/json"),
+//	    bodies.Field("response_body", respBody, resp.Header.Get("Content-Type")),
+//	)
+//	// "request_body":{"content_type":"application/json","size":87,"body":{"amount":1200,"card":{"number":"***MASKED***"}}}
+func (c BodyConfig) Field(key string, body []byte, contentType string) Field {
+	if contentType == "" && len(body) > 0 {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	value := bodyValue{ContentType: mediaType, Size: len(body)}
+
+	maxBytes := c.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultBodyMaxBytes
+	}
+	switch {
+	case len(body) == 0:
+	case !c.isText(mediaType) || !utf8.Valid(body):
+		value.Omitted = BodyOmittedBinary
+	case len(body) > maxBytes:
+		value.Omitted = BodyOmittedSize
+	case isJSONType(mediaType):
+		var ok bool
+		if value.Body, ok = c.jsonBody(body); !ok {
+			value.Omitted = BodyOmittedInvalid
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		value.Body = formBody(body)
+	default:
+		value.Body = string(body)
+	}
+	if value.Omitted != "" {
+		sum := sha256.Sum256(body)
+		value.SHA256 = hex.EncodeToString(sum[:])
+	}
+	return Field{Key: key, Value: value}
+}
+
+// Message creates a field describing a gRPC or other structured message,
+// converted through its JSON encoding as in Payload and then logged as a
+// JSON body, so that the size cap applies to it too. A message that cannot
+// be encoded is logged as the encoding error.
+//
+// Example:
+//
+//	bodies := logx.BodyConfig{Paths: []string{"card.number"}}
+//	logger.Info("RPC completed", bodies.Message("request", req), bodies.Message("response", resp))
+func (c BodyConfig) Message(key string, message interface{}) Field {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return Field{Key: key, Value: err.Error()}
+	}
+	return c.Field(key, data, "application/json")
+}
+
+// isText reports whether bodies of mediaType are logged as text.
+func (c BodyConfig) isText(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		isJSONType(mediaType),
+		mediaType == "application/xml", strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/x-www-form-urlencoded",
+		mediaType == "application/javascript", mediaType == "application/graphql":
+		return true
+	}
+	for _, t := range c.TextTypes {
+		if strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONType reports whether mediaType denotes JSON or newline-delimited JSON.
+func isJSONType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-ndjson"
+}
+
+// jsonBody decodes a JSON body, or an NDJSON stream into an array, and
+// masks it. It returns false if the body cannot be decoded.
+func (c BodyConfig) jsonBody(body []byte) (interface{}, bool) {
+	var tree interface{}
+	if err := json.Unmarshal(body, &tree); err == nil {
+		return c.maskJSON(tree), true
+	}
+	var lines []interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		var tree interface{}
+		if err := json.Unmarshal([]byte(line), &tree); err != nil {
+			return nil, false
+		}
+		lines = append(lines, c.maskJSON(tree))
+	}
+	return lines, true
+}
+
+// maskJSON masks sensitive keys and Paths in a decoded JSON value.
+func (c BodyConfig) maskJSON(tree interface{}) interface{} {
+	tree = maskTree(tree)
+	for _, path := range c.Paths {
+		tree = maskPath(tree, strings.Split(path, "."))
+	}
+	return tree
+}
+
+// formBody decodes a URL-encoded form body with the values of sensitive
+// keys masked. Bodies that cannot be decoded are logged as strings.
+func formBody(body []byte) interface{} {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return string(body)
+	}
+	form := make(map[string]interface{}, len(values))
+	for key, vs := range values {
+		var v interface{} = vs
+		if len(vs) == 1 {
+			v = vs[0]
+		}
+		form[key] = maskSensitiveData(key, v)
+	}
+	return form
+}
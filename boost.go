@@ -0,0 +1,123 @@
+package logx
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// verbosityBoost is the state of a temporary Debug window opened by Boost.
+type verbosityBoost struct {
+	mu       sync.Mutex
+	timer    *time.Timer // Ends the running boost, nil if none
+	previous Level       // Level to restore when the boost ends
+	gen      int         // Incremented by every Boost, so stale timers do nothing
+}
+
+// Boost raises the level of the logger and every logger sharing its state
+// to Debug for d, then restores the previous level. It writes an Info
+// "Verbosity boost started" entry when the window opens and a "Verbosity
+// boost ended" entry when it closes. Calling Boost during a boost restarts
+// the window with the new duration. If the level was changed with SetLevel
+// during the boost, it is left alone when the boost ends.
+//
+// Example:
+//
+//	logger.Boost(5 * time.Minute) // full detail while investigating
+func (l *Logger) Boost(d time.Duration) {
+	b := &l.shared.boost
+	b.mu.Lock()
+	if b.timer != nil {
+		b.timer.Stop()
+	} else {
+		b.previous = l.GetLevel()
+		if b.previous > DebugLevel {
+			l.SetLevel(DebugLevel)
+		}
+	}
+	b.gen++
+	gen := b.gen
+	previous := b.previous
+	b.timer = time.AfterFunc(d, func() { l.endBoost(gen) })
+	b.mu.Unlock()
+
+	l.emit(&Entry{Time: time.Now(), Level: InfoLevel, Message: "Verbosity boost started", Fields: []Field{
+		String("boost_duration", d.String()),
+		String("previous_level", previous.String()),
+	}})
+}
+
+// EndBoost ends a running boost early and restores the previous level. It
+// does nothing if no boost is running.
+func (l *Logger) EndBoost() {
+	b := &l.shared.boost
+	b.mu.Lock()
+	gen := b.gen
+	b.mu.Unlock()
+	l.endBoost(gen)
+}
+
+// endBoost ends the boost identified by gen, unless a later Boost call has
+// replaced it.
+func (l *Logger) endBoost(gen int) {
+	b := &l.shared.boost
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer == nil || gen != b.gen {
+		return
+	}
+	b.timer.Stop()
+	b.timer = nil
+
+	// Write the marker before restoring, so that it passes the boosted level
+	restore := b.previous > DebugLevel && l.GetLevel() == DebugLevel
+	restored := l.GetLevel()
+	if restore {
+		restored = b.previous
+	}
+	l.emit(&Entry{Time: time.Now(), Level: InfoLevel, Message: "Verbosity boost ended", Fields: []Field{
+		String("restored_level", restored.String()),
+	}})
+	if restore {
+		l.SetLevel(b.previous)
+	}
+}
+
+// BoostOnSignal calls Boost with d every time the process receives one of
+// signals, SIGUSR1 by default, so that an operator can turn on Debug
+// logging of a running service without restarting it:
+//
+//	kill -USR1 <pid>
+//
+// It returns a function that stops listening. On platforms without
+// SIGUSR1, such as Windows, BoostOnSignal does nothing unless signals are
+// given.
+func (l *Logger) BoostOnSignal(d time.Duration, signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = boostSignals
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				l.Boost(d)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logx
+
+import "os"
+
+// boostSignals is empty, as this platform has no SIGUSR1.
+var boostSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logx
+
+import (
+	"os"
+	"syscall"
+)
+
+// boostSignals are the signals BoostOnSignal listens to by default.
+var boostSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,236 @@
+package logx
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// MaxRate is the sustained number of entries per second above which
+	// the breaker trips.
+	MaxRate float64
+
+	// Sustain is how long the rate must stay above MaxRate before the
+	// breaker trips, so that short bursts are tolerated.
+	// Default: 10s
+	Sustain time.Duration
+
+	// Cooldown is how long the rate must stay at or below MaxRate before
+	// normal logging is restored.
+	// Default: 30s
+	Cooldown time.Duration
+
+	// SummaryInterval is how often a summary of suppressed entries is
+	// written while the breaker is open.
+	// Default: 10s
+	SummaryInterval time.Duration
+
+	// Interval is the period over which the rate is measured.
+	// Default: 1s
+	Interval time.Duration
+}
+
+// CircuitBreaker is a Processor that protects the application from
+// log-amplified overload. When the rate of entries stays above MaxRate for
+// Sustain, the breaker opens and the logger switches to a degraded mode in
+// which only Error and Fatal entries are written, plus a periodic summary
+// of the suppressed entries. Once the rate has stayed at or below MaxRate
+// for Cooldown, the breaker closes and normal logging resumes. Entries
+// suppressed while open still count towards the rate.
+//
+// Call Close when the breaker is no longer needed to stop its background
+// goroutine.
+type CircuitBreaker struct {
+	config       CircuitBreakerConfig
+	perInterval  float64
+	tripAfter    int
+	restoreAfter int
+
+	mu         sync.Mutex
+	count      int // Entries seen in the current interval
+	over       int // Consecutive intervals above the rate
+	under      int // Consecutive intervals at or below the rate while open
+	open       bool
+	openedAt   time.Time
+	suppressed map[Level]int // Entries suppressed since the last summary
+	total      int           // Entries suppressed since the breaker opened
+	lastReport time.Time
+	emit       func(e *Entry)
+	stop       chan struct{}
+	done       chan struct{}
+	once       sync.Once
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with the given configuration.
+//
+// Example:
+//
+//	// Degrade to errors only above 5000 entries/s sustained for 10s
+//	breaker := logx.NewCircuitBreaker(logx.CircuitBreakerConfig{MaxRate: 5000})
+//	defer breaker.Close()
+//	config := logx.DefaultConfig()
+//	config.Processors = []logx.Processor{breaker}
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.Sustain <= 0 {
+		config.Sustain = 10 * time.Second
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	if config.SummaryInterval <= 0 {
+		config.SummaryInterval = 10 * time.Second
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	cb := &CircuitBreaker{
+		config:       config,
+		perInterval:  config.MaxRate * config.Interval.Seconds(),
+		tripAfter:    intervals(config.Sustain, config.Interval),
+		restoreAfter: intervals(config.Cooldown, config.Interval),
+		suppressed:   make(map[Level]int),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go cb.run()
+	return cb
+}
+
+// intervals returns how many whole intervals make up d, at least one.
+func intervals(d, interval time.Duration) int {
+	n := int((d + interval - 1) / interval)
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// Bind sets the function used to write state changes and summaries.
+func (cb *CircuitBreaker) Bind(emit func(e *Entry)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.emit = emit
+}
+
+// Open reports whether the breaker is open, i.e. the logger is degraded.
+func (cb *CircuitBreaker) Open() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.open
+}
+
+// Process counts the entry and suppresses it if the breaker is open and it
+// is below Error.
+func (cb *CircuitBreaker) Process(e *Entry) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.count++
+	if !cb.open || e.Level >= ErrorLevel {
+		return true
+	}
+	cb.suppressed[e.Level]++
+	cb.total++
+	return false
+}
+
+// tickLocked evaluates the rate of the interval that just ended.
+// cb.mu must be held.
+func (cb *CircuitBreaker) tickLocked(now time.Time) {
+	exceeded := float64(cb.count) > cb.perInterval
+	cb.count = 0
+
+	if !cb.open {
+		if !exceeded {
+			cb.over = 0
+			return
+		}
+		cb.over++
+		if cb.over >= cb.tripAfter {
+			cb.open = true
+			cb.openedAt = now
+			cb.lastReport = now
+			cb.over = 0
+			cb.under = 0
+			cb.total = 0
+			cb.emitLocked(WarnLevel, "Log circuit breaker opened, writing errors only",
+				Float64("max_rate", cb.config.MaxRate))
+		}
+		return
+	}
+
+	if exceeded {
+		cb.under = 0
+	} else {
+		cb.under++
+	}
+	if cb.under >= cb.restoreAfter {
+		cb.summaryLocked()
+		cb.open = false
+		cb.under = 0
+		cb.emitLocked(WarnLevel, "Log circuit breaker closed, normal logging restored",
+			Int("suppressed_count", cb.total),
+			String("degraded_for", now.Sub(cb.openedAt).Round(time.Millisecond).String()))
+		return
+	}
+	if now.Sub(cb.lastReport) >= cb.config.SummaryInterval {
+		cb.lastReport = now
+		cb.summaryLocked()
+	}
+}
+
+// summaryLocked writes the entries suppressed since the last summary, by
+// level. cb.mu must be held.
+func (cb *CircuitBreaker) summaryLocked() {
+	if len(cb.suppressed) == 0 {
+		return
+	}
+	fields := make([]Field, 0, len(cb.suppressed)+1)
+	total := 0
+	for level := TraceLevel; level < ErrorLevel; level++ {
+		if n := cb.suppressed[level]; n > 0 {
+			fields = append(fields, Int("suppressed_"+strings.ToLower(level.String()), n))
+			total += n
+		}
+	}
+	fields = append([]Field{Int("suppressed_count", total)}, fields...)
+	cb.suppressed = make(map[Level]int)
+	cb.emitLocked(WarnLevel, "Log entries suppressed by circuit breaker", fields...)
+}
+
+// emitLocked writes an entry generated by the breaker. cb.mu must be held.
+func (cb *CircuitBreaker) emitLocked(level Level, msg string, fields ...Field) {
+	if cb.emit == nil {
+		return
+	}
+	cb.emit(&Entry{Time: time.Now(), Level: level, Message: msg, Fields: fields})
+}
+
+// run evaluates the rate at the end of every interval until Close is called.
+func (cb *CircuitBreaker) run() {
+	defer close(cb.done)
+	ticker := time.NewTicker(cb.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			cb.mu.Lock()
+			cb.tickLocked(now)
+			cb.mu.Unlock()
+		case <-cb.stop:
+			cb.mu.Lock()
+			cb.summaryLocked()
+			cb.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Close stops the background goroutine and writes a summary of entries
+// suppressed since the last one. It is safe to call Close more than once.
+func (cb *CircuitBreaker) Close() error {
+	cb.once.Do(func() { close(cb.stop) })
+	<-cb.done
+	return nil
+}
@@ -0,0 +1,118 @@
+package logx
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget limits how much a request-scoped logger may write. Zero values
+// mean unlimited. Once either limit is reached, further entries below Warn
+// are suppressed and summarized; Warn, Error and Fatal entries are always
+// written and still count towards the budget.
+type Budget struct {
+	// MaxEntries is the maximum number of entries.
+	MaxEntries int64
+
+	// MaxBytes is the maximum estimated encoded size of the entries.
+	MaxBytes int64
+}
+
+// logBudget tracks the usage of a Budget, shared by a budgeted logger and
+// the loggers derived from it.
+type logBudget struct {
+	limit Budget
+
+	mu              sync.Mutex
+	entries         int64
+	bytes           int64
+	suppressed      int64
+	suppressedBytes int64
+	exceeded        bool
+	ended           bool
+}
+
+// allow accounts an entry of the given size and reports whether it may be
+// written. The second result is true for the first suppressed entry.
+func (b *logBudget) allow(level Level, size int64) (allowed, first bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	over := (b.limit.MaxEntries > 0 && b.entries+1 > b.limit.MaxEntries) ||
+		(b.limit.MaxBytes > 0 && b.bytes+size > b.limit.MaxBytes)
+	if over && level < WarnLevel {
+		b.suppressed++
+		b.suppressedBytes += size
+		first = !b.exceeded
+		b.exceeded = true
+		return false, first
+	}
+	b.entries++
+	b.bytes += size
+	return true, false
+}
+
+// WithBudget creates a child logger that enforces a log budget, typically
+// one per request, so that a single pathological request cannot generate
+// millions of lines. Loggers derived from the child share its budget.
+//
+// When the budget is first exhausted, a Warn entry announces it; call
+// EndBudget when the request completes to write a summary of what was
+// suppressed. Both entries carry the logger's fields, such as a request ID.
+//
+// Example:
+//
+//	reqLogger := logger.WithBudget(logx.Budget{MaxEntries: 1000, MaxBytes: 1 << 20}).
+//	    With(logx.RequestID(id))
+//	defer reqLogger.EndBudget()
+func (l *Logger) WithBudget(budget Budget) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	child := l.child(append([]Field(nil), l.fields...))
+	child.budget = &logBudget{limit: budget}
+	return child
+}
+
+// EndBudget writes a Warn summary of the entries suppressed by the
+// logger's budget, if any. The summary is written at most once; EndBudget
+// does nothing for loggers without a budget.
+func (l *Logger) EndBudget() {
+	b := l.budget
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ended {
+		return
+	}
+	b.ended = true
+	if b.suppressed > 0 {
+		l.emitBudget("Log budget summary", []Field{
+			Int64("suppressed_count", b.suppressed),
+			Int64("suppressed_bytes", b.suppressedBytes),
+			Int64("written_count", b.entries),
+		})
+	}
+}
+
+// checkBudget accounts an entry against the logger's budget and reports
+// whether it may be written.
+func (l *Logger) checkBudget(level Level, msg string, fields []Field) bool {
+	b := l.budget
+	size := estimateSize(&Entry{Message: msg, LoggerName: l.name, Fields: fields})
+	allowed, first := b.allow(level, size)
+	if first {
+		l.emitBudget("Log budget exceeded, suppressing entries below Warn", []Field{
+			Int64("max_entries", b.limit.MaxEntries),
+			Int64("max_bytes", b.limit.MaxBytes),
+		})
+	}
+	return allowed
+}
+
+// emitBudget writes a budget notice with the logger's fields.
+func (l *Logger) emitBudget(msg string, fields []Field) {
+	l.mu.RLock()
+	all := append(append([]Field(nil), l.fields...), fields...)
+	l.mu.RUnlock()
+	l.emit(&Entry{Time: time.Now(), Level: WarnLevel, LoggerName: l.name, Message: msg, Fields: all})
+}
@@ -0,0 +1,103 @@
+package logx
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// CallerComponentConfig configures the component field derived from the
+// package of the logging call; see Config.CallerComponent.
+type CallerComponentConfig struct {
+	// TrimPrefix is removed from the package path, along with the slash
+	// following it. The root package of the prefix is named after its last
+	// path element.
+	// Default: the path of the main module, so that
+	// "github.com/acme/shop/internal/orders" becomes "internal/orders"
+	TrimPrefix string `yaml:"trim_prefix"`
+
+	// Depth is the number of leading path elements kept after trimming,
+	// so that the subpackages of a package share its component: with
+	// Depth 2, "internal/orders/pricing" becomes "internal/orders".
+	// Default: 0 (the whole path)
+	Depth int `yaml:"depth"`
+}
+
+// callerComponents derives components from call sites.
+type callerComponents struct {
+	prefix string
+	depth  int
+	cache  sync.Map // Component by program counter of the call site
+}
+
+// newCallerComponents returns the components for config, or nil if
+// disabled.
+func newCallerComponents(config *CallerComponentConfig) *callerComponents {
+	if config == nil {
+		return nil
+	}
+	c := &callerComponents{prefix: config.TrimPrefix, depth: config.Depth}
+	if c.prefix == "" {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			c.prefix = info.Main.Path
+		}
+	}
+	c.prefix = strings.TrimSuffix(c.prefix, "/")
+	return c
+}
+
+// lookup returns the component of the function skip frames above the
+// caller of lookup.
+func (c *callerComponents) lookup(skip int) string {
+	var pcs [1]uintptr
+	// Skip runtime.Callers, lookup and its caller.
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return ""
+	}
+	if component, ok := c.cache.Load(pcs[0]); ok {
+		return component.(string)
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	component := c.component(packagePath(frame.Function))
+	c.cache.Store(pcs[0], component)
+	return component
+}
+
+// component trims the package path pkg to a component.
+func (c *callerComponents) component(pkg string) string {
+	if c.prefix != "" {
+		if pkg == c.prefix {
+			return path.Base(pkg)
+		}
+		pkg = strings.TrimPrefix(pkg, c.prefix+"/")
+	}
+	if c.depth > 0 {
+		elements := strings.SplitN(pkg, "/", c.depth+1)
+		if len(elements) > c.depth {
+			pkg = strings.Join(elements[:c.depth], "/")
+		}
+	}
+	return pkg
+}
+
+// hasKey reports whether fields contain a field with key.
+func hasKey(fields []Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// packagePath returns the import path of the package of a function named
+// as by runtime.Frame, such as "github.com/acme/shop/orders.(*Service).Place".
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
@@ -0,0 +1,174 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CLIConfig enables the command-line mode of Config.CLI, in which entries
+// are written as human progress lines instead of log records.
+type CLIConfig struct {
+	// Output receives the progress lines.
+	// Default: os.Stderr
+	Output io.Writer
+
+	// Verbose also writes Debug and Trace entries as progress lines, e.g.
+	// for a --verbose flag. They are always written to the JSON file.
+	// Default: false (Info and above)
+	Verbose bool
+
+	// Progress, if set, is cleared before each line is written and redrawn
+	// after it, so that lines scroll above an active progress bar.
+	// Default: nil
+	Progress ProgressBar
+}
+
+// ProgressBar is implemented by terminal progress bars, such as those of
+// github.com/schollz/progressbar, that can be cleared and redrawn around
+// progress lines written by the CLI mode.
+type ProgressBar interface {
+	Clear() error
+	RenderBlank() error
+}
+
+// cliOutput serializes the progress lines written by the cores of a CLI
+// mode output and the redrawing of its progress bar.
+type cliOutput struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	progress ProgressBar
+	color    bool
+	palette  map[zapcore.Level]string
+}
+
+// cliCore writes entries as progress lines:
+//
+//	Downloaded archive size=1048576 path="/tmp/my file.tgz"
+//	warning: Checksum file missing, skipping verification
+//	error: Extraction failed error="unexpected EOF"
+type cliCore struct {
+	zapcore.LevelEnabler
+	output *cliOutput
+	fields []zapcore.Field
+}
+
+// newCLICore creates the core of the primary output in CLI mode: progress
+// lines to CLI.Output and, if OutputPath is set, strict JSON to that file.
+func (oc *outputConfig) newCLICore(config *Config) (zapcore.Core, error) {
+	writer := config.CLI.Output
+	if writer == nil {
+		writer = os.Stderr
+	}
+	level := oc.level
+	if !config.CLI.Verbose {
+		level = zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l >= zapcore.InfoLevel && oc.level.Enabled(l)
+		})
+	}
+	color := useColor(oc.theme, writer)
+	lines := &cliCore{
+		LevelEnabler: level,
+		output: &cliOutput{
+			writer:   writer,
+			progress: config.CLI.Progress,
+			color:    color,
+			palette:  themePalettes[oc.theme],
+		},
+	}
+	if config.OutputPath == "" {
+		return lines, nil
+	}
+	mirror := *oc
+	mirror.strict = true
+	file, err := mirror.newCore(DefaultSinkName+".json", false, config.OutputPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return zapcore.NewTee(lines, file), nil
+}
+
+// With returns a core adding fields to every line.
+func (c *cliCore) With(fields []zapcore.Field) zapcore.Core {
+	return &cliCore{
+		LevelEnabler: c.LevelEnabler,
+		output:       c.output,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check adds the core if the entry's level is enabled.
+func (c *cliCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write formats the entry as a progress line and writes it.
+func (c *cliCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var line strings.Builder
+	if ent.Level != zapcore.InfoLevel {
+		prefix := strings.ToLower(ent.Level.String())
+		if ent.Level == zapcore.WarnLevel {
+			prefix = "warning"
+		}
+		if color := levelColor(c.output.palette, ent.Level); c.output.color && color != "" {
+			prefix = color + prefix + ansiReset
+		}
+		line.WriteString(prefix + ": ")
+	}
+	line.WriteString(ent.Message)
+	for _, f := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		line.WriteString(" " + f.Key + "=" + cliValue(f))
+	}
+	line.WriteString("\n")
+
+	out := c.output
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if out.progress != nil {
+		out.progress.Clear()
+	}
+	_, err := io.WriteString(out.writer, line.String())
+	if out.progress != nil {
+		out.progress.RenderBlank()
+	}
+	return err
+}
+
+// Sync flushes the output if it supports syncing.
+func (c *cliCore) Sync() error {
+	if syncer, ok := c.output.writer.(interface{ Sync() error }); ok {
+		c.output.mu.Lock()
+		defer c.output.mu.Unlock()
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// cliValue formats the value of a field for a progress line. Strings are
+// quoted when they contain spaces, quotes or "=", and structured values
+// are written as JSON.
+func cliValue(f zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	switch v := enc.Fields[f.Key].(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	default:
+		if data, err := json.Marshal(v); err == nil && len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+			return string(data)
+		}
+		return fmt.Sprint(v)
+	}
+}
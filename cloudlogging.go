@@ -0,0 +1,184 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CloudLoggingConfig configures a sink writing entries to Google Cloud
+// Logging.
+type CloudLoggingConfig struct {
+	// Project is the Google Cloud project the entries are written to.
+	// Required.
+	Project string
+
+	// LogID names the log within the project, e.g. "checkout".
+	// Default: "app"
+	LogID string
+
+	// Token returns an OAuth2 access token with the logging.write scope,
+	// e.g. from golang.org/x/oauth2/google or the metadata server. It is
+	// called for every batch and should cache tokens. Required.
+	Token func() (string, error)
+
+	// ResourceType is the monitored resource type of the entries, e.g.
+	// "k8s_container" on GKE or "gce_instance" on GCE.
+	// Default: "global"
+	ResourceType string
+
+	// ResourceLabels are the labels identifying the monitored resource,
+	// e.g. project_id, location, cluster_name, namespace_name, pod_name
+	// and container_name for "k8s_container".
+	// Default: {"project_id": Project}
+	ResourceLabels map[string]string
+
+	// Labels are added to every entry.
+	// Default: nil
+	Labels map[string]string
+
+	// TraceField is the field holding the trace ID that correlates an
+	// entry with its trace in Cloud Trace. Bare IDs are expanded to
+	// "projects/<Project>/traces/<ID>".
+	// Default: "trace", or KeyTraceID if absent
+	TraceField string
+
+	// Endpoint is the Cloud Logging API URL.
+	// Default: "https://logging.googleapis.com"
+	Endpoint string
+
+	// Batch configures batching. Batches are capped at 1000 entries and
+	// the 10 MB limit of entries.write requests.
+	Batch BatchConfig
+}
+
+// cloudLoggingMaxBytes keeps entries.write requests within the 10 MB
+// Cloud Logging limit, leaving room for the envelope of the entries.
+const cloudLoggingMaxBytes = 8 << 20
+
+// cloudLoggingSeverities maps logx level names to Cloud Logging
+// severities.
+var cloudLoggingSeverities = map[string]string{
+	"TRACE": "DEBUG", "DEBUG": "DEBUG", "INFO": "INFO", "WARN": "WARNING", "ERROR": "ERROR", "FATAL": "CRITICAL",
+}
+
+// NewCloudLoggingSink returns a sink writing entries with the Cloud
+// Logging entries.write API. The level becomes the entry severity, the
+// timestamp the entry time, the event ID the insert ID, the caller the
+// source location, and the trace and span IDs correlate the entry with
+// Cloud Trace; the remaining fields, including the message, form the JSON
+// payload.
+//
+// Example:
+//
+//	gcl, err := logx.NewCloudLoggingSink(logx.CloudLoggingConfig{
+//	    Project:      "my-project",
+//	    LogID:        "checkout",
+//	    Token:        tokenSource,
+//	    ResourceType: "k8s_container",
+//	    ResourceLabels: map[string]string{
+//	        "project_id": "my-project", "location": "europe-west1", "cluster_name": "prod",
+//	        "namespace_name": "shop", "pod_name": os.Getenv("HOSTNAME"), "container_name": "checkout",
+//	    },
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "gcl", Writer: gcl}}
+//	defer gcl.Close()
+func NewCloudLoggingSink(config CloudLoggingConfig) (*APISink, error) {
+	if config.Project == "" || config.Token == nil {
+		return nil, fmt.Errorf("cloud logging sink requires a project and a token")
+	}
+	if config.LogID == "" {
+		config.LogID = "app"
+	}
+	if config.ResourceType == "" {
+		config.ResourceType = "global"
+	}
+	if config.ResourceLabels == nil {
+		config.ResourceLabels = map[string]string{"project_id": config.Project}
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://logging.googleapis.com"
+	}
+	traceFields := []string{"trace", KeyTraceID}
+	if config.TraceField != "" {
+		traceFields = []string{config.TraceField}
+	}
+	target := strings.TrimSuffix(config.Endpoint, "/") + "/v2/entries:write"
+	request := map[string]interface{}{
+		"logName":        "projects/" + config.Project + "/logs/" + url.PathEscape(config.LogID),
+		"resource":       map[string]interface{}{"type": config.ResourceType, "labels": config.ResourceLabels},
+		"partialSuccess": true,
+	}
+	if len(config.Labels) > 0 {
+		request["labels"] = config.Labels
+	}
+
+	return newAPISink(config.Batch, apiFormat{
+		maxEntries: 1000,
+		maxBytes:   cloudLoggingMaxBytes,
+		request: func(batch []apiEntry) (*http.Request, error) {
+			token, err := config.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get cloud logging token: %w", err)
+			}
+			entries := make([]map[string]interface{}, len(batch))
+			for i, e := range batch {
+				entries[i] = cloudLoggingEntry(e.fields, e.keys, config.Project, traceFields)
+			}
+			body := make(map[string]interface{}, len(request)+1)
+			for key, value := range request {
+				body[key] = value
+			}
+			body["entries"] = entries
+			return newJSONRequest(target, body, map[string]string{"Authorization": "Bearer " + token})
+		},
+	})
+}
+
+// cloudLoggingEntry converts the fields of an entry to a Cloud Logging
+// LogEntry, moving the fields with a LogEntry equivalent out of the
+// payload.
+func cloudLoggingEntry(fields map[string]interface{}, keys *entryKeys, project string, traceFields []string) map[string]interface{} {
+	entry := map[string]interface{}{
+		"timestamp": keys.takeTime(fields).Format(time.RFC3339Nano),
+	}
+	severity := "DEFAULT"
+	if mapped, ok := cloudLoggingSeverities[keys.levelOf(fields)]; ok {
+		severity = mapped
+		delete(fields, keys.level)
+	}
+	entry["severity"] = severity
+	for _, key := range traceFields {
+		if trace := fieldValue(fields, key); trace != "" {
+			if !strings.HasPrefix(trace, "projects/") {
+				trace = "projects/" + project + "/traces/" + trace
+			}
+			entry["trace"] = trace
+			delete(fields, key)
+			break
+		}
+	}
+	if spanID := fieldValue(fields, KeySpanID); spanID != "" {
+		entry["spanId"] = spanID
+		delete(fields, KeySpanID)
+	}
+	if id := fieldValue(fields, EventIDKey); id != "" {
+		entry["insertId"] = id
+		delete(fields, EventIDKey)
+	}
+	if caller, ok := fields[keys.caller].(string); ok {
+		if i := strings.LastIndexByte(caller, ':'); i > 0 {
+			if line, err := strconv.Atoi(caller[i+1:]); err == nil {
+				entry["sourceLocation"] = map[string]interface{}{"file": caller[:i], "line": strconv.Itoa(line)}
+				delete(fields, keys.caller)
+			}
+		}
+	}
+	entry["jsonPayload"] = fields
+	return entry
+}
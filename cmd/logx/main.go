@@ -0,0 +1,183 @@
+// Command logx pretty-prints JSON logs written by go-logx.
+//
+// It reads entries from the named files, or from standard input if no files
+// are given, and renders them as colorized, human-friendly lines. Entries
+// can be filtered by minimum level, field values and time range. With
+// -since, files that have a sidecar index (see Config.IndexInterval) are
+// read from the indexed offset closest to the start of the range.
+//
+// The redact subcommand instead reports which fields of the inputs would be
+// masked under the default sensitive key policy, optionally adjusted with
+// -add and -remove, so masking changes can be validated before rollout.
+//
+// Usage:
+//
+//	logx [flags] [file ...]
+//	logx redact [-add key] [-remove key] [file ...]
+//
+// Examples:
+//
+//	kubectl logs -f deploy/api | logx -level warn
+//	logx -field user_id=42 -since 15m /var/log/app.log
+//	logx -since 2024-05-01T10:00:00Z -until 2024-05-01T11:00:00Z app.log.1 app.log
+//	logx redact -add iban -remove email captured.log
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// fieldFlags collects repeated -field key=value flags.
+type fieldFlags map[string]string
+
+func (f fieldFlags) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f fieldFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[key] = val
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "logx:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments and renders every input to out.
+func run(args []string, stdin io.Reader, out io.Writer) error {
+	if len(args) > 0 && args[0] == "redact" {
+		return runRedact(args[1:], stdin, out)
+	}
+	fields := fieldFlags{}
+	flags := flag.NewFlagSet("logx", flag.ContinueOnError)
+	level := flags.String("level", "", "minimum level to show (trace, debug, info, warn, error, fatal)")
+	since := flags.String("since", "", "show entries at or after this time (RFC3339 or a duration such as 15m)")
+	until := flags.String("until", "", "show entries before this time (RFC3339 or a duration such as 5m)")
+	noColor := flags.Bool("no-color", false, "disable colorized output")
+	forceColor := flags.Bool("color", false, "force colorized output even when not writing to a terminal")
+	flags.Var(fields, "field", "only show entries with field key=value (repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: logx [flags] [file ...]")
+		fmt.Fprintln(flags.Output(), "       logx redact [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filter := entryFilter{fields: fields}
+	if *level != "" {
+		rank, ok := levelRank(*level)
+		if !ok {
+			return fmt.Errorf("unknown level %q", *level)
+		}
+		filter.minLevel = rank
+	}
+	now := time.Now()
+	var err error
+	if filter.since, err = parseTimeFlag(*since, now); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.until, err = parseTimeFlag(*until, now); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	color := useColor(out)
+	if *noColor {
+		color = false
+	}
+	if *forceColor {
+		color = true
+	}
+	r := renderer{out: bufio.NewWriter(out), color: color}
+	defer r.out.Flush()
+
+	if flags.NArg() == 0 {
+		return render(stdin, &filter, &r)
+	}
+	for _, name := range flags.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		if !filter.since.IsZero() {
+			// Skip ahead using the sidecar index, if the file has one.
+			if offset, err := logx.IndexOffset(name, filter.since); err == nil && offset > 0 {
+				file.Seek(offset, io.SeekStart)
+			}
+		}
+		err = render(file, &filter, &r)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// parseTimeFlag accepts an RFC3339 timestamp or a duration relative to now.
+// An empty value yields the zero time.
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// useColor reports whether out is a terminal and colors are not disabled
+// through the NO_COLOR convention.
+func useColor(out io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// render reads lines from in and renders those that pass the filter.
+// Lines that are not JSON objects are passed through unchanged when no
+// filter is active.
+func render(in io.Reader, filter *entryFilter, r *renderer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		entry, ok := parseEntry(line)
+		if !ok {
+			if filter.empty() {
+				r.raw(line)
+			}
+			continue
+		}
+		if filter.matches(entry) {
+			r.entry(entry)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// keyFlags collects repeated key flags.
+type keyFlags []string
+
+func (k *keyFlags) String() string {
+	return strings.Join(*k, ",")
+}
+
+func (k *keyFlags) Set(value string) error {
+	if value == "" {
+		return fmt.Errorf("empty key")
+	}
+	*k = append(*k, value)
+	return nil
+}
+
+// runRedact implements the redact subcommand, which reports the fields that
+// would be masked in the inputs under the default policy adjusted by -add
+// and -remove.
+func runRedact(args []string, stdin io.Reader, out io.Writer) error {
+	var add, remove keyFlags
+	flags := flag.NewFlagSet("logx redact", flag.ContinueOnError)
+	flags.Var(&add, "add", "treat this key as sensitive (repeatable)")
+	flags.Var(&remove, "remove", "do not treat this key as sensitive (repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: logx redact [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	for _, key := range add {
+		logx.AddSensitiveKey(key)
+	}
+	for _, key := range remove {
+		logx.RemoveSensitiveKey(key)
+	}
+
+	inputs := []io.Reader{stdin}
+	if flags.NArg() > 0 {
+		inputs = inputs[:0]
+		for _, name := range flags.Args() {
+			file, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			inputs = append(inputs, file)
+		}
+	}
+	report, err := logx.PreviewRedactionReader(io.MultiReader(inputs...))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Examined %d entries, %d with masked fields", report.Entries, report.Masked)
+	if report.Skipped > 0 {
+		fmt.Fprintf(out, " (%d non-JSON lines skipped)", report.Skipped)
+	}
+	fmt.Fprintln(out)
+	if len(report.Fields) == 0 {
+		return nil
+	}
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "FIELD\tENTRIES\tEXAMPLE")
+	for _, field := range report.Fields {
+		fmt.Fprintf(table, "%s\t%d\t%s\n", field.Key, field.Count, field.Example)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keys recognized for the entry envelope, in order of preference.
+var (
+	timeKeys    = []string{"timestamp", "ts", "time", "@timestamp"}
+	levelKeys   = []string{"level", "lvl", "severity"}
+	messageKeys = []string{"message", "msg"}
+	loggerKeys  = []string{"logger", "name"}
+	callerKeys  = []string{"caller"}
+	stackKeys   = []string{"stacktrace", "stack"}
+)
+
+// levelRanks orders level names from least to most severe.
+var levelRanks = map[string]int{
+	"TRACE": 0, "DEBUG": 1, "INFO": 2, "WARN": 3, "WARNING": 3,
+	"ERROR": 4, "DPANIC": 5, "PANIC": 5, "FATAL": 6,
+}
+
+// levelRank returns the severity rank of a level name.
+func levelRank(name string) (int, bool) {
+	rank, ok := levelRanks[strings.ToUpper(name)]
+	return rank, ok
+}
+
+// entry is a decoded log line with its envelope extracted.
+type entry struct {
+	time    time.Time
+	rawTime string
+	level   string
+	message string
+	logger  string
+	caller  string
+	stack   string
+	fields  map[string]interface{}
+}
+
+// take removes and returns the first present key from fields as a string.
+func take(fields map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if v, ok := fields[key]; ok {
+			delete(fields, key)
+			if s, ok := v.(string); ok {
+				return s
+			}
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// parseEntry decodes a JSON log line. It returns false for lines that are
+// not JSON objects.
+func parseEntry(line []byte) (*entry, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, false
+	}
+	e := &entry{fields: fields}
+	e.rawTime = take(fields, timeKeys)
+	if t, err := time.Parse(time.RFC3339Nano, e.rawTime); err == nil {
+		e.time = t
+	}
+	e.level = strings.ToUpper(take(fields, levelKeys))
+	e.message = take(fields, messageKeys)
+	e.logger = take(fields, loggerKeys)
+	e.caller = take(fields, callerKeys)
+	e.stack = take(fields, stackKeys)
+	return e, true
+}
+
+// entryFilter selects the entries to render.
+type entryFilter struct {
+	minLevel int
+	fields   map[string]string
+	since    time.Time
+	until    time.Time
+}
+
+// empty reports whether the filter lets every entry through.
+func (f *entryFilter) empty() bool {
+	return f.minLevel == 0 && len(f.fields) == 0 && f.since.IsZero() && f.until.IsZero()
+}
+
+// matches reports whether the entry passes every condition of the filter.
+func (f *entryFilter) matches(e *entry) bool {
+	if f.minLevel > 0 {
+		rank, ok := levelRank(e.level)
+		if !ok || rank < f.minLevel {
+			return false
+		}
+	}
+	if !f.since.IsZero() && (e.time.IsZero() || e.time.Before(f.since)) {
+		return false
+	}
+	if !f.until.IsZero() && (e.time.IsZero() || !e.time.Before(f.until)) {
+		return false
+	}
+	for key, want := range f.fields {
+		var got string
+		switch key {
+		case "logger":
+			got = e.logger
+		case "message":
+			got = e.message
+		default:
+			v, ok := e.fields[key]
+			if !ok {
+				return false
+			}
+			got = fmt.Sprint(v)
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// ANSI escape sequences used for colorized output.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiBold    = "\x1b[1m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// levelColors maps level names to their display color.
+var levelColors = map[string]string{
+	"TRACE":  ansiDim,
+	"DEBUG":  ansiMagenta,
+	"INFO":   ansiGreen,
+	"WARN":   ansiYellow,
+	"ERROR":  ansiRed,
+	"DPANIC": ansiBold + ansiRed,
+	"PANIC":  ansiBold + ansiRed,
+	"FATAL":  ansiBold + ansiRed,
+}
+
+// renderer writes entries in human-friendly form.
+type renderer struct {
+	out   *bufio.Writer
+	color bool
+}
+
+// paint wraps s in the given color when colors are enabled.
+func (r *renderer) paint(color, s string) string {
+	if !r.color || color == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// raw writes a line that could not be parsed.
+func (r *renderer) raw(line []byte) {
+	r.out.Write(line)
+	r.out.WriteByte('\n')
+}
+
+// entry writes a single entry:
+//
+//	15:04:05.000 INFO  [http] Request served  status=200 path=/users (handler.go:42)
+func (r *renderer) entry(e *entry) {
+	timestamp := e.rawTime
+	if !e.time.IsZero() {
+		timestamp = e.time.Local().Format("2006-01-02 15:04:05.000")
+	}
+	if timestamp != "" {
+		r.out.WriteString(r.paint(ansiDim, timestamp))
+		r.out.WriteByte(' ')
+	}
+	r.out.WriteString(r.paint(levelColors[e.level], fmt.Sprintf("%-5s", e.level)))
+	r.out.WriteByte(' ')
+	if e.logger != "" {
+		r.out.WriteString(r.paint(ansiBlue, "["+e.logger+"]"))
+		r.out.WriteByte(' ')
+	}
+	r.out.WriteString(r.paint(ansiBold, e.message))
+
+	keys := make([]string, 0, len(e.fields))
+	for key := range e.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			r.out.WriteString("  ")
+		} else {
+			r.out.WriteByte(' ')
+		}
+		r.out.WriteString(r.paint(ansiCyan, key))
+		r.out.WriteByte('=')
+		r.out.WriteString(formatValue(e.fields[key]))
+	}
+	if e.caller != "" {
+		r.out.WriteByte(' ')
+		r.out.WriteString(r.paint(ansiDim, "("+e.caller+")"))
+	}
+	r.out.WriteByte('\n')
+	if e.stack != "" {
+		for _, line := range strings.Split(e.stack, "\n") {
+			r.out.WriteString(r.paint(ansiDim, "    "+line))
+			r.out.WriteByte('\n')
+		}
+	}
+}
+
+// formatValue renders a field value compactly, quoting strings that
+// contain spaces or are empty.
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			return fmt.Sprintf("%q", value)
+		}
+		return value
+	case json.Number:
+		return value.String()
+	case nil:
+		return "null"
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
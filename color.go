@@ -0,0 +1,162 @@
+package logx
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ColorTheme selects the colors of level names in development console
+// output.
+//
+// Colors are only written to terminals. The NO_COLOR environment variable
+// (https://no-color.org), when set to a non-empty value, disables them; the
+// FORCE_COLOR environment variable, when set to a value other than "",
+// "0" or "false", enables them even when the output is not a terminal, for
+// example in CI logs that render ANSI escapes. NO_COLOR takes precedence.
+type ColorTheme int
+
+const (
+	// ThemeNone writes monochrome output. It is the default.
+	ThemeNone ColorTheme = iota
+
+	// ThemeDark uses bright colors suited to dark terminal backgrounds.
+	ThemeDark
+
+	// ThemeLight uses deeper colors that remain readable on light
+	// terminal backgrounds.
+	ThemeLight
+
+	// ThemeHighContrast renders level names as bold text on a solid
+	// background, for low-vision users and projectors.
+	ThemeHighContrast
+)
+
+// String returns the name of the theme.
+func (t ColorTheme) String() string {
+	switch t {
+	case ThemeNone:
+		return "none"
+	case ThemeDark:
+		return "dark"
+	case ThemeLight:
+		return "light"
+	case ThemeHighContrast:
+		return "high-contrast"
+	default:
+		return "unknown"
+	}
+}
+
+// ansiReset ends a colored span.
+const ansiReset = "\x1b[0m"
+
+// ansiDim renders text faint, so that secondary details such as the caller
+// recede behind the message.
+const ansiDim = "\x1b[2m"
+
+// colorTheme returns the theme of console output: ColorTheme, or ThemeDark
+// when only EnableColor is set.
+func (c *Config) colorTheme() ColorTheme {
+	if c.ColorTheme == ThemeNone && c.EnableColor {
+		return ThemeDark
+	}
+	return c.ColorTheme
+}
+
+// themePalettes holds the ANSI escape sequence of each level in each theme.
+// Levels missing from a palette, such as zap's DPanic and Panic, use the
+// color of the nearest more severe level.
+var themePalettes = map[ColorTheme]map[zapcore.Level]string{
+	ThemeDark: {
+		zapcore.DebugLevel: "\x1b[35m",   // Magenta
+		zapcore.InfoLevel:  "\x1b[36m",   // Cyan
+		zapcore.WarnLevel:  "\x1b[33m",   // Yellow
+		zapcore.ErrorLevel: "\x1b[31m",   // Red
+		zapcore.FatalLevel: "\x1b[1;31m", // Bold red
+	},
+	ThemeLight: {
+		zapcore.DebugLevel: "\x1b[34m",   // Blue
+		zapcore.InfoLevel:  "\x1b[32m",   // Green
+		zapcore.WarnLevel:  "\x1b[35m",   // Magenta, as yellow fades on white
+		zapcore.ErrorLevel: "\x1b[31m",   // Red
+		zapcore.FatalLevel: "\x1b[1;31m", // Bold red
+	},
+	ThemeHighContrast: {
+		zapcore.DebugLevel: "\x1b[1;97;44m", // White on blue
+		zapcore.InfoLevel:  "\x1b[1;30;42m", // Black on green
+		zapcore.WarnLevel:  "\x1b[1;30;43m", // Black on yellow
+		zapcore.ErrorLevel: "\x1b[1;97;41m", // White on red
+		zapcore.FatalLevel: "\x1b[1;97;45m", // White on magenta
+	},
+}
+
+// levelColor returns the escape sequence of level in palette.
+func levelColor(palette map[zapcore.Level]string, level zapcore.Level) string {
+	for l := level; l <= zapcore.FatalLevel; l++ {
+		if color, ok := palette[l]; ok {
+			return color
+		}
+	}
+	return ""
+}
+
+// themeLevelEncoder returns a level encoder writing capitalized level
+// names in the colors of theme.
+func themeLevelEncoder(theme ColorTheme) zapcore.LevelEncoder {
+	palette := themePalettes[theme]
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		color := levelColor(palette, level)
+		if color == "" {
+			enc.AppendString(level.CapitalString())
+			return
+		}
+		enc.AppendString(color + level.CapitalString() + ansiReset)
+	}
+}
+
+// dimCallerEncoder wraps a caller encoder to write the caller dimmed.
+func dimCallerEncoder(encode zapcore.CallerEncoder) zapcore.CallerEncoder {
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		encode(caller, &dimEncoder{PrimitiveArrayEncoder: enc})
+	}
+}
+
+// dimEncoder writes the strings appended to it dimmed.
+type dimEncoder struct {
+	zapcore.PrimitiveArrayEncoder
+}
+
+// AppendString appends s dimmed.
+func (e *dimEncoder) AppendString(s string) {
+	e.PrimitiveArrayEncoder.AppendString(ansiDim + s + ansiReset)
+}
+
+// useColor reports whether console output in theme written to w should be
+// colored, following NO_COLOR, FORCE_COLOR and whether w is a terminal.
+func useColor(theme ColorTheme, w io.Writer) bool {
+	if _, ok := themePalettes[theme]; !ok {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("FORCE_COLOR")) {
+	case "", "0", "false":
+	default:
+		return true
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device, such as a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,271 @@
+package logx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ComponentConfig is attached to a node of the dotted logger name hierarchy
+// built by Component and Named, such as "http" or "http.client". It applies
+// to the entries of loggers with that name and of their descendants, unless
+// a descendant overrides it.
+type ComponentConfig struct {
+	// Level, if set, is the minimum level of the component, replacing the
+	// logger's level and any Level inherited from an ancestor. It can be
+	// below the logger's level to debug a single component. Trace entries
+	// are treated as Debug, as in the outputs. Sinks with their own Level
+	// ignore it.
+	// Default: nil (inherited)
+	Level *Level
+
+	// Sampler, if set, runs on the entries of the component before
+	// Config.Processors, typically an AdaptiveSampler or RateLimiter. A
+	// Sampler set on a descendant replaces it. The caller is responsible
+	// for closing it.
+	// Default: nil (inherited)
+	Sampler Processor
+
+	// SensitiveKeys are masked in the entries of the component, in addition
+	// to the process-wide sensitive keys and to the keys of its ancestors.
+	// Default: nil
+	SensitiveKeys []string
+}
+
+// componentState is the configuration in effect for a configured node,
+// resolved from the node and its ancestors.
+type componentState struct {
+	level    Level
+	hasLevel bool
+	sampler  Processor
+	masked   map[string]bool // Lowercase keys masked in addition to the sensitive keys
+}
+
+// componentRegistry holds the component configurations of a logger and of
+// every logger sharing its state.
+type componentRegistry struct {
+	mu      sync.Mutex // Serializes configuration changes
+	configs map[string]ComponentConfig
+	states  atomic.Pointer[map[string]*componentState] // Immutable snapshot resolved from configs
+	global  *atomic.Int32                              // The logger's level, see loggerShared.logLevel
+	floor   zap.AtomicLevel                            // Lowest level of the logger and of every component
+	emit    func(*Entry)                               // Bound to emitting samplers
+	loggers sync.Map                                   // Loggers returned by the package-level Named, keyed by name
+}
+
+// newComponentRegistry returns an empty registry lowering floor for its
+// components. New sets global and emit once the logger exists.
+func newComponentRegistry(floor zap.AtomicLevel) *componentRegistry {
+	return &componentRegistry{configs: make(map[string]ComponentConfig), floor: floor}
+}
+
+// lookup returns the state of the nearest configured node at or above name,
+// or nil if there is none.
+func (r *componentRegistry) lookup(name string) *componentState {
+	states := r.states.Load()
+	if states == nil || len(*states) == 0 {
+		return nil
+	}
+	for {
+		if s, ok := (*states)[name]; ok {
+			return s
+		}
+		if name == "" {
+			return nil
+		}
+		name = parentComponent(name)
+	}
+}
+
+// parentComponent returns the name of the parent of a non-root component.
+func parentComponent(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// enabled reports whether the component name admits entries at level. The
+// outputs only enforce the floor, so names without a Level are checked
+// against the logger's level here.
+func (r *componentRegistry) enabled(name string, level zapcore.Level) bool {
+	states := r.states.Load()
+	if states == nil || len(*states) == 0 {
+		// The floor is the logger's level and the outputs enforce it.
+		return true
+	}
+	if s := r.lookup(name); s != nil && s.hasLevel {
+		return level >= toZapLevel(s.level)
+	}
+	return level >= toZapLevel(Level(r.global.Load()))
+}
+
+// update applies change to the configurations under the registry's lock
+// and resolves the states of every configured node again.
+func (r *componentRegistry) update(change func(configs map[string]ComponentConfig)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change(r.configs)
+
+	states := make(map[string]*componentState, len(r.configs))
+	for node := range r.configs {
+		s := &componentState{}
+		// Walk from the node up to the root; the nearest Level and Sampler
+		// win, masked keys accumulate.
+		for n := node; ; n = parentComponent(n) {
+			if c, ok := r.configs[n]; ok {
+				if c.Level != nil && !s.hasLevel {
+					s.level, s.hasLevel = *c.Level, true
+				}
+				if c.Sampler != nil && s.sampler == nil {
+					s.sampler = c.Sampler
+				}
+				for _, key := range c.SensitiveKeys {
+					if s.masked == nil {
+						s.masked = make(map[string]bool)
+					}
+					s.masked[strings.ToLower(key)] = true
+				}
+			}
+			if n == "" {
+				break
+			}
+		}
+		states[node] = s
+	}
+	r.states.Store(&states)
+	r.applyLevel()
+}
+
+// setLevels replaces the Level of every component with levels, keeping the
+// rest of their configuration.
+func (r *componentRegistry) setLevels(levels map[string]Level) {
+	r.update(func(configs map[string]ComponentConfig) {
+		for name, c := range configs {
+			if _, ok := levels[name]; ok || c.Level == nil {
+				continue
+			}
+			c.Level = nil
+			if c.Sampler == nil && len(c.SensitiveKeys) == 0 {
+				delete(configs, name)
+			} else {
+				configs[name] = c
+			}
+		}
+		for name, level := range levels {
+			c := configs[name]
+			c.Level = &level
+			configs[name] = c
+		}
+	})
+}
+
+// applyLevel lowers the floor enforced by the outputs to the lowest level
+// of the logger and of every component, so that components configured
+// below the logger's level reach componentCore.
+func (r *componentRegistry) applyLevel() {
+	floor := toZapLevel(Level(r.global.Load()))
+	if states := r.states.Load(); states != nil {
+		for _, s := range *states {
+			if s.hasLevel && toZapLevel(s.level) < floor {
+				floor = toZapLevel(s.level)
+			}
+		}
+	}
+	r.floor.SetLevel(floor)
+}
+
+// componentCore enforces component levels on a core that follows the
+// logger's level. It relies on zap setting Entry.LoggerName to the dotted
+// logger name.
+type componentCore struct {
+	zapcore.Core
+	components *componentRegistry
+}
+
+// newComponentCore wraps core with the component levels of components.
+func newComponentCore(core zapcore.Core, components *componentRegistry) zapcore.Core {
+	return &componentCore{Core: core, components: components}
+}
+
+// With adds fields to the wrapped core.
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: c.Core.With(fields), components: c.components}
+}
+
+// Check adds the wrapped core if the entry's component admits its level.
+func (c *componentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.components.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Component creates a child logger for a component of the application, as
+// Named does, and records the full dotted name in the "component" field.
+// Chained calls build a hierarchy: the levels, samplers and masking set
+// with ConfigureComponent on a node apply to all its descendants.
+//
+// Example:
+//
+//	client := logger.Component("http").Component("client")
+//	client.Info("Request sent") // logger and component: "http.client"
+func (l *Logger) Component(name string) *Logger {
+	child := l.Named(name)
+	child.fields = setField(child.fields, Component(child.name))
+	return child
+}
+
+// ConfigureComponent attaches config to the component with the given full
+// dotted name, replacing its previous configuration. It applies at once to
+// every logger sharing the logger's state whose name is name or starts with
+// name followed by a dot, including loggers created before the call, and
+// to entries emitted by processors on their behalf. The empty name
+// configures the root of the hierarchy.
+//
+// Example:
+//
+//	debug := logx.DebugLevel
+//	logger.ConfigureComponent("http", logx.ComponentConfig{
+//	    Level:         &debug,
+//	    SensitiveKeys: []string{"cookie"},
+//	})
+//	logger.Component("http").Component("client").Debug("Dialing") // written
+func (l *Logger) ConfigureComponent(name string, config ComponentConfig) {
+	components := l.shared.components
+	if ep, ok := config.Sampler.(EmittingProcessor); ok {
+		ep.Bind(components.emit)
+	}
+	components.update(func(configs map[string]ComponentConfig) { configs[name] = config })
+}
+
+// ResetComponent removes the configuration attached to the component with
+// the given full dotted name, which then inherits from its ancestors again.
+func (l *Logger) ResetComponent(name string) {
+	l.shared.components.update(func(configs map[string]ComponentConfig) { delete(configs, name) })
+}
+
+// ComponentLevel returns the minimum level in effect for the component
+// with the given full dotted name: its own Level, the nearest Level of an
+// ancestor, or the logger's level.
+func (l *Logger) ComponentLevel(name string) Level {
+	if s := l.shared.components.lookup(name); s != nil && s.hasLevel {
+		return s.level
+	}
+	return l.GetLevel()
+}
+
+// ConfigureComponent attaches config to a component of the default logger;
+// see Logger.ConfigureComponent. It returns an error if the default logger
+// is not initialized.
+func ConfigureComponent(name string, config ComponentConfig) error {
+	if defaultLogger == nil {
+		return fmt.Errorf("default logger is not initialized")
+	}
+	defaultLogger.ConfigureComponent(name, config)
+	return nil
+}
@@ -0,0 +1,199 @@
+package logx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the layout of a configuration file read by LoadConfig.
+type configFile struct {
+	Level            Level                  `yaml:"level"`
+	NamedLevels      map[string]Level       `yaml:"named_levels"`
+	DetectLevel      bool                   `yaml:"detect_level"`
+	Verbosity        int                    `yaml:"verbosity"`
+	LevelSchedule    []LevelWindow          `yaml:"level_schedule"`
+	OutputPath       string                 `yaml:"output_path"`
+	Development      bool                   `yaml:"development"`
+	DevDual          bool                   `yaml:"dev_dual"`
+	EnableColor      bool                   `yaml:"enable_color"`
+	SplitStdStreams  bool                   `yaml:"split_std_streams"`
+	AddCaller        bool                   `yaml:"add_caller"`
+	AddStacktrace    bool                   `yaml:"add_stacktrace"`
+	SanitizeStrings  bool                   `yaml:"sanitize_strings"`
+	EventID          bool                   `yaml:"event_id"`
+	TimeZone         string                 `yaml:"time_zone"`
+	TimeLayout       string                 `yaml:"time_layout"`
+	Keys             EncoderKeys            `yaml:"keys"`
+	StreamLabels     []string               `yaml:"stream_labels"`
+	StrictNDJSON     bool                   `yaml:"strict_ndjson"`
+	NonFinite        NonFinitePolicy        `yaml:"non_finite"`
+	ErrorFingerprint bool                   `yaml:"error_fingerprint"`
+	CallerComponent  *CallerComponentConfig `yaml:"caller_component"`
+	StrictOrdering   bool                   `yaml:"strict_ordering"`
+	IndexInterval    int                    `yaml:"index_interval"`
+	Rotation         *rotationFile          `yaml:"rotation"`
+	Sinks            []sinkFile             `yaml:"sinks"`
+	Masking          maskingFile            `yaml:"masking"`
+	Processors       []ProcessorSpec        `yaml:"processors"`
+}
+
+// rotationFile is the "rotation" section of a configuration file.
+type rotationFile struct {
+	MaxSizeMB  int           `yaml:"max_size_mb"`
+	MaxBackups int           `yaml:"max_backups"`
+	MaxAgeDays int           `yaml:"max_age_days"`
+	Compress   bool          `yaml:"compress"`
+	Interval   string        `yaml:"interval"` // "", "hourly" or "daily"
+	Retention  time.Duration `yaml:"retention"`
+	Service    string        `yaml:"service"`
+}
+
+// sinkFile is an entry of the "sinks" list of a configuration file.
+type sinkFile struct {
+	Name        string `yaml:"name"`
+	OutputPath  string `yaml:"output_path"`
+	Development bool   `yaml:"development"`
+	Level       *Level `yaml:"level"`
+}
+
+// maskingFile is the "masking" section of a configuration file.
+type maskingFile struct {
+	SensitiveKeys []string `yaml:"sensitive_keys"`
+	UnmaskedKeys  []string `yaml:"unmasked_keys"`
+}
+
+// LoadConfig reads a logger configuration from a YAML (or JSON) file.
+// Settings missing from the file keep their DefaultConfig values, levels
+// are given by name, and unknown keys are rejected so that typos do not go
+// unnoticed.
+//
+// The masking section adds keys to and removes keys from the process-wide
+// list of sensitive keys, as AddSensitiveKey and RemoveSensitiveKey do, when
+// the file is loaded. Processors are built as by BuildPipeline; close them
+// with Pipeline(config.Processors).Close() when the logger is no longer
+// used.
+//
+// Example file:
+//
+//	level: debug
+//	named_levels:
+//	  http: debug
+//	  db: warn
+//	output_path: /var/log/app/app.log
+//	time_zone: UTC
+//	rotation:
+//	  max_size_mb: 100
+//	  max_backups: 10
+//	sinks:
+//	  - name: audit
+//	    output_path: /var/log/app/audit.log
+//	    level: info
+//	masking:
+//	  sensitive_keys: [session_id]
+//	  unmasked_keys: [email]
+//	processors:
+//	  - type: route
+//	    routes:
+//	      - fields: {category: audit}
+//	        sinks: [audit]
+//
+// Example:
+//
+//	config, err := logx.LoadConfig("/etc/app/logging.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	logger, err := logx.New(config)
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logger config: %w", err)
+	}
+	defaults := DefaultConfig()
+	file := configFile{
+		Level:         defaults.Level,
+		AddCaller:     defaults.AddCaller,
+		AddStacktrace: defaults.AddStacktrace,
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse logger config: %w", err)
+	}
+
+	config := defaults
+	config.Level = file.Level
+	config.NamedLevels = file.NamedLevels
+	config.DetectLevel = file.DetectLevel
+	config.Verbosity = file.Verbosity
+	config.LevelSchedule = file.LevelSchedule
+	config.OutputPath = file.OutputPath
+	config.Development = file.Development
+	config.DevDual = file.DevDual
+	config.EnableColor = file.EnableColor
+	config.SplitStdStreams = file.SplitStdStreams
+	config.AddCaller = file.AddCaller
+	config.AddStacktrace = file.AddStacktrace
+	config.SanitizeStrings = file.SanitizeStrings
+	config.EventID = file.EventID
+	config.TimeZone = file.TimeZone
+	config.TimeLayout = file.TimeLayout
+	config.Keys = file.Keys
+	config.StreamLabels = file.StreamLabels
+	config.StrictNDJSON = file.StrictNDJSON
+	config.NonFinite = file.NonFinite
+	config.ErrorFingerprint = file.ErrorFingerprint
+	config.CallerComponent = file.CallerComponent
+	config.StrictOrdering = file.StrictOrdering
+	config.IndexInterval = file.IndexInterval
+
+	if r := file.Rotation; r != nil {
+		rotation := &RotationConfig{
+			MaxSizeMB:  r.MaxSizeMB,
+			MaxBackups: r.MaxBackups,
+			MaxAgeDays: r.MaxAgeDays,
+			Compress:   r.Compress,
+			Retention:  r.Retention,
+			Service:    r.Service,
+		}
+		switch r.Interval {
+		case "":
+		case "hourly":
+			rotation.Interval = RotateHourly
+		case "daily":
+			rotation.Interval = RotateDaily
+		default:
+			return nil, fmt.Errorf("logger config: unknown rotation interval %q", r.Interval)
+		}
+		config.Rotation = rotation
+	}
+	for _, s := range file.Sinks {
+		config.Sinks = append(config.Sinks, SinkConfig{
+			Name:        s.Name,
+			OutputPath:  s.OutputPath,
+			Development: s.Development,
+			Level:       s.Level,
+		})
+	}
+	if len(file.Processors) > 0 {
+		pipeline, err := BuildPipeline(file.Processors)
+		if err != nil {
+			return nil, fmt.Errorf("logger config: %w", err)
+		}
+		config.Processors = pipeline
+	}
+
+	for _, key := range file.Masking.SensitiveKeys {
+		AddSensitiveKey(key)
+	}
+	for _, key := range file.Masking.UnmaskedKeys {
+		RemoveSensitiveKey(key)
+	}
+	return config, nil
+}
@@ -0,0 +1,77 @@
+//go:build js && wasm
+
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"syscall/js"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// browserConsole reports whether stdout is replaced by the browser console,
+// which shows JSON entries as structured objects.
+const browserConsole = true
+
+// newStdout returns the output of the default sink when it writes to
+// stdout: in the browser, the JavaScript console.
+func newStdout(encoderConfig zapcore.EncoderConfig) zapcore.WriteSyncer {
+	return &consoleWriter{
+		console:    js.Global().Get("console"),
+		levelKey:   encoderConfig.LevelKey,
+		messageKey: encoderConfig.MessageKey,
+	}
+}
+
+// consoleWriter writes JSON entries to the browser console with the
+// method matching their level, the message first and the entry as an
+// object the developer tools can expand.
+type consoleWriter struct {
+	console    js.Value
+	levelKey   string
+	messageKey string
+}
+
+func (w *consoleWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(line, &fields); err != nil {
+			w.console.Call("log", string(line))
+			continue
+		}
+		method := "log"
+		if name, ok := fields[w.levelKey].(string); ok {
+			if level, err := ParseLevel(name); err == nil {
+				method = consoleMethod(level)
+			}
+		}
+		message, _ := fields[w.messageKey].(string)
+		delete(fields, w.messageKey)
+		w.console.Call(method, message, js.ValueOf(fields))
+	}
+	return len(p), nil
+}
+
+// Sync does nothing, as the console is written synchronously.
+func (w *consoleWriter) Sync() error {
+	return nil
+}
+
+// consoleMethod returns the console method for level, so that the browser
+// filters and highlights entries by severity.
+func consoleMethod(level Level) string {
+	switch {
+	case level <= DebugLevel:
+		return "debug"
+	case level == InfoLevel:
+		return "log"
+	case level == WarnLevel:
+		return "warn"
+	}
+	return "error"
+}
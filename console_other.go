@@ -0,0 +1,18 @@
+//go:build !(js && wasm)
+
+package logx
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// browserConsole reports whether stdout is replaced by the browser console.
+const browserConsole = false
+
+// newStdout returns the output of the default sink when it writes to
+// stdout.
+func newStdout(zapcore.EncoderConfig) zapcore.WriteSyncer {
+	return zapcore.AddSync(os.Stdout)
+}
@@ -0,0 +1,44 @@
+package logx
+
+import (
+	"context"
+	"time"
+)
+
+// ContextKey is the field key used by CtxInfo.
+const ContextKey = "context"
+
+// CtxInfo creates a field describing the state of ctx, which makes
+// timeout-related errors much easier to diagnose. The field is an object
+// with the following sub-fields:
+//
+//	done:      whether the context was already done
+//	deadline:  the context deadline, if it has one
+//	remaining: the time left until the deadline, negative once it passed
+//	error:     the context error, if it is done
+//	cause:     the cancellation cause, if it differs from the error
+//
+// Example:
+//
+//	if err := db.QueryContext(ctx, query); err != nil {
+//	    logger.Error("Query failed", logx.ErrorField(err), logx.CtxInfo(ctx))
+//	}
+//	// "context":{"done":true,"deadline":"...","remaining":"-12ms","error":"context deadline exceeded"}
+func CtxInfo(ctx context.Context) Field {
+	info := map[string]interface{}{"done": false}
+	if ctx == nil {
+		return Field{Key: ContextKey, Value: info}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		info["deadline"] = deadline.Format(time.RFC3339Nano)
+		info["remaining"] = time.Until(deadline).String()
+	}
+	if err := ctx.Err(); err != nil {
+		info["done"] = true
+		info["error"] = err.Error()
+		if cause := context.Cause(ctx); cause != nil && cause != err {
+			info["cause"] = cause.Error()
+		}
+	}
+	return Field{Key: ContextKey, Value: info}
+}
@@ -0,0 +1,286 @@
+package logx
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Coverage records the log statements exercised while it is set as
+// Config.Coverage, typically during a test run, so that logging paths that
+// are dead or never tested can be found in large codebases. A statement is
+// exercised when it is called, even if its level is disabled.
+//
+// Example:
+//
+//	var logCoverage = logx.NewCoverage()
+//
+//	func TestMain(m *testing.M) {
+//	    config := logx.DefaultConfig()
+//	    config.Coverage = logCoverage
+//	    logx.Init(config)
+//	    code := m.Run()
+//	    if report, err := logCoverage.Report("."); err == nil {
+//	        report.WriteText(os.Stderr)
+//	    }
+//	    os.Exit(code)
+//	}
+type Coverage struct {
+	mu    sync.Mutex
+	sites map[coverageKey]*CoverageSite
+}
+
+// coverageKey identifies a call site.
+type coverageKey struct {
+	file string
+	line int
+}
+
+// CoverageSite is a log statement: a logging call site and its message
+// template.
+type CoverageSite struct {
+	File    string `json:"file"`    // Path of the source file
+	Line    int    `json:"line"`    // Line of the call
+	Level   Level  `json:"level"`   // Level of the statement
+	Message string `json:"message"` // Message, or format string of the f variants
+	Count   int    `json:"count"`   // Number of times the statement was exercised
+}
+
+// CoverageReport lists the log statements found in source code together
+// with those exercised at run time.
+type CoverageReport struct {
+	Statements []CoverageSite `json:"statements"` // All statements, sorted by file and line
+	Covered    int            `json:"covered"`    // Statements exercised at least once
+}
+
+// NewCoverage creates an empty coverage recorder.
+func NewCoverage() *Coverage {
+	return &Coverage{sites: make(map[coverageKey]*CoverageSite)}
+}
+
+// record counts the call site skip frames above record.
+func (c *Coverage) record(skip int, level Level, msg string) {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return
+	}
+	key := coverageKey{file: file, line: line}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	site, ok := c.sites[key]
+	if !ok {
+		site = &CoverageSite{File: file, Line: line, Level: level, Message: msg}
+		c.sites[key] = site
+	}
+	site.Count++
+}
+
+// Sites returns the statements exercised so far, sorted by file and line.
+func (c *Coverage) Sites() []CoverageSite {
+	c.mu.Lock()
+	sites := make([]CoverageSite, 0, len(c.sites))
+	for _, site := range c.sites {
+		sites = append(sites, *site)
+	}
+	c.mu.Unlock()
+	sortCoverageSites(sites)
+	return sites
+}
+
+// Report scans the Go source files under roots, excluding tests, vendor and
+// testdata directories, for log statements: calls of methods or functions
+// named Trace, Debug, Info, Warn, Error or Fatal, or their f variants, with
+// a string literal message, except those of the fmt, errors and log
+// packages. Files given as roots are always scanned. It
+// returns every statement found, with the number of times it was
+// exercised, plus exercised statements the scan did not find, such as
+// calls with computed messages.
+func (c *Coverage) Report(roots ...string) (*CoverageReport, error) {
+	exercised := make(map[coverageKey]CoverageSite)
+	for _, site := range c.Sites() {
+		exercised[coverageKey{file: site.File, line: site.Line}] = site
+	}
+
+	report := &CoverageReport{}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path != root && (!strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go")) {
+				return nil
+			}
+			statements, err := scanLogStatements(path)
+			if err != nil {
+				return err
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			for _, s := range statements {
+				site := s.site
+				for line := s.site.Line; line <= s.endLine; line++ {
+					key := coverageKey{file: abs, line: line}
+					if hit, ok := exercised[key]; ok {
+						site.Count += hit.Count
+						delete(exercised, key)
+					}
+				}
+				report.Statements = append(report.Statements, site)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan log statements: %w", err)
+		}
+	}
+	for _, site := range exercised {
+		report.Statements = append(report.Statements, site)
+	}
+	sortCoverageSites(report.Statements)
+	for _, site := range report.Statements {
+		if site.Count > 0 {
+			report.Covered++
+		}
+	}
+	return report, nil
+}
+
+// Uncovered returns the statements that were never exercised.
+func (r *CoverageReport) Uncovered() []CoverageSite {
+	var sites []CoverageSite
+	for _, site := range r.Statements {
+		if site.Count == 0 {
+			sites = append(sites, site)
+		}
+	}
+	return sites
+}
+
+// WriteText writes a summary line followed by the statements that were
+// never exercised:
+//
+//	log statement coverage: 41/57 (71.9%)
+//	never exercised:
+//	  internal/billing/charge.go:88 ERROR "Refund failed"
+func (r *CoverageReport) WriteText(w io.Writer) error {
+	percent := 100.0
+	if len(r.Statements) > 0 {
+		percent = float64(r.Covered) * 100 / float64(len(r.Statements))
+	}
+	if _, err := fmt.Fprintf(w, "log statement coverage: %d/%d (%.1f%%)\n", r.Covered, len(r.Statements), percent); err != nil {
+		return err
+	}
+	uncovered := r.Uncovered()
+	if len(uncovered) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, "never exercised:\n"); err != nil {
+		return err
+	}
+	for _, site := range uncovered {
+		if _, err := fmt.Fprintf(w, "  %s:%d %s %q\n", site.File, site.Line, site.Level, site.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logStatement is a log statement found in source code, spanning lines
+// site.Line to endLine.
+type logStatement struct {
+	site    CoverageSite
+	endLine int
+}
+
+// statementLevels maps the names of logging calls to their levels.
+var statementLevels = map[string]Level{
+	"Trace": TraceLevel, "Debug": DebugLevel, "Info": InfoLevel,
+	"Warn": WarnLevel, "Error": ErrorLevel, "Fatal": FatalLevel,
+}
+
+// nonLogPackages are the standard packages whose functions share names
+// with logging calls, such as fmt.Errorf and log.Fatal.
+var nonLogPackages = map[string]bool{"fmt": true, "errors": true, "log": true}
+
+// scanLogStatements parses a Go source file and returns its log statements.
+func scanLogStatements(path string) ([]logStatement, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var statements []logStatement
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		var name string
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok && nonLogPackages[pkg.Name] {
+				return true
+			}
+			name = fun.Sel.Name
+		case *ast.Ident:
+			name = fun.Name
+		}
+		level, ok := statementLevels[strings.TrimSuffix(name, "f")]
+		if !ok {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		msg, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		statements = append(statements, logStatement{
+			site: CoverageSite{
+				File:    path,
+				Line:    fset.Position(call.Pos()).Line,
+				Level:   level,
+				Message: msg,
+			},
+			endLine: fset.Position(call.End()).Line,
+		})
+		return true
+	})
+	return statements, nil
+}
+
+// sortCoverageSites sorts sites by file and line.
+func sortCoverageSites(sites []CoverageSite) {
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].File != sites[j].File {
+			return sites[i].File < sites[j].File
+		}
+		return sites[i].Line < sites[j].Line
+	})
+}
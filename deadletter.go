@@ -0,0 +1,97 @@
+package logx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// deadLetters appends the entries a sink failed to deliver to a file, one
+// JSON line each.
+type deadLetters struct {
+	path string
+	mu   sync.Mutex
+}
+
+// newDeadLetters returns the dead-letter file at path, or nil if path is
+// empty.
+func newDeadLetters(path string) *deadLetters {
+	if path == "" {
+		return nil
+	}
+	return &deadLetters{path: path}
+}
+
+// append writes the lines to the file. The file is opened for every
+// append, so that it can be replayed, moved or removed at any time.
+func (d *deadLetters) append(lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(bytes.TrimSuffix(line, []byte("\n")))
+		buf.WriteByte('\n')
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	file, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write dead-letter file: %w", err)
+	}
+	return file.Close()
+}
+
+// ReplayDeadLetters writes the entries of the dead-letter file at path to
+// w, one line per Write, typically the sink that failed to deliver them
+// once its endpoint is back, and returns the number of entries replayed.
+// The file is moved aside to path + ".replay" first, so that entries
+// failing again are appended to a new dead-letter file by the sink. If w
+// fails, the entries not replayed are kept in the ".replay" file and
+// replayed first by the next call. A missing file replays nothing.
+//
+// Example:
+//
+//	n, err := logx.ReplayDeadLetters("/var/log/app/webhook.dead", webhook)
+//	if err != nil {
+//	    log.Printf("replayed %d entries: %v", n, err)
+//	}
+func ReplayDeadLetters(path string, w io.Writer) (int, error) {
+	replay := path + ".replay"
+	if _, err := os.Stat(replay); errors.Is(err, fs.ErrNotExist) {
+		if err := os.Rename(path, replay); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to move dead-letter file: %w", err)
+		}
+	}
+	data, err := os.ReadFile(replay)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read dead-letter file: %w", err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	replayed := 0
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if _, err := w.Write(line); err != nil {
+			// Keep the entries not replayed for the next call
+			if werr := os.WriteFile(replay, bytes.Join(lines[i:], nil), 0644); werr != nil {
+				return replayed, errors.Join(fmt.Errorf("failed to replay dead letters: %w", err), werr)
+			}
+			return replayed, fmt.Errorf("failed to replay dead letters: %w", err)
+		}
+		replayed++
+	}
+	if err := os.Remove(replay); err != nil {
+		return replayed, fmt.Errorf("failed to remove replayed dead letters: %w", err)
+	}
+	return replayed, nil
+}
@@ -0,0 +1,181 @@
+package logx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDedupKeys bounds the number of distinct entries a Deduplicator tracks at
+// once. Entries beyond the limit are passed through unmodified.
+const maxDedupKeys = 10000
+
+// dedupState tracks one distinct entry within the current window.
+type dedupState struct {
+	first    time.Time // Time of the entry that opened the window
+	repeated int       // Number of duplicates suppressed in the window
+	entry    Entry     // Copy of the first entry, used for the summary
+}
+
+// Deduplicator is a Processor that collapses identical entries logged within
+// a time window. The first entry of a window is written immediately, later
+// identical entries are suppressed, and when the window closes a single copy
+// of the entry is written with a "repeat_count" field holding the number of
+// suppressed duplicates. As the summary is itself one of the duplicates, it
+// also carries the sampling metadata (SampledKey, SuppressedCountKey and
+// SampleWindowKey) with a suppressed count of one less.
+//
+// Entries are identical when they share level, message, logger name and the
+// values of the configured key fields. Fatal entries are never suppressed.
+//
+// Call Close when the deduplicator is no longer needed to stop its background
+// goroutine and flush pending summaries.
+type Deduplicator struct {
+	window time.Duration
+	keys   []string
+
+	mu     sync.Mutex
+	states map[string]*dedupState
+	emit   func(e *Entry)
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewDeduplicator creates a Deduplicator that collapses identical entries
+// within the given window. The keys name the fields that, together with the
+// message, identify an entry; fields not listed are ignored when comparing.
+//
+// Example:
+//
+//	dedup := logx.NewDeduplicator(10*time.Second, "host", "attempt_target")
+//	defer dedup.Close()
+//	config := logx.DefaultConfig()
+//	config.Processors = []logx.Processor{dedup}
+func NewDeduplicator(window time.Duration, keys ...string) *Deduplicator {
+	if window <= 0 {
+		window = time.Second
+	}
+	d := &Deduplicator{
+		window: window,
+		keys:   keys,
+		states: make(map[string]*dedupState),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Bind sets the function used to write repeat summaries.
+func (d *Deduplicator) Bind(emit func(e *Entry)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.emit = emit
+}
+
+// key builds the identity of an entry from its level, logger name, message
+// and configured key fields.
+func (d *Deduplicator) key(e *Entry) string {
+	var b strings.Builder
+	b.WriteString(e.Level.String())
+	b.WriteByte(0)
+	b.WriteString(e.LoggerName)
+	b.WriteByte(0)
+	b.WriteString(e.Message)
+	for _, key := range d.keys {
+		b.WriteByte(0)
+		for _, field := range e.Fields {
+			if field.Key == key {
+				fmt.Fprint(&b, field.Value)
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// Process suppresses the entry if an identical one was seen within the window.
+func (d *Deduplicator) Process(e *Entry) bool {
+	if e.Level >= FatalLevel {
+		return true
+	}
+	key := d.key(e)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.states[key]
+	if ok && e.Time.Sub(state.first) < d.window {
+		state.repeated++
+		return false
+	}
+	if ok {
+		d.flushLocked(state)
+	} else if len(d.states) >= maxDedupKeys {
+		return true
+	}
+
+	d.states[key] = &dedupState{
+		first: e.Time,
+		entry: Entry{
+			Time:       e.Time,
+			Level:      e.Level,
+			LoggerName: e.LoggerName,
+			Message:    e.Message,
+			Fields:     append([]Field(nil), e.Fields...),
+		},
+	}
+	return true
+}
+
+// flushLocked emits a repeat summary for the state if any duplicates were
+// suppressed. d.mu must be held.
+func (d *Deduplicator) flushLocked(state *dedupState) {
+	if state.repeated == 0 || d.emit == nil {
+		return
+	}
+	summary := state.entry
+	summary.Time = time.Now()
+	summary.Fields = append(append([]Field(nil), state.entry.Fields...), Int("repeat_count", state.repeated))
+	summary.Fields = append(summary.Fields, samplingFields(state.repeated-1, d.window)...)
+	d.emit(&summary)
+}
+
+// sweep flushes and forgets every state whose window has closed. If all is
+// true, every state is flushed regardless of its age.
+func (d *Deduplicator) sweep(now time.Time, all bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, state := range d.states {
+		if all || now.Sub(state.first) >= d.window {
+			d.flushLocked(state)
+			delete(d.states, key)
+		}
+	}
+}
+
+// run periodically sweeps closed windows until Close is called.
+func (d *Deduplicator) run() {
+	defer close(d.done)
+	ticker := time.NewTicker(d.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			d.sweep(now, false)
+		case <-d.stop:
+			d.sweep(time.Now(), true)
+			return
+		}
+	}
+}
+
+// Close stops the background sweeper and writes summaries for all pending
+// windows. It is safe to call Close more than once.
+func (d *Deduplicator) Close() error {
+	d.once.Do(func() { close(d.stop) })
+	<-d.done
+	return nil
+}
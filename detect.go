@@ -0,0 +1,126 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DetectLevel returns the level requested by the command line or the
+// environment, following common platform conventions, and whether one was
+// requested. The sources are checked in order:
+//
+//  1. a --log-level flag (also -log-level, with "=" or a separate value);
+//  2. a --debug flag, for DebugLevel;
+//  3. the LOG_LEVEL variable;
+//  4. the AWS_LAMBDA_LOG_LEVEL variable set by AWS Lambda;
+//  5. the DEBUG variable, for DebugLevel, unless it is a false boolean
+//     such as "0" or "false".
+//
+// Level names are parsed by ParseLevel; invalid names are skipped. Flags
+// after a "--" argument are ignored. See also Config.DetectLevel.
+//
+// Example:
+//
+//	config := logx.DefaultConfig()
+//	if level, ok := logx.DetectLevel(); ok {
+//	    config.Level = level
+//	}
+func DetectLevel() (Level, bool) {
+	level, ok, _ := detectLevel(os.Args[1:], os.Getenv)
+	return level, ok
+}
+
+// detectLevel implements DetectLevel for the given arguments and
+// environment. The returned error describes the invalid names skipped.
+func detectLevel(args []string, getenv func(string) string) (Level, bool, error) {
+	type source struct {
+		name  string
+		value string
+	}
+	flag, debugFlag := levelFlags(args)
+	sources := []source{{"--log-level flag", flag}}
+	if debugFlag {
+		sources = append(sources, source{"--debug flag", "debug"})
+	}
+	sources = append(sources,
+		source{"LOG_LEVEL", getenv("LOG_LEVEL")},
+		source{"AWS_LAMBDA_LOG_LEVEL", getenv("AWS_LAMBDA_LOG_LEVEL")},
+	)
+	if debug := getenv("DEBUG"); debug != "" {
+		// Values such as "*" or "app:*" enable debugging too.
+		if enabled, err := strconv.ParseBool(debug); err != nil || enabled {
+			sources = append(sources, source{"DEBUG", "debug"})
+		}
+	}
+
+	var errs []error
+	for _, s := range sources {
+		if s.value == "" {
+			continue
+		}
+		level, err := ParseLevel(s.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		return level, true, errors.Join(errs...)
+	}
+	return InfoLevel, false, errors.Join(errs...)
+}
+
+// levelFlags returns the value of the last --log-level flag in args and
+// whether a --debug flag is present.
+func levelFlags(args []string) (level string, debug bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "log-level":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			level = value
+		case "debug":
+			if enabled, err := strconv.ParseBool(value); !hasValue || (err == nil && enabled) {
+				debug = true
+			}
+		}
+	}
+	return level, debug
+}
+
+// configLevel returns the level to apply for config: the detected level
+// if Config.DetectLevel is set and a level was requested, Level otherwise.
+// The error describes invalid level names that were skipped.
+func configLevel(config *Config) (Level, error) {
+	if !config.DetectLevel {
+		return config.Level, nil
+	}
+	level, ok, err := detectLevel(os.Args[1:], os.Getenv)
+	if !ok {
+		level = config.Level
+	}
+	return level, err
+}
+
+// warnInvalidLevel writes a Warn entry for the invalid level names skipped
+// by Config.DetectLevel.
+func (l *Logger) warnInvalidLevel(err error) {
+	l.emit(&Entry{
+		Time:    time.Now(),
+		Level:   WarnLevel,
+		Message: "Ignored invalid log level",
+		Fields:  []Field{ErrorField(err), String("applied_level", l.GetLevel().String())},
+	})
+}
@@ -0,0 +1,196 @@
+package logx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix starts every value written by Config.Encryption.
+const EncryptedPrefix = "enc:v1:"
+
+// EncryptionConfig enables field-level encryption with Config.Encryption.
+// The values of the designated keys are replaced with ciphertext that only
+// the holder of the private key can recover, e.g. with DecryptValue during
+// incident response, while the rest of the aggregation pipeline sees no
+// readable value.
+//
+// Values are encoded as JSON and sealed with AES-256-GCM under a random key
+// per value, which is itself encrypted with RSA-OAEP (SHA-256) under
+// PublicKey. They are written as strings of the form
+// "enc:v1:<key id>:<base64>", or "enc:v1:<base64>" without a KeyID.
+type EncryptionConfig struct {
+	// PublicKey encrypts the values. Required; see ParsePublicKeyPEM.
+	PublicKey *rsa.PublicKey
+
+	// KeyID identifies PublicKey in the encrypted values so that the
+	// matching private key can be found after keys are rotated.
+	// Default: "" (not written)
+	KeyID string
+
+	// Keys are the field keys, matched case-insensitively, whose values
+	// are encrypted. Encryption takes precedence over masking.
+	Keys []string
+}
+
+// fieldEncryption encrypts the values of the keys of an EncryptionConfig.
+type fieldEncryption struct {
+	publicKey *rsa.PublicKey
+	prefix    string
+	keys      map[string]bool
+}
+
+// newFieldEncryption validates config, returning nil if it is nil.
+func newFieldEncryption(config *EncryptionConfig) (*fieldEncryption, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.PublicKey == nil {
+		return nil, fmt.Errorf("Encryption requires a PublicKey")
+	}
+	if strings.Contains(config.KeyID, ":") {
+		return nil, fmt.Errorf("Encryption KeyID %q must not contain ':'", config.KeyID)
+	}
+	e := &fieldEncryption{
+		publicKey: config.PublicKey,
+		prefix:    EncryptedPrefix,
+		keys:      make(map[string]bool, len(config.Keys)),
+	}
+	if config.KeyID != "" {
+		e.prefix += config.KeyID + ":"
+	}
+	for _, key := range config.Keys {
+		e.keys[strings.ToLower(key)] = true
+	}
+	return e, nil
+}
+
+// encrypts reports whether the values of key are encrypted.
+func (e *fieldEncryption) encrypts(key string) bool {
+	return e != nil && e.keys[strings.ToLower(key)]
+}
+
+// encrypt returns the encrypted form of value. If the value cannot be
+// encrypted, it is masked instead so that it never leaks.
+func (e *fieldEncryption) encrypt(value interface{}) interface{} {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return maskValue(value)
+	}
+	sealed, err := seal(e.publicKey, plaintext)
+	if err != nil {
+		return maskValue(value)
+	}
+	return e.prefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// seal encrypts plaintext with a random AES-256-GCM key and returns the
+// RSA-OAEP encrypted key, the nonce and the ciphertext.
+func seal(publicKey *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append(wrapped, nonce...)
+	return gcm.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+// newGCM returns an AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DecryptValue recovers a value written by Config.Encryption with the
+// private key matching its public key. It returns the JSON encoding of the
+// original value and the key ID, if one was written.
+//
+// Example:
+//
+//	plaintext, keyID, err := logx.DecryptValue(entry["email"].(string), privateKey)
+//	if err == nil {
+//	    fmt.Printf("%s (key %s)\n", plaintext, keyID) // "user@example.com" (key 2024-q3)
+//	}
+func DecryptValue(value string, privateKey *rsa.PrivateKey) ([]byte, string, error) {
+	rest, ok := strings.CutPrefix(value, EncryptedPrefix)
+	if !ok {
+		return nil, "", errors.New("not an encrypted value")
+	}
+	var keyID string
+	if i := strings.LastIndexByte(rest, ':'); i >= 0 {
+		keyID, rest = rest[:i], rest[i+1:]
+	}
+	sealed, err := base64.StdEncoding.DecodeString(rest)
+	if err != nil {
+		return nil, keyID, fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	size := privateKey.Size()
+	if len(sealed) < size {
+		return nil, keyID, errors.New("encrypted value is too short")
+	}
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, privateKey, sealed[:size], nil)
+	if err != nil {
+		return nil, keyID, fmt.Errorf("failed to decrypt value key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, keyID, err
+	}
+	sealed = sealed[size:]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, keyID, errors.New("encrypted value is too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, keyID, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, keyID, nil
+}
+
+// ParsePublicKeyPEM parses a PEM encoded RSA public key, in PKIX ("PUBLIC
+// KEY") or PKCS #1 ("RSA PUBLIC KEY") form, for EncryptionConfig.
+func ParsePublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+	return publicKey, nil
+}
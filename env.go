@@ -0,0 +1,68 @@
+package logx
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// EnvKey is the field key used by Env.
+const EnvKey = "env"
+
+// Env creates a field with the values of the selected environment
+// variables, for a safe startup-time dump of the environment. Keys may be
+// exact names or path.Match patterns such as "APP_*". Unset variables are
+// omitted.
+//
+// Values are masked when the variable's name is a sensitive key or contains
+// one as an underscore-separated part, so DB_PASSWORD and
+// AWS_SECRET_ACCESS_KEY are masked while DB_HOST is not.
+//
+// Example:
+//
+//	logx.Info("Starting", logx.Env("APP_*", "DB_HOST", "DB_PASSWORD"))
+//	// {"message":"Starting","env":{"APP_MODE":"prod","DB_HOST":"db1","DB_PASSWORD":"s3***rd"}}
+func Env(keys ...string) Field {
+	values := make(map[string]string)
+	var environ []string
+	for _, key := range keys {
+		if !strings.ContainsAny(key, "*?[") {
+			if value, ok := os.LookupEnv(key); ok {
+				values[key] = maskEnvValue(key, value)
+			}
+			continue
+		}
+		if environ == nil {
+			environ = os.Environ()
+		}
+		for _, kv := range environ {
+			name, value, _ := strings.Cut(kv, "=")
+			if matched, _ := path.Match(key, name); matched {
+				values[name] = maskEnvValue(name, value)
+			}
+		}
+	}
+	return Field{Key: EnvKey, Value: values}
+}
+
+// maskEnvValue masks value if the variable name is sensitive.
+func maskEnvValue(name, value string) string {
+	if !isSensitiveEnvName(name) {
+		return value
+	}
+	return maskString(value)
+}
+
+// isSensitiveEnvName reports whether an environment variable name is a
+// sensitive key or has one as an underscore-separated part.
+func isSensitiveEnvName(name string) bool {
+	if isSensitiveKey(name) {
+		return true
+	}
+	for _, part := range strings.Split(name, "_") {
+		if part != "" && isSensitiveKey(part) {
+			return true
+		}
+	}
+	return false
+}
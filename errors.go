@@ -0,0 +1,89 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCausesSuffix is appended to the key of an error field to form the key
+// of the structured causes of a combined error; see errorCauses.
+const ErrorCausesSuffix = "_causes"
+
+// Limits on the causes written for a combined error, so that a pathological
+// error tree cannot blow up an entry.
+const (
+	maxErrorCauses     = 100 // Causes written per combined error
+	maxErrorCauseDepth = 8   // Nesting depth of combined errors
+)
+
+// errorCause describes one error combined into another by errors.Join,
+// go.uber.org/multierr or github.com/hashicorp/go-multierror. Causes that
+// combine errors themselves list them in Causes.
+type errorCause struct {
+	Type    string       `json:"type"`
+	Message string       `json:"message"`
+	Causes  []errorCause `json:"causes,omitempty"`
+}
+
+// combinedErrors returns the errors combined into err, following single
+// wrapping such as fmt.Errorf("...: %w", errors.Join(...)), or nil if err
+// does not combine several errors.
+func combinedErrors(err error) []error {
+	for err != nil {
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			return e.Unwrap()
+		case interface{ WrappedErrors() []error }: // hashicorp/go-multierror
+			return e.WrappedErrors()
+		case interface{ Errors() []error }: // go.uber.org/multierr
+			return e.Errors()
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// errorCauses returns the structured causes of value if it is an error
+// combining several errors, so that every cause can be counted by error
+// analytics instead of appearing only in one concatenated message. With
+// sanitize set, types and messages are sanitized like other strings.
+func errorCauses(value interface{}, sanitize bool) []errorCause {
+	err, ok := value.(error)
+	if !ok || err == nil {
+		return nil
+	}
+	return buildErrorCauses(combinedErrors(err), sanitize, 1)
+}
+
+// buildErrorCauses describes errs and, up to maxErrorCauseDepth, the errors
+// they combine.
+func buildErrorCauses(errs []error, sanitize bool, depth int) []errorCause {
+	var causes []errorCause
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if len(causes) == maxErrorCauses {
+			break
+		}
+		cause := errorCause{Type: fmt.Sprintf("%T", originalError(err)), Message: err.Error()}
+		if sanitize {
+			cause.Type, cause.Message = sanitizeString(cause.Type), sanitizeString(cause.Message)
+		}
+		if depth < maxErrorCauseDepth {
+			cause.Causes = buildErrorCauses(combinedErrors(err), sanitize, depth+1)
+		}
+		causes = append(causes, cause)
+	}
+	return causes
+}
+
+// originalError returns the error that err stands in for, such as the
+// original of an error rewritten by a Rewriter, so that causes are written
+// with their own type.
+func originalError(err error) error {
+	if e, ok := err.(interface{ original() error }); ok {
+		return e.original()
+	}
+	return err
+}
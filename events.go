@@ -0,0 +1,60 @@
+package logx
+
+// Standard event fields written by DeprecationWarn, FeatureFlag, the
+// startup banner and the RuntimeMonitor. The event field holds one of the
+// Event values, so that occurrences can be aggregated across services with
+// a single query.
+const (
+	EventKey            = "event"
+	EventDeprecation    = "deprecation"
+	EventFeatureFlag    = "feature_flag"
+	EventStartup        = "startup"
+	EventRuntimeGC      = "runtime_gc"
+	EventGoroutineSpike = "goroutine_spike"
+	EventMemoryLimit    = "memory_limit"
+)
+
+// DeprecationWarn writes a standardized Warn entry recording that a
+// deprecated feature was used, and when it will be removed (a version or a
+// date). Each feature is reported once per logger and the loggers derived
+// from it, so the call can sit on a hot path.
+//
+// Example:
+//
+//	func (c *Client) GetUserV1(id string) (*User, error) {
+//	    c.logger.DeprecationWarn("client.GetUserV1", "v3.0.0")
+//	    return c.GetUser(context.Background(), id)
+//	}
+//	// {"level":"WARN","message":"Deprecated feature used","event":"deprecation","feature":"client.GetUserV1","removal":"v3.0.0"}
+func (l *Logger) DeprecationWarn(feature, removal string) {
+	if _, seen := l.shared.reported.LoadOrStore(EventDeprecation+"\x00"+feature, struct{}{}); seen {
+		return
+	}
+	l.log(WarnLevel, "Deprecated feature used", []Field{
+		String(EventKey, EventDeprecation),
+		String("feature", feature),
+		String("removal", removal),
+	})
+}
+
+// FeatureFlag writes a standardized Info entry recording the state of a
+// feature flag. A flag is reported the first time it is seen and whenever
+// its state changes, per logger and the loggers derived from it, so
+// evaluations can be passed on every request.
+//
+// Example:
+//
+//	enabled := flags.Enabled("new-checkout")
+//	logger.FeatureFlag("new-checkout", enabled)
+//	// {"level":"INFO","message":"Feature flag evaluated","event":"feature_flag","flag":"new-checkout","enabled":true}
+func (l *Logger) FeatureFlag(name string, enabled bool) {
+	previous, seen := l.shared.reported.Swap(EventFeatureFlag+"\x00"+name, enabled)
+	if seen && previous.(bool) == enabled {
+		return
+	}
+	l.log(InfoLevel, "Feature flag evaluated", []Field{
+		String(EventKey, EventFeatureFlag),
+		String("flag", name),
+		Bool("enabled", enabled),
+	})
+}
@@ -9,4 +9,5 @@ require github.com/seasbee/go-logx v0.0.0-00010101000000-000000000000
 require (
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
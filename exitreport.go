@@ -0,0 +1,200 @@
+package logx
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExitReportMessage is the message of the entry written by Fatal before the
+// process exits; see Config.ExitReport.
+const ExitReportMessage = "Process exiting"
+
+// exitReportBuckets is the number of buckets the error window is split into.
+const exitReportBuckets = 60
+
+// ExitReportConfig configures the report written when Fatal exits the
+// process; see Config.ExitReport.
+type ExitReportConfig struct {
+	// SnapshotPath, if set, is the file the entries retained by
+	// Config.History are written to before exiting, as by WriteSnapshot.
+	// The report names it in its "snapshot_path" field. Ignored if the
+	// history is disabled.
+	// Default: "" (no snapshot)
+	SnapshotPath string
+
+	// ErrorWindow is the period over which "recent_error_count" counts
+	// Error and Fatal entries.
+	// Default: 5m
+	ErrorWindow time.Duration
+
+	// FlushTimeout bounds the time spent flushing the outputs and sinks
+	// before exiting, so that an unreachable endpoint cannot keep a failing
+	// process alive.
+	// Default: 5s
+	FlushTimeout time.Duration
+}
+
+// errorBucket counts the errors written during one slice of the window.
+type errorBucket struct {
+	slot  int64 // Index of the slice since the Unix epoch
+	count uint64
+}
+
+// exitReport accumulates what the exit report needs while the logger runs.
+type exitReport struct {
+	config  ExitReportConfig
+	started time.Time
+	errors  atomic.Uint64
+
+	mu      sync.Mutex
+	buckets [exitReportBuckets]errorBucket
+	writers []interface{ Dropped() uint64 } // Outputs counting the entries they dropped
+}
+
+// newExitReport creates the exit report for config, or returns nil if
+// disabled.
+func newExitReport(config *ExitReportConfig, outputs *Config) *exitReport {
+	if config == nil {
+		return nil
+	}
+	r := &exitReport{config: *config, started: time.Now()}
+	if r.config.ErrorWindow <= 0 {
+		r.config.ErrorWindow = 5 * time.Minute
+	}
+	if r.config.FlushTimeout <= 0 {
+		r.config.FlushTimeout = 5 * time.Second
+	}
+	r.setOutputs(outputs)
+	return r
+}
+
+// setOutputs records the outputs of config that count dropped entries,
+// such as a WebhookSink.
+func (r *exitReport) setOutputs(config *Config) {
+	var writers []interface{ Dropped() uint64 }
+	if w, ok := config.OutputWriter.(interface{ Dropped() uint64 }); ok {
+		writers = append(writers, w)
+	}
+	for _, sc := range config.Sinks {
+		if w, ok := sc.Writer.(interface{ Dropped() uint64 }); ok {
+			writers = append(writers, w)
+		}
+	}
+	r.mu.Lock()
+	r.writers = writers
+	r.mu.Unlock()
+}
+
+// recordError counts an Error or Fatal entry written at t.
+func (r *exitReport) recordError(t time.Time) {
+	r.errors.Add(1)
+	slot := t.UnixNano() / int64(r.bucketWidth())
+	b := &r.buckets[slot%exitReportBuckets]
+	r.mu.Lock()
+	if b.slot != slot {
+		*b = errorBucket{slot: slot}
+	}
+	b.count++
+	r.mu.Unlock()
+}
+
+// recentErrors returns the number of errors written during the window
+// ending at t.
+func (r *exitReport) recentErrors(t time.Time) uint64 {
+	current := t.UnixNano() / int64(r.bucketWidth())
+	var count uint64
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.buckets {
+		if b.slot > current-exitReportBuckets && b.slot <= current {
+			count += b.count
+		}
+	}
+	return count
+}
+
+// bucketWidth returns the duration of one slice of the window.
+func (r *exitReport) bucketWidth() time.Duration {
+	if width := r.config.ErrorWindow / exitReportBuckets; width > 0 {
+		return width
+	}
+	return 1
+}
+
+// dropped returns the entries dropped by the asynchronous queue and by the
+// outputs counting them.
+func (r *exitReport) dropped(async *asyncQueue) uint64 {
+	var total uint64
+	if async != nil {
+		total = async.dropped.Load()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, w := range r.writers {
+		total += w.Dropped()
+	}
+	return total
+}
+
+// flushForExit prepares the process to exit after a Fatal entry. With
+// Config.ExitReport set, it writes the history snapshot and the exit
+// report, then flushes the outputs for at most FlushTimeout; otherwise it
+// flushes them without a bound.
+func (l *Logger) flushForExit() {
+	r := l.shared.exitReport
+	if r == nil {
+		l.Sync()
+		return
+	}
+	now := time.Now()
+	fields := []Field{
+		Int64("uptime_ms", now.Sub(r.started).Milliseconds()),
+		Int64("error_count", int64(r.errors.Load())),
+		Int64("recent_error_count", int64(r.recentErrors(now))),
+		Int64("error_window_ms", r.config.ErrorWindow.Milliseconds()),
+		Int64("dropped_entries", int64(r.dropped(l.shared.async))),
+	}
+	if r.config.SnapshotPath != "" && l.shared.history != nil {
+		if err := l.writeExitSnapshot(r.config.SnapshotPath); err != nil {
+			fields = append(fields, String("snapshot_error", err.Error()))
+		} else {
+			fields = append(fields, String("snapshot_path", r.config.SnapshotPath))
+		}
+	}
+	l.emit(&Entry{
+		Time:       now,
+		Level:      FatalLevel,
+		Message:    ExitReportMessage,
+		Fields:     fields,
+		LoggerName: l.name,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		l.Sync()
+		close(done)
+	}()
+	timer := time.NewTimer(r.config.FlushTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		// Exit anyway; entries still in flight are lost.
+	}
+}
+
+// writeExitSnapshot writes every entry retained by the history to path.
+func (l *Logger) writeExitSnapshot(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	if err := l.WriteSnapshot(file, 0); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,143 @@
+package logx
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FailoverConfig configures a FailoverWriter.
+type FailoverConfig struct {
+	// Writers are tried in order: the primary output first, then each
+	// fallback, e.g. a network sink, a local file and os.Stderr.
+	Writers []io.Writer
+
+	// RetryInterval is how long a writer that failed is skipped before
+	// it is tried again, so that a dead primary does not slow every write
+	// down with its timeout. The last writer is never skipped.
+	// Default: 10s
+	RetryInterval time.Duration
+
+	// OnFailover is called when a write fails and is retried on the next
+	// writer. It must not log to the same output.
+	// Default: nil
+	OnFailover func(event FailoverEvent)
+}
+
+// FailoverEvent describes a failed write retried on the next writer.
+type FailoverEvent struct {
+	Writer int   // Index of the writer that failed in FailoverConfig.Writers
+	Err    error // Error returned by the writer
+}
+
+// FailoverStats counts the writes of a FailoverWriter, by writer.
+type FailoverStats struct {
+	Writes   []uint64 `json:"writes"`   // Successful writes
+	Failures []uint64 `json:"failures"` // Failed writes, each retried on the next writer if any
+}
+
+// FailoverWriter is an output writing each entry to the first of a chain
+// of writers that accepts it, so that entries survive the failure of the
+// primary output. A write fails over when the writer returns an error or
+// writes short; a failed writer is skipped for RetryInterval. Writers that
+// report delivery failures asynchronously, such as the API sinks, never
+// fail a write; use their spool for durability instead.
+type FailoverWriter struct {
+	config    FailoverConfig
+	writes    []atomic.Uint64
+	failures  []atomic.Uint64
+	mu        sync.Mutex
+	downUntil []time.Time // Time until which each writer is skipped
+}
+
+// NewFailoverWriter creates a FailoverWriter for the given chain. Use it
+// as Config.OutputWriter or as the Writer of a sink.
+//
+// Example:
+//
+//	file, _ := os.OpenFile("/var/log/app/fallback.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+//	output := logx.NewFailoverWriter(logx.FailoverConfig{
+//	    Writers: []io.Writer{syslogSink, file, os.Stderr},
+//	    OnFailover: func(event logx.FailoverEvent) {
+//	        failovers.WithLabelValues(strconv.Itoa(event.Writer)).Inc()
+//	    },
+//	})
+//	config.OutputWriter = output
+func NewFailoverWriter(config FailoverConfig) *FailoverWriter {
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 10 * time.Second
+	}
+	return &FailoverWriter{
+		config:    config,
+		writes:    make([]atomic.Uint64, len(config.Writers)),
+		failures:  make([]atomic.Uint64, len(config.Writers)),
+		downUntil: make([]time.Time, len(config.Writers)),
+	}
+}
+
+// Write writes p to the first writer that accepts it. It fails with the
+// errors of every writer tried if none does.
+func (w *FailoverWriter) Write(p []byte) (int, error) {
+	if len(w.config.Writers) == 0 {
+		return 0, errors.New("failover writer has no writers")
+	}
+	var errs []error
+	last := len(w.config.Writers) - 1
+	for i, writer := range w.config.Writers {
+		if i < last && w.skipped(i) {
+			continue
+		}
+		n, err := writer.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err == nil {
+			w.writes[i].Add(1)
+			return len(p), nil
+		}
+		w.failures[i].Add(1)
+		w.mu.Lock()
+		w.downUntil[i] = time.Now().Add(w.config.RetryInterval)
+		w.mu.Unlock()
+		errs = append(errs, err)
+		if i < last && w.config.OnFailover != nil {
+			w.config.OnFailover(FailoverEvent{Writer: i, Err: err})
+		}
+	}
+	return 0, errors.Join(errs...)
+}
+
+// skipped reports whether writer i failed less than RetryInterval ago.
+func (w *FailoverWriter) skipped(i int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return time.Now().Before(w.downUntil[i])
+}
+
+// Stats returns the writes and failures of each writer so far.
+func (w *FailoverWriter) Stats() FailoverStats {
+	stats := FailoverStats{
+		Writes:   make([]uint64, len(w.writes)),
+		Failures: make([]uint64, len(w.failures)),
+	}
+	for i := range w.writes {
+		stats.Writes[i] = w.writes[i].Load()
+		stats.Failures[i] = w.failures[i].Load()
+	}
+	return stats
+}
+
+// Sync syncs every writer implementing Sync() error.
+func (w *FailoverWriter) Sync() error {
+	var errs []error
+	for _, writer := range w.config.Writers {
+		if syncer, ok := writer.(interface{ Sync() error }); ok {
+			if err := syncer.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
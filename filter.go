@@ -0,0 +1,144 @@
+package logx
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// entryMatcher holds the compiled conditions shared by filter and routing
+// rules. Empty conditions match every entry.
+type entryMatcher struct {
+	levels   []Level
+	minLevel Level
+	logger   string
+	message  *regexp.Regexp
+	fields   map[string]string
+}
+
+// newEntryMatcher validates and compiles the given conditions.
+func newEntryMatcher(levels []Level, minLevel Level, logger, message string, fields map[string]string) (entryMatcher, error) {
+	m := entryMatcher{levels: levels, minLevel: minLevel, logger: logger, fields: fields}
+	if message != "" {
+		re, err := regexp.Compile(message)
+		if err != nil {
+			return m, fmt.Errorf("invalid message pattern: %w", err)
+		}
+		m.message = re
+	}
+	if logger != "" {
+		if _, err := path.Match(logger, ""); err != nil {
+			return m, fmt.Errorf("invalid logger pattern: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// matches reports whether the entry satisfies every condition.
+func (m *entryMatcher) matches(e *Entry) bool {
+	if e.Level < m.minLevel {
+		return false
+	}
+	if len(m.levels) > 0 {
+		found := false
+		for _, level := range m.levels {
+			if level == e.Level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if m.logger != "" {
+		if ok, _ := path.Match(m.logger, e.LoggerName); !ok {
+			return false
+		}
+	}
+	if m.message != nil && !m.message.MatchString(e.Message) {
+		return false
+	}
+	for key, want := range m.fields {
+		found := false
+		for _, field := range e.Fields {
+			if field.Key == key && fmt.Sprint(field.Value) == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// compiledRule is a FilterRule with its conditions compiled.
+type compiledRule struct {
+	entryMatcher
+	action      FilterAction
+	downgradeTo Level
+}
+
+// Filter is a Processor that drops or downgrades entries according to a list
+// of declarative rules. Rules are evaluated in order and the first matching
+// rule wins.
+type Filter struct {
+	rules []compiledRule
+}
+
+// NewFilter compiles the given rules into a Filter.
+// An error is returned if a message pattern, logger pattern or action is invalid.
+//
+// Example:
+//
+//	filter, err := logx.NewFilter(logx.FilterRule{
+//	    Levels:      []logx.Level{logx.WarnLevel},
+//	    Message:     "^connection pool exhausted, retrying",
+//	    Action:      logx.FilterDowngrade,
+//	    DowngradeTo: logx.DebugLevel,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config := logx.DefaultConfig()
+//	config.Processors = []logx.Processor{filter}
+func NewFilter(rules ...FilterRule) (*Filter, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		action := rule.Action
+		if action == "" {
+			action = FilterDrop
+		}
+		if action != FilterDrop && action != FilterDowngrade {
+			return nil, fmt.Errorf("filter rule %d: unknown action %q", i, action)
+		}
+		matcher, err := newEntryMatcher(rule.Levels, TraceLevel, rule.Logger, rule.Message, rule.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("filter rule %d: %w", i, err)
+		}
+		compiled = append(compiled, compiledRule{
+			entryMatcher: matcher,
+			action:       action,
+			downgradeTo:  rule.DowngradeTo,
+		})
+	}
+	return &Filter{rules: compiled}, nil
+}
+
+// Process applies the first matching rule to the entry.
+func (f *Filter) Process(e *Entry) bool {
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(e) {
+			continue
+		}
+		if rule.action == FilterDowngrade {
+			e.Level = rule.downgradeTo
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package logx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// FingerprintKey is the field key of the error fingerprint added when
+// Config.ErrorFingerprint is enabled.
+const FingerprintKey = "error_fingerprint"
+
+// fingerprintFrames is the number of stack frames, starting at the logging
+// call site, that contribute to a fingerprint.
+const fingerprintFrames = 3
+
+// normalizeMessage replaces the parts of a message that vary between
+// occurrences of the same failure with "?": quoted values, UUIDs,
+// hexadecimal values and numbers. It is equivalent to replacing the matches
+// of `"[^"]*"|'[^']*'|<UUID>|0x[0-9a-fA-F]+|\d+`, without regexp, which
+// logx_minimal builds leave out.
+func normalizeMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); {
+		if n := noiseLength(msg[i:]); n > 0 {
+			b.WriteByte('?')
+			i += n
+			continue
+		}
+		b.WriteByte(msg[i])
+		i++
+	}
+	return b.String()
+}
+
+// noiseLength returns the length of the variable part at the start of s,
+// or 0 if there is none, trying the alternatives in the order of
+// normalizeMessage.
+func noiseLength(s string) int {
+	switch c := s[0]; {
+	case c == '"' || c == '\'':
+		if end := strings.IndexByte(s[1:], c); end >= 0 {
+			return end + 2
+		}
+	case isUUID(s):
+		return 36
+	case c == '0' && len(s) > 2 && s[1] == 'x' && isHexDigit(s[2]):
+		n := 3
+		for n < len(s) && isHexDigit(s[n]) {
+			n++
+		}
+		return n
+	}
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// isUUID reports whether s starts with a UUID in its textual form.
+func isUUID(s string) bool {
+	if len(s) < 36 {
+		return false
+	}
+	for i := 0; i < 36; i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHexDigit(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isHexDigit reports whether c is a hexadecimal digit.
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// errorFingerprint computes a stable identifier for a failure from the type
+// of the first error field (unwrapped to its root cause), the normalized
+// error message (or the entry message if there is no error field) and the
+// functions of the top stack frames. skip is the number of frames to ascend
+// from the caller of errorFingerprint to reach the logging call site.
+func errorFingerprint(msg string, fields []Field, skip int) string {
+	var errType, errMsg string
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok && err != nil {
+			root := err
+			for next := errors.Unwrap(root); next != nil; next = errors.Unwrap(root) {
+				root = next
+			}
+			errType = fmt.Sprintf("%T", root)
+			errMsg = err.Error()
+			break
+		}
+	}
+	if errMsg == "" {
+		errMsg = msg
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(errType))
+	hash.Write([]byte{0})
+	hash.Write([]byte(normalizeMessage(errMsg)))
+
+	// Skip runtime.Callers, errorFingerprint and its caller.
+	pcs := make([]uintptr, fingerprintFrames)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip+2, pcs)])
+	for {
+		frame, more := frames.Next()
+		hash.Write([]byte{0})
+		hash.Write([]byte(frame.Function))
+		if !more {
+			break
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
@@ -0,0 +1,146 @@
+package logx
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// File is a file opened for writing by a FileSystem. *os.File and the
+// files of most virtual filesystem libraries, such as afero, implement it.
+type File interface {
+	Write(p []byte) (int, error)
+	Sync() error
+	Close() error
+	Stat() (os.FileInfo, error)
+}
+
+// FileSystem opens the files written by file outputs: Config.OutputPath,
+// SinkConfig.OutputPath and their sidecar indexes. Replacing it lets tests
+// log to memory and deployments target storage other than the local disk.
+//
+// An afero filesystem can be adapted with a one-line wrapper:
+//
+//	type aferoFS struct{ afero.Fs }
+//
+//	func (a aferoFS) OpenFile(name string, flag int, perm os.FileMode) (logx.File, error) {
+//	    return a.Fs.OpenFile(name, flag, perm)
+//	}
+type FileSystem interface {
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+}
+
+// OSFileSystem is the FileSystem backed by the operating system. It is used
+// when Config.FileSystem is nil.
+var OSFileSystem FileSystem = osFileSystem{}
+
+// osFileSystem opens files with os.OpenFile.
+type osFileSystem struct{}
+
+// OpenFile opens the named file with os.OpenFile.
+func (osFileSystem) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+// MemFileSystem is an in-memory FileSystem, intended for tests. It is safe
+// for concurrent use.
+type MemFileSystem struct {
+	mu    sync.Mutex
+	files map[string]*memFileData
+}
+
+// memFileData is the content of a file in a MemFileSystem.
+type memFileData struct {
+	mu      sync.Mutex
+	data    bytes.Buffer
+	modTime time.Time
+}
+
+// NewMemFileSystem creates an empty in-memory filesystem.
+//
+// Example:
+//
+//	memfs := logx.NewMemFileSystem()
+//	config := logx.DefaultConfig()
+//	config.OutputPath = "app.log"
+//	config.FileSystem = memfs
+//	logger, _ := logx.New(config)
+//	logger.Info("hello")
+//	data, _ := memfs.ReadFile("app.log")
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{files: make(map[string]*memFileData)}
+}
+
+// OpenFile opens the named file for appending. O_CREATE creates a missing
+// file, O_TRUNC empties it and O_EXCL fails if it exists.
+func (m *MemFileSystem) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	switch {
+	case ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case !ok:
+		data = &memFileData{modTime: time.Now()}
+		m.files[name] = data
+	}
+	if flag&os.O_TRUNC != 0 {
+		data.mu.Lock()
+		data.data.Reset()
+		data.mu.Unlock()
+	}
+	return &memFile{name: name, data: data}, nil
+}
+
+// ReadFile returns a copy of the content of the named file.
+func (m *MemFileSystem) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	data, ok := m.files[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	data.mu.Lock()
+	defer data.mu.Unlock()
+	return bytes.Clone(data.data.Bytes()), nil
+}
+
+// memFile is an open file of a MemFileSystem. Writes always append.
+type memFile struct {
+	name string
+	data *memFileData
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.data.mu.Lock()
+	defer f.data.mu.Unlock()
+	f.data.modTime = time.Now()
+	return f.data.data.Write(p)
+}
+
+func (f *memFile) Sync() error  { return nil }
+func (f *memFile) Close() error { return nil }
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.data.mu.Lock()
+	defer f.data.mu.Unlock()
+	return memFileInfo{name: f.name, size: int64(f.data.data.Len()), modTime: f.data.modTime}, nil
+}
+
+// memFileInfo describes a memFile.
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() os.FileMode  { return 0644 }
+func (i memFileInfo) ModTime() time.Time { return i.modTime }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() interface{}   { return nil }
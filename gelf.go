@@ -0,0 +1,333 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GELF chunking limits of the Graylog UDP input.
+const (
+	gelfDefaultChunkSize = 1420 // Fits an Ethernet MTU with IP and UDP headers
+	gelfMaxChunks        = 128
+	gelfChunkHeader      = 12 // Magic bytes, message ID, sequence number and count
+)
+
+// GELFConfig configures a sink writing entries as GELF 1.1 messages for
+// Graylog. Set either Address, to send messages over the network, or
+// Writer, to write them one per line, e.g. to a file read by a sidecar.
+type GELFConfig struct {
+	// Network is "udp" or "tcp".
+	// Default: "udp"
+	Network string
+
+	// Address is the host:port of the Graylog GELF input, e.g.
+	// "graylog:12201".
+	Address string
+
+	// Writer, if set instead of Address, receives the GELF messages, each
+	// followed by a newline.
+	Writer io.Writer
+
+	// Host is the "host" of the messages.
+	// Default: os.Hostname()
+	Host string
+
+	// ChunkSize is the largest UDP datagram sent. Larger messages are split
+	// into up to 128 chunks; messages that need more are dropped.
+	// Default: 1420
+	ChunkSize int
+
+	// Compress gzips UDP messages before chunking them.
+	// Default: false
+	Compress bool
+
+	// Timeout bounds dialing and every TCP write.
+	// Default: 5s
+	Timeout time.Duration
+
+	// OnError is called when an entry cannot be converted or sent. It must
+	// not log to the same sink.
+	// Default: nil (errors are only returned by Write)
+	OnError func(err error)
+}
+
+// GELFSink converts the JSON entries of a sink to GELF messages and writes
+// them to Graylog. The entry message becomes "short_message", the
+// stacktrace "full_message", the level a syslog severity and every other
+// field an additional field prefixed with "_". Structured values are
+// written as JSON strings and booleans as "true" or "false", since GELF
+// only allows strings and numbers.
+//
+// Over TCP, messages are terminated by a null byte and the connection is
+// re-established once when a write fails.
+type GELFSink struct {
+	config GELFConfig
+	keys   atomic.Pointer[entryKeys] // Envelope of the entries, bound by the logger
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewGELFSink returns a sink writing entries as GELF messages.
+//
+// Example:
+//
+//	gelf, err := logx.NewGELFSink(logx.GELFConfig{Address: "graylog:12201"})
+//	config.Sinks = []logx.SinkConfig{{Name: "graylog", Writer: gelf}}
+//	defer gelf.Close()
+func NewGELFSink(config GELFConfig) (*GELFSink, error) {
+	if (config.Address == "") == (config.Writer == nil) {
+		return nil, fmt.Errorf("gelf sink requires either an address or a writer")
+	}
+	switch config.Network {
+	case "":
+		config.Network = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("gelf sink: unsupported network %q", config.Network)
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	if config.ChunkSize <= gelfChunkHeader {
+		config.ChunkSize = gelfDefaultChunkSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	s := &GELFSink{config: config}
+	s.keys.Store(defaultEntryKeys)
+	if config.Address != "" {
+		if err := s.dial(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// dial connects to Address.
+func (s *GELFSink) dial() error {
+	conn, err := net.DialTimeout(s.config.Network, s.config.Address, s.config.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to gelf input %s: %w", s.config.Address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// bindKeys sets the envelope of the entries written by the logger the sink
+// is given to.
+func (s *GELFSink) bindKeys(keys *entryKeys) {
+	s.keys.Store(keys)
+}
+
+// Write converts the encoded entries in p to GELF messages and sends them.
+func (s *GELFSink) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		message, err := gelfMessage(line, s.keys.Load(), s.config.Host)
+		if err == nil {
+			err = s.send(message)
+		}
+		if err != nil {
+			if s.config.OnError != nil {
+				s.config.OnError(err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(p), nil
+}
+
+// send writes one GELF message.
+func (s *GELFSink) send(message []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.Writer != nil {
+		_, err := s.config.Writer.Write(append(message, '\n'))
+		return err
+	}
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return err
+		}
+	}
+	if s.config.Network == "udp" {
+		return s.sendUDP(message)
+	}
+	message = append(message, 0)
+	if err := s.writeTCP(message); err != nil {
+		s.conn.Close()
+		if err := s.dial(); err != nil {
+			s.conn = nil
+			return err
+		}
+		return s.writeTCP(message)
+	}
+	return nil
+}
+
+// writeTCP writes a null-terminated message to the TCP connection.
+func (s *GELFSink) writeTCP(message []byte) error {
+	s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout))
+	if _, err := s.conn.Write(message); err != nil {
+		return fmt.Errorf("failed to send gelf message to %s: %w", s.config.Address, err)
+	}
+	return nil
+}
+
+// sendUDP writes a message as one datagram, or as chunks if it does not
+// fit in ChunkSize.
+func (s *GELFSink) sendUDP(message []byte) error {
+	if s.config.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(message)
+		zw.Close()
+		message = buf.Bytes()
+	}
+	if len(message) <= s.config.ChunkSize {
+		_, err := s.conn.Write(message)
+		return err
+	}
+	payload := s.config.ChunkSize - gelfChunkHeader
+	count := (len(message) + payload - 1) / payload
+	if count > gelfMaxChunks {
+		return fmt.Errorf("gelf message of %d bytes needs %d chunks, more than %d", len(message), count, gelfMaxChunks)
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, s.config.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * payload
+		if end > len(message) {
+			end = len(message)
+		}
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, message[i*payload:end]...)
+		if _, err := s.conn.Write(chunk); err != nil {
+			return fmt.Errorf("failed to send gelf chunk to %s: %w", s.config.Address, err)
+		}
+	}
+	return nil
+}
+
+// Sync does nothing; messages are sent by Write.
+func (s *GELFSink) Sync() error {
+	return nil
+}
+
+// Close closes the network connection, if any.
+func (s *GELFSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// gelfInvalidKey matches the characters not allowed in the names of GELF
+// additional fields.
+var gelfInvalidKey = regexp.MustCompile(`[^\w.\-]`)
+
+// gelfMessage converts a JSON entry following envelope to a GELF 1.1 message
+// from host.
+func gelfMessage(line []byte, envelope *entryKeys, host string) ([]byte, error) {
+	var entry map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to decode entry: %w", err)
+	}
+	t := envelope.takeTime(entry)
+	message := map[string]interface{}{
+		"version":   "1.1",
+		"host":      host,
+		"timestamp": math.Round(float64(t.UnixNano())/1e6) / 1e3,
+	}
+	message["short_message"], _ = entry[envelope.message].(string)
+	delete(entry, envelope.message)
+	if severity, ok := syslogSeverities[envelope.levelOf(entry)]; ok {
+		message["level"] = severity
+		delete(entry, envelope.level)
+	}
+	if stack, ok := entry[envelope.stacktrace]; ok {
+		if frames, ok := stack.([]interface{}); ok {
+			message["full_message"] = joinLines(frames)
+		} else {
+			message["full_message"] = gelfValue(stack)
+		}
+		delete(entry, envelope.stacktrace)
+	}
+
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := "_" + gelfInvalidKey.ReplaceAllString(key, "_")
+		if name == "_id" {
+			name = "_id_"
+		}
+		message[name] = gelfValue(entry[key])
+	}
+	return json.Marshal(message)
+}
+
+// gelfValue converts a decoded JSON value to a GELF field value: a string
+// or a number.
+func gelfValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string, json.Number:
+		return v
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// joinLines joins the frames of a strict NDJSON stacktrace with newlines.
+func joinLines(frames []interface{}) string {
+	lines := make([]string, len(frames))
+	for i, frame := range frames {
+		lines[i] = fmt.Sprint(frame)
+	}
+	return strings.Join(lines, "\n")
+}
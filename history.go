@@ -0,0 +1,161 @@
+package logx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// HistoryConfig configures the bounded in-memory history of recent entries
+// that Logger.Snapshot reads from.
+type HistoryConfig struct {
+	// MaxEntries is the maximum number of entries retained. Zero disables
+	// the history.
+	MaxEntries int
+
+	// MaxAge discards entries older than this. If zero, entries are only
+	// discarded when MaxEntries is reached.
+	MaxAge time.Duration
+}
+
+// history is a ring buffer of masked copies of recently written entries.
+type history struct {
+	mu      sync.Mutex
+	entries []Entry
+	start   int
+	count   int
+	maxAge  time.Duration
+}
+
+// newHistory creates the history for config, or returns nil if disabled.
+func newHistory(config HistoryConfig) *history {
+	if config.MaxEntries <= 0 {
+		return nil
+	}
+	return &history{entries: make([]Entry, config.MaxEntries), maxAge: config.MaxAge}
+}
+
+// record appends masked, a masked copy of an entry, evicting the oldest
+// entry when the history is full.
+func (h *history) record(masked Entry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == len(h.entries) {
+		h.entries[h.start] = Entry{}
+		h.start = (h.start + 1) % len(h.entries)
+		h.count--
+	}
+	h.entries[(h.start+h.count)%len(h.entries)] = masked
+	h.count++
+}
+
+// since returns the retained entries written at or after t, oldest first.
+func (h *history) since(t time.Time) []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.maxAge > 0 {
+		if oldest := time.Now().Add(-h.maxAge); oldest.After(t) {
+			t = oldest
+		}
+	}
+	var entries []Entry
+	for i := 0; i < h.count; i++ {
+		e := h.entries[(h.start+i)%len(h.entries)]
+		if !e.Time.Before(t) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Snapshot returns the entries written during the last d by this logger and
+// every logger sharing its state, oldest first, as retained by the history
+// configured in Config.History. A non-positive d returns every retained
+// entry. Sensitive fields are masked or encrypted, as in the output. If the
+// history is disabled, nil is returned.
+//
+// Example:
+//
+//	config := logx.DefaultConfig()
+//	config.History = logx.HistoryConfig{MaxEntries: 10000, MaxAge: time.Hour}
+//	logger, _ := logx.New(config)
+//	...
+//	recent := logger.Snapshot(15 * time.Minute)
+func (l *Logger) Snapshot(d time.Duration) []Entry {
+	if l.shared.history == nil {
+		return nil
+	}
+	var since time.Time
+	if d > 0 {
+		since = time.Now().Add(-d)
+	}
+	return l.shared.history.since(since)
+}
+
+// WriteSnapshot writes the entries returned by Snapshot to w as JSON lines,
+// for attaching recent logs to bug reports and support bundles. The
+// timestamp, level, message and logger name are written under the keys and
+// in the time layout of the logger's outputs.
+//
+// Example:
+//
+//	file, _ := os.Create("support-bundle/recent.log")
+//	defer file.Close()
+//	if err := logger.WriteSnapshot(file, 30*time.Minute); err != nil {
+//	    return err
+//	}
+func (l *Logger) WriteSnapshot(w io.Writer, d time.Duration) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	keys := l.shared.keys.Load()
+	for _, e := range l.Snapshot(d) {
+		doc := make(map[string]interface{}, len(e.Fields)+4)
+		for _, field := range e.Fields {
+			doc[field.Key] = schemaValue(field.Value)
+		}
+		doc[keys.time] = keys.encodeTime(e.Time)
+		doc[keys.level] = keys.levelName(e.Level)
+		doc[keys.message] = e.Message
+		if e.LoggerName != "" {
+			doc[keys.logger] = e.LoggerName
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// schemaValue converts a field value to the JSON value it is encoded as.
+func schemaValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return value
+	case bool:
+		return value
+	case error:
+		return value.Error()
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case time.Duration:
+		return value.Seconds()
+	case []byte:
+		return string(value)
+	case fmt.Stringer:
+		return value.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Sprint(v)
+	}
+	return decoded
+}
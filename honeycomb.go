@@ -0,0 +1,70 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HoneycombConfig configures a sink sending entries as Honeycomb events.
+type HoneycombConfig struct {
+	// APIKey authenticates with Honeycomb. Required.
+	APIKey string
+
+	// Dataset receives the events. Required.
+	Dataset string
+
+	// Endpoint is the Honeycomb API URL, e.g. for the EU region
+	// "https://api.eu1.honeycomb.io".
+	// Default: "https://api.honeycomb.io"
+	Endpoint string
+
+	// Attributes renames entry keys to event columns, e.g. "message" to
+	// "name". Keys not listed are sent unchanged.
+	// Default: nil
+	Attributes map[string]string
+
+	// Batch configures batching.
+	Batch BatchConfig
+}
+
+// NewHoneycombSink returns a sink sending entries to the Honeycomb batch
+// events API. Every field of an entry becomes a column of the event and the
+// entry's timestamp becomes the event time.
+//
+// Example:
+//
+//	honeycomb, err := logx.NewHoneycombSink(logx.HoneycombConfig{
+//	    APIKey:  os.Getenv("HONEYCOMB_API_KEY"),
+//	    Dataset: "checkout",
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "honeycomb", Writer: honeycomb}}
+//	defer honeycomb.Close()
+func NewHoneycombSink(config HoneycombConfig) (*APISink, error) {
+	if config.APIKey == "" || config.Dataset == "" {
+		return nil, fmt.Errorf("honeycomb sink requires an API key and a dataset")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://api.honeycomb.io"
+	}
+	target := strings.TrimSuffix(config.Endpoint, "/") + "/1/batch/" + url.PathEscape(config.Dataset)
+	headers := map[string]string{"X-Honeycomb-Team": config.APIKey}
+
+	return newAPISink(config.Batch, apiFormat{request: func(batch []apiEntry) (*http.Request, error) {
+		events := make([]map[string]interface{}, len(batch))
+		for i, e := range batch {
+			entry := e.fields
+			t := e.keys.takeTime(entry)
+			mapAttributes(entry, config.Attributes)
+			events[i] = map[string]interface{}{
+				"time": t.Format(time.RFC3339Nano),
+				"data": entry,
+			}
+		}
+		return newJSONRequest(target, events, headers)
+	}})
+}
@@ -0,0 +1,158 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IndexSuffix is appended to a log file's path to name its sidecar index.
+const IndexSuffix = ".idx"
+
+// indexHeader starts every sidecar index file. Records follow as pairs of
+// big-endian int64 values: the Unix time in nanoseconds and the byte
+// offset of the entry written at that time.
+var indexHeader = []byte("LGXIDX1\n")
+
+// indexRecordSize is the encoded size of an IndexRecord.
+const indexRecordSize = 16
+
+// IndexRecord maps a time to the byte offset of the entry written at that
+// time in a log file. Every entry before Offset was written before Time.
+type IndexRecord struct {
+	Time   time.Time
+	Offset int64
+}
+
+// indexedWriter writes entries to a log file and records the offset of
+// every interval-th entry in the sidecar index. Each Write call carries
+// exactly one encoded entry.
+type indexedWriter struct {
+	mu       sync.Mutex
+	file     File
+	index    File
+	interval int
+	offset   int64
+	count    int
+}
+
+// newIndexedWriter wraps file, opened in append mode at path, and opens or
+// creates its sidecar index on fsys.
+func newIndexedWriter(fsys FileSystem, file File, path string, interval int) (*indexedWriter, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
+	}
+	index, err := fsys.OpenFile(path+IndexSuffix, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log index: %w", err)
+	}
+	indexInfo, err := index.Stat()
+	if err == nil && indexInfo.Size() == 0 {
+		_, err = index.Write(indexHeader)
+	}
+	if err != nil {
+		index.Close()
+		return nil, fmt.Errorf("failed to initialize log index: %w", err)
+	}
+	return &indexedWriter{file: file, index: index, interval: interval, offset: info.Size()}, nil
+}
+
+// Write writes one entry, first recording its offset if it is due.
+func (w *indexedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.count%w.interval == 0 {
+		var record [indexRecordSize]byte
+		binary.BigEndian.PutUint64(record[:8], uint64(time.Now().UnixNano()))
+		binary.BigEndian.PutUint64(record[8:], uint64(w.offset))
+		// A failed index write only degrades seeking, never logging.
+		w.index.Write(record[:])
+	}
+	w.count++
+	n, err := w.file.Write(p)
+	w.offset += int64(n)
+	return n, err
+}
+
+// Sync flushes the log file and its index to disk.
+func (w *indexedWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	return w.index.Sync()
+}
+
+// Close closes the log file and its index.
+func (w *indexedWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.file.Close()
+	if indexErr := w.index.Close(); err == nil {
+		err = indexErr
+	}
+	return err
+}
+
+// ReadIndex reads the sidecar index of the log file at logPath, in the
+// order the records were written.
+func ReadIndex(logPath string) ([]IndexRecord, error) {
+	data, err := os.ReadFile(logPath + IndexSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, indexHeader) {
+		return nil, fmt.Errorf("%s: not a logx index", logPath+IndexSuffix)
+	}
+	data = data[len(indexHeader):]
+	records := make([]IndexRecord, 0, len(data)/indexRecordSize)
+	for len(data) >= indexRecordSize {
+		records = append(records, IndexRecord{
+			Time:   time.Unix(0, int64(binary.BigEndian.Uint64(data[:8]))),
+			Offset: int64(binary.BigEndian.Uint64(data[8:16])),
+		})
+		data = data[indexRecordSize:]
+	}
+	return records, nil
+}
+
+// IndexOffset returns the byte offset in the log file at logPath from which
+// to read to find every entry written at or after t, using its sidecar
+// index. Reading from the returned offset may still yield some entries
+// written before t, which the caller should skip. If the file has no usable
+// index, 0 is returned.
+//
+// Example:
+//
+//	offset, _ := logx.IndexOffset("/var/log/app.log", since)
+//	file.Seek(offset, io.SeekStart)
+func IndexOffset(logPath string, t time.Time) (int64, error) {
+	records, err := ReadIndex(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		return 0, err
+	}
+	// Records are appended in time order; find the last one at or before t.
+	i := sort.Search(len(records), func(i int) bool {
+		return records[i].Time.After(t)
+	})
+	for i--; i >= 0; i-- {
+		// Ignore records that point past the end, e.g. after truncation.
+		if records[i].Offset <= info.Size() {
+			return records[i].Offset, nil
+		}
+	}
+	return 0, nil
+}
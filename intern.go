@@ -0,0 +1,106 @@
+package logx
+
+import "sync"
+
+// defaultInternSize is the default maximum number of distinct values an
+// intern table holds.
+const defaultInternSize = 4096
+
+// internTable stores one canonical copy of repeated field values. Once the
+// table is full, new values are passed through unchanged, which bounds its
+// memory under high-cardinality traffic.
+type internTable struct {
+	keys map[string]struct{}
+
+	mu     sync.RWMutex
+	values map[string]string
+	max    int
+}
+
+// newInternTable creates a table for the given field keys, or returns nil
+// if there are none.
+func newInternTable(keys []string, max int) *internTable {
+	if len(keys) == 0 {
+		return nil
+	}
+	if max <= 0 {
+		max = defaultInternSize
+	}
+	t := &internTable{keys: make(map[string]struct{}, len(keys)), values: make(map[string]string), max: max}
+	for _, key := range keys {
+		t.keys[key] = struct{}{}
+	}
+	return t
+}
+
+// lookup returns the canonical copy of the value of b, converting and
+// storing it on first use. A hit does not allocate.
+func (t *internTable) lookup(b []byte) string {
+	t.mu.RLock()
+	s, ok := t.values[string(b)]
+	t.mu.RUnlock()
+	if ok {
+		return s
+	}
+	s = string(b)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if existing, ok := t.values[s]; ok {
+		return existing
+	}
+	if len(t.values) < t.max {
+		t.values[s] = s
+	}
+	return s
+}
+
+// intern returns the canonical copy of s, storing it on first use.
+func (t *internTable) intern(s string) string {
+	t.mu.RLock()
+	canonical, ok := t.values[s]
+	t.mu.RUnlock()
+	if ok {
+		return canonical
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if existing, ok := t.values[s]; ok {
+		return existing
+	}
+	if len(t.values) < t.max {
+		t.values[s] = s
+	}
+	return s
+}
+
+// internFields replaces string values of configured keys with their
+// canonical copies, so that entries retained by the history, subscribers
+// or processors share one copy of each repeated value. fields is modified
+// in place.
+func (t *internTable) internFields(fields []Field) {
+	for i, field := range fields {
+		if _, ok := t.keys[field.Key]; !ok {
+			continue
+		}
+		if s, ok := field.Value.(string); ok {
+			fields[i].Value = t.intern(s)
+		}
+	}
+}
+
+// InternedString creates a string field from b, reusing the canonical copy
+// of the value if key is listed in Config.InternKeys. Repeated values, such
+// as routes or service names parsed from a request, then need no new string
+// allocation. For other keys it is equivalent to String(key, string(b)).
+//
+// Example:
+//
+//	logger.Info("Request served", logger.InternedString("route", routeBytes))
+func (l *Logger) InternedString(key string, b []byte) Field {
+	if t := l.shared.intern; t != nil {
+		if _, ok := t.keys[key]; ok {
+			return String(key, t.lookup(b))
+		}
+	}
+	return String(key, string(b))
+}
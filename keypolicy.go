@@ -0,0 +1,141 @@
+package logx
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// KeyPolicy guards the envelope of every entry against user-supplied field
+// keys. Keys containing dots, spaces or other punctuation are rewritten, as
+// some backends split dotted keys into nested objects or reject them, and
+// keys equal to an envelope key such as "message" or "level" are prefixed
+// so that they cannot collide with it. Every rewritten key is counted; see
+// Logger.FixedKeys.
+//
+// Keys are rewritten at encode time, after masking and before the renames
+// of Config.FieldProfile, so profile keys such as "trace.id" are kept.
+type KeyPolicy struct {
+	// AllowDots keeps dots in keys, for backends that store dotted keys
+	// as-is.
+	// Default: false
+	AllowDots bool
+
+	// Replacement replaces every character other than letters, digits,
+	// '_' and '-' (and '.' with AllowDots).
+	// Default: "_"
+	Replacement string
+
+	// Reserved lists the keys fields may not use.
+	// Default: the envelope keys of the encoder: "timestamp", "level",
+	// "message", "logger", "caller" and "stacktrace", or their
+	// FieldProfile names
+	Reserved []string
+
+	// ReservedPrefix is prepended to a field key that is reserved.
+	// Default: "field_"
+	ReservedPrefix string
+}
+
+// keyPolicy is the compiled form of a KeyPolicy.
+type keyPolicy struct {
+	allowDots   bool
+	replacement string
+	reserved    map[string]bool
+	prefix      string
+	fixed       atomic.Uint64 // Keys rewritten so far
+}
+
+// newKeyPolicy compiles policy, defaulting reserved keys to the envelope of
+// encoderConfig, or returns nil if policy is nil.
+func newKeyPolicy(policy *KeyPolicy, encoderConfig zapcore.EncoderConfig) *keyPolicy {
+	if policy == nil {
+		return nil
+	}
+	p := &keyPolicy{
+		allowDots:   policy.AllowDots,
+		replacement: policy.Replacement,
+		prefix:      policy.ReservedPrefix,
+		reserved:    make(map[string]bool),
+	}
+	if p.replacement == "" {
+		p.replacement = "_"
+	}
+	if p.prefix == "" {
+		p.prefix = "field_"
+	}
+	reserved := policy.Reserved
+	if reserved == nil {
+		reserved = []string{
+			encoderConfig.TimeKey, encoderConfig.LevelKey, encoderConfig.MessageKey,
+			encoderConfig.NameKey, encoderConfig.CallerKey, encoderConfig.StacktraceKey,
+		}
+	}
+	for _, key := range reserved {
+		if key != "" {
+			p.reserved[key] = true
+		}
+	}
+	return p
+}
+
+// apply returns key rewritten to follow the policy, counting keys that
+// had to be changed.
+func (p *keyPolicy) apply(key string) string {
+	fixed := key
+	for i, r := range key {
+		if !p.allowed(r) {
+			fixed = p.replace(key, i)
+			break
+		}
+	}
+	if p.reserved[fixed] {
+		fixed = p.prefix + fixed
+	}
+	if fixed != key {
+		p.fixed.Add(1)
+	}
+	return fixed
+}
+
+// allowed reports whether r may appear in a key unchanged.
+func (p *keyPolicy) allowed(r rune) bool {
+	switch {
+	case r == '_' || r == '-':
+		return true
+	case r == '.':
+		return p.allowDots
+	case r == utf8.RuneError:
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// replace rewrites the disallowed characters of key, starting at byte
+// offset start.
+func (p *keyPolicy) replace(key string, start int) string {
+	var b strings.Builder
+	b.Grow(len(key))
+	b.WriteString(key[:start])
+	for _, r := range key[start:] {
+		if p.allowed(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(p.replacement)
+		}
+	}
+	return b.String()
+}
+
+// FixedKeys returns how many field keys Config.KeyPolicy has rewritten
+// since the logger was created, across every logger sharing its state. A
+// growing count points at call sites passing malformed or reserved keys.
+func (l *Logger) FixedKeys() uint64 {
+	if l.shared.keyPolicy == nil {
+		return 0
+	}
+	return l.shared.keyPolicy.fixed.Load()
+}
@@ -0,0 +1,101 @@
+package logx
+
+import "time"
+
+// Well-known field keys. Using these instead of ad-hoc names keeps entries
+// from different teams and services consistent, so dashboards and queries
+// do not fragment across variants such as "req_id" and "request_id".
+const (
+	KeyRequestID  = "request_id"  // Identifier of the request being served
+	KeyTraceID    = "trace_id"    // Distributed trace identifier
+	KeySpanID     = "span_id"     // Span identifier within the trace
+	KeyUserID     = "user_id"     // Identifier of the acting user
+	KeyTenant     = "tenant"      // Tenant the work is performed for
+	KeyService    = "service"     // Name of the emitting service
+	KeyComponent  = "component"   // Component or subsystem within the service
+	KeyOperation  = "operation"   // Name of the operation being performed
+	KeyMethod     = "method"      // HTTP or RPC method
+	KeyPath       = "path"        // Request path or route
+	KeyStatusCode = "status_code" // HTTP or RPC status code
+	KeyDurationMS = "duration_ms" // Elapsed time in milliseconds
+	KeyBytes      = "bytes"       // Size of a payload in bytes
+	KeyRemoteAddr = "remote_addr" // Address of the remote peer
+	KeyError      = "error"       // Error message; see ErrorField
+)
+
+// RequestID creates a field with the standard request ID key.
+//
+// Example:
+//
+//	logger = logger.With(logx.RequestID(r.Header.Get("X-Request-ID")))
+func RequestID(id string) Field {
+	return Field{Key: KeyRequestID, Value: id}
+}
+
+// TraceID creates a field with the standard trace ID key.
+func TraceID(id string) Field {
+	return Field{Key: KeyTraceID, Value: id}
+}
+
+// SpanID creates a field with the standard span ID key.
+func SpanID(id string) Field {
+	return Field{Key: KeySpanID, Value: id}
+}
+
+// UserID creates a field with the standard user ID key.
+//
+// Example:
+//
+//	logx.Info("User logged in", logx.UserID("12345"))
+func UserID(id string) Field {
+	return Field{Key: KeyUserID, Value: id}
+}
+
+// Tenant creates a field with the standard tenant key.
+func Tenant(tenant string) Field {
+	return Field{Key: KeyTenant, Value: tenant}
+}
+
+// Component creates a field with the standard component key.
+func Component(name string) Field {
+	return Field{Key: KeyComponent, Value: name}
+}
+
+// Operation creates a field with the standard operation key.
+func Operation(name string) Field {
+	return Field{Key: KeyOperation, Value: name}
+}
+
+// StatusCode creates a field with the standard status code key.
+func StatusCode(code int) Field {
+	return Field{Key: KeyStatusCode, Value: code}
+}
+
+// DurationMS creates a field with the elapsed time in whole milliseconds
+// under the standard duration key.
+//
+// Example:
+//
+//	start := time.Now()
+//	handle(r)
+//	logx.Info("Request completed", logx.DurationMS(time.Since(start)))
+func DurationMS(d time.Duration) Field {
+	return Field{Key: KeyDurationMS, Value: d.Milliseconds()}
+}
+
+// HTTPRequest returns the standard fields describing an HTTP request, for
+// use with With or as trailing fields of a request log line.
+//
+// Example:
+//
+//	logx.Info("Request completed",
+//	    append(logx.HTTPRequest(r.Method, r.URL.Path, 200, time.Since(start)),
+//	        logx.RequestID(id))...)
+func HTTPRequest(method, path string, status int, elapsed time.Duration) []Field {
+	return []Field{
+		{Key: KeyMethod, Value: method},
+		{Key: KeyPath, Value: path},
+		StatusCode(status),
+		DurationMS(elapsed),
+	}
+}
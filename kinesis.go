@@ -0,0 +1,196 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AWSCredentials are the credentials used to sign requests to AWS.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// KinesisConfig configures a sink writing entries to an AWS Kinesis data
+// stream.
+type KinesisConfig struct {
+	// Stream is the name of the data stream. Required.
+	Stream string
+
+	// Region is the AWS region of the stream, e.g. "eu-west-1". Required.
+	Region string
+
+	// Credentials returns the credentials requests are signed with. It is
+	// called for every batch, so that rotating credentials are picked up.
+	// Required.
+	Credentials func() (AWSCredentials, error)
+
+	// PartitionKeyField selects the field whose value becomes the record's
+	// partition key, e.g. KeyTenant, so that related entries land on the
+	// same shard in order. Entries without the field are spread evenly
+	// across shards.
+	// Default: "" (spread evenly)
+	PartitionKeyField string
+
+	// Endpoint is the Kinesis API URL.
+	// Default: "https://kinesis.<Region>.amazonaws.com"
+	Endpoint string
+
+	// Batch configures batching. Batches are capped at the PutRecords
+	// limits of 500 records and 5 MiB per request.
+	Batch BatchConfig
+}
+
+// kinesisMaxBytes keeps PutRecords requests within the 5 MiB Kinesis limit,
+// leaving room for partition keys.
+const kinesisMaxBytes = 5<<20 - 500*256
+
+// NewKinesisSink returns a sink writing every entry as a record of a
+// Kinesis data stream with PutRecords. The record data is the JSON entry.
+// Records rejected by Kinesis, e.g. because a shard is throttled, are
+// reported to OnError with their count.
+//
+// Example:
+//
+//	kinesis, err := logx.NewKinesisSink(logx.KinesisConfig{
+//	    Stream:            "logs",
+//	    Region:            "eu-west-1",
+//	    Credentials:       credentialsFromEnv,
+//	    PartitionKeyField: logx.KeyTenant,
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "kinesis", Writer: kinesis}}
+//	defer kinesis.Close()
+func NewKinesisSink(config KinesisConfig) (*APISink, error) {
+	if config.Stream == "" || config.Region == "" || config.Credentials == nil {
+		return nil, fmt.Errorf("kinesis sink requires a stream, a region and credentials")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://kinesis." + config.Region + ".amazonaws.com"
+	}
+	var sequence atomic.Uint64 // Spreads entries without a partition key
+
+	return newAPISink(config.Batch, apiFormat{
+		maxEntries: 500,
+		maxBytes:   kinesisMaxBytes,
+		request: func(batch []apiEntry) (*http.Request, error) {
+			credentials, err := config.Credentials()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get kinesis credentials: %w", err)
+			}
+			records := make([]map[string]interface{}, len(batch))
+			for i, entry := range batch {
+				key := fieldValue(entry.fields, config.PartitionKeyField)
+				if key == "" {
+					key = strconv.FormatUint(sequence.Add(1), 10)
+				}
+				if len(key) > 256 {
+					key = key[:256]
+				}
+				records[i] = map[string]interface{}{"Data": entry.raw, "PartitionKey": key}
+			}
+			req, err := newJSONRequest(strings.TrimSuffix(config.Endpoint, "/"),
+				map[string]interface{}{"StreamName": config.Stream, "Records": records},
+				map[string]string{
+					"Content-Type": "application/x-amz-json-1.1",
+					"X-Amz-Target": "Kinesis_20131202.PutRecords",
+				})
+			if err != nil {
+				return nil, err
+			}
+			if err := signAWSv4(req, credentials, config.Region, "kinesis", time.Now()); err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		response: func(resp *http.Response, body []byte) error {
+			var result struct{ FailedRecordCount int }
+			if json.Unmarshal(body, &result) == nil && result.FailedRecordCount > 0 {
+				return fmt.Errorf("%d records rejected", result.FailedRecordCount)
+			}
+			return nil
+		},
+	})
+}
+
+// signAWSv4 signs req with AWS Signature Version 4.
+func signAWSv4(req *http.Request, credentials AWSCredentials, region, service string, now time.Time) error {
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+	}
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// sha256Hex returns the hex SHA-256 of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package logx
+
+import (
+	"context"
+	"runtime/pprof"
+	"sort"
+)
+
+// WithPprofLabels returns a child logger with the pprof labels carried by
+// ctx attached as fields, so that goroutines labeled for profiling get the
+// same dimensions on their logs. Only the keys listed in Config.PprofLabels
+// are attached, or every label if none are listed.
+//
+// Go does not expose the labels of the running goroutine, so they are read
+// from the context that pprof.Do or pprof.WithLabels returned, which is the
+// context the labeled code runs with.
+//
+// Example:
+//
+//	pprof.Do(ctx, pprof.Labels("worker", "billing", "tenant", id), func(ctx context.Context) {
+//	    log := logger.WithPprofLabels(ctx)
+//	    log.Info("Invoice batch started") // includes "worker" and "tenant"
+//	})
+func (l *Logger) WithPprofLabels(ctx context.Context) *Logger {
+	return l.With(pprofLabelFields(ctx, l.shared.pprofLabels)...)
+}
+
+// pprofLabelFields returns the pprof labels of ctx with the given keys as
+// string fields, or all labels sorted by key if keys is empty.
+func pprofLabelFields(ctx context.Context, keys []string) []Field {
+	if ctx == nil {
+		return nil
+	}
+	var fields []Field
+	if len(keys) > 0 {
+		for _, key := range keys {
+			if value, ok := pprof.Label(ctx, key); ok {
+				fields = append(fields, String(key, value))
+			}
+		}
+		return fields
+	}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		fields = append(fields, String(key, value))
+		return true
+	})
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	return fields
+}
@@ -0,0 +1,153 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FileLockMode controls how file outputs coordinate with other processes
+// writing to the same file.
+//
+// Every entry is written with a single write call on a file opened in
+// append mode, which local filesystems apply atomically, so lines from
+// several processes never interleave within an entry. Locking additionally
+// detects unintended sharing and protects filesystems without atomic
+// appends.
+type FileLockMode int
+
+const (
+	// FileLockNone does not lock. It is the default.
+	FileLockNone FileLockMode = iota
+
+	// FileLockExclusive claims each output file for this process. Creating
+	// a logger fails if another process has claimed the file or is sharing
+	// it, and other processes fail to claim it while this one is running.
+	FileLockExclusive
+
+	// FileLockShared lets several processes append to the same file.
+	// Each entry is written while holding an advisory lock on the file, and
+	// creating a logger fails if another process has claimed the file
+	// exclusively. Sidecar indexes (Config.IndexInterval) are not supported
+	// on shared files, as their offsets would be wrong.
+	FileLockShared
+)
+
+// Advisory lock regions, far beyond any real file content. Claiming locks
+// the owner byte, exclusively or shared; writes in shared mode lock the
+// write byte exclusively.
+const (
+	lockOwnerOffset = 1 << 62
+	lockWriteOffset = lockOwnerOffset + 1
+)
+
+// errLockUnsupported is returned when locking is requested for a file or
+// platform that does not support it.
+var errLockUnsupported = errors.New("file locking is not supported")
+
+// errLockHeld is returned by lockRange when a non-blocking lock conflicts
+// with a lock held by another process.
+var errLockHeld = errors.New("lock held by another process")
+
+// lockOutput applies mode to file, opened at path, and returns the file to
+// write to.
+func lockOutput(file File, path string, mode FileLockMode) (File, error) {
+	if mode == FileLockNone {
+		return file, nil
+	}
+	fd, ok := file.(interface{ Fd() uintptr })
+	if !ok {
+		return nil, fmt.Errorf("log file %s: %w by its FileSystem", path, errLockUnsupported)
+	}
+	err := lockRange(fd.Fd(), lockOwnerOffset, mode == FileLockExclusive, false)
+	if errors.Is(err, errLockHeld) {
+		if mode == FileLockExclusive {
+			return nil, fmt.Errorf("log file %s is in use by another process; use FileLockShared in every process that writes to it", path)
+		}
+		return nil, fmt.Errorf("log file %s is claimed exclusively by another process", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock log file %s: %w", path, err)
+	}
+	claimed := newClaimedFile(file, fd.Fd(), path, mode == FileLockExclusive)
+	if mode == FileLockShared {
+		return &sharedFile{File: claimed, fd: fd.Fd()}, nil
+	}
+	return claimed, nil
+}
+
+// claims holds the owner locks of the output files open in the process.
+// Where closing any descriptor of a file releases the process's locks on
+// it, such as when Reload closes the previous descriptor of an unchanged
+// path, the remaining claims are taken again after every close.
+var claims = struct {
+	sync.Mutex
+	files map[*claimedFile]struct{}
+}{files: make(map[*claimedFile]struct{})}
+
+// claimedFile is an output file whose owner lock is held by the process.
+type claimedFile struct {
+	File
+	fd        uintptr
+	path      string
+	exclusive bool
+}
+
+// newClaimedFile registers the claim on file, whose owner lock has been
+// taken.
+func newClaimedFile(file File, fd uintptr, path string, exclusive bool) *claimedFile {
+	f := &claimedFile{File: file, fd: fd, path: path, exclusive: exclusive}
+	claims.Lock()
+	defer claims.Unlock()
+	claims.files[f] = struct{}{}
+	return f
+}
+
+// Close releases the claim, closes the file and takes the remaining
+// claims again.
+func (f *claimedFile) Close() error {
+	claims.Lock()
+	delete(claims.files, f)
+	claims.Unlock()
+	err := f.File.Close()
+	if reclaimErr := reclaimLocks(); err == nil {
+		err = reclaimErr
+	}
+	return err
+}
+
+// reclaimLocks takes the owner lock of every claimed file again, if closing
+// a descriptor may have released it, and returns the first error.
+func reclaimLocks() error {
+	if !closeReleasesLocks {
+		return nil
+	}
+	claims.Lock()
+	defer claims.Unlock()
+	var firstErr error
+	for f := range claims.files {
+		if err := lockRange(f.fd, lockOwnerOffset, f.exclusive, false); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to reclaim log file %s: %w", f.path, err)
+		}
+	}
+	return firstErr
+}
+
+// sharedFile writes each entry while holding the write lock, so that
+// processes sharing the file never interleave partial writes.
+type sharedFile struct {
+	File
+	fd uintptr
+	mu sync.Mutex // Serializes writers within the process
+}
+
+// Write writes p under the write lock. If the lock cannot be taken, p is
+// still written, relying on append atomicity.
+func (f *sharedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := lockRange(f.fd, lockWriteOffset, true, true); err == nil {
+		defer unlockRange(f.fd, lockWriteOffset)
+	}
+	return f.File.Write(p)
+}
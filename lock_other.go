@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package logx
+
+// closeReleasesLocks is false: no locks are taken on this platform.
+const closeReleasesLocks = false
+
+// lockRange reports that locking is not supported on this platform.
+func lockRange(fd uintptr, off int64, exclusive, wait bool) error {
+	return errLockUnsupported
+}
+
+// unlockRange does nothing on this platform.
+func unlockRange(fd uintptr, off int64) error {
+	return nil
+}
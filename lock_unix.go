@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logx
+
+import (
+	"errors"
+	"io"
+	"syscall"
+)
+
+// closeReleasesLocks reports that closing any descriptor of a file releases
+// the locks the process holds on it.
+const closeReleasesLocks = true
+
+// lockRange takes a POSIX advisory lock on the byte at off, exclusive or
+// shared, waiting for conflicting locks if wait is set. POSIX locks belong
+// to the process, so loggers within one process never conflict, and a
+// process releases its locks on a file when it closes any descriptor of it.
+func lockRange(fd uintptr, off int64, exclusive, wait bool) error {
+	lock := syscall.Flock_t{Type: syscall.F_RDLCK, Whence: io.SeekStart, Start: off, Len: 1}
+	if exclusive {
+		lock.Type = syscall.F_WRLCK
+	}
+	cmd := syscall.F_SETLK
+	if wait {
+		cmd = syscall.F_SETLKW
+	}
+	err := syscall.FcntlFlock(fd, cmd, &lock)
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EACCES) {
+		return errLockHeld
+	}
+	return err
+}
+
+// unlockRange releases the lock on the byte at off.
+func unlockRange(fd uintptr, off int64) error {
+	lock := syscall.Flock_t{Type: syscall.F_UNLCK, Whence: io.SeekStart, Start: off, Len: 1}
+	return syscall.FcntlFlock(fd, syscall.F_SETLK, &lock)
+}
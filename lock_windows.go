@@ -0,0 +1,57 @@
+//go:build windows
+
+package logx
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+	errorLockViolation      = syscall.Errno(33)
+)
+
+// closeReleasesLocks is false: Windows locks belong to the file handle
+// and survive closing other handles of the file.
+const closeReleasesLocks = false
+
+// lockRange locks the byte at off with LockFileEx, exclusive or shared,
+// waiting for conflicting locks if wait is set. Windows locks belong to the
+// file handle, so loggers within one process can conflict.
+func lockRange(fd uintptr, off int64, exclusive, wait bool) error {
+	var flags uintptr
+	if exclusive {
+		flags |= lockfileExclusiveLock
+	}
+	if !wait {
+		flags |= lockfileFailImmediately
+	}
+	overlapped := syscall.Overlapped{Offset: uint32(off), OffsetHigh: uint32(off >> 32)}
+	r, _, err := procLockFileEx.Call(fd, flags, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r != 0 {
+		return nil
+	}
+	if errors.Is(err, errorLockViolation) {
+		return errLockHeld
+	}
+	return err
+}
+
+// unlockRange releases the lock on the byte at off.
+func unlockRange(fd uintptr, off int64) error {
+	overlapped := syscall.Overlapped{Offset: uint32(off), OffsetHigh: uint32(off >> 32)}
+	r, _, err := procUnlockFileEx.Call(fd, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r != 0 {
+		return nil
+	}
+	return err
+}
@@ -8,9 +8,14 @@
 package logx
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -78,6 +83,10 @@ func Bool(key string, value bool) Field {
 // Use this when you need to log complex types or when the type
 // is not known at compile time.
 //
+// Values implementing zapcore.ObjectMarshaler or zapcore.ArrayMarshaler
+// are written by their own marshaling methods, with the sensitive keys
+// they produce masked, and a zapcore.Field is written under key.
+//
 // Example:
 //
 //	logx.Info("Complex data", logx.Any("user", userStruct))
@@ -94,7 +103,7 @@ func Any(key string, value interface{}) Field {
 //	    logx.Error("Operation failed", logx.ErrorField(err))
 //	}
 func ErrorField(err error) Field {
-	return Field{Key: "error", Value: err}
+	return Field{Key: KeyError, Value: err}
 }
 
 // Logger wraps the zap logger with additional functionality including
@@ -105,9 +114,79 @@ func ErrorField(err error) Field {
 // in all log messages, and provides methods for creating child loggers
 // with additional fields.
 type Logger struct {
-	zapLogger *zap.Logger  // The underlying zap logger
-	fields    []Field      // Fields to include in all log messages
-	mu        sync.RWMutex // Mutex for thread-safe field operations
+	zapLogger *zap.Logger            // The underlying zap logger
+	sinks     map[string]*zap.Logger // Additional named outputs, keyed by sink name
+	fields    []Field                // Fields to include in all log messages
+	name      string                 // Dotted logger name, empty for the root logger
+	shared    *loggerShared          // State shared with derived loggers
+	budget    *logBudget             // Log budget shared with derived loggers, nil if unlimited
+	ctx       context.Context        // Context bound by WithContext, nil if none
+	txn       *Transaction           // Transaction holding the entries until Commit, nil if none
+	mu        sync.RWMutex           // Mutex for thread-safe field operations
+}
+
+// loggerShared holds the state created by New and shared by a logger and
+// every logger derived from it through With or Named.
+type loggerShared struct {
+	level       zap.AtomicLevel               // Minimum level of every output, shared by derived loggers
+	logLevel    atomic.Int32                  // The logx Level last set, as level cannot represent Trace
+	verbosity   atomic.Int32                  // Highest verbosity written through V
+	emitLogger  *zap.Logger                   // Caller-less zap logger for processor-generated entries
+	processors  []Processor                   // Entry processors
+	hub         *subscriptionHub              // Live entry subscriptions
+	sanitize    bool                          // Sanitize strings before encoding
+	eventID     bool                          // Stamp entries with a ULID event_id field
+	fingerprint bool                          // Add an error_fingerprint field to Error and Fatal entries
+	callerComps *callerComponents             // Components derived from call sites, nil if disabled
+	fieldKeys   map[string]string             // Field renames of the configured FieldProfile
+	history     *history                      // Recent entries for Snapshot, nil if disabled
+	withCache   *withCache                    // Cached With children, nil if disabled
+	async       *asyncQueue                   // Asynchronous write queue, nil if synchronous
+	pprofLabels []string                      // pprof label keys attached by WithPprofLabels
+	intern      *internTable                  // Canonical copies of repeated field values, nil if disabled
+	sequencer   *sequencer                    // Global write order for StrictOrdering, nil if disabled
+	spanEvents  SpanEventFunc                 // Adds Warn and above entries to the span of a bound context, nil if disabled
+	encryption  *fieldEncryption              // Encrypts the values of designated keys, nil if disabled
+	anyBudget   *AnyBudget                    // Size budget of structured values, nil if unbounded
+	coverage    *Coverage                     // Recorder of exercised log statements, nil if disabled
+	nonFinite   NonFinitePolicy               // Writing of NaN and infinite floats
+	labelKeys   map[string]bool               // Keys of the fields grouped as stream labels, nil if none
+	sinkFloor   atomic.Pointer[zapcore.Level] // Lowest level admitted by a sink with its own level, nil if none
+	reported    sync.Map                      // Deprecation and feature flag events already written
+	boost       verbosityBoost                // Temporary Debug window opened by Boost
+	schedule    *levelSchedule                // Level windows of Config.LevelSchedule
+	stats       *logStats                     // Usage statistics for Stats, nil if disabled
+	exitReport  *exitReport                   // Report written by Fatal before exiting, nil if disabled
+	addCaller   bool                          // Record the call site in Entry.Caller
+	keyPolicy   *keyPolicy                    // Field key rewriting, nil if disabled
+	keys        atomic.Pointer[entryKeys]     // Envelope written by the current outputs
+	profile     *FieldProfile                 // FieldProfile given to New, kept by Reload
+	core        *swapCore                     // Core of the primary output, replaced by Reload
+	sinkCores   map[string]*swapCore          // Cores of the sinks, replaced by Reload
+	files       *outputFiles                  // Files opened by the current outputs
+	reload      sync.RWMutex                  // Held for reading by writes and for writing while Reload swaps cores or a transaction commits
+	components  *componentRegistry            // Levels, samplers and masking of components
+}
+
+// toZapLevel converts a logx level to the equivalent zap level.
+// Unknown levels map to zap's InfoLevel.
+func toZapLevel(level Level) zapcore.Level {
+	switch level {
+	case TraceLevel:
+		return zapcore.DebugLevel // Use Debug level for Trace since zap doesn't have Trace
+	case DebugLevel:
+		return zapcore.DebugLevel
+	case InfoLevel:
+		return zapcore.InfoLevel
+	case WarnLevel:
+		return zapcore.WarnLevel
+	case ErrorLevel:
+		return zapcore.ErrorLevel
+	case FatalLevel:
+		return zapcore.FatalLevel
+	default:
+		return zapcore.InfoLevel
+	}
 }
 
 // New creates a new logger instance with the specified configuration.
@@ -128,94 +207,744 @@ type Logger struct {
 //	    log.Fatal(err)
 //	}
 func New(config *Config) (*Logger, error) {
-	// Convert our level to zap level
-	zapLevel := zapcore.InfoLevel
-	switch config.Level {
-	case TraceLevel:
-		zapLevel = zapcore.DebugLevel // Use Debug level for Trace since zap doesn't have Trace
-	case DebugLevel:
-		zapLevel = zapcore.DebugLevel
-	case InfoLevel:
-		zapLevel = zapcore.InfoLevel
-	case WarnLevel:
-		zapLevel = zapcore.WarnLevel
-	case ErrorLevel:
-		zapLevel = zapcore.ErrorLevel
-	case FatalLevel:
-		zapLevel = zapcore.FatalLevel
+	// Convert our level to an atomic zap level shared by every output and
+	// every derived logger, so that SetLevel applies to all of them.
+	level, levelErr := configLevel(config)
+	zapLevel := zap.NewAtomicLevelAt(toZapLevel(level))
+
+	windows, err := parseLevelWindows(config.LevelSchedule)
+	if err != nil {
+		return nil, err
+	}
+	encryption, err := newFieldEncryption(config.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	async, err := newAsyncQueue(config.Async)
+	if err != nil {
+		return nil, err
+	}
+	components := newComponentRegistry(zapLevel)
+	stats := newLogStats(config.Stats)
+	built, err := buildOutputs(config, config.FieldProfile, zapLevel, components, async, stats)
+	if err != nil {
+		if async != nil {
+			async.close()
+		}
+		return nil, err
+	}
+	core := newSwapCore(built.core)
+
+	// Create zap logger options. The caller skip accounts for the public
+	// logging method, Logger.log, Logger.write and Logger.writeLocked
+	// sitting between user code and zap.
+	// Fatal entries do not exit inside zap so that they can be written to
+	// every routed sink first; the logx Fatal methods exit afterwards.
+	options := []zap.Option{zap.AddCallerSkip(4), zap.WithFatalHook(noopFatalHook{})}
+	if config.AddCaller {
+		options = append(options, zap.AddCaller())
+	}
+	if config.AddStacktrace {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	zapLogger := zap.New(core, options...)
+
+	var sinks map[string]*zap.Logger
+	var sinkCores map[string]*swapCore
+	if len(built.sinks) > 0 {
+		sinks = make(map[string]*zap.Logger, len(built.sinks))
+		sinkCores = make(map[string]*swapCore, len(built.sinks))
+		for name, sinkCore := range built.sinks {
+			sinkCores[name] = newSwapCore(sinkCore)
+			sinks[name] = zap.New(sinkCores[name], options...)
+		}
+	}
+
+	logger := &Logger{
+		zapLogger: zapLogger,
+		sinks:     sinks,
+		fields:    []Field{},
+		shared: &loggerShared{
+			level:       zapLevel,
+			emitLogger:  zapLogger.WithOptions(zap.WithCaller(false)),
+			processors:  config.Processors,
+			hub:         newSubscriptionHub(),
+			sanitize:    config.SanitizeStrings,
+			eventID:     config.EventID,
+			fingerprint: config.ErrorFingerprint,
+			callerComps: newCallerComponents(config.CallerComponent),
+			fieldKeys:   config.FieldProfile.fieldKeys(),
+			history:     newHistory(config.History),
+			withCache:   newWithCache(config.WithCacheSize),
+			async:       async,
+			pprofLabels: config.PprofLabels,
+			intern:      newInternTable(config.InternKeys, config.InternMaxValues),
+			sequencer:   newSequencer(config.StrictOrdering),
+			addCaller:   config.AddCaller,
+			keyPolicy:   newKeyPolicy(config.KeyPolicy, built.encoderConfig),
+			profile:     config.FieldProfile,
+			core:        core,
+			sinkCores:   sinkCores,
+			files:       built.files,
+			components:  components,
+			spanEvents:  config.SpanEvents,
+			encryption:  encryption,
+			anyBudget:   config.AnyBudget,
+			coverage:    config.Coverage,
+			nonFinite:   config.NonFinite,
+			labelKeys:   newStreamLabelKeys(config.StreamLabels),
+			schedule:    newLevelSchedule(),
+			stats:       stats,
+			exitReport:  newExitReport(config.ExitReport, config),
+		},
+	}
+	logger.shared.logLevel.Store(int32(level))
+	components.global = &logger.shared.logLevel
+	components.emit = logger.emit
+	if len(config.NamedLevels) > 0 {
+		components.setLevels(config.NamedLevels)
+	}
+	logger.shared.verbosity.Store(int32(config.Verbosity))
+	logger.shared.sinkFloor.Store(sinkLevelFloor(config.Sinks))
+	logger.shared.keys.Store(built.keys)
+	for _, p := range config.Processors {
+		if ep, ok := p.(EmittingProcessor); ok {
+			ep.Bind(logger.emit)
+		}
+		if kb, ok := p.(keysBinder); ok {
+			kb.bindKeys(built.keys)
+		}
+	}
+	if async != nil {
+		recovered, err := async.recoverWAL()
+		if err != nil {
+			async.close()
+			return nil, err
+		}
+		if recovered > 0 {
+			logger.emit(&Entry{
+				Time:    time.Now(),
+				Level:   WarnLevel,
+				Message: "Recovered log entries from write-ahead log",
+				Fields:  []Field{Int("recovered_count", recovered), String("wal_path", config.Async.WALPath)},
+			})
+		}
+	}
+	if levelErr != nil {
+		logger.warnInvalidLevel(levelErr)
+	}
+	logger.shared.schedule.set(logger, windows)
+	if config.Banner != nil {
+		logger.writeBanner(config)
 	}
+	return logger, nil
+}
 
-	// Create encoder config
+// newEncoderConfig returns the encoder configuration shared by all outputs.
+// Timestamps and Time fields are rendered in location with layout.
+func newEncoderConfig(location *time.Location, layout string) zapcore.EncoderConfig {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(t.Format(time.RFC3339Nano))
-	}
+	encoderConfig.EncodeTime = timeEncoder(location, layout)
 	encoderConfig.LevelKey = "level"
 	encoderConfig.MessageKey = "message"
 	encoderConfig.CallerKey = "caller"
 	encoderConfig.StacktraceKey = "stacktrace"
 	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	return encoderConfig
+}
+
+// timeEncoder returns the encoder of timestamps for a Config.TimeLayout
+// value, rendering layouts in location.
+func timeEncoder(location *time.Location, layout string) zapcore.TimeEncoder {
+	switch layout {
+	case TimeLayoutEpoch:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendFloat64(float64(t.UnixNano()) / float64(time.Second))
+		}
+	case TimeLayoutEpochMillis:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendInt64(t.UnixMilli())
+		}
+	case TimeLayoutEpochNanos:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendInt64(t.UnixNano())
+		}
+	case "":
+		layout = time.RFC3339Nano
+	}
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.In(location).Format(layout))
+	}
+}
+
+// loadTimeZone resolves a Config.TimeZone value. An empty name selects the
+// host's local timezone.
+func loadTimeZone(name string) (*time.Location, error) {
+	switch name {
+	case "", "Local":
+		return time.Local, nil
+	case "UTC":
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// outputConfig holds the settings shared by every output of a logger: the
+// primary output and each sink.
+type outputConfig struct {
+	encoderConfig zapcore.EncoderConfig
+	strict        bool // Guarantee one line per entry
+	indexInterval int  // Entries between sidecar index records, 0 to disable
+	fs            FileSystem
+	fileLock      FileLockMode
+	rotation      *RotationConfig // Rotation of file outputs, nil to disable
+	location      *time.Location  // Time zone of timestamps and rotation periods
+	keys          *entryKeys      // Envelope written by the outputs, bound to writers decoding it
+	theme         ColorTheme      // Colors of console output on terminals
+	level         zapcore.LevelEnabler
+	async         *asyncQueue  // Queue for asynchronous writes, nil if synchronous
+	stats         *logStats    // Counter of the bytes written, nil if disabled
+	files         *outputFiles // Files opened by the outputs
+}
 
-	// Create core
-	var core zapcore.Core
-	if config.Development {
-		core = zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
-			zapLevel,
-		)
+// keysBinder is implemented by writers that decode the entries written to
+// them, such as APISink, and by processors that build the envelope of
+// entries, such as SchemaValidator, to learn the envelope of the logger
+// they are given to.
+type keysBinder interface {
+	bindKeys(keys *entryKeys)
+}
+
+// builtOutputs are the cores created by buildOutputs.
+type builtOutputs struct {
+	core          zapcore.Core            // Primary output
+	sinks         map[string]zapcore.Core // Sink outputs, keyed by sink name
+	files         *outputFiles            // Files opened by the cores
+	encoderConfig zapcore.EncoderConfig
+	keys          *entryKeys // Envelope written by the cores
+}
+
+// buildOutputs validates the output settings of config and creates the
+// cores of the primary output and of every sink. The entry keys and
+// constant fields follow profile, and the outputs following level enforce
+// the levels of components. If it fails, the files opened so far are
+// closed.
+func buildOutputs(config *Config, profile *FieldProfile, level zap.AtomicLevel, components *componentRegistry, async *asyncQueue, stats *logStats) (*builtOutputs, error) {
+	location, err := loadTimeZone(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig := newEncoderConfig(location, config.TimeLayout)
+	profile.apply(&encoderConfig)
+	if err := config.Keys.apply(&encoderConfig); err != nil {
+		return nil, err
+	}
+	if config.FileLock == FileLockShared && config.IndexInterval > 0 {
+		return nil, fmt.Errorf("IndexInterval cannot be used with FileLockShared")
+	}
+	fsys := config.FileSystem
+	if fsys == nil {
+		fsys = OSFileSystem
+	}
+	if config.Rotation != nil && fsys != OSFileSystem {
+		return nil, fmt.Errorf("Rotation requires the OS FileSystem")
+	}
+	if config.Rotation != nil && config.FileLock == FileLockShared {
+		return nil, fmt.Errorf("Rotation cannot be used with FileLockShared")
+	}
+	outputs := &outputConfig{
+		encoderConfig: encoderConfig,
+		strict:        config.StrictNDJSON,
+		indexInterval: config.IndexInterval,
+		fs:            fsys,
+		fileLock:      config.FileLock,
+		rotation:      config.Rotation,
+		location:      location,
+		keys:          newEntryKeys(encoderConfig, profile, config.TimeLayout, location),
+		theme:         config.colorTheme(),
+		level:         level,
+		async:         async,
+		stats:         stats,
+		files:         &outputFiles{},
+	}
+
+	// Development mode always writes console output to stdout.
+	core, err := outputs.newPrimaryCore(config)
+	if err != nil {
+		outputs.files.close()
+		return nil, err
+	}
+	sinks, err := newSinkCores(config.Sinks, outputs)
+	if err != nil {
+		outputs.files.close()
+		return nil, err
+	}
+	if fields := profile.fields(); fields != nil {
+		core = core.With(fields)
+		for name, sink := range sinks {
+			sinks[name] = sink.With(fields)
+		}
+	}
+	core = newComponentCore(core, components)
+	for _, sc := range config.Sinks {
+		if sc.Level == nil {
+			sinks[sc.Name] = newComponentCore(sinks[sc.Name], components)
+		}
+	}
+	return &builtOutputs{core: core, sinks: sinks, files: outputs.files, encoderConfig: encoderConfig, keys: outputs.keys}, nil
+}
+
+// newPrimaryCore creates the core of the default sink. Development mode
+// writes console output to OutputWriter or stdout; with DevDual it
+// additionally mirrors strict JSON to OutputPath. CLI mode replaces both.
+func (oc *outputConfig) newPrimaryCore(config *Config) (zapcore.Core, error) {
+	if config.CLI != nil {
+		return oc.newCLICore(config)
+	}
+	toStdout := config.OutputWriter == nil && (config.Development || config.OutputPath == "")
+	if !config.Development {
+		if toStdout && config.SplitStdStreams {
+			return oc.newStdStreamsCore(false)
+		}
+		return oc.newCore(DefaultSinkName, false, config.OutputPath, config.OutputWriter)
+	}
+	var console zapcore.Core
+	var err error
+	if toStdout && config.SplitStdStreams {
+		console, err = oc.newStdStreamsCore(true)
 	} else {
-		// Production configuration
-		var output zapcore.WriteSyncer
-		if config.OutputPath != "" {
-			file, err := os.OpenFile(config.OutputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err != nil {
-				return nil, fmt.Errorf("failed to open log file: %w", err)
-			}
-			output = zapcore.AddSync(file)
-		} else {
-			output = zapcore.AddSync(os.Stdout)
+		console, err = oc.newCore(DefaultSinkName, true, "", config.OutputWriter)
+	}
+	if err != nil || !config.DevDual {
+		return console, err
+	}
+	if config.OutputPath == "" {
+		return nil, fmt.Errorf("DevDual requires OutputPath")
+	}
+	mirror := *oc
+	mirror.strict = true
+	file, err := mirror.newCore(DefaultSinkName+".json", false, config.OutputPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return zapcore.NewTee(console, file), nil
+}
+
+// newStdStreamsCore creates a default sink core writing entries below
+// Error to stdout and Error and above to stderr.
+func (oc *outputConfig) newStdStreamsCore(console bool) (zapcore.Core, error) {
+	low, high := *oc, *oc
+	low.level = zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level < zapcore.ErrorLevel && oc.level.Enabled(level)
+	})
+	high.level = zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level >= zapcore.ErrorLevel && oc.level.Enabled(level)
+	})
+	stdout, err := low.newCore(DefaultSinkName, console, "", os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := high.newCore(DefaultSinkName+".stderr", console, "", os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	return zapcore.NewTee(stdout, stderr), nil
+}
+
+// encoder returns the console encoder when console is true and the JSON
+// encoder otherwise. With color, console level names are written in the
+// colors of the theme. In strict mode, the encoder guarantees that every
+// entry is written as exactly one line.
+func (oc *outputConfig) encoder(console, color bool) zapcore.Encoder {
+	encoder := zapcore.NewJSONEncoder(oc.encoderConfig)
+	if console {
+		encoderConfig := oc.encoderConfig
+		if color {
+			encoderConfig.EncodeLevel = themeLevelEncoder(oc.theme)
+			encoderConfig.EncodeCaller = dimCallerEncoder(encoderConfig.EncodeCaller)
 		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+	if oc.strict {
+		encoder = newStrictEncoder(encoder, oc.encoderConfig)
+	}
+	return encoder
+}
 
-		core = zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
-			output,
-			zapLevel,
-		)
+// newCore creates a core writing to writer if set, otherwise to the file at
+// outputPath, otherwise to stdout, or the browser console under js/wasm.
+// The name identifies the output across restarts for asynchronous
+// write-ahead log recovery.
+func (oc *outputConfig) newCore(name string, console bool, outputPath string, writer io.Writer) (zapcore.Core, error) {
+	var output zapcore.WriteSyncer
+	color := false
+	switch {
+	case writer != nil:
+		if kb, ok := writer.(keysBinder); ok {
+			kb.bindKeys(oc.keys)
+		}
+		output = zapcore.AddSync(writer)
+		color = console && useColor(oc.theme, writer)
+	case outputPath != "" && oc.rotation != nil:
+		rotating, err := newRotatingWriter(oc, outputPath, *oc.rotation)
+		if err != nil {
+			return nil, err
+		}
+		oc.files.add(rotating.Close)
+		output = rotating
+	case outputPath != "":
+		file, closeFile, _, err := oc.openFile(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		oc.files.add(closeFile)
+		output = file
+	default:
+		output = newStdout(oc.encoderConfig)
+		// The browser console shows JSON entries as objects, not text
+		console = console && !browserConsole
+		color = console && useColor(oc.theme, os.Stdout)
+	}
+	if oc.stats != nil {
+		output = &statsWriteSyncer{WriteSyncer: output, stats: oc.stats}
 	}
+	if oc.async != nil {
+		oc.async.register(name, output)
+		output = &asyncWriteSyncer{queue: oc.async, key: name, output: output}
+	}
+	return zapcore.NewCore(oc.encoder(console, color), output, oc.level), nil
+}
 
-	// Create zap logger options
-	options := []zap.Option{}
-	if config.AddCaller {
-		options = append(options, zap.AddCaller())
+// openFile opens the log file at path for appending, with its lock and
+// sidecar index, and returns the writer for it, a function closing it and
+// its current size.
+func (oc *outputConfig) openFile(path string) (zapcore.WriteSyncer, func() error, int64, error) {
+	flag := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	if oc.fileLock != FileLockNone {
+		// Shared advisory locks need a readable descriptor
+		flag = os.O_APPEND | os.O_CREATE | os.O_RDWR
 	}
-	if config.AddStacktrace {
-		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	file, err := oc.fs.OpenFile(path, flag, 0644)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to open log file: %w", err)
 	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		reclaimLocks()
+		return nil, nil, 0, fmt.Errorf("failed to stat log file: %w", err)
+	}
+	locked, err := lockOutput(file, path, oc.fileLock)
+	if err != nil {
+		file.Close()
+		reclaimLocks()
+		return nil, nil, 0, err
+	}
+	if oc.indexInterval > 0 {
+		indexed, err := newIndexedWriter(oc.fs, locked, path, oc.indexInterval)
+		if err != nil {
+			locked.Close()
+			return nil, nil, 0, err
+		}
+		return indexed, indexed.Close, info.Size(), nil
+	}
+	return zapcore.AddSync(locked), locked.Close, info.Size(), nil
+}
 
-	zapLogger := zap.New(core, options...)
+// noopFatalHook lets zap return from writing a Fatal entry so that logx can
+// write it to every target sink before exiting.
+type noopFatalHook struct{}
 
-	return &Logger{
-		zapLogger: zapLogger,
-		fields:    []Field{},
-	}, nil
+// OnWrite does nothing; the caller is responsible for exiting.
+func (noopFatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
+
+// log is the single path through which every entry is written. It checks
+// the level, runs the configured processors and hands the result to zap.
+// All public logging methods must call log directly so that the caller
+// skip configured in New points at user code.
+func (l *Logger) log(level Level, msg string, fields []Field) {
+	if l.shared.coverage != nil {
+		// The call site is two frames above log, past the public method.
+		l.shared.coverage.record(2, level, msg)
+	}
+	if !l.enabled(level) {
+		return
+	}
+
+	l.mu.RLock()
+	allFields := make([]Field, 0, len(l.fields)+len(fields))
+	allFields = append(allFields, l.fields...)
+	l.mu.RUnlock()
+	allFields = append(allFields, fields...)
+	if l.shared.intern != nil {
+		l.shared.intern.internFields(allFields[len(allFields)-len(fields):])
+	}
+	if l.shared.callerComps != nil && !hasKey(allFields, KeyComponent) {
+		// The call site is two frames above log, past the public method.
+		allFields = append(allFields, Component(l.shared.callerComps.lookup(2)))
+	}
+	if l.shared.fingerprint && level >= ErrorLevel {
+		// The call site is two frames above log, past the public method.
+		allFields = append(allFields, String(FingerprintKey, errorFingerprint(msg, allFields, 2)))
+	}
+
+	var sampler Processor
+	if component := l.shared.components.lookup(l.name); component != nil {
+		sampler = component.sampler
+	}
+	var entry *Entry
+	if len(l.shared.processors) > 0 || sampler != nil || l.shared.hub.active() || l.shared.history != nil {
+		entry = acquireEntry()
+		defer releaseEntry(entry)
+		entry.Time = time.Now()
+		entry.Level = level
+		entry.LoggerName = l.name
+		entry.Message = msg
+		entry.Fields = allFields
+		if l.shared.addCaller {
+			// The call site is two frames above log, past the public method.
+			entry.Caller = zapcore.NewEntryCaller(runtime.Caller(2)).TrimmedPath()
+		}
+		if sampler != nil && !sampler.Process(entry) {
+			return
+		}
+		if !runProcessors(l.shared.processors, entry) {
+			return
+		}
+		level, msg, allFields = entry.Level, entry.Message, entry.Fields
+	}
+
+	if l.budget != nil && !l.checkBudget(level, msg, allFields) {
+		return
+	}
+
+	if l.ctx != nil && l.shared.spanEvents != nil && level >= WarnLevel {
+		l.addSpanEvent(level, msg, allFields)
+	}
+
+	var sinks []string
+	if entry != nil {
+		sinks = entry.Sinks
+	}
+	if l.txn != nil {
+		if level < FatalLevel {
+			l.txn.add(l.targets(sinks), level, msg, allFields, entry, l.name)
+			return
+		}
+		l.txn.Commit()
+	}
+	l.write(l.targets(sinks), level, msg, allFields, entry, l.name)
+}
+
+// enabled reports whether an entry at level can be written to any output:
+// the primary output at the level of the logger's component, or a sink
+// with its own level.
+func (l *Logger) enabled(level Level) bool {
+	zapLevel := toZapLevel(level)
+	if l.zapLogger.Core().Enabled(zapLevel) && l.shared.components.enabled(l.name, zapLevel) {
+		return true
+	}
+	floor := l.shared.sinkFloor.Load()
+	return floor != nil && zapLevel >= *floor
+}
+
+// targets resolves sink names to the zap loggers that write to them.
+// No names, "default" and unknown names all resolve to the primary output,
+// so that entries are never lost to a misconfigured route.
+func (l *Logger) targets(names []string) []*zap.Logger {
+	if len(names) == 0 {
+		return []*zap.Logger{l.zapLogger}
+	}
+	targets := make([]*zap.Logger, 0, len(names))
+	for _, name := range names {
+		zl, ok := l.sinks[name]
+		if !ok {
+			zl = l.zapLogger
+		}
+		duplicate := false
+		for _, t := range targets {
+			if t == zl {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			targets = append(targets, zl)
+		}
+	}
+	return targets
+}
+
+// write hands the entry to each target zap logger. Subscribers are notified
+// and the history is updated once, when the entry is first accepted by a
+// target. The name of the logger selects the masking of its component.
+func (l *Logger) write(targets []*zap.Logger, level Level, msg string, fields []Field, entry *Entry, name string) {
+	// Keep Reload from closing the outputs until the entry is written.
+	l.shared.reload.RLock()
+	defer l.shared.reload.RUnlock()
+	l.writeLocked(targets, level, msg, fields, entry, name, nil)
+}
+
+// writeLocked is write with the reload lock held. If checked is set, the
+// time, caller and stacktrace recorded in it when the entry was logged
+// replace those of the write.
+func (l *Logger) writeLocked(targets []*zap.Logger, level Level, msg string, fields []Field, entry *Entry, name string, checked *zapcore.Entry) {
+	if l.shared.sanitize {
+		msg = sanitizeString(msg)
+	}
+	if seq := l.shared.sequencer; seq != nil {
+		// Hold the sequencer from the timestamp taken by Check until the
+		// entry is handed to every target.
+		seq.mu.Lock()
+		defer seq.mu.Unlock()
+	}
+	var zapFields []zap.Field
+	accepted := false
+	for _, zl := range targets {
+		ce := zl.Check(toZapLevel(level), msg)
+		if ce == nil {
+			continue
+		}
+		if !accepted {
+			accepted = true
+			if l.shared.sequencer != nil {
+				fields = append(fields, Field{Key: SequenceKey, Value: l.shared.sequencer.assign()})
+			}
+			if l.shared.eventID {
+				fields = append(fields, String(EventIDKey, NewEventID()))
+			}
+			if entry != nil {
+				entry.Fields = fields
+			}
+			// Encrypt once, so that every output gets the same ciphertext
+			protected := l.protectFields(fields, name)
+			if entry != nil && (l.shared.hub.active() || l.shared.history != nil) {
+				copied := maskedCopy(entry, protected)
+				if l.shared.hub.active() {
+					l.shared.hub.publish(copied)
+				}
+				if l.shared.history != nil {
+					l.shared.history.record(copied)
+				}
+			}
+			if l.shared.stats != nil {
+				l.shared.stats.record(level, msg)
+			}
+			if l.shared.exitReport != nil && level >= ErrorLevel {
+				l.shared.exitReport.recordError(time.Now())
+			}
+			zapFields = l.convertFields(protected, name)
+		}
+		if checked != nil {
+			ce.Entry.Time, ce.Entry.Caller, ce.Entry.Stack = checked.Time, checked.Caller, checked.Stack
+		}
+		ce.Write(zapFields...)
+	}
+}
+
+// emit writes an entry generated by a processor, such as a summary, without
+// running it through the processor chain. Caller information is omitted
+// because the entry does not originate from a logging call.
+func (l *Logger) emit(e *Entry) {
+	zl := l.shared.emitLogger
+	if e.LoggerName != "" {
+		zl = zl.Named(e.LoggerName)
+	}
+	l.write([]*zap.Logger{zl}, e.Level, e.Message, e.Fields, e, e.LoggerName)
 }
 
-// convertFields converts logx fields to zap fields, applying sensitive data masking
-func (l *Logger) convertFields(fields []Field) []zap.Field {
+// convertFields converts logx fields, protected by protectFields, to zap
+// fields, applying the masking of structured values, including the keys of
+// the named component, the non-finite float policy, the size budget of
+// structured values and the field renames of the configured profile,
+// grouping stream labels, and adding the structured causes of combined
+// errors
+func (l *Logger) convertFields(fields []Field, name string) []zap.Field {
 	zapFields := make([]zap.Field, 0, len(fields))
+	var masked map[string]bool
+	var dropped []string
+	var labels streamLabels
+	if component := l.shared.components.lookup(name); component != nil {
+		masked = component.masked
+	}
 
 	for _, field := range fields {
-		// Apply sensitive data masking
-		maskedValue := maskSensitiveData(field.Key, field.Value)
-		zapFields = append(zapFields, zap.Any(field.Key, maskedValue))
+		maskedValue, keep := l.shared.nonFinite.apply(field.Value)
+		if !keep {
+			dropped = append(dropped, field.Key)
+			continue
+		}
+		if l.shared.labelKeys[field.Key] {
+			labels = labels.add(field.Key, maskedValue)
+			continue
+		}
+		maskedValue = marshalerValue(maskedValue, masked)
+		if l.shared.anyBudget != nil {
+			maskedValue = l.shared.anyBudget.apply(maskedValue)
+		}
+		key := field.Key
+		if l.shared.keyPolicy != nil {
+			key = l.shared.keyPolicy.apply(key)
+		}
+		if renamed, ok := l.shared.fieldKeys[key]; ok {
+			key = renamed
+		}
+		causes := errorCauses(maskedValue, l.shared.sanitize)
+		if l.shared.sanitize {
+			key = sanitizeString(key)
+			zapFields = append(zapFields, zapField(key, sanitizeValue(maskedValue)))
+		} else {
+			zapFields = append(zapFields, zapField(key, maskedValue))
+		}
+		if causes != nil {
+			zapFields = append(zapFields, zap.Any(key+ErrorCausesSuffix, causes))
+		}
+	}
+	if dropped != nil {
+		zapFields = append(zapFields, zap.Strings(NonFiniteKey, dropped))
+	}
+	if labels != nil {
+		zapFields = append([]zap.Field{zap.Object(StreamLabelsKey, labels)}, zapFields...)
 	}
 
 	return zapFields
 }
 
+// protectFields returns a copy of fields with field encryption and
+// sensitive data masking applied, including the keys of the named
+// component.
+func (l *Logger) protectFields(fields []Field, name string) []Field {
+	var masked map[string]bool
+	if component := l.shared.components.lookup(name); component != nil {
+		masked = component.masked
+	}
+	protected := make([]Field, len(fields))
+	for i, field := range fields {
+		protected[i] = Field{Key: field.Key, Value: l.protectValue(field, masked)}
+	}
+	return protected
+}
+
+// protectValue returns the value of field as written: encrypted if its key
+// is designated by Config.Encryption, otherwise masked if its key is
+// sensitive globally or in masked, the keys of the logger's component.
+func (l *Logger) protectValue(field Field, masked map[string]bool) interface{} {
+	if l.shared.encryption.encrypts(field.Key) {
+		return l.shared.encryption.encrypt(field.Value)
+	}
+	if masked != nil && masked[strings.ToLower(field.Key)] {
+		return maskValue(field.Value)
+	}
+	return maskSensitiveData(field.Key, field.Value)
+}
+
 // Trace logs a trace message (most verbose level).
 // Trace messages are typically used for detailed debugging and
 // are usually disabled in production environments.
@@ -223,9 +952,7 @@ func (l *Logger) convertFields(fields []Field) []zap.Field {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Trace(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Debug(msg, zapFields...)
+	l.log(TraceLevel, msg, fields)
 }
 
 // Debug logs a debug message.
@@ -235,9 +962,7 @@ func (l *Logger) Trace(msg string, fields ...Field) {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Debug(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Debug(msg, zapFields...)
+	l.log(DebugLevel, msg, fields)
 }
 
 // Info logs an info message.
@@ -247,9 +972,7 @@ func (l *Logger) Debug(msg string, fields ...Field) {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Info(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Info(msg, zapFields...)
+	l.log(InfoLevel, msg, fields)
 }
 
 // Warn logs a warning message.
@@ -259,9 +982,7 @@ func (l *Logger) Info(msg string, fields ...Field) {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Warn(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Warn(msg, zapFields...)
+	l.log(WarnLevel, msg, fields)
 }
 
 // Error logs an error message.
@@ -271,9 +992,7 @@ func (l *Logger) Warn(msg string, fields ...Field) {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Error(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Error(msg, zapFields...)
+	l.log(ErrorLevel, msg, fields)
 }
 
 // Tracef logs a formatted trace message (most verbose level).
@@ -283,8 +1002,7 @@ func (l *Logger) Error(msg string, fields ...Field) {
 //
 //	logger.Tracef("Processing user %s with ID %d", username, userID)
 func (l *Logger) Tracef(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Trace(msg)
+	l.log(TraceLevel, fmt.Sprintf(format, args...), nil)
 }
 
 // Debugf logs a formatted debug message.
@@ -294,8 +1012,7 @@ func (l *Logger) Tracef(format string, args ...interface{}) {
 //
 //	logger.Debugf("Processing request %s with ID %d", requestType, requestID)
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Debug(msg)
+	l.log(DebugLevel, fmt.Sprintf(format, args...), nil)
 }
 
 // Fatal logs a fatal message and then calls os.Exit(1).
@@ -305,43 +1022,212 @@ func (l *Logger) Debugf(format string, args ...interface{}) {
 // The message and fields are automatically masked for sensitive data
 // based on the field keys.
 func (l *Logger) Fatal(msg string, fields ...Field) {
-	allFields := append(l.fields, fields...)
-	zapFields := l.convertFields(allFields)
-	l.zapLogger.Fatal(msg, zapFields...)
+	l.log(FatalLevel, msg, fields)
+	// Flush queued entries before exiting
+	l.flushForExit()
+	os.Exit(1)
 }
 
 // With creates a new logger instance that includes the specified fields
 // in all subsequent log messages. This is useful for creating contextual
-// loggers that automatically include relevant information.
+// loggers that automatically include relevant information. A field whose
+// key is already inherited replaces the inherited value in place instead of
+// being written twice.
 //
 // The returned logger is thread-safe and can be used concurrently.
-// The original logger is not modified.
+// The original logger is not modified. If Config.WithCacheSize is set,
+// calls with identical scalar fields on the same logger return the same
+// cached child.
 //
 // Example:
 //
 //	userLogger := logger.With(logx.String("user_id", "12345"))
 //	userLogger.Info("User action") // Will include user_id in all messages
+//
+//	// After authentication, replace the anonymous user ID
+//	authLogger := userLogger.With(logx.String("user_id", "67890"))
 func (l *Logger) With(fields ...Field) *Logger {
+	cache := l.shared.withCache
+	if cache == nil {
+		return l.with(fields)
+	}
+	fieldsKey, ok := fieldsCacheKey(fields)
+	if !ok {
+		return l.with(fields)
+	}
+	key := withCacheKey{parent: l, fields: fieldsKey}
+	if child, ok := cache.get(key); ok {
+		return child
+	}
+	return cache.add(key, l.with(fields))
+}
+
+// with creates a child logger with the given fields added, replacing
+// inherited fields with the same key.
+func (l *Logger) with(fields []Field) *Logger {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	newFields := make([]Field, 0, len(l.fields)+len(fields))
+	added := append([]Field(nil), fields...)
+	if l.shared.intern != nil {
+		l.shared.intern.internFields(added)
+	}
+	newFields := make([]Field, 0, len(l.fields)+len(added))
 	newFields = append(newFields, l.fields...)
-	newFields = append(newFields, fields...)
+	for _, field := range added {
+		newFields = setField(newFields, field)
+	}
+
+	return l.child(newFields)
+}
+
+// setField replaces the field with the same key in fields, or appends it.
+func setField(fields []Field, field Field) []Field {
+	for i := range fields {
+		if fields[i].Key == field.Key {
+			fields[i] = field
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// Without creates a child logger without the inherited fields with the
+// given keys, so that request-scoped loggers can drop stale context.
+// The original logger is not modified.
+//
+// Example:
+//
+//	// The session is gone; stop tagging entries with it
+//	logger = logger.Without("session_id", "user_id")
+func (l *Logger) Without(keys ...string) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	newFields := make([]Field, 0, len(l.fields))
+	for _, field := range l.fields {
+		removed := false
+		for _, key := range keys {
+			if field.Key == key {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			newFields = append(newFields, field)
+		}
+	}
+	return l.child(newFields)
+}
 
+// Fields returns a copy of the context fields the logger adds to every
+// entry, in order. Values are returned as given to With, before masking.
+//
+// Example:
+//
+//	job := Job{Payload: payload, LogFields: reqLogger.Fields()}
+func (l *Logger) Fields() []Field {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Field(nil), l.fields...)
+}
+
+// WithFieldsFrom creates a child logger with the context fields of other
+// added, as With does, so that a request logger can be reconstructed on a
+// logger with different outputs, such as the base logger of a worker pool.
+// The name and outputs of l are kept.
+//
+// Example:
+//
+//	func (p *Pool) Submit(reqLogger *logx.Logger, job func(*logx.Logger)) {
+//	    p.queue <- func() { job(p.logger.WithFieldsFrom(reqLogger)) }
+//	}
+func (l *Logger) WithFieldsFrom(other *Logger) *Logger {
+	if other == nil {
+		return l
+	}
+	return l.With(other.Fields()...)
+}
+
+// child creates a logger sharing l's outputs, name and state with the given
+// fields. l.mu must be held.
+func (l *Logger) child(fields []Field) *Logger {
 	return &Logger{
 		zapLogger: l.zapLogger,
-		fields:    newFields,
+		sinks:     l.sinks,
+		fields:    fields,
+		name:      l.name,
+		shared:    l.shared,
+		budget:    l.budget,
+		ctx:       l.ctx,
+		txn:       l.txn,
 	}
 }
 
+// Named creates a child logger with the given name segment appended to the
+// logger's name, separated by a dot. The name is available to processors
+// through Entry.LoggerName and is written as the "logger" key.
+//
+// Example:
+//
+//	dbLogger := logger.Named("db")
+//	dbLogger.Named("pool").Info("Connection acquired") // logger: "db.pool"
+func (l *Logger) Named(name string) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	fullName := name
+	if l.name != "" && name != "" {
+		fullName = l.name + "." + name
+	} else if name == "" {
+		fullName = l.name
+	}
+
+	return &Logger{
+		zapLogger: l.zapLogger.Named(name),
+		sinks:     namedSinks(l.sinks, name),
+		fields:    append([]Field(nil), l.fields...),
+		name:      fullName,
+		shared:    l.shared,
+		budget:    l.budget,
+		ctx:       l.ctx,
+		txn:       l.txn,
+	}
+}
+
+// SetLevel changes the minimum level of the logger at runtime. The change
+// applies to every output and to every logger sharing the logger's state:
+// its parent and all loggers derived from either through With or Named,
+// including those created before the call.
+//
+// Example:
+//
+//	requestLogger := logger.With(logx.String("request_id", id))
+//	logger.SetLevel(logx.DebugLevel) // requestLogger now logs Debug too
+func (l *Logger) SetLevel(level Level) {
+	l.shared.logLevel.Store(int32(level))
+	l.shared.components.applyLevel()
+}
+
+// GetLevel returns the current minimum level of the logger.
+func (l *Logger) GetLevel() Level {
+	return Level(l.shared.logLevel.Load())
+}
+
 // Sync flushes any buffered log entries.
 // It's important to call this before the application exits
 // to ensure all log messages are written.
 //
-// This method delegates to the underlying zap logger's Sync method.
+// This method delegates to the underlying zap logger's Sync method,
+// and syncs every configured sink.
 func (l *Logger) Sync() error {
-	return l.zapLogger.Sync()
+	err := l.zapLogger.Sync()
+	for _, zl := range l.sinks {
+		if sinkErr := zl.Sync(); sinkErr != nil && err == nil {
+			err = sinkErr
+		}
+	}
+	return err
 }
 
 // Infof logs a formatted info message.
@@ -351,8 +1237,7 @@ func (l *Logger) Sync() error {
 //
 //	logger.Infof("User %s logged in from %s", username, ipAddress)
 func (l *Logger) Infof(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Info(msg)
+	l.log(InfoLevel, fmt.Sprintf(format, args...), nil)
 }
 
 // Warnf logs a formatted warning message.
@@ -362,8 +1247,7 @@ func (l *Logger) Infof(format string, args ...interface{}) {
 //
 //	logger.Warnf("High memory usage: %d%%", memoryUsage)
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Warn(msg)
+	l.log(WarnLevel, fmt.Sprintf(format, args...), nil)
 }
 
 // Errorf logs a formatted error message.
@@ -373,8 +1257,7 @@ func (l *Logger) Warnf(format string, args ...interface{}) {
 //
 //	logger.Errorf("Failed to connect to database: %v", err)
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Error(msg)
+	l.log(ErrorLevel, fmt.Sprintf(format, args...), nil)
 }
 
 // Fatalf logs a formatted fatal message and then calls os.Exit(1).
@@ -384,6 +1267,8 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 //
 //	logger.Fatalf("Critical configuration error: %s", configError)
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.Fatal(msg)
+	l.log(FatalLevel, fmt.Sprintf(format, args...), nil)
+	// Flush queued entries before exiting
+	l.flushForExit()
+	os.Exit(1)
 }
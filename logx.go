@@ -8,7 +8,10 @@
 package logx
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -76,6 +79,59 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel converts a case-insensitive level name such as "debug" or
+// "WARN" into a Level, e.g. when reading the level from a flag or an
+// environment variable. "warning" is accepted as an alias for WarnLevel.
+//
+// Example:
+//
+//	level, err := logx.ParseLevel(os.Getenv("LOG_LEVEL"))
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "TRACE":
+		return TraceLevel, nil
+	case "DEBUG":
+		return DebugLevel, nil
+	case "INFO":
+		return InfoLevel, nil
+	case "WARN", "WARNING":
+		return WarnLevel, nil
+	case "ERROR":
+		return ErrorLevel, nil
+	case "FATAL":
+		return FatalLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so that levels are written
+// by name in JSON and YAML.
+func (l Level) MarshalText() ([]byte, error) {
+	if l < TraceLevel || l > FatalLevel {
+		return nil, fmt.Errorf("unknown log level %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel, so
+// that levels can be read from flags, JSON and YAML configuration files.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Numeric timestamp formats for Config.TimeLayout.
+const (
+	TimeLayoutEpoch       = "epoch"        // Seconds since the Unix epoch, with a fraction
+	TimeLayoutEpochMillis = "epoch_millis" // Integer milliseconds since the Unix epoch
+	TimeLayoutEpochNanos  = "epoch_nanos"  // Integer nanoseconds since the Unix epoch
+)
+
 // Config holds the configuration for creating a logger instance.
 // All fields are optional and have sensible defaults.
 type Config struct {
@@ -84,17 +140,109 @@ type Config struct {
 	// Default: InfoLevel
 	Level Level
 
+	// DetectLevel replaces Level with the level requested by the command
+	// line or the environment (--log-level, --debug, LOG_LEVEL,
+	// AWS_LAMBDA_LOG_LEVEL or DEBUG), when one is, so that binaries follow
+	// platform conventions without custom glue; see DetectLevel. Level
+	// remains the fallback. Invalid names are reported in a Warn entry.
+	// Reload detects the level again.
+	// Default: false
+	DetectLevel bool
+
+	// NamedLevels sets the minimum level of loggers by their full dotted
+	// name, overriding Level for the named loggers and their descendants,
+	// e.g. {"http": DebugLevel, "db": WarnLevel}. ParseNamedLevels reads
+	// them from a string such as "http=debug,db=warn".
+	// Default: nil
+	NamedLevels map[string]Level
+
+	// Verbosity is the highest verbosity written through Logger.V, in the
+	// style of klog and logr. Verbose entries are Trace entries, so they
+	// also require Level to admit Trace.
+	// Default: 0 (only V(0))
+	Verbosity int
+
+	// LevelSchedule changes the level during recurring windows given by
+	// cron expressions, e.g. Debug during a weekly deployment window, so
+	// that planned verbosity changes need no operator. Level applies
+	// outside the windows; when windows overlap, the most verbose level
+	// wins. Opening and closing a window writes an Info entry, and as with
+	// Boost, a level set with SetLevel during a window is kept when it
+	// closes. Windows are checked at the start of every minute.
+	// Default: nil
+	LevelSchedule []LevelWindow
+
 	// OutputPath specifies the file path for log output.
 	// If empty, logs will be written to stdout.
 	// Default: "" (stdout)
 	OutputPath string
 
+	// OutputWriter, if set, receives the log output instead of OutputPath
+	// or stdout, e.g. a bytes.Buffer in tests, a gzip writer or a pipe to
+	// another process. If it implements Sync() error, Sync is called when
+	// the logger syncs. With Development, console output goes to it; with
+	// DevDual, the JSON mirror still goes to OutputPath.
+	// Default: nil
+	OutputWriter io.Writer
+
+	// SplitStdStreams writes entries below Error to stdout and Error and
+	// Fatal entries to stderr, as container platforms expect. It applies
+	// when the default output goes to stdout, including development
+	// console output.
+	// Default: false
+	SplitStdStreams bool
+
+	// FileSystem opens the files written by OutputPath, SinkConfig.OutputPath
+	// and their sidecar indexes, e.g. a MemFileSystem in tests. The
+	// asynchronous write-ahead file always uses the local disk.
+	// Default: nil (OSFileSystem)
+	FileSystem FileSystem
+
+	// Rotation rotates OutputPath and file sinks by size or time, keeping
+	// a bounded number of optionally compressed backups. It cannot be
+	// combined with a custom FileSystem or FileLockShared.
+	// Default: nil (files grow without bound)
+	Rotation *RotationConfig
+
+	// FileLock coordinates file outputs with other processes: claim each
+	// file exclusively and fail if another process already writes to it, or
+	// share it safely by locking around every entry.
+	// Default: FileLockNone
+	FileLock FileLockMode
+
 	// Development enables development mode with console output
 	// and more verbose formatting. In production, JSON output
 	// is used for better parsing.
 	// Default: false
 	Development bool
 
+	// DevDual, together with Development, mirrors every entry as strict
+	// JSON (see StrictNDJSON) to OutputPath while the console output still
+	// goes to stdout, so that local tooling and tests parsing logs keep
+	// consuming the machine format.
+	// Default: false
+	DevDual bool
+
+	// CLI, if set, switches the primary output to the command-line mode for
+	// tools: entries are written to the terminal as human progress lines
+	// ("Downloaded archive size=1048576", "warning: ..."), while OutputPath,
+	// if set (e.g. from a --log-file flag), receives every entry as strict
+	// JSON. Development, OutputWriter and SplitStdStreams are ignored.
+	// Default: nil
+	CLI *CLIConfig
+
+	// ColorTheme colors level names in console output (Development mode
+	// and development sinks) written to a terminal. NO_COLOR and
+	// FORCE_COLOR are honored; see ColorTheme.
+	// Default: ThemeNone (monochrome)
+	ColorTheme ColorTheme
+
+	// EnableColor colors console output written to a terminal with the
+	// ThemeDark colors when ColorTheme is ThemeNone, as a simple toggle
+	// for the common case. Colored output also dims the caller.
+	// Default: false
+	EnableColor bool
+
 	// AddCaller adds the calling function's file name and line number
 	// to log messages. This is useful for debugging.
 	// Default: true
@@ -104,6 +252,199 @@ type Config struct {
 	// This can be helpful for debugging but increases log size.
 	// Default: true
 	AddStacktrace bool
+
+	// Processors are applied to every entry, in order, before it is written.
+	// A processor may modify the entry or drop it entirely; see Filter for
+	// rule-based dropping and downgrading of entries.
+	// Default: nil (no processing)
+	Processors []Processor
+
+	// Sinks declares additional named outputs that entries can be routed
+	// to with a Router. The output configured by OutputPath and Development
+	// is always available under the name "default".
+	// Default: nil (no additional sinks)
+	Sinks []SinkConfig
+
+	// SanitizeStrings replaces invalid UTF-8 sequences and escapes control
+	// characters and newlines in messages, field keys and string field
+	// values, guaranteeing one line per entry in every encoding, including
+	// development console output, even when logging raw user input.
+	// Default: false
+	SanitizeStrings bool
+
+	// KeyPolicy, if set, rewrites field keys containing dots, spaces or
+	// other punctuation and prefixes keys that collide with envelope keys
+	// such as "message" and "level".
+	// Default: nil (keys are written as given)
+	KeyPolicy *KeyPolicy
+
+	// EventID stamps every entry with a unique, time-sortable ULID in the
+	// "event_id" field. The same ID is written to every sink the entry is
+	// routed to, so it can be used to reference and deduplicate entries.
+	// Default: false
+	EventID bool
+
+	// SpanEvents, if set, adds the Warn, Error and Fatal entries of loggers
+	// bound to a context with WithContext as events to the span active in
+	// that context; see SpanEventFunc.
+	// Default: nil
+	SpanEvents SpanEventFunc
+
+	// Encryption replaces the values of designated keys with ciphertext
+	// that only the holder of a private key can recover, so that they stay
+	// unreadable in the aggregation pipeline but remain available for
+	// incident response; see EncryptionConfig.
+	// Default: nil (no encryption)
+	Encryption *EncryptionConfig
+
+	// AnyBudget summarizes structured field values, such as those passed
+	// to Any, whose JSON exceeds a size budget, optionally storing them
+	// under a reference ID; see AnyBudget.
+	// Default: nil (values are always written in full)
+	AnyBudget *AnyBudget
+
+	// Coverage records the log statements exercised, even at disabled
+	// levels, so that a test run can report logging paths that are never
+	// taken; see Coverage.
+	// Default: nil
+	Coverage *Coverage
+
+	// TimeZone sets the location entry timestamps and Time fields are
+	// rendered in: "UTC", "Local" or an IANA name such as "Europe/Berlin".
+	// Use it where logs must be kept in a specific legal timezone
+	// regardless of the host's configuration.
+	// Default: "" (the host's local timezone)
+	TimeZone string
+
+	// TimeLayout is the format of entry timestamps and Time fields: a
+	// time.Format layout such as time.RFC3339, rendered in TimeZone, or
+	// TimeLayoutEpoch, TimeLayoutEpochMillis or TimeLayoutEpochNanos for
+	// numeric Unix times, as required by the ingestion pipeline.
+	// Default: "" (time.RFC3339Nano)
+	TimeLayout string
+
+	// StrictNDJSON guarantees exactly one line per entry on every output,
+	// so that line-oriented shippers never split an entry. Stacktraces are
+	// rendered as an array of frames and any newline that would otherwise
+	// reach the output, including in development console output, is
+	// escaped.
+	// Default: false
+	StrictNDJSON bool
+
+	// NonFinite selects how NaN and infinite float values, which JSON
+	// cannot represent, are written: as strings, as null, or by dropping
+	// the field and listing its key in a NonFiniteKey field. It applies to
+	// floats at the top level and in slices and maps of floats or
+	// interface values.
+	// Default: NonFiniteString
+	NonFinite NonFinitePolicy
+
+	// ErrorFingerprint adds an "error_fingerprint" field to Error and Fatal
+	// entries: a hash of the error type, the error message with variable
+	// parts such as numbers and IDs removed, and the top stack frames.
+	// Downstream tooling can group identical failures by this field.
+	// Default: false
+	ErrorFingerprint bool
+
+	// CallerComponent, if set, adds a "component" field naming the package
+	// of the logging call, so that entries can be attributed to a package
+	// even when it logs through a shared logger instead of one created with
+	// Component. Entries already carrying a component keep it.
+	// Default: nil (disabled)
+	CallerComponent *CallerComponentConfig
+
+	// FieldProfile renames well-known keys at encode time to follow the
+	// conventions of a log backend: ProfileOTel, ProfileECS, ProfileGCP or
+	// a custom profile loaded with LoadFieldProfile.
+	// Default: nil (logx keys)
+	FieldProfile *FieldProfile
+
+	// StreamLabels designates the keys of fields that are stream labels
+	// rather than content, for Loki/Promtail-style backends. They are
+	// grouped, masked as usual and converted to strings, into one
+	// StreamLabelsKey object written before the other fields with its keys
+	// in sorted order, so that label extraction is cheap and consistent.
+	// Keep them to low-cardinality values such as service or environment.
+	// Default: nil
+	StreamLabels []string
+
+	// Keys overrides the envelope keys of entries, such as "ts", "lvl" and
+	// "msg" instead of "timestamp", "level" and "message", so that output
+	// matches the schema expected by downstream parsers. It takes
+	// precedence over FieldProfile.
+	// Default: zero value (logx keys)
+	Keys EncoderKeys
+
+	// History retains recent entries in memory so that Logger.Snapshot can
+	// return the logs of the last minutes on demand, e.g. for support
+	// bundles.
+	// Default: disabled
+	History HistoryConfig
+
+	// Stats counts the entries written by level and by message template,
+	// and the bytes written, for Logger.Stats.
+	// Default: nil (disabled)
+	Stats *StatsConfig
+
+	// ExitReport, if set, makes Fatal write a final ExitReportMessage entry
+	// before exiting, with the uptime, the Error and Fatal entries written
+	// in total and recently, the entries dropped by the asynchronous queue
+	// and by sinks counting them, and the file the History was written to;
+	// the outputs are then flushed with a bounded timeout.
+	// Default: nil (Fatal flushes without a report or timeout)
+	ExitReport *ExitReportConfig
+
+	// WithCacheSize bounds an LRU cache of child loggers created by With.
+	// When set, calling With repeatedly on the same logger with identical
+	// scalar fields, e.g. in per-request middleware, returns the same
+	// prepared child instead of allocating a new logger every time.
+	// Default: 0 (disabled)
+	WithCacheSize int
+
+	// IndexInterval maintains a sidecar index next to every log file
+	// (OutputPath and file sinks), recording the time and byte offset of
+	// every IndexInterval-th entry in "<path>.idx". The reader package and
+	// the logx command use it to seek to a time range in large files.
+	// Default: 0 (no index)
+	IndexInterval int
+
+	// Async writes entries from a background goroutine through a bounded
+	// queue, with watermark callbacks to shed load before it overflows.
+	// Call Logger.Close or Logger.Sync before exiting to flush the queue.
+	// Default: nil (synchronous)
+	Async *AsyncConfig
+
+	// PprofLabels selects the pprof labels that Logger.WithPprofLabels
+	// attaches as fields.
+	// Default: nil (all labels)
+	PprofLabels []string
+
+	// InternKeys lists field keys whose string values are repetitive, such
+	// as service names, routes or enum strings. Their values are interned
+	// so that retained entries share one copy of each value, and
+	// Logger.InternedString converts byte slices without allocating a new
+	// string.
+	// Default: nil (no interning)
+	InternKeys []string
+
+	// InternMaxValues bounds the number of distinct interned values.
+	// Default: 4096
+	InternMaxValues int
+
+	// StrictOrdering guarantees a single global order of entries across
+	// goroutines: timestamps never go backwards, every output and the
+	// asynchronous queue receive entries in the same order, and each entry
+	// carries a gap-free "seq" number. Consumers that replay logs as an
+	// event stream can rely on it. Writes are serialized, which costs
+	// throughput under heavy concurrent logging.
+	// Default: false
+	StrictOrdering bool
+
+	// Banner, if set, writes a single startup entry summarizing the
+	// application version, effective configuration, sinks and host when
+	// the logger is created.
+	// Default: nil (no banner)
+	Banner *BannerConfig
 }
 
 // DefaultConfig returns a default configuration suitable for most applications.
@@ -182,7 +523,7 @@ func InitDefault() error {
 //	logx.Trace("Processing request", logx.String("request_id", "12345"))
 func Trace(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Trace(msg, fields...)
+		defaultLogger.log(TraceLevel, msg, fields)
 	}
 }
 
@@ -194,7 +535,7 @@ func Trace(msg string, fields ...Field) {
 //	logx.Tracef("Processing user %s with ID %d", username, userID)
 func Tracef(format string, args ...interface{}) {
 	if defaultLogger != nil {
-		defaultLogger.Tracef(format, args...)
+		defaultLogger.log(TraceLevel, fmt.Sprintf(format, args...), nil)
 	}
 }
 
@@ -206,7 +547,7 @@ func Tracef(format string, args ...interface{}) {
 //	logx.Debugf("Processing request %s with ID %d", requestType, requestID)
 func Debugf(format string, args ...interface{}) {
 	if defaultLogger != nil {
-		defaultLogger.Debugf(format, args...)
+		defaultLogger.log(DebugLevel, fmt.Sprintf(format, args...), nil)
 	}
 }
 
@@ -223,7 +564,7 @@ func Debugf(format string, args ...interface{}) {
 //	logx.Debug("Database query executed", logx.Int("rows_affected", 5))
 func Debug(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Debug(msg, fields...)
+		defaultLogger.log(DebugLevel, msg, fields)
 	}
 }
 
@@ -240,7 +581,7 @@ func Debug(msg string, fields ...Field) {
 //	logx.Info("User logged in", logx.String("user_id", "12345"))
 func Info(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Info(msg, fields...)
+		defaultLogger.log(InfoLevel, msg, fields)
 	}
 }
 
@@ -257,7 +598,7 @@ func Info(msg string, fields ...Field) {
 //	logx.Warn("High memory usage detected", logx.Float64("usage_percent", 85.5))
 func Warn(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Warn(msg, fields...)
+		defaultLogger.log(WarnLevel, msg, fields)
 	}
 }
 
@@ -274,7 +615,7 @@ func Warn(msg string, fields ...Field) {
 //	logx.Error("Database connection failed", logx.ErrorField(err))
 func Error(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Error(msg, fields...)
+		defaultLogger.log(ErrorLevel, msg, fields)
 	}
 }
 
@@ -292,10 +633,11 @@ func Error(msg string, fields ...Field) {
 //	logx.Fatal("Critical configuration error", logx.String("config_file", "app.conf"))
 func Fatal(msg string, fields ...Field) {
 	if defaultLogger != nil {
-		defaultLogger.Fatal(msg, fields...)
-	} else {
-		os.Exit(1)
+		defaultLogger.log(FatalLevel, msg, fields)
+		// Flush queued entries before exiting
+		defaultLogger.flushForExit()
 	}
+	os.Exit(1)
 }
 
 // With creates a new logger instance that includes the specified fields
@@ -316,6 +658,45 @@ func With(fields ...Field) *Logger {
 	return nil
 }
 
+// Subscribe returns a subscription to the entries written by the default
+// logger and all loggers derived from it. See Logger.Subscribe for details.
+//
+// If the default logger is not initialized, nil is returned.
+//
+// Example:
+//
+//	sub := logx.Subscribe(nil)
+//	defer sub.Close()
+//	go streamToDebugEndpoint(sub.C)
+func Subscribe(filter func(e *Entry) bool) *Subscription {
+	if defaultLogger != nil {
+		return defaultLogger.Subscribe(filter)
+	}
+	return nil
+}
+
+// SetLevel changes the minimum level of the default logger and of every
+// logger derived from it, including those created before the call.
+// If the default logger is not initialized, this function does nothing.
+//
+// Example:
+//
+//	logx.SetLevel(logx.DebugLevel)
+func SetLevel(level Level) {
+	if defaultLogger != nil {
+		defaultLogger.SetLevel(level)
+	}
+}
+
+// GetLevel returns the current minimum level of the default logger.
+// If the default logger is not initialized, InfoLevel is returned.
+func GetLevel() Level {
+	if defaultLogger != nil {
+		return defaultLogger.GetLevel()
+	}
+	return InfoLevel
+}
+
 // Sync flushes any buffered log entries from the default logger.
 // It's important to call this before the application exits
 // to ensure all log messages are written.
@@ -0,0 +1,250 @@
+package logxintegration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Default images of the container backends.
+const (
+	LokiImage          = "grafana/loki:2.9.4"
+	ElasticsearchImage = "docker.elastic.co/elasticsearch/elasticsearch:8.11.0"
+	KafkaImage         = "apache/kafka:3.7.0"
+)
+
+// httpGet sends a request to a backend and decodes its JSON response into
+// v. A 404 response leaves v unchanged.
+func httpGet(ctx context.Context, method, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s responded %s: %.256s", method, req.URL.Path, resp.Status, body)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
+
+// LokiBackend is a Grafana Loki server in a container. Sinks push to
+// PushURL; Entries reads back the lines matching Query, decoded from JSON.
+type LokiBackend struct {
+	// Query is the LogQL stream selector of Entries.
+	// Default: `{job=~".+"}`
+	Query string
+
+	container *container
+	started   time.Time
+}
+
+// StartLoki starts a Loki server and waits until it is ready. The test is
+// skipped if Docker is not available.
+func StartLoki(tb testing.TB, options ContainerOptions) *LokiBackend {
+	tb.Helper()
+	options = options.resolve(LokiImage)
+	b := &LokiBackend{Query: `{job=~".+"}`, started: time.Now()}
+	b.container = startContainer(tb, options.Image, 3100, 0, nil)
+	b.container.waitReady(tb, options.StartTimeout, func(ctx context.Context) error {
+		return httpGet(ctx, http.MethodGet, b.Endpoint()+"/ready", nil)
+	})
+	return b
+}
+
+// Endpoint returns the base URL of the server.
+func (b *LokiBackend) Endpoint() string {
+	return "http://" + b.container.host
+}
+
+// PushURL returns the URL of the push API.
+func (b *LokiBackend) PushURL() string {
+	return b.Endpoint() + "/loki/api/v1/push"
+}
+
+// Entries returns the lines pushed since the server started that match
+// Query, oldest first. Lines that are not JSON are returned as a
+// "message" field.
+func (b *LokiBackend) Entries(ctx context.Context) ([]map[string]interface{}, error) {
+	query := url.Values{
+		"query":     {b.Query},
+		"start":     {strconv.FormatInt(b.started.Add(-time.Hour).UnixNano(), 10)},
+		"end":       {strconv.FormatInt(time.Now().Add(time.Minute).UnixNano(), 10)},
+		"limit":     {"5000"},
+		"direction": {"forward"},
+	}
+	var response struct {
+		Data struct {
+			Result []struct {
+				Values [][2]string `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := httpGet(ctx, http.MethodGet, b.Endpoint()+"/loki/api/v1/query_range?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+	var entries []map[string]interface{}
+	for _, stream := range response.Data.Result {
+		for _, value := range stream.Values {
+			entry, err := decodeLine([]byte(value[1]))
+			if err != nil {
+				entry = map[string]interface{}{"message": value[1]}
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// ElasticsearchBackend is a single-node Elasticsearch cluster in a
+// container, with security disabled. Entries returns the documents of
+// Index.
+type ElasticsearchBackend struct {
+	// Index is the index, alias or pattern read by Entries.
+	// Default: "*"
+	Index string
+
+	container *container
+}
+
+// StartElasticsearch starts an Elasticsearch node and waits until the
+// cluster is available. The test is skipped if Docker is not available.
+func StartElasticsearch(tb testing.TB, options ContainerOptions) *ElasticsearchBackend {
+	tb.Helper()
+	options = options.resolve(ElasticsearchImage)
+	b := &ElasticsearchBackend{Index: "*"}
+	b.container = startContainer(tb, options.Image, 9200, 0, []string{
+		"discovery.type=single-node",
+		"xpack.security.enabled=false",
+		"ES_JAVA_OPTS=-Xms512m -Xmx512m",
+	})
+	b.container.waitReady(tb, options.StartTimeout, func(ctx context.Context) error {
+		return httpGet(ctx, http.MethodGet, b.Endpoint()+"/_cluster/health?wait_for_status=yellow&timeout=5s", nil)
+	})
+	return b
+}
+
+// Endpoint returns the URL of the node.
+func (b *ElasticsearchBackend) Endpoint() string {
+	return "http://" + b.container.host
+}
+
+// Entries refreshes Index, so that recent writes are visible, and returns
+// up to 10000 of its documents.
+func (b *ElasticsearchBackend) Entries(ctx context.Context) ([]map[string]interface{}, error) {
+	index := url.PathEscape(b.Index)
+	if err := httpGet(ctx, http.MethodPost, b.Endpoint()+"/"+index+"/_refresh", nil); err != nil {
+		return nil, err
+	}
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := httpGet(ctx, http.MethodGet, b.Endpoint()+"/"+index+"/_search?size=10000", &response); err != nil {
+		return nil, err
+	}
+	entries := make([]map[string]interface{}, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		entries = append(entries, hit.Source)
+	}
+	return entries, nil
+}
+
+// KafkaBackend is a single-node Kafka broker in KRaft mode in a container.
+// Producers connect to Endpoint; Entries consumes Topic from the
+// beginning with the console consumer of the image.
+type KafkaBackend struct {
+	// Topic is the topic read by Entries, created when the broker starts.
+	// Default: "logs"
+	Topic string
+
+	container *container
+}
+
+// kafkaInternalAddress is the listener used by the tools run inside the
+// container.
+const kafkaInternalAddress = "localhost:19092"
+
+// StartKafka starts a Kafka broker, creates the "logs" topic and waits
+// until the broker accepts clients. The test is skipped if Docker is not
+// available.
+func StartKafka(tb testing.TB, options ContainerOptions) *KafkaBackend {
+	tb.Helper()
+	options = options.resolve(KafkaImage)
+	port, err := freePort()
+	if err != nil {
+		tb.Fatalf("Failed to find a free port: %v", err)
+	}
+	b := &KafkaBackend{Topic: "logs"}
+	// The broker must advertise the published port to clients outside the
+	// container, and an address of its own to the tools inside it.
+	b.container = startContainer(tb, options.Image, 9092, port, []string{
+		"KAFKA_NODE_ID=1",
+		"KAFKA_PROCESS_ROLES=broker,controller",
+		"KAFKA_LISTENERS=PLAINTEXT://:9092,INTERNAL://:19092,CONTROLLER://:9093",
+		"KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://127.0.0.1:" + strconv.Itoa(port) + ",INTERNAL://" + kafkaInternalAddress,
+		"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP=PLAINTEXT:PLAINTEXT,INTERNAL:PLAINTEXT,CONTROLLER:PLAINTEXT",
+		"KAFKA_INTER_BROKER_LISTENER_NAME=INTERNAL",
+		"KAFKA_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+		"KAFKA_CONTROLLER_QUORUM_VOTERS=1@localhost:9093",
+		"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR=1",
+		"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR=1",
+		"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR=1",
+	})
+	b.container.waitReady(tb, options.StartTimeout, func(ctx context.Context) error {
+		_, err := b.container.exec(ctx, "/opt/kafka/bin/kafka-topics.sh", "--bootstrap-server", kafkaInternalAddress,
+			"--create", "--if-not-exists", "--topic", b.Topic)
+		return err
+	})
+	return b
+}
+
+// Endpoint returns the bootstrap address of the broker.
+func (b *KafkaBackend) Endpoint() string {
+	return b.container.host
+}
+
+// Entries returns the records of Topic, decoded from JSON. Records that
+// are not JSON are returned as a "message" field.
+func (b *KafkaBackend) Entries(ctx context.Context) ([]map[string]interface{}, error) {
+	out, err := b.container.exec(ctx, "/opt/kafka/bin/kafka-console-consumer.sh", "--bootstrap-server", kafkaInternalAddress,
+		"--topic", b.Topic, "--from-beginning", "--timeout-ms", "3000")
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	var entries []map[string]interface{}
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+		entry, err := decodeLine(line)
+		if err != nil {
+			entry = map[string]interface{}{"message": string(line)}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
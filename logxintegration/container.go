@@ -0,0 +1,136 @@
+package logxintegration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DefaultStartTimeout bounds the time a container backend takes to start
+// and become ready, including pulling its image.
+const DefaultStartTimeout = 3 * time.Minute
+
+// ContainerOptions customizes a container backend.
+type ContainerOptions struct {
+	// Image replaces the default image of the backend, e.g. to pin another
+	// version.
+	// Default: the image named by the backend's Start function
+	Image string
+
+	// StartTimeout bounds starting the container and waiting until the
+	// backend is ready.
+	// Default: DefaultStartTimeout
+	StartTimeout time.Duration
+}
+
+// container is a running Docker container, removed when the test ends.
+type container struct {
+	id   string
+	host string // 127.0.0.1:port mapped to the backend's port
+}
+
+// dockerAvailable reports why Docker cannot be used, or nil.
+func dockerAvailable() error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("docker command not found")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{.ServerVersion}}").CombinedOutput(); err != nil {
+		return fmt.Errorf("docker daemon not reachable: %s", bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// freePort returns a TCP port of the loopback interface that is not in
+// use.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// startContainer runs image with port published on a free loopback port
+// and env set, skipping the test if Docker is not available. The container
+// is removed when the test ends. hostPort, if not zero, is the port to
+// publish on instead.
+func startContainer(tb testing.TB, image string, port, hostPort int, env []string, args ...string) *container {
+	tb.Helper()
+	if err := dockerAvailable(); err != nil {
+		tb.Skipf("Skipping container backend %s: %v", image, err)
+	}
+	if hostPort == 0 {
+		var err error
+		if hostPort, err = freePort(); err != nil {
+			tb.Fatalf("Failed to find a free port: %v", err)
+		}
+	}
+	run := []string{"run", "--detach", "--rm", "--publish", fmt.Sprintf("127.0.0.1:%d:%d", hostPort, port)}
+	for _, e := range env {
+		run = append(run, "--env", e)
+	}
+	run = append(run, image)
+	run = append(run, args...)
+	out, err := exec.Command("docker", run...).Output()
+	if err != nil {
+		tb.Fatalf("Failed to start %s: %v", image, commandError(err))
+	}
+	c := &container{id: strings.TrimSpace(string(out)), host: "127.0.0.1:" + strconv.Itoa(hostPort)}
+	tb.Cleanup(func() {
+		exec.Command("docker", "rm", "--force", c.id).Run()
+	})
+	return c
+}
+
+// exec runs a command in the container and returns its standard output.
+func (c *container) exec(ctx context.Context, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, "docker", append([]string{"exec", c.id}, args...)...).Output()
+	if err != nil {
+		return out, commandError(err)
+	}
+	return out, nil
+}
+
+// waitReady calls ready until it succeeds, failing the test with the
+// container's logs if timeout elapses first.
+func (c *container) waitReady(tb testing.TB, timeout time.Duration, ready func(ctx context.Context) error) {
+	tb.Helper()
+	err := poll(timeout, func(ctx context.Context) (bool, error) {
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		err := ready(checkCtx)
+		return err == nil, err
+	})
+	if err != nil {
+		logs, _ := exec.Command("docker", "logs", "--tail", "50", c.id).CombinedOutput()
+		tb.Fatalf("Backend in container %.12s not ready: %v\n%s", c.id, err, logs)
+	}
+}
+
+// commandError adds the standard error of a failed command to err.
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(exitErr.Stderr))
+	}
+	return err
+}
+
+// resolve fills in the defaults of options.
+func (o ContainerOptions) resolve(image string) ContainerOptions {
+	if o.Image == "" {
+		o.Image = image
+	}
+	if o.StartTimeout <= 0 {
+		o.StartTimeout = DefaultStartTimeout
+	}
+	return o
+}
@@ -0,0 +1,247 @@
+// Package logxintegration helps verify end to end that entries written by
+// a logx sink reach their backend. It starts ephemeral backends, either in
+// process (HTTPBackend) or in Docker containers (Loki, Elasticsearch and
+// Kafka), and waits for the expected entries to arrive:
+//
+//	func TestShipping(t *testing.T) {
+//	    backend := logxintegration.NewHTTPBackend(t)
+//	    webhook, _ := logx.NewWebhookSink(logx.WebhookConfig{URL: backend.Endpoint()})
+//	    // ... create a logger writing to webhook and log ...
+//	    logxintegration.AssertDelivered(t, backend, 10*time.Second,
+//	        map[string]interface{}{"message": "Order placed", "order_id": "o-1"})
+//	}
+//
+// Container backends run through the docker command, so that the package
+// has no dependencies; tests using them are skipped when Docker is not
+// available.
+package logxintegration
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Backend is a log backend receiving the entries of a sink under test.
+type Backend interface {
+	// Endpoint returns the address sinks deliver to: a URL, or host:port
+	// for Kafka.
+	Endpoint() string
+
+	// Entries returns the entries received so far, decoded from JSON.
+	Entries(ctx context.Context) ([]map[string]interface{}, error)
+}
+
+// pollInterval is the time between two reads of a backend while waiting.
+const pollInterval = 250 * time.Millisecond
+
+// WaitForEntries waits until backend has received at least n entries and
+// returns them. It fails the test if they do not arrive within timeout.
+func WaitForEntries(tb testing.TB, backend Backend, n int, timeout time.Duration) []map[string]interface{} {
+	tb.Helper()
+	var entries []map[string]interface{}
+	err := poll(timeout, func(ctx context.Context) (bool, error) {
+		var err error
+		entries, err = backend.Entries(ctx)
+		return len(entries) >= n, err
+	})
+	if err != nil {
+		tb.Fatalf("Expected %d entries at %s, got %d: %v", n, backend.Endpoint(), len(entries), err)
+	}
+	return entries
+}
+
+// AssertDelivered waits until backend has received, for every entry in
+// want, an entry containing all of its fields, and fails the test if that
+// does not happen within timeout. Values are compared by their printed
+// form, so that 3 matches the decoded float64(3).
+func AssertDelivered(tb testing.TB, backend Backend, timeout time.Duration, want ...map[string]interface{}) {
+	tb.Helper()
+	var missing []map[string]interface{}
+	err := poll(timeout, func(ctx context.Context) (bool, error) {
+		entries, err := backend.Entries(ctx)
+		missing = missing[:0]
+		for _, w := range want {
+			if !containsEntry(entries, w) {
+				missing = append(missing, w)
+			}
+		}
+		return len(missing) == 0, err
+	})
+	if err != nil {
+		tb.Fatalf("Entries not delivered to %s: %v (missing %v)", backend.Endpoint(), err, missing)
+	}
+}
+
+// containsEntry reports whether an entry of entries has all fields of want.
+func containsEntry(entries []map[string]interface{}, want map[string]interface{}) bool {
+	for _, entry := range entries {
+		matched := true
+		for key, value := range want {
+			got, ok := entry[key]
+			if !ok || fmt.Sprint(got) != fmt.Sprint(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// poll calls check until it returns true or timeout elapses. The last
+// error returned by check is reported on timeout.
+func poll(timeout time.Duration, check func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var lastErr error
+	for {
+		done, err := check(ctx)
+		if done {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return lastErr
+			}
+			return fmt.Errorf("timed out after %s", timeout)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// HTTPBackend is an in-process HTTP endpoint recording the entries posted
+// to it, for sinks such as logx.WebhookSink. Bodies may be a JSON entry, a
+// JSON array of entries or NDJSON, optionally gzipped.
+type HTTPBackend struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	entries  []map[string]interface{}
+	headers  []http.Header
+	failures int // Requests still to reject
+	status   int // Status of rejected requests
+}
+
+// NewHTTPBackend starts an HTTPBackend, closed when the test ends.
+func NewHTTPBackend(tb testing.TB) *HTTPBackend {
+	b := &HTTPBackend{}
+	b.server = httptest.NewServer(http.HandlerFunc(b.serve))
+	tb.Cleanup(b.server.Close)
+	return b
+}
+
+// serve records the entries of a request.
+func (b *HTTPBackend) serve(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	b.headers = append(b.headers, r.Header.Clone())
+	if b.failures > 0 {
+		b.failures--
+		b.mu.Unlock()
+		w.WriteHeader(b.status)
+		return
+	}
+	b.mu.Unlock()
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := decodeEntries(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.mu.Lock()
+	b.entries = append(b.entries, entries...)
+	b.mu.Unlock()
+}
+
+// Endpoint returns the URL of the backend.
+func (b *HTTPBackend) Endpoint() string {
+	return b.server.URL
+}
+
+// Entries returns the entries received so far.
+func (b *HTTPBackend) Entries(context.Context) ([]map[string]interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]map[string]interface{}(nil), b.entries...), nil
+}
+
+// Headers returns the headers of every request received so far, including
+// rejected ones.
+func (b *HTTPBackend) Headers() []http.Header {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]http.Header(nil), b.headers...)
+}
+
+// Fail rejects the next n requests with status, e.g. to check that a sink
+// retries while its endpoint is down.
+func (b *HTTPBackend) Fail(n, status int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures, b.status = n, status
+}
+
+// decodeEntries decodes a JSON entry, a JSON array of entries or NDJSON.
+func decodeEntries(data []byte) ([]map[string]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var entries []map[string]interface{}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+		return entries, nil
+	}
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := decodeLine(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// decodeLine decodes one JSON entry.
+func decodeLine(line []byte) (map[string]interface{}, error) {
+	var entry map[string]interface{}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil, fmt.Errorf("invalid JSON entry %.64q: %w", line, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,269 @@
+package logx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TenantQuota limits how much a single tenant may log. Zero values mean
+// unlimited. Error and Fatal entries are always written but still count
+// towards the quota.
+type TenantQuota struct {
+	// MaxEntries is the maximum number of entries per window.
+	MaxEntries int64
+
+	// MaxBytes is the maximum estimated encoded size of entries per window.
+	MaxBytes int64
+
+	// Window is the period after which usage is reset. If zero, the quota
+	// applies to the lifetime of the manager.
+	Window time.Duration
+}
+
+// TenantUsage reports the logging activity of a tenant.
+type TenantUsage struct {
+	Entries        int64     // Entries written since the manager was created
+	Bytes          int64     // Estimated bytes written since the manager was created
+	DroppedEntries int64     // Entries dropped because the quota was exceeded
+	DroppedBytes   int64     // Estimated bytes dropped because the quota was exceeded
+	WindowEntries  int64     // Entries written in the current window
+	WindowBytes    int64     // Estimated bytes written in the current window
+	WindowStart    time.Time // Start of the current window
+}
+
+// ManagerConfig configures a Manager.
+type ManagerConfig struct {
+	// Base is the configuration each tenant logger is created from.
+	// If nil, DefaultConfig is used.
+	Base *Config
+
+	// TenantConfig optionally returns an isolated configuration for a
+	// tenant, for example to give a tenant its own output file or level.
+	// If it is nil or returns nil, a copy of Base is used.
+	TenantConfig func(tenant string) *Config
+
+	// Quota is applied to every tenant.
+	Quota TenantQuota
+
+	// TenantKey is the field key identifying the tenant on every entry.
+	// Default: "tenant"
+	TenantKey string
+}
+
+// tenantState holds the logger and quota accounting for one tenant.
+type tenantState struct {
+	logger *Logger
+	quota  *quotaProcessor
+}
+
+// Manager creates and caches one logger per tenant, each with its own
+// configuration, quota and usage accounting, so that a single service can
+// enforce fair logging budgets across tenants.
+//
+// A Manager is safe for concurrent use.
+type Manager struct {
+	config ManagerConfig
+
+	mu      sync.Mutex
+	tenants map[string]*tenantState
+}
+
+// NewManager creates a Manager with the given configuration.
+//
+// Example:
+//
+//	manager := logx.NewManager(logx.ManagerConfig{
+//	    Base:  logx.DefaultConfig(),
+//	    Quota: logx.TenantQuota{MaxEntries: 10000, MaxBytes: 10 << 20, Window: time.Hour},
+//	})
+//	logger, err := manager.Logger("acme")
+//	if err != nil {
+//	    return err
+//	}
+//	logger.Info("Invoice generated") // includes "tenant":"acme"
+func NewManager(config ManagerConfig) *Manager {
+	if config.Base == nil {
+		config.Base = DefaultConfig()
+	}
+	if config.TenantKey == "" {
+		config.TenantKey = KeyTenant
+	}
+	return &Manager{
+		config:  config,
+		tenants: make(map[string]*tenantState),
+	}
+}
+
+// Logger returns the logger for the tenant, creating it on first use.
+// An error is returned if the tenant's configuration is invalid.
+func (m *Manager) Logger(tenant string) (*Logger, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state, ok := m.tenants[tenant]; ok {
+		return state.logger, nil
+	}
+
+	var config Config
+	if m.config.TenantConfig != nil {
+		if tc := m.config.TenantConfig(tenant); tc != nil {
+			config = *tc
+		} else {
+			config = *m.config.Base
+		}
+	} else {
+		config = *m.config.Base
+	}
+
+	quota := newQuotaProcessor(m.config.TenantKey, tenant, m.config.Quota)
+	config.Processors = append(append([]Processor{}, config.Processors...), quota)
+
+	logger, err := New(&config)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %q: %w", tenant, err)
+	}
+	logger = logger.With(String(m.config.TenantKey, tenant))
+
+	m.tenants[tenant] = &tenantState{logger: logger, quota: quota}
+	return logger, nil
+}
+
+// Usage returns the usage of a tenant. The second result is false if no
+// logger has been created for the tenant.
+func (m *Manager) Usage(tenant string) (TenantUsage, bool) {
+	m.mu.Lock()
+	state, ok := m.tenants[tenant]
+	m.mu.Unlock()
+	if !ok {
+		return TenantUsage{}, false
+	}
+	return state.quota.usage(), true
+}
+
+// Tenants returns the usage of every tenant with a logger, keyed by tenant.
+func (m *Manager) Tenants() map[string]TenantUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := make(map[string]TenantUsage, len(m.tenants))
+	for tenant, state := range m.tenants {
+		usage[tenant] = state.quota.usage()
+	}
+	return usage
+}
+
+// Sync flushes the loggers of every tenant. The first error encountered is
+// returned after all loggers have been synced.
+func (m *Manager) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var firstErr error
+	for _, state := range m.tenants {
+		if err := state.logger.Sync(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// estimateSize approximates the encoded size of an entry in bytes. It is
+// used for byte quotas, where encoding the entry twice would be too costly.
+func estimateSize(e *Entry) int64 {
+	// Envelope: timestamp, level, caller and punctuation
+	size := int64(96 + len(e.Message) + len(e.LoggerName))
+	for _, field := range e.Fields {
+		size += int64(len(field.Key)) + 6
+		switch v := field.Value.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		case error:
+			if v != nil {
+				size += int64(len(v.Error()))
+			}
+		default:
+			size += int64(len(fmt.Sprint(v)))
+		}
+	}
+	return size
+}
+
+// quotaProcessor enforces a TenantQuota and accounts usage for one tenant.
+type quotaProcessor struct {
+	tenantKey string
+	tenant    string
+	quota     TenantQuota
+
+	mu       sync.Mutex
+	current  TenantUsage
+	notified bool
+	emit     func(e *Entry)
+}
+
+// newQuotaProcessor creates a quota processor for the tenant.
+func newQuotaProcessor(tenantKey, tenant string, quota TenantQuota) *quotaProcessor {
+	return &quotaProcessor{
+		tenantKey: tenantKey,
+		tenant:    tenant,
+		quota:     quota,
+		current:   TenantUsage{WindowStart: time.Now()},
+	}
+}
+
+// Bind sets the function used to write the quota-exceeded notice.
+func (q *quotaProcessor) Bind(emit func(e *Entry)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.emit = emit
+}
+
+// Process accounts the entry and drops it if the tenant is over quota.
+func (q *quotaProcessor) Process(e *Entry) bool {
+	size := estimateSize(e)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.quota.Window > 0 && e.Time.Sub(q.current.WindowStart) >= q.quota.Window {
+		q.current.WindowStart = e.Time
+		q.current.WindowEntries = 0
+		q.current.WindowBytes = 0
+		q.notified = false
+	}
+
+	over := (q.quota.MaxEntries > 0 && q.current.WindowEntries+1 > q.quota.MaxEntries) ||
+		(q.quota.MaxBytes > 0 && q.current.WindowBytes+size > q.quota.MaxBytes)
+	if over && e.Level < ErrorLevel {
+		q.current.DroppedEntries++
+		q.current.DroppedBytes += size
+		if !q.notified && q.emit != nil {
+			q.notified = true
+			q.emit(&Entry{
+				Time:       time.Now(),
+				Level:      WarnLevel,
+				LoggerName: e.LoggerName,
+				Message:    "Tenant log quota exceeded",
+				Fields: []Field{
+					String(q.tenantKey, q.tenant),
+					Int64("window_entries", q.current.WindowEntries),
+					Int64("window_bytes", q.current.WindowBytes),
+				},
+			})
+		}
+		return false
+	}
+
+	q.current.Entries++
+	q.current.Bytes += size
+	q.current.WindowEntries++
+	q.current.WindowBytes += size
+	return true
+}
+
+// usage returns a snapshot of the accounted usage.
+func (q *quotaProcessor) usage() TenantUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current
+}
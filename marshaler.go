@@ -0,0 +1,263 @@
+package logx
+
+import (
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// marshalerValue prepares values encoding themselves for zap, so that they
+// are written through their MarshalLogObject or MarshalLogArray methods
+// rather than by reflection: zapcore.ObjectMarshaler and ArrayMarshaler
+// values, and zapcore.Field values, are wrapped to mask the sensitive keys
+// they produce, globally or in masked, the keys of the logger's component.
+// Other values, and the entries of a transaction, whose fields are masked
+// already, are returned unchanged.
+func marshalerValue(value interface{}, masked map[string]bool) interface{} {
+	switch v := value.(type) {
+	case transactionEntries:
+		return v
+	case zapcore.Field:
+		switch v.Type {
+		case zapcore.ObjectMarshalerType:
+			v.Interface = maskingObject{v.Interface.(zapcore.ObjectMarshaler), masked}
+		case zapcore.ArrayMarshalerType:
+			v.Interface = maskingArray{v.Interface.(zapcore.ArrayMarshaler), masked}
+		}
+		return v
+	case zapcore.ObjectMarshaler:
+		return maskingObject{v, masked}
+	case zapcore.ArrayMarshaler:
+		return maskingArray{v, masked}
+	}
+	return value
+}
+
+// zapField returns the zap field for a value prepared by marshalerValue.
+// The value of a zapcore.Field is written under key.
+func zapField(key string, value interface{}) zap.Field {
+	switch v := value.(type) {
+	case zapcore.Field:
+		v.Key = key
+		return v
+	case maskingObject:
+		return zap.Object(key, v)
+	case maskingArray:
+		return zap.Array(key, v)
+	}
+	return zap.Any(key, value)
+}
+
+// maskingObject is an ObjectMarshaler masking the sensitive keys produced
+// by another.
+type maskingObject struct {
+	zapcore.ObjectMarshaler
+	masked map[string]bool
+}
+
+// MarshalLogObject encodes the wrapped object with sensitive keys masked.
+func (o maskingObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(maskingEncoder{enc, o.masked})
+}
+
+// maskingArray is an ArrayMarshaler masking the sensitive keys of the
+// objects appended by another.
+type maskingArray struct {
+	zapcore.ArrayMarshaler
+	masked map[string]bool
+}
+
+// MarshalLogArray encodes the wrapped array with sensitive keys masked.
+func (a maskingArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(maskingArrayEncoder{enc, a.masked})
+}
+
+// maskingArrayEncoder is an ArrayEncoder wrapping the objects and arrays
+// appended to it in masking marshalers.
+type maskingArrayEncoder struct {
+	zapcore.ArrayEncoder
+	masked map[string]bool
+}
+
+func (e maskingArrayEncoder) AppendObject(v zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(maskingObject{v, e.masked})
+}
+
+func (e maskingArrayEncoder) AppendArray(v zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(maskingArray{v, e.masked})
+}
+
+// maskingEncoder is an ObjectEncoder masking the values of sensitive keys
+// as convertFields masks top-level fields: strings partially, other values
+// entirely. Nested objects and arrays are masked recursively.
+type maskingEncoder struct {
+	zapcore.ObjectEncoder
+	masked map[string]bool
+}
+
+// masks reports whether the value of key must be masked.
+func (e maskingEncoder) masks(key string) bool {
+	return e.masked[strings.ToLower(key)] || isSensitiveKey(key)
+}
+
+// sensitive reports whether the value of key must be masked, and writes
+// the masked value if so.
+func (e maskingEncoder) sensitive(key string) bool {
+	if e.masks(key) {
+		e.ObjectEncoder.AddString(key, maskedValue)
+		return true
+	}
+	return false
+}
+
+func (e maskingEncoder) AddString(key, value string) {
+	if e.masks(key) {
+		value = maskString(value)
+	}
+	e.ObjectEncoder.AddString(key, value)
+}
+
+func (e maskingEncoder) AddByteString(key string, value []byte) {
+	if e.masks(key) {
+		e.ObjectEncoder.AddString(key, maskString(string(value)))
+		return
+	}
+	e.ObjectEncoder.AddByteString(key, value)
+}
+
+func (e maskingEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	if e.sensitive(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, maskingObject{v, e.masked})
+}
+
+func (e maskingEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	if e.sensitive(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, maskingArray{v, e.masked})
+}
+
+func (e maskingEncoder) AddReflected(key string, v interface{}) error {
+	if e.sensitive(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddReflected(key, v)
+}
+
+func (e maskingEncoder) AddBinary(key string, v []byte) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddBinary(key, v)
+	}
+}
+
+func (e maskingEncoder) AddBool(key string, v bool) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddBool(key, v)
+	}
+}
+
+func (e maskingEncoder) AddComplex128(key string, v complex128) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddComplex128(key, v)
+	}
+}
+
+func (e maskingEncoder) AddComplex64(key string, v complex64) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddComplex64(key, v)
+	}
+}
+
+func (e maskingEncoder) AddDuration(key string, v time.Duration) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddDuration(key, v)
+	}
+}
+
+func (e maskingEncoder) AddFloat64(key string, v float64) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddFloat64(key, v)
+	}
+}
+
+func (e maskingEncoder) AddFloat32(key string, v float32) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddFloat32(key, v)
+	}
+}
+
+func (e maskingEncoder) AddInt(key string, v int) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddInt(key, v)
+	}
+}
+
+func (e maskingEncoder) AddInt64(key string, v int64) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddInt64(key, v)
+	}
+}
+
+func (e maskingEncoder) AddInt32(key string, v int32) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddInt32(key, v)
+	}
+}
+
+func (e maskingEncoder) AddInt16(key string, v int16) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddInt16(key, v)
+	}
+}
+
+func (e maskingEncoder) AddInt8(key string, v int8) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddInt8(key, v)
+	}
+}
+
+func (e maskingEncoder) AddTime(key string, v time.Time) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddTime(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUint(key string, v uint) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUint(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUint64(key string, v uint64) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUint64(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUint32(key string, v uint32) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUint32(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUint16(key string, v uint16) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUint16(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUint8(key string, v uint8) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUint8(key, v)
+	}
+}
+
+func (e maskingEncoder) AddUintptr(key string, v uintptr) {
+	if !e.sensitive(key) {
+		e.ObjectEncoder.AddUintptr(key, v)
+	}
+}
@@ -146,7 +146,12 @@ func maskSensitiveData(key string, value interface{}) interface{} {
 	if !isSensitiveKey(key) {
 		return value
 	}
+	return maskValue(value)
+}
 
+// maskValue masks a value regardless of its key, according to its type as
+// described for maskSensitiveData.
+func maskValue(value interface{}) interface{} {
 	switch v := value.(type) {
 	case string:
 		if v == "" {
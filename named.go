@@ -0,0 +1,99 @@
+package logx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseNamedLevels parses a list of per-logger minimum levels in the form
+// "http=debug,db=warn", as accepted by Config.NamedLevels. Names are full
+// dotted logger names, and levels are parsed by ParseLevel. Whitespace
+// around names and levels is ignored and an empty spec yields no levels.
+//
+// Example:
+//
+//	levels, err := logx.ParseNamedLevels(os.Getenv("LOG_LEVELS"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config.NamedLevels = levels
+func ParseNamedLevels(spec string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, levelName, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid named level %q: expected name=level", item)
+		}
+		level, err := ParseLevel(strings.TrimSpace(levelName))
+		if err != nil {
+			return nil, fmt.Errorf("invalid named level %q: %w", item, err)
+		}
+		levels[name] = level
+	}
+	return levels, nil
+}
+
+// SetNamedLevel sets the minimum level of the loggers with the given full
+// dotted name and of their descendants, as the Level of a ComponentConfig
+// does, keeping the rest of the component's configuration.
+//
+// Example:
+//
+//	logger.SetNamedLevel("db", logx.WarnLevel)
+//	logger.Named("db").Named("pool").Info("Connection acquired") // skipped
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	l.shared.components.update(func(configs map[string]ComponentConfig) {
+		c := configs[name]
+		c.Level = &level
+		configs[name] = c
+	})
+}
+
+// SetNamedLevels replaces the minimum levels of every named logger with
+// levels: names missing from levels follow their ancestors or the logger's
+// level again. It is how Config.NamedLevels is applied by New and Reload.
+//
+// Example:
+//
+//	levels, _ := logx.ParseNamedLevels("http=debug,db=warn")
+//	logger.SetNamedLevels(levels)
+func (l *Logger) SetNamedLevels(levels map[string]Level) {
+	l.shared.components.setLevels(levels)
+}
+
+// Named returns the default logger's child with the given name, creating
+// it on first use. Every call with the same name returns the same logger,
+// so packages can keep their loggers in package variables or look them up
+// where needed. Its level can be set with SetNamedLevel or
+// Config.NamedLevels. If the default logger is not initialized, nil is
+// returned.
+//
+// Example:
+//
+//	var log = logx.Named("http")
+//	log.Debug("Dialing", logx.String("host", host))
+func Named(name string) *Logger {
+	if defaultLogger == nil {
+		return nil
+	}
+	loggers := &defaultLogger.shared.components.loggers
+	if logger, ok := loggers.Load(name); ok {
+		return logger.(*Logger)
+	}
+	logger, _ := loggers.LoadOrStore(name, defaultLogger.Named(name))
+	return logger.(*Logger)
+}
+
+// SetNamedLevel sets the minimum level of the default logger's loggers
+// with the given name; see Logger.SetNamedLevel. If the default logger is
+// not initialized, this function does nothing.
+func SetNamedLevel(name string, level Level) {
+	if defaultLogger != nil {
+		defaultLogger.SetNamedLevel(name, level)
+	}
+}
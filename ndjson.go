@@ -0,0 +1,81 @@
+package logx
+
+import (
+	"bytes"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// strictEncoder wraps an encoder so that every entry occupies exactly one
+// line. Stacktraces become an array field of frames, and any carriage
+// return or newline left in the encoded entry is escaped.
+type strictEncoder struct {
+	zapcore.Encoder
+	stackKey   string
+	lineEnding string
+}
+
+// newStrictEncoder wraps encoder, which was created from encoderConfig.
+func newStrictEncoder(encoder zapcore.Encoder, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	lineEnding := encoderConfig.LineEnding
+	if lineEnding == "" {
+		lineEnding = zapcore.DefaultLineEnding
+	}
+	return &strictEncoder{Encoder: encoder, stackKey: encoderConfig.StacktraceKey, lineEnding: lineEnding}
+}
+
+// Clone clones the wrapped encoder, keeping the strict framing.
+func (s *strictEncoder) Clone() zapcore.Encoder {
+	return &strictEncoder{Encoder: s.Encoder.Clone(), stackKey: s.stackKey, lineEnding: s.lineEnding}
+}
+
+// EncodeEntry encodes the entry on a single line.
+func (s *strictEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	if ent.Stack != "" && s.stackKey != "" {
+		fields = append(fields[:len(fields):len(fields)], zap.Strings(s.stackKey, stackFrames(ent.Stack)))
+		ent.Stack = ""
+	}
+	buf, err := s.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	body := bytes.TrimSuffix(buf.Bytes(), []byte(s.lineEnding))
+	if bytes.IndexAny(body, "\r\n") < 0 {
+		return buf, nil
+	}
+	escaped := make([]byte, 0, len(body)+8)
+	for _, c := range body {
+		switch c {
+		case '\n':
+			escaped = append(escaped, '\\', 'n')
+		case '\r':
+			escaped = append(escaped, '\\', 'r')
+		default:
+			escaped = append(escaped, c)
+		}
+	}
+	buf.Reset()
+	buf.Write(escaped)
+	buf.AppendString(s.lineEnding)
+	return buf, nil
+}
+
+// stackFrames splits a zap stacktrace, in which each frame is a function
+// line followed by a tab-indented location line, into one string per frame.
+func stackFrames(stack string) []string {
+	lines := strings.Split(stack, "\n")
+	frames := make([]string, 0, len(lines)/2+1)
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t") && len(frames) > 0 {
+			frames[len(frames)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		if line != "" {
+			frames = append(frames, line)
+		}
+	}
+	return frames
+}
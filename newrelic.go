@@ -0,0 +1,79 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// NewRelicEUEndpoint is the New Relic Log API endpoint for accounts in the
+// EU region.
+const NewRelicEUEndpoint = "https://log-api.eu.newrelic.com/log/v1"
+
+// NewRelicConfig configures a sink sending entries to the New Relic Log API.
+type NewRelicConfig struct {
+	// APIKey is a license or ingest key. Required.
+	APIKey string
+
+	// Endpoint is the Log API URL.
+	// Default: "https://log-api.newrelic.com/log/v1" (US region)
+	Endpoint string
+
+	// Attributes renames entry keys to log attributes, e.g. "trace_id" to
+	// "trace.id" for distributed tracing. Keys not listed are sent
+	// unchanged.
+	// Default: nil
+	Attributes map[string]string
+
+	// CommonAttributes are sent once per batch and apply to every entry,
+	// e.g. "service.name" or "hostname".
+	// Default: nil
+	CommonAttributes map[string]interface{}
+
+	// Batch configures batching.
+	Batch BatchConfig
+}
+
+// NewNewRelicSink returns a sink sending entries to the New Relic Log API.
+// The entry's message and timestamp map to the log's message and timestamp,
+// and every other field becomes an attribute.
+//
+// Example:
+//
+//	newRelic, err := logx.NewNewRelicSink(logx.NewRelicConfig{
+//	    APIKey:           os.Getenv("NEW_RELIC_LICENSE_KEY"),
+//	    CommonAttributes: map[string]interface{}{"service.name": "checkout"},
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "newrelic", Writer: newRelic}}
+//	defer newRelic.Close()
+func NewNewRelicSink(config NewRelicConfig) (*APISink, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("new relic sink requires an API key")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://log-api.newrelic.com/log/v1"
+	}
+	headers := map[string]string{"Api-Key": config.APIKey}
+
+	return newAPISink(config.Batch, apiFormat{request: func(batch []apiEntry) (*http.Request, error) {
+		logs := make([]map[string]interface{}, len(batch))
+		for i, e := range batch {
+			entry := e.fields
+			t := e.keys.takeTime(entry)
+			message := entry[e.keys.message]
+			delete(entry, e.keys.message)
+			mapAttributes(entry, config.Attributes)
+			logs[i] = map[string]interface{}{
+				"timestamp":  t.UnixMilli(),
+				"message":    message,
+				"attributes": entry,
+			}
+		}
+		payload := map[string]interface{}{"logs": logs}
+		if len(config.CommonAttributes) > 0 {
+			payload["common"] = map[string]interface{}{"attributes": config.CommonAttributes}
+		}
+		return newJSONRequest(config.Endpoint, []interface{}{payload}, headers)
+	}})
+}
@@ -0,0 +1,158 @@
+package logx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// NonFinitePolicy selects how Config.NonFinite writes NaN and infinite
+// float values, which JSON cannot represent.
+type NonFinitePolicy int
+
+// Non-finite float policies.
+const (
+	// NonFiniteString writes "NaN", "+Inf" and "-Inf" strings.
+	NonFiniteString NonFinitePolicy = iota
+	// NonFiniteNull writes null.
+	NonFiniteNull
+	// NonFiniteDrop drops fields containing non-finite values and lists
+	// their keys in a NonFiniteKey field.
+	NonFiniteDrop
+)
+
+// NonFiniteKey is the key of the field listing the fields dropped by
+// NonFiniteDrop.
+const NonFiniteKey = "non_finite_fields"
+
+// String returns the name of the policy.
+func (p NonFinitePolicy) String() string {
+	switch p {
+	case NonFiniteString:
+		return "string"
+	case NonFiniteNull:
+		return "null"
+	case NonFiniteDrop:
+		return "drop"
+	default:
+		return fmt.Sprintf("NonFinitePolicy(%d)", int(p))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p NonFinitePolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting "string",
+// "null" and "drop".
+func (p *NonFinitePolicy) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "string", "":
+		*p = NonFiniteString
+	case "null":
+		*p = NonFiniteNull
+	case "drop":
+		*p = NonFiniteDrop
+	default:
+		return fmt.Errorf("unknown non-finite policy %q", text)
+	}
+	return nil
+}
+
+// apply returns value with its non-finite floats replaced following the
+// policy, or false if the field must be dropped. Floats are found at the
+// top level and in slices and maps of floats or interface values; other
+// structured values are left to the encoder.
+func (p NonFinitePolicy) apply(value interface{}) (interface{}, bool) {
+	replaced, changed := p.replace(value)
+	if changed && p == NonFiniteDrop {
+		return nil, false
+	}
+	return replaced, true
+}
+
+// replace returns value with its non-finite floats replaced, and whether
+// any was found. Containers are copied before they are changed.
+func (p NonFinitePolicy) replace(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case float64:
+		if isNonFinite(v) {
+			return p.substitute(v), true
+		}
+	case float32:
+		if isNonFinite(float64(v)) {
+			return p.substitute(float64(v)), true
+		}
+	case []float64:
+		for _, f := range v {
+			if isNonFinite(f) {
+				out := make([]interface{}, len(v))
+				for i, f := range v {
+					out[i], _ = p.replace(f)
+				}
+				return out, true
+			}
+		}
+	case []interface{}:
+		var out []interface{}
+		for i, elem := range v {
+			if replaced, changed := p.replace(elem); changed {
+				if out == nil {
+					out = append([]interface{}(nil), v...)
+				}
+				out[i] = replaced
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case map[string]float64:
+		for _, f := range v {
+			if isNonFinite(f) {
+				out := make(map[string]interface{}, len(v))
+				for key, f := range v {
+					out[key], _ = p.replace(f)
+				}
+				return out, true
+			}
+		}
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for key, elem := range v {
+			if replaced, changed := p.replace(elem); changed {
+				if out == nil {
+					out = make(map[string]interface{}, len(v))
+					for k, e := range v {
+						out[k] = e
+					}
+				}
+				out[key] = replaced
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	}
+	return value, false
+}
+
+// isNonFinite reports whether f is NaN or infinite.
+func isNonFinite(f float64) bool {
+	return math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+// substitute returns the value written instead of the non-finite f.
+func (p NonFinitePolicy) substitute(f float64) interface{} {
+	if p == NonFiniteNull {
+		return nil
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f > 0:
+		return "+Inf"
+	default:
+		return "-Inf"
+	}
+}
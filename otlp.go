@@ -0,0 +1,430 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLPProtocol is the transport and encoding of an OTLP sink, named as in
+// the OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
+type OTLPProtocol string
+
+// OTLP protocols.
+const (
+	OTLPHTTPProtobuf OTLPProtocol = "http/protobuf"
+	OTLPHTTPJSON     OTLPProtocol = "http/json"
+	OTLPGRPC         OTLPProtocol = "grpc"
+)
+
+// OTLPConfig configures a sink exporting entries as OpenTelemetry log
+// records.
+type OTLPConfig struct {
+	// Endpoint is the base URL of the OpenTelemetry collector or vendor
+	// endpoint, without the signal path: "/v1/logs" is appended for the
+	// HTTP protocols. Use an https URL for TLS; gRPC over http uses
+	// HTTP/2 without TLS.
+	// Default: "http://localhost:4318", or "http://localhost:4317" for OTLPGRPC
+	Endpoint string
+
+	// Protocol is the transport and encoding of the export requests.
+	// Default: OTLPHTTPProtobuf
+	Protocol OTLPProtocol
+
+	// Headers are sent with every request, e.g. the API key of a vendor.
+	// Default: nil
+	Headers map[string]string
+
+	// Resource holds the attributes of the resource producing the logs,
+	// such as "service.name", "service.version" and
+	// "deployment.environment".
+	// Default: {"service.name": "unknown_service:<executable name>"}
+	Resource map[string]string
+
+	// ScopeName is the name of the instrumentation scope of the records.
+	// Default: "github.com/seasbee/go-logx"
+	ScopeName string
+
+	// Batch configures batching. For OTLPGRPC, a Client set here must
+	// support HTTP/2.
+	Batch BatchConfig
+}
+
+// otlpMaxBytes keeps export requests within the 4 MiB default message
+// limit of the OpenTelemetry collector.
+const otlpMaxBytes = 3 << 20
+
+// otlpGRPCPath is the gRPC method exporting logs.
+const otlpGRPCPath = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+// otlpSeverities maps logx level names to OpenTelemetry severity numbers.
+var otlpSeverities = map[string]int64{
+	"TRACE": 1, "DEBUG": 5, "INFO": 9, "WARN": 13, "ERROR": 17, "FATAL": 21,
+}
+
+// NewOTLPSink returns a sink exporting entries to an OpenTelemetry
+// collector, or any backend accepting OTLP, as log records. The level
+// becomes the severity, the message the body, the timestamp the record
+// time, and the trace and span IDs correlate the record with its trace;
+// the remaining fields become attributes, with nested objects and arrays
+// kept structured. Set Config.FieldProfile to ProfileOTel to name the
+// attributes after the OpenTelemetry semantic conventions.
+//
+// Example:
+//
+//	otlp, err := logx.NewOTLPSink(logx.OTLPConfig{
+//	    Endpoint: "http://otel-collector:4317",
+//	    Protocol: logx.OTLPGRPC,
+//	    Resource: map[string]string{"service.name": "checkout", "deployment.environment": "prod"},
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "otlp", Writer: otlp}}
+//	defer otlp.Close()
+func NewOTLPSink(config OTLPConfig) (*APISink, error) {
+	if config.Protocol == "" {
+		config.Protocol = OTLPHTTPProtobuf
+	}
+	target, contentType := "", ""
+	switch config.Protocol {
+	case OTLPHTTPProtobuf, OTLPHTTPJSON:
+		if config.Endpoint == "" {
+			config.Endpoint = "http://localhost:4318"
+		}
+		target = strings.TrimSuffix(config.Endpoint, "/") + "/v1/logs"
+		contentType = "application/x-protobuf"
+		if config.Protocol == OTLPHTTPJSON {
+			contentType = "application/json"
+		}
+	case OTLPGRPC:
+		if config.Endpoint == "" {
+			config.Endpoint = "http://localhost:4317"
+		}
+		target = strings.TrimSuffix(config.Endpoint, "/") + otlpGRPCPath
+		contentType = "application/grpc"
+		if config.Batch.Client == nil {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.Protocols = new(http.Protocols)
+			transport.Protocols.SetHTTP2(true)
+			transport.Protocols.SetUnencryptedHTTP2(true)
+			config.Batch.Client = &http.Client{Timeout: 10 * time.Second, Transport: transport}
+		}
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", config.Protocol)
+	}
+	if config.Resource == nil {
+		executable, _ := os.Executable()
+		config.Resource = map[string]string{"service.name": "unknown_service:" + filepath.Base(executable)}
+	}
+	if config.ScopeName == "" {
+		config.ScopeName = "github.com/seasbee/go-logx"
+	}
+	resource := make([]otlpKeyValue, 0, len(config.Resource))
+	for key, value := range config.Resource {
+		resource = append(resource, otlpKeyValue{key, value})
+	}
+	sort.Slice(resource, func(i, j int) bool { return resource[i].key < resource[j].key })
+
+	format := apiFormat{
+		maxBytes: otlpMaxBytes,
+		request: func(batch []apiEntry) (*http.Request, error) {
+			records := make([]otlpRecord, len(batch))
+			for i, e := range batch {
+				records[i] = newOTLPRecord(e.fields, e.keys)
+			}
+			var body []byte
+			switch config.Protocol {
+			case OTLPHTTPJSON:
+				var err error
+				if body, err = json.Marshal(otlpJSONRequest(resource, config.ScopeName, records)); err != nil {
+					return nil, fmt.Errorf("failed to encode batch: %w", err)
+				}
+			case OTLPGRPC:
+				message := otlpProtobufRequest(resource, config.ScopeName, records)
+				body = binary.BigEndian.AppendUint32([]byte{0}, uint32(len(message)))
+				body = append(body, message...)
+			default:
+				body = otlpProtobufRequest(resource, config.ScopeName, records)
+			}
+			req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", contentType)
+			if config.Protocol == OTLPGRPC {
+				req.Header.Set("TE", "trailers")
+			}
+			for key, value := range config.Headers {
+				req.Header.Set(key, value)
+			}
+			return req, nil
+		},
+	}
+	if config.Protocol == OTLPGRPC {
+		format.response = otlpGRPCStatus
+	}
+	return newAPISink(config.Batch, format)
+}
+
+// otlpGRPCStatus returns the error reported by the gRPC status of resp, in
+// its trailers or, for trailers-only responses, its headers.
+func otlpGRPCStatus(resp *http.Response, body []byte) error {
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status == "0" {
+		return nil
+	}
+	if status == "" {
+		return fmt.Errorf("gRPC response without status")
+	}
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+	return fmt.Errorf("gRPC status %s: %s", status, message)
+}
+
+// otlpRecord is an OpenTelemetry log record converted from an entry.
+type otlpRecord struct {
+	time         time.Time
+	severity     int64
+	severityText string
+	body         interface{}
+	attributes   []otlpKeyValue
+	traceID      []byte
+	spanID       []byte
+}
+
+// otlpKeyValue is an attribute. Its value is a string, int64, float64,
+// bool, []interface{} of values, []otlpKeyValue, or nil.
+type otlpKeyValue struct {
+	key   string
+	value interface{}
+}
+
+// newOTLPRecord converts the fields of an entry to a log record, taking
+// the level and message from the envelope keys and the trace context from
+// the keys written by default or by ProfileOTel.
+func newOTLPRecord(fields map[string]interface{}, keys *entryKeys) otlpRecord {
+	record := otlpRecord{time: keys.takeTime(fields)}
+	if level, ok := fields[keys.level].(string); ok {
+		record.severityText = level
+		record.severity = otlpSeverities[keys.levelOf(fields)]
+		delete(fields, keys.level)
+	}
+	if message, ok := fields[keys.message]; ok {
+		record.body = otlpValue(message)
+		delete(fields, keys.message)
+	}
+	if id, err := hex.DecodeString(fieldValue(fields, KeyTraceID)); err == nil && len(id) == 16 {
+		record.traceID = id
+		delete(fields, KeyTraceID)
+	}
+	if id, err := hex.DecodeString(fieldValue(fields, KeySpanID)); err == nil && len(id) == 8 {
+		record.spanID = id
+		delete(fields, KeySpanID)
+	}
+	record.attributes = otlpValue(fields).([]otlpKeyValue)
+	return record
+}
+
+// otlpValue converts a value decoded from an entry to an attribute value.
+// Map keys are sorted.
+func otlpValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, elem := range v {
+			values[i] = otlpValue(elem)
+		}
+		return values
+	case map[string]interface{}:
+		values := make([]otlpKeyValue, 0, len(v))
+		for key, elem := range v {
+			values = append(values, otlpKeyValue{key, otlpValue(elem)})
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i].key < values[j].key })
+		return values
+	}
+	return value
+}
+
+// otlpJSONRequest returns an ExportLogsServiceRequest in the OTLP JSON
+// encoding.
+func otlpJSONRequest(resource []otlpKeyValue, scope string, records []otlpRecord) map[string]interface{} {
+	logRecords := make([]map[string]interface{}, len(records))
+	for i, r := range records {
+		timestamp := strconv.FormatInt(r.time.UnixNano(), 10)
+		record := map[string]interface{}{
+			"timeUnixNano":         timestamp,
+			"observedTimeUnixNano": timestamp,
+			"severityNumber":       r.severity,
+			"severityText":         r.severityText,
+			"body":                 otlpJSONValue(r.body),
+			"attributes":           otlpJSONKeyValues(r.attributes),
+		}
+		if r.traceID != nil {
+			record["traceId"] = hex.EncodeToString(r.traceID)
+		}
+		if r.spanID != nil {
+			record["spanId"] = hex.EncodeToString(r.spanID)
+		}
+		logRecords[i] = record
+	}
+	return map[string]interface{}{
+		"resourceLogs": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": otlpJSONKeyValues(resource)},
+			"scopeLogs": []interface{}{map[string]interface{}{
+				"scope":      map[string]interface{}{"name": scope},
+				"logRecords": logRecords,
+			}},
+		}},
+	}
+}
+
+// otlpJSONKeyValues returns attributes in the OTLP JSON encoding.
+func otlpJSONKeyValues(values []otlpKeyValue) []interface{} {
+	encoded := make([]interface{}, len(values))
+	for i, kv := range values {
+		encoded[i] = map[string]interface{}{"key": kv.key, "value": otlpJSONValue(kv.value)}
+	}
+	return encoded
+}
+
+// otlpJSONValue returns an AnyValue in the OTLP JSON encoding, with 64-bit
+// integers as strings.
+func otlpJSONValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, elem := range v {
+			values[i] = otlpJSONValue(elem)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case []otlpKeyValue:
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": otlpJSONKeyValues(v)}}
+	}
+	return map[string]interface{}{}
+}
+
+// otlpProtobufRequest returns an ExportLogsServiceRequest in the protobuf
+// encoding, following opentelemetry/proto/logs/v1/logs.proto.
+func otlpProtobufRequest(resource []otlpKeyValue, scope string, records []otlpRecord) []byte {
+	var scopeLogs []byte
+	scopeLogs = protoBytes(scopeLogs, 1, protoString(nil, 1, scope)) // InstrumentationScope
+	for _, r := range records {
+		var record []byte
+		timestamp := uint64(r.time.UnixNano())
+		record = protoFixed64(record, 1, timestamp)
+		record = protoVarint(record, 2, uint64(r.severity))
+		record = protoString(record, 3, r.severityText)
+		record = protoBytes(record, 5, protoAnyValue(r.body))
+		for _, kv := range r.attributes {
+			record = protoBytes(record, 6, protoKeyValue(kv))
+		}
+		record = protoBytes(record, 9, r.traceID)
+		record = protoBytes(record, 10, r.spanID)
+		record = protoFixed64(record, 11, timestamp)
+		scopeLogs = protoBytes(scopeLogs, 2, record)
+	}
+	var resourceMessage []byte
+	for _, kv := range resource {
+		resourceMessage = protoBytes(resourceMessage, 1, protoKeyValue(kv))
+	}
+	var resourceLogs []byte
+	resourceLogs = protoBytes(resourceLogs, 1, resourceMessage)
+	resourceLogs = protoBytes(resourceLogs, 2, scopeLogs)
+	return protoBytes(nil, 1, resourceLogs)
+}
+
+// protoKeyValue encodes a KeyValue message.
+func protoKeyValue(kv otlpKeyValue) []byte {
+	return protoBytes(protoString(nil, 1, kv.key), 2, protoAnyValue(kv.value))
+}
+
+// protoAnyValue encodes an AnyValue message.
+func protoAnyValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return protoBytes(nil, 1, []byte(v))
+	case bool:
+		b := uint64(0)
+		if v {
+			b = 1
+		}
+		return protoVarint(nil, 2, b)
+	case int64:
+		return protoVarint(nil, 3, uint64(v))
+	case float64:
+		return protoFixed64(nil, 4, math.Float64bits(v))
+	case []interface{}:
+		array := []byte{}
+		for _, elem := range v {
+			array = protoBytes(array, 1, protoAnyValue(elem))
+		}
+		return protoBytes(nil, 5, array)
+	case []otlpKeyValue:
+		list := []byte{}
+		for _, kv := range v {
+			list = protoBytes(list, 1, protoKeyValue(kv))
+		}
+		return protoBytes(nil, 6, list)
+	}
+	return []byte{} // An empty AnyValue
+}
+
+// protoVarint appends a varint field.
+func protoVarint(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+// protoFixed64 appends a 64-bit field.
+func protoFixed64(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|1)
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+// protoString appends a string field, omitted when empty.
+func protoString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return protoBytes(b, field, []byte(s))
+}
+
+// protoBytes appends a length-delimited field, omitted when nil.
+func protoBytes(b []byte, field int, data []byte) []byte {
+	if data == nil {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
@@ -0,0 +1,48 @@
+package logx
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicKey is the field key used by PanicValue.
+const PanicKey = "panic"
+
+// PanicValue creates a field describing a value returned by recover, so that
+// recovered panics appear the same way across services. The field is an
+// object with the following sub-fields:
+//
+//	message: the error message, string, or formatted value
+//	type:    the Go type of the recovered value
+//	stack:   the stack of the goroutine at the time PanicValue is called
+//
+// PanicValue should be called from the deferred function that recovered, so
+// that the stack still contains the frames that panicked.
+//
+// Example:
+//
+//	defer func() {
+//	    if r := recover(); r != nil {
+//	        logx.Error("Recovered from panic", logx.PanicValue(r))
+//	    }
+//	}()
+func PanicValue(recovered interface{}) Field {
+	var message string
+	switch v := recovered.(type) {
+	case nil:
+		message = "nil"
+	case error:
+		message = v.Error()
+	case string:
+		message = v
+	case fmt.Stringer:
+		message = v.String()
+	default:
+		message = fmt.Sprintf("%v", v)
+	}
+	return Field{Key: PanicKey, Value: map[string]string{
+		"message": message,
+		"type":    fmt.Sprintf("%T", recovered),
+		"stack":   string(debug.Stack()),
+	}}
+}
@@ -0,0 +1,81 @@
+package logx
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maskedValue replaces values removed by a field mask.
+const maskedValue = "***MASKED***"
+
+// Payload creates a field holding a copy of a request or response message
+// with the values at the given field mask paths masked, instead of dumping
+// the whole message with Any. Paths are dot-separated field names, e.g.
+// "card.number", and descend through nested messages and repeated fields.
+// Fields whose names are sensitive keys are masked at any depth.
+//
+// The message is converted through its JSON encoding, so generated
+// protobuf structs are addressed by their proto field names. A message that
+// cannot be encoded is logged as the encoding error.
+//
+// Example:
+//
+//	logger.Info("RPC completed",
+//	    logx.String("method", info.FullMethod),
+//	    logx.Payload("request", req, "card.number", "card.cvv", "contacts.phone"),
+//	)
+func Payload(key string, payload interface{}, paths ...string) Field {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Field{Key: key, Value: err.Error()}
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return Field{Key: key, Value: err.Error()}
+	}
+	tree = maskTree(tree)
+	for _, path := range paths {
+		tree = maskPath(tree, strings.Split(path, "."))
+	}
+	return Field{Key: key, Value: tree}
+}
+
+// maskTree masks the values of sensitive keys at every depth of a decoded
+// JSON value.
+func maskTree(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isSensitiveKey(key) {
+				v[key] = maskSensitiveData(key, child)
+			} else {
+				v[key] = maskTree(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = maskTree(child)
+		}
+	}
+	return v
+}
+
+// maskPath masks the value at path within a decoded JSON value. Arrays are
+// traversed element by element without consuming a path segment, as
+// repeated fields are in protobuf field masks.
+func maskPath(v interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return maskedValue
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if child, ok := v[path[0]]; ok {
+			v[path[0]] = maskPath(child, path[1:])
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = maskPath(child, path)
+		}
+	}
+	return v
+}
@@ -0,0 +1,225 @@
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProcessorSpec declares a single stage of a processing pipeline in a
+// configuration file. Type selects the processor; the remaining keys are
+// interpreted according to the type:
+//
+//	filter:    rules
+//	dedup:     window, keys
+//	rewrite:   rewrites
+//	ratelimit: limit, interval, key_field
+//	breaker:   max_rate, sustain, cooldown, interval
+//	adaptive:  base_rate, boost_rate, window, lookback
+//	enrich:    fields
+//	route:     routes
+//	schema:    schema, on_error
+type ProcessorSpec struct {
+	Type string `yaml:"type"`
+
+	// filter
+	Rules []FilterRule `yaml:"rules"`
+
+	// dedup
+	Window time.Duration `yaml:"window"`
+	Keys   []string      `yaml:"keys"`
+
+	// rewrite
+	Rewrites []RewriteRule `yaml:"rewrites"`
+
+	// ratelimit
+	Limit    int           `yaml:"limit"`
+	Interval time.Duration `yaml:"interval"`
+	KeyField string        `yaml:"key_field"`
+
+	// breaker (also uses interval)
+	MaxRate  float64       `yaml:"max_rate"`
+	Sustain  time.Duration `yaml:"sustain"`
+	Cooldown time.Duration `yaml:"cooldown"`
+
+	// adaptive (also uses window)
+	BaseRate  float64 `yaml:"base_rate"`
+	BoostRate float64 `yaml:"boost_rate"`
+	Lookback  int     `yaml:"lookback"`
+
+	// enrich
+	Fields map[string]string `yaml:"fields"`
+
+	// route
+	Routes []RouteRule `yaml:"routes"`
+
+	// schema
+	Schema  string       `yaml:"schema"` // Path to a JSON Schema file
+	OnError SchemaAction `yaml:"on_error"`
+}
+
+// Pipeline is an ordered chain of processors built from configuration.
+// It can be assigned directly to Config.Processors.
+type Pipeline []Processor
+
+// Close stops every processor in the pipeline that holds background
+// resources, such as a Deduplicator or RateLimiter. The first error
+// encountered is returned after all processors have been closed.
+func (p Pipeline) Close() error {
+	var firstErr error
+	for _, processor := range p {
+		if closer, ok := processor.(io.Closer); ok {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// BuildPipeline creates the processors described by specs, in order.
+// If any spec is invalid, processors created so far are closed and an
+// error identifying the offending stage is returned.
+func BuildPipeline(specs []ProcessorSpec) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(specs))
+	for i, spec := range specs {
+		processor, err := buildProcessor(spec)
+		if err != nil {
+			pipeline.Close()
+			return nil, fmt.Errorf("pipeline stage %d (%s): %w", i, spec.Type, err)
+		}
+		pipeline = append(pipeline, processor)
+	}
+	return pipeline, nil
+}
+
+// buildProcessor creates the processor for a single spec.
+func buildProcessor(spec ProcessorSpec) (Processor, error) {
+	switch spec.Type {
+	case "filter":
+		return NewFilter(spec.Rules...)
+	case "route":
+		return NewRouter(spec.Routes...)
+	case "rewrite", "schema":
+		return buildRuleProcessor(spec)
+	case "dedup":
+		return NewDeduplicator(spec.Window, spec.Keys...), nil
+	case "ratelimit":
+		if spec.Limit <= 0 {
+			return nil, fmt.Errorf("limit must be positive")
+		}
+		return NewRateLimiter(spec.Limit, spec.Interval, spec.KeyField), nil
+	case "breaker":
+		if spec.MaxRate <= 0 {
+			return nil, fmt.Errorf("max_rate must be positive")
+		}
+		return NewCircuitBreaker(CircuitBreakerConfig{
+			MaxRate:  spec.MaxRate,
+			Sustain:  spec.Sustain,
+			Cooldown: spec.Cooldown,
+			Interval: spec.Interval,
+		}), nil
+	case "adaptive":
+		return NewAdaptiveSampler(AdaptiveSamplerConfig{
+			BaseRate:  spec.BaseRate,
+			BoostRate: spec.BoostRate,
+			Window:    spec.Window,
+			Lookback:  spec.Lookback,
+		}), nil
+	case "enrich":
+		keys := make([]string, 0, len(spec.Fields))
+		for key := range spec.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]Field, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, String(key, spec.Fields[key]))
+		}
+		return NewEnricher(fields...), nil
+	default:
+		return nil, fmt.Errorf("unknown processor type %q", spec.Type)
+	}
+}
+
+// LoadPipeline reads a pipeline declaration from a YAML (or JSON) file and
+// builds it. The file contains a "processors" list of ProcessorSpec entries.
+//
+// Example file:
+//
+//	processors:
+//	  - type: filter
+//	    rules:
+//	      - fields: {path: /healthz}
+//	  - type: rewrite
+//	    rewrites:
+//	      - pattern: "0x[0-9a-f]+"
+//	        replacement: "0x?"
+//	  - type: dedup
+//	    window: 10s
+//	    keys: [target]
+//	  - type: enrich
+//	    fields: {region: eu-west-1}
+//	  - type: route
+//	    routes:
+//	      - min_level: error
+//	        sinks: [default, alerting]
+//
+// Example:
+//
+//	pipeline, err := logx.LoadPipeline("/etc/app/logging.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer pipeline.Close()
+//	config := logx.DefaultConfig()
+//	config.Processors = pipeline
+func LoadPipeline(path string) (Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline config: %w", err)
+	}
+	var file struct {
+		Processors []ProcessorSpec `yaml:"processors"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline config: %w", err)
+	}
+	return BuildPipeline(file.Processors)
+}
+
+// Enricher is a Processor that adds a fixed set of fields to every entry.
+// Fields already present on the entry are left untouched.
+type Enricher struct {
+	fields []Field
+}
+
+// NewEnricher creates an Enricher that adds the given fields.
+//
+// Example:
+//
+//	enricher := logx.NewEnricher(logx.String("region", "eu-west-1"))
+func NewEnricher(fields ...Field) *Enricher {
+	return &Enricher{fields: fields}
+}
+
+// Process appends the configured fields that the entry does not already have.
+func (en *Enricher) Process(e *Entry) bool {
+	for _, field := range en.fields {
+		present := false
+		for _, existing := range e.Fields {
+			if existing.Key == field.Key {
+				present = true
+				break
+			}
+		}
+		if !present {
+			e.Fields = append(e.Fields, field)
+		}
+	}
+	return true
+}
@@ -0,0 +1,13 @@
+//go:build !logx_minimal
+
+package logx
+
+// buildRuleProcessor creates the processor for a rewrite or schema spec.
+func buildRuleProcessor(spec ProcessorSpec) (Processor, error) {
+	switch spec.Type {
+	case "rewrite":
+		return NewRewriter(spec.Rewrites...)
+	default:
+		return LoadSchemaValidator(spec.Schema, spec.OnError)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build logx_minimal
+
+package logx
+
+import "fmt"
+
+// buildRuleProcessor fails: the rewrite and schema processors are compiled
+// out of logx_minimal builds.
+func buildRuleProcessor(spec ProcessorSpec) (Processor, error) {
+	return nil, fmt.Errorf("processor type %q is not available in logx_minimal builds", spec.Type)
+}
@@ -0,0 +1,102 @@
+package logx
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is the structured representation of a single log entry as it flows
+// through the processing pipeline, before it is encoded and written. It is
+// the common representation used by processors, subscriptions, the history
+// and the reader package.
+//
+// Processors may modify any of the fields in place. Fields contains both the
+// logger's context fields and the fields passed to the logging call, in that
+// order, and has not yet been masked.
+//
+// Entries passed to processors are pooled and reused once the entry has
+// been written. A processor that keeps an entry after Process returns, for
+// example to write it later, must keep a Clone rather than the pointer.
+type Entry struct {
+	Time       time.Time // The time at which the entry was created
+	Level      Level     // The level the entry will be written at
+	LoggerName string    // The dotted name of the logger, if any
+	Message    string    // The log message
+	Caller     string    // Short file:line of the logging call, if Config.AddCaller is set
+	Fields     []Field   // Context and call-site fields
+	Sinks      []string  // Names of the sinks to write to; empty means the default sink
+}
+
+// entryPool recycles the entries built for the processing pipeline.
+var entryPool = sync.Pool{New: func() interface{} { return new(Entry) }}
+
+// acquireEntry returns an empty entry from the pool.
+func acquireEntry() *Entry {
+	return entryPool.Get().(*Entry)
+}
+
+// releaseEntry clears the entry and returns it to the pool.
+func releaseEntry(e *Entry) {
+	*e = Entry{}
+	entryPool.Put(e)
+}
+
+// Clone returns a deep copy of the entry whose Fields and Sinks can be
+// modified and retained independently of the original.
+func (e *Entry) Clone() *Entry {
+	c := *e
+	if e.Fields != nil {
+		c.Fields = append(make([]Field, 0, len(e.Fields)), e.Fields...)
+	}
+	if e.Sinks != nil {
+		c.Sinks = append(make([]string, 0, len(e.Sinks)), e.Sinks...)
+	}
+	return &c
+}
+
+// Processor inspects and optionally modifies log entries before they are
+// written. Processors are run in the order they are configured; returning
+// false from Process drops the entry and stops the chain.
+//
+// Implementations must be safe for concurrent use, as a single processor
+// is shared by a logger and all loggers derived from it.
+type Processor interface {
+	Process(e *Entry) bool
+}
+
+// ProcessorFunc is an adapter that allows an ordinary function to be used
+// as a Processor.
+//
+// Example:
+//
+//	dropHealthChecks := logx.ProcessorFunc(func(e *logx.Entry) bool {
+//	    return e.Message != "health check"
+//	})
+type ProcessorFunc func(e *Entry) bool
+
+// Process calls f(e).
+func (f ProcessorFunc) Process(e *Entry) bool {
+	return f(e)
+}
+
+// EmittingProcessor is implemented by processors that write entries of their
+// own, such as periodic summaries of suppressed entries. New calls Bind with
+// a function that writes an entry directly, bypassing the processor chain.
+//
+// If the same processor is configured on several loggers, entries are
+// emitted through the logger created last.
+type EmittingProcessor interface {
+	Processor
+	Bind(emit func(e *Entry))
+}
+
+// runProcessors runs the entry through each processor in order and reports
+// whether the entry should still be written.
+func runProcessors(processors []Processor, e *Entry) bool {
+	for _, p := range processors {
+		if !p.Process(e) {
+			return false
+		}
+	}
+	return true
+}
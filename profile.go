@@ -0,0 +1,355 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// FieldProfile renames well-known logx keys at encode time, so that one
+// codebase can emit whichever schema a deployment's backend expects.
+// Processors and subscribers always see the original logx keys.
+//
+// Keys maps logx keys to output keys. The entry envelope keys are
+// "timestamp", "level", "message", "logger", "caller" and "stacktrace";
+// any other key renames the field with that key, e.g. "error" or
+// "event_id". Levels maps level names such as "WARN" to the value written
+// instead. Fields are constant fields added to every entry, such as the
+// version of the schema.
+type FieldProfile struct {
+	Name   string            `yaml:"name"`
+	Keys   map[string]string `yaml:"keys"`
+	Levels map[string]string `yaml:"levels"`
+	Fields map[string]string `yaml:"fields"`
+}
+
+// EncoderKeys overrides the envelope keys of entries, e.g. to match the
+// "ts", "lvl" and "msg" keys expected by an existing parser. Empty keys
+// keep their default, or the name given by Config.FieldProfile.
+type EncoderKeys struct {
+	Timestamp  string `yaml:"timestamp"`  // Default: "timestamp"
+	Level      string `yaml:"level"`      // Default: "level"
+	Message    string `yaml:"message"`    // Default: "message"
+	Logger     string `yaml:"logger"`     // Default: "logger"
+	Caller     string `yaml:"caller"`     // Default: "caller"
+	Stacktrace string `yaml:"stacktrace"` // Default: "stacktrace"
+}
+
+// apply sets the non-empty keys in encoderConfig and checks that the
+// resulting envelope keys are distinct.
+func (k EncoderKeys) apply(encoderConfig *zapcore.EncoderConfig) error {
+	for _, key := range []struct {
+		value  string
+		target *string
+	}{
+		{k.Timestamp, &encoderConfig.TimeKey},
+		{k.Level, &encoderConfig.LevelKey},
+		{k.Message, &encoderConfig.MessageKey},
+		{k.Logger, &encoderConfig.NameKey},
+		{k.Caller, &encoderConfig.CallerKey},
+		{k.Stacktrace, &encoderConfig.StacktraceKey},
+	} {
+		if key.value != "" {
+			*key.target = key.value
+		}
+	}
+	seen := make(map[string]bool, 6)
+	for _, key := range []string{
+		encoderConfig.TimeKey, encoderConfig.LevelKey, encoderConfig.MessageKey,
+		encoderConfig.NameKey, encoderConfig.CallerKey, encoderConfig.StacktraceKey,
+	} {
+		if seen[key] {
+			return fmt.Errorf("Keys: duplicate entry key %q", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// entryKeys describes the entry envelope as written by the outputs of a
+// logger, for code that reads entries back or writes them outside of zap,
+// such as the sinks decoding their input.
+type entryKeys struct {
+	time       string            // Key of the timestamp
+	level      string            // Key of the level
+	message    string            // Key of the message
+	logger     string            // Key of the logger name
+	caller     string            // Key of the caller
+	stacktrace string            // Key of the stacktrace
+	levels     map[string]string // Level names written by the profile, keyed by logx name
+	layout     string            // Config.TimeLayout of the timestamp
+	location   *time.Location    // Config.TimeZone of the timestamp
+}
+
+// defaultEntryKeys describes the envelope written with the default
+// configuration. Sinks use it until a logger binds its own.
+var defaultEntryKeys = newEntryKeys(newEncoderConfig(time.Local, ""), nil, "", time.Local)
+
+// newEntryKeys describes the envelope written with encoderConfig, the level
+// names of profile and the timestamp layout and location it was created
+// with.
+func newEntryKeys(encoderConfig zapcore.EncoderConfig, profile *FieldProfile, layout string, location *time.Location) *entryKeys {
+	k := &entryKeys{
+		time:       encoderConfig.TimeKey,
+		level:      encoderConfig.LevelKey,
+		message:    encoderConfig.MessageKey,
+		logger:     encoderConfig.NameKey,
+		caller:     encoderConfig.CallerKey,
+		stacktrace: encoderConfig.StacktraceKey,
+		layout:     layout,
+		location:   location,
+	}
+	if profile != nil {
+		k.levels = profile.Levels
+	}
+	return k
+}
+
+// levelName returns the name level is written as.
+func (k *entryKeys) levelName(level Level) string {
+	name := level.String()
+	if written, ok := k.levels[name]; ok {
+		return written
+	}
+	return name
+}
+
+// levelOf returns the logx name of the level of fields, such as "WARN", or
+// "" if it is missing.
+func (k *entryKeys) levelOf(fields map[string]interface{}) string {
+	written, _ := fields[k.level].(string)
+	for name, value := range k.levels {
+		if value == written {
+			return name
+		}
+	}
+	return written
+}
+
+// encodeTime returns t as written in the layout of the envelope.
+func (k *entryKeys) encodeTime(t time.Time) interface{} {
+	switch k.layout {
+	case TimeLayoutEpoch:
+		return float64(t.UnixNano()) / float64(time.Second)
+	case TimeLayoutEpochMillis:
+		return t.UnixMilli()
+	case TimeLayoutEpochNanos:
+		return t.UnixNano()
+	case "":
+		return t.In(k.location).Format(time.RFC3339Nano)
+	}
+	return t.In(k.location).Format(k.layout)
+}
+
+// takeTime removes the timestamp from fields, an entry decoded with
+// json.Decoder.UseNumber, and returns it, or the current time if it is
+// missing or invalid.
+func (k *entryKeys) takeTime(fields map[string]interface{}) time.Time {
+	value := fields[k.time]
+	delete(fields, k.time)
+	if t, ok := k.decodeTime(value); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// decodeTime decodes a timestamp written in the layout of the envelope.
+func (k *entryKeys) decodeTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		switch k.layout {
+		case TimeLayoutEpoch:
+			f, err := v.Float64()
+			if err != nil {
+				return time.Time{}, false
+			}
+			sec, frac := math.Modf(f)
+			return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3), true
+		case TimeLayoutEpochMillis:
+			ms, err := v.Int64()
+			return time.UnixMilli(ms), err == nil
+		case TimeLayoutEpochNanos:
+			ns, err := v.Int64()
+			return time.Unix(0, ns), err == nil
+		}
+	case string:
+		layout := k.layout
+		switch layout {
+		case "", TimeLayoutEpoch, TimeLayoutEpochMillis, TimeLayoutEpochNanos:
+			layout = time.RFC3339Nano
+		}
+		t, err := time.ParseInLocation(layout, v, k.location)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// ECSVersion is the version of the Elastic Common Schema followed by
+// ProfileECS, written as its "ecs.version" field.
+const ECSVersion = "8.11.0"
+
+// Predefined field convention profiles.
+var (
+	// ProfileOTel follows the OpenTelemetry log data model and semantic
+	// conventions.
+	ProfileOTel = &FieldProfile{
+		Name: "otel",
+		Keys: map[string]string{
+			"level":        "severity_text",
+			"message":      "body",
+			"logger":       "otel.scope.name",
+			"caller":       "code.filepath",
+			"stacktrace":   "exception.stacktrace",
+			"error":        "exception.message",
+			EventIDKey:     "log.record.uid",
+			KeyTraceID:     "trace_id",
+			KeySpanID:      "span_id",
+			FingerprintKey: "exception.fingerprint",
+		},
+	}
+
+	// ProfileECS follows the Elastic Common Schema, so that entries can be
+	// indexed by Elasticsearch without a Logstash transform.
+	ProfileECS = &FieldProfile{
+		Name: "ecs",
+		Keys: map[string]string{
+			"timestamp":  "@timestamp",
+			"level":      "log.level",
+			"logger":     "log.logger",
+			"caller":     "log.origin.file.name",
+			"stacktrace": "error.stack_trace",
+			"error":      "error.message",
+			EventIDKey:   "event.id",
+			KeyTraceID:   "trace.id",
+			KeySpanID:    "span.id",
+		},
+		Fields: map[string]string{
+			"ecs.version": ECSVersion,
+		},
+	}
+
+	// ProfileGCP follows the structured logging conventions of Google
+	// Cloud Logging, including its severity names.
+	ProfileGCP = &FieldProfile{
+		Name: "gcp",
+		Keys: map[string]string{
+			"timestamp":  "time",
+			"level":      "severity",
+			"stacktrace": "stack_trace",
+			EventIDKey:   "logging.googleapis.com/insertId",
+			KeyTraceID:   "logging.googleapis.com/trace",
+			KeySpanID:    "logging.googleapis.com/spanId",
+		},
+		Levels: map[string]string{
+			"WARN":  "WARNING",
+			"FATAL": "CRITICAL",
+		},
+	}
+)
+
+// LoadFieldProfile reads a custom profile from a YAML (or JSON) file.
+//
+// Example file:
+//
+//	name: acme
+//	keys:
+//	  message: msg
+//	  level: lvl
+//	  error: err
+//	levels:
+//	  WARN: WARNING
+//	fields:
+//	  schema: acme/2
+func LoadFieldProfile(path string) (*FieldProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read field profile: %w", err)
+	}
+	var profile FieldProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse field profile: %w", err)
+	}
+	for name := range profile.Levels {
+		if _, err := ParseLevel(name); err != nil {
+			return nil, fmt.Errorf("field profile: %w", err)
+		}
+	}
+	return &profile, nil
+}
+
+// key returns the output key for a logx key.
+func (p *FieldProfile) key(name string) string {
+	if p != nil {
+		if renamed, ok := p.Keys[name]; ok {
+			return renamed
+		}
+	}
+	return name
+}
+
+// apply renames the envelope keys and level names of encoderConfig.
+func (p *FieldProfile) apply(encoderConfig *zapcore.EncoderConfig) {
+	if p == nil {
+		return
+	}
+	encoderConfig.TimeKey = p.key(encoderConfig.TimeKey)
+	encoderConfig.LevelKey = p.key(encoderConfig.LevelKey)
+	encoderConfig.MessageKey = p.key(encoderConfig.MessageKey)
+	encoderConfig.NameKey = p.key(encoderConfig.NameKey)
+	encoderConfig.CallerKey = p.key(encoderConfig.CallerKey)
+	encoderConfig.StacktraceKey = p.key(encoderConfig.StacktraceKey)
+	if len(p.Levels) > 0 {
+		encodeLevel := encoderConfig.EncodeLevel
+		encoderConfig.EncodeLevel = func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+			if name, ok := p.Levels[level.CapitalString()]; ok {
+				enc.AppendString(name)
+				return
+			}
+			encodeLevel(level, enc)
+		}
+	}
+}
+
+// fields returns the constant fields of the profile, sorted by key.
+func (p *FieldProfile) fields() []zapcore.Field {
+	if p == nil || len(p.Fields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]zapcore.Field, len(keys))
+	for i, key := range keys {
+		fields[i] = zap.String(key, p.Fields[key])
+	}
+	return fields
+}
+
+// fieldKeys returns the renames that apply to fields, excluding the
+// envelope keys, or nil if there are none.
+func (p *FieldProfile) fieldKeys() map[string]string {
+	if p == nil {
+		return nil
+	}
+	keys := make(map[string]string, len(p.Keys))
+	for name, renamed := range p.Keys {
+		switch name {
+		case "timestamp", "level", "message", "logger", "caller", "stacktrace":
+			continue
+		}
+		keys[name] = renamed
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys
+}
@@ -0,0 +1,391 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a compiled entry query. Its Match method can be passed directly
+// to Subscribe.
+//
+// Queries combine conditions with AND, OR, NOT and parentheses; keywords
+// are case-insensitive. A condition compares a subject with a value:
+//
+//	level    =, !=, <, <=, >, >=  against a level name, e.g. level>=warn
+//	msg      =, !=, ~             against the message (also "message")
+//	logger   =, !=, ~             against the logger name
+//	fields.K =, !=, <, <=, >, >=, ~  against the value of field K
+//
+// ~ matches a regular expression. Values are bare words or double-quoted
+// strings with Go escapes. Field values are compared by their string form,
+// or numerically when both sides are numbers. A condition on a missing
+// field is false, except for !=.
+//
+// Example:
+//
+//	q, err := logx.ParseQuery(`level>=error AND fields.user_id="42" AND msg~"timeout"`)
+type Query struct {
+	source string
+	root   queryNode
+}
+
+// queryNode is a node of a parsed query.
+type queryNode interface {
+	match(e *Entry) bool
+}
+
+// ParseQuery compiles a query expression.
+func ParseQuery(expr string) (*Query, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+	return &Query{source: expr, root: root}, nil
+}
+
+// MustParseQuery is like ParseQuery but panics if the expression is
+// invalid. It is intended for tests and fixed expressions.
+func MustParseQuery(expr string) *Query {
+	q, err := ParseQuery(expr)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the source expression.
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match reports whether the entry satisfies the query.
+func (q *Query) Match(e *Entry) bool {
+	return q.root.match(e)
+}
+
+// Filter returns the entries that satisfy the query, in order.
+func (q *Query) Filter(entries []Entry) []Entry {
+	var matched []Entry
+	for i := range entries {
+		if q.Match(&entries[i]) {
+			matched = append(matched, entries[i])
+		}
+	}
+	return matched
+}
+
+// Query returns the entries retained by the history configured in
+// Config.History that satisfy the query expression, oldest first. Use it
+// for debug endpoints and test assertions.
+//
+// Example:
+//
+//	timeouts, err := logger.Query(`level>=error AND msg~"timeout"`)
+func (l *Logger) Query(expr string) ([]Entry, error) {
+	q, err := ParseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(l.Snapshot(0)), nil
+}
+
+// Query tokens.
+const (
+	tokenWord = iota
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+// queryToken is a lexical token of a query.
+type queryToken struct {
+	kind int
+	text string
+}
+
+// lexQuery splits a query expression into tokens.
+func lexQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, queryToken{tokenLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, queryToken{tokenRParen, ")"})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			value, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", expr[i:end+1])
+			}
+			tokens = append(tokens, queryToken{tokenString, value})
+			i = end + 1
+		case strings.ContainsRune("=!<>~", rune(c)):
+			op := expr[i : i+1]
+			if i+1 < len(expr) && expr[i+1] == '=' && c != '=' && c != '~' {
+				op = expr[i : i+2]
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!'")
+			}
+			tokens = append(tokens, queryToken{tokenOp, op})
+			i += len(op)
+		default:
+			end := i
+			for end < len(expr) && !isQueryDelimiter(rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, queryToken{tokenWord, expr[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// isQueryDelimiter reports whether r ends a bare word.
+func isQueryDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()"=!<>~`, r)
+}
+
+// queryParser is a recursive descent parser over query tokens.
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// keyword reports whether the next token is the given keyword and
+// consumes it if so.
+func (p *queryParser) keyword(word string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// next consumes and returns the next token.
+func (p *queryParser) next() (queryToken, error) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, fmt.Errorf("unexpected end of query")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.keyword("OR") {
+		var right queryNode
+		right, err = p.parseAnd()
+		left = orNode{left, right}
+	}
+	return left, err
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.keyword("AND") {
+		var right queryNode
+		right, err = p.parseUnary()
+		left = andNode{left, right}
+	}
+	return left, err
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.keyword("NOT") {
+		operand, err := p.parseUnary()
+		return notNode{operand}, err
+	}
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenLParen {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, err := p.next(); err != nil || t.kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *queryParser) parseCondition() (queryNode, error) {
+	subject, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if subject.kind != tokenWord {
+		return nil, fmt.Errorf("expected a subject, got %q", subject.text)
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != tokenOp {
+		return nil, fmt.Errorf("expected an operator after %s, got %q", subject.text, op.text)
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value after %s%s, got %q", subject.text, op.text, value.text)
+	}
+
+	cond := &conditionNode{op: op.text, value: value.text}
+	name := strings.ToLower(subject.text)
+	switch {
+	case name == "level":
+		level, err := ParseLevel(value.text)
+		if err != nil {
+			return nil, err
+		}
+		if op.text == "~" {
+			return nil, fmt.Errorf("operator ~ is not supported for level")
+		}
+		cond.subject = func(e *Entry) (interface{}, bool) { return e.Level, true }
+		cond.level = level
+	case name == "msg" || name == "message":
+		cond.subject = func(e *Entry) (interface{}, bool) { return e.Message, true }
+	case name == "logger":
+		cond.subject = func(e *Entry) (interface{}, bool) { return e.LoggerName, true }
+	case strings.HasPrefix(subject.text, "fields.") && len(subject.text) > len("fields."):
+		key := subject.text[len("fields."):]
+		cond.subject = func(e *Entry) (interface{}, bool) { return entryField(e, key) }
+	default:
+		return nil, fmt.Errorf("unknown subject %q", subject.text)
+	}
+	if op.text == "~" {
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, err
+		}
+		cond.re = re
+	}
+	if number, err := strconv.ParseFloat(value.text, 64); err == nil {
+		cond.number, cond.isNumber = number, true
+	}
+	return cond, nil
+}
+
+// entryField returns the value of the last field with the given key.
+func entryField(e *Entry, key string) (interface{}, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+type andNode struct{ left, right queryNode }
+
+func (n andNode) match(e *Entry) bool { return n.left.match(e) && n.right.match(e) }
+
+type orNode struct{ left, right queryNode }
+
+func (n orNode) match(e *Entry) bool { return n.left.match(e) || n.right.match(e) }
+
+type notNode struct{ operand queryNode }
+
+func (n notNode) match(e *Entry) bool { return !n.operand.match(e) }
+
+// conditionNode compares a subject of the entry with a value.
+type conditionNode struct {
+	subject  func(e *Entry) (interface{}, bool)
+	op       string
+	value    string
+	level    Level
+	re       *regexp.Regexp
+	number   float64
+	isNumber bool
+}
+
+func (c *conditionNode) match(e *Entry) bool {
+	v, ok := c.subject(e)
+	if !ok {
+		return c.op == "!="
+	}
+	if level, ok := v.(Level); ok {
+		return compareOrdered(float64(level), float64(c.level), c.op)
+	}
+	s := fieldString(v)
+	switch c.op {
+	case "~":
+		return c.re.MatchString(s)
+	case "=":
+		return s == c.value
+	case "!=":
+		return s != c.value
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && c.isNumber {
+		return compareOrdered(n, c.number, c.op)
+	}
+	return compareOrdered(float64(strings.Compare(s, c.value)), 0, c.op)
+}
+
+// fieldString returns the string form of a field value.
+func fieldString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case error:
+		if v == nil {
+			return ""
+		}
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compareOrdered applies an ordering operator.
+func compareOrdered(a, b float64, op string) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
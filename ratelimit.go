@@ -0,0 +1,176 @@
+package logx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxRateLimitKeys bounds the number of distinct keys a RateLimiter tracks in
+// a window. Entries for further keys share a single overflow budget.
+const maxRateLimitKeys = 10000
+
+// rateLimitOverflowKey identifies the shared budget used once
+// maxRateLimitKeys is reached.
+const rateLimitOverflowKey = "\x00overflow"
+
+// rateBucket counts the entries seen for one key in the current window.
+type rateBucket struct {
+	allowed    int
+	suppressed int
+	loggerName string
+}
+
+// RateLimiter is a Processor that allows at most a fixed number of entries
+// per interval. When a key field is configured, each distinct value of that
+// field (for example each user_id or endpoint) gets its own budget, so one
+// noisy tenant or route cannot starve the others. Entries without the key
+// field share a single budget.
+//
+// When a window closes, a Warn entry summarizing the suppressed entries is
+// written for each key that exceeded its budget. The summary counts the
+// suppressed entries under SuppressedCountKey and SampleWindowKey; as it is
+// not one of them, it is not marked with SampledKey. Error and Fatal
+// entries are never suppressed.
+//
+// Call Close when the rate limiter is no longer needed to stop its background
+// goroutine and flush pending summaries.
+type RateLimiter struct {
+	limit    int
+	interval time.Duration
+	keyField string
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	emit    func(e *Entry)
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewRateLimiter creates a RateLimiter allowing limit entries per interval.
+// If keyField is non-empty, budgets are tracked per value of that field.
+//
+// Example:
+//
+//	// At most 100 entries per second for each tenant
+//	limiter := logx.NewRateLimiter(100, time.Second, "tenant_id")
+//	defer limiter.Close()
+//	config := logx.DefaultConfig()
+//	config.Processors = []logx.Processor{limiter}
+func NewRateLimiter(limit int, interval time.Duration, keyField string) *RateLimiter {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	r := &RateLimiter{
+		limit:    limit,
+		interval: interval,
+		keyField: keyField,
+		buckets:  make(map[string]*rateBucket),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Bind sets the function used to write suppression summaries.
+func (r *RateLimiter) Bind(emit func(e *Entry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emit = emit
+}
+
+// key returns the budget key for the entry.
+func (r *RateLimiter) key(e *Entry) string {
+	if r.keyField == "" {
+		return ""
+	}
+	for _, field := range e.Fields {
+		if field.Key == r.keyField {
+			return fmt.Sprint(field.Value)
+		}
+	}
+	return ""
+}
+
+// Process suppresses the entry if its key has exhausted the current budget.
+func (r *RateLimiter) Process(e *Entry) bool {
+	if e.Level >= ErrorLevel {
+		return true
+	}
+	key := r.key(e)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= maxRateLimitKeys {
+			key = rateLimitOverflowKey
+			bucket = r.buckets[key]
+		}
+		if bucket == nil {
+			bucket = &rateBucket{loggerName: e.LoggerName}
+			r.buckets[key] = bucket
+		}
+	}
+	if bucket.allowed < r.limit {
+		bucket.allowed++
+		return true
+	}
+	bucket.suppressed++
+	return false
+}
+
+// flushLocked emits suppression summaries and resets all budgets.
+// r.mu must be held.
+func (r *RateLimiter) flushLocked() {
+	for key, bucket := range r.buckets {
+		if bucket.suppressed > 0 && r.emit != nil {
+			fields := []Field{
+				Int(SuppressedCountKey, bucket.suppressed),
+				String(SampleWindowKey, r.interval.String()),
+			}
+			if r.keyField != "" && key != rateLimitOverflowKey {
+				fields = append([]Field{String(r.keyField, key)}, fields...)
+			}
+			r.emit(&Entry{
+				Time:       time.Now(),
+				Level:      WarnLevel,
+				LoggerName: bucket.loggerName,
+				Message:    "Log entries suppressed by rate limiter",
+				Fields:     fields,
+			})
+		}
+		delete(r.buckets, key)
+	}
+}
+
+// run resets budgets at the end of every interval until Close is called.
+func (r *RateLimiter) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.mu.Lock()
+			r.flushLocked()
+			r.mu.Unlock()
+		case <-r.stop:
+			r.mu.Lock()
+			r.flushLocked()
+			r.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Close stops the background goroutine and writes summaries for the current
+// window. It is safe to call Close more than once.
+func (r *RateLimiter) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+	return nil
+}
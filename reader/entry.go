@@ -0,0 +1,193 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// Entry is a decoded log entry. The envelope written by logx is available
+// as struct fields; every other key is in Fields and can be read with the
+// typed accessors.
+type Entry struct {
+	Time       time.Time
+	Level      logx.Level
+	Message    string
+	Logger     string
+	Caller     string
+	Stacktrace string
+	Fields     map[string]interface{} // Numbers are json.Number
+	Raw        []byte                 // The line the entry was decoded from
+}
+
+// parseLevel converts a level name written by logx to a Level.
+func parseLevel(name string) (logx.Level, bool) {
+	switch strings.ToUpper(name) {
+	case "TRACE":
+		return logx.TraceLevel, true
+	case "DEBUG":
+		return logx.DebugLevel, true
+	case "INFO":
+		return logx.InfoLevel, true
+	case "WARN", "WARNING":
+		return logx.WarnLevel, true
+	case "ERROR":
+		return logx.ErrorLevel, true
+	case "FATAL", "DPANIC", "PANIC":
+		return logx.FatalLevel, true
+	default:
+		return logx.InfoLevel, false
+	}
+}
+
+// Parse decodes a single JSON log line.
+func Parse(line []byte) (*Entry, error) {
+	fields, err := decodeJSON(line)
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{Fields: fields, Level: logx.InfoLevel, Raw: append([]byte(nil), line...)}
+	if v, ok := fields["timestamp"]; ok {
+		delete(fields, "timestamp")
+		e.Time = parseTime(v)
+	}
+	if v, ok := fields["level"].(string); ok {
+		delete(fields, "level")
+		e.Level, _ = parseLevel(v)
+	}
+	e.Message = takeString(fields, "message")
+	e.Logger = takeString(fields, "logger")
+	e.Caller = takeString(fields, "caller")
+	switch stack := fields["stacktrace"].(type) {
+	case string:
+		e.Stacktrace = stack
+	case []interface{}:
+		frames := make([]string, len(stack))
+		for i, frame := range stack {
+			frames[i] = fmt.Sprint(frame)
+		}
+		e.Stacktrace = strings.Join(frames, "\n")
+	}
+	delete(fields, "stacktrace")
+	return e, nil
+}
+
+// parseTime accepts RFC 3339 timestamps and epoch seconds.
+func parseTime(v interface{}) time.Time {
+	switch value := v.(type) {
+	case string:
+		t, _ := time.Parse(time.RFC3339Nano, value)
+		return t
+	case json.Number:
+		seconds, err := value.Float64()
+		if err != nil {
+			return time.Time{}
+		}
+		return time.Unix(0, int64(seconds*float64(time.Second)))
+	}
+	return time.Time{}
+}
+
+// takeString removes key from fields and returns its string value.
+func takeString(fields map[string]interface{}, key string) string {
+	v, ok := fields[key]
+	if !ok {
+		return ""
+	}
+	delete(fields, key)
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// Has reports whether the entry has a field with the given key.
+func (e *Entry) Has(key string) bool {
+	_, ok := e.Fields[key]
+	return ok
+}
+
+// Value returns the decoded value of a field.
+func (e *Entry) Value(key string) (interface{}, bool) {
+	v, ok := e.Fields[key]
+	return v, ok
+}
+
+// String returns a string field. Non-string values are formatted.
+func (e *Entry) String(key string) (string, bool) {
+	v, ok := e.Fields[key]
+	if !ok {
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	if n, ok := v.(json.Number); ok {
+		return n.String(), true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v), true
+	}
+	return string(data), true
+}
+
+// Int64 returns an integer field. Strings holding integers are accepted.
+func (e *Entry) Int64(key string) (int64, bool) {
+	switch v := e.Fields[key].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Float64 returns a numeric field. Strings holding numbers are accepted.
+func (e *Entry) Float64(key string) (float64, bool) {
+	switch v := e.Fields[key].(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Bool returns a boolean field.
+func (e *Entry) Bool(key string) (bool, bool) {
+	b, ok := e.Fields[key].(bool)
+	return b, ok
+}
+
+// TimeField returns a time field written as an RFC 3339 string or epoch seconds.
+func (e *Entry) TimeField(key string) (time.Time, bool) {
+	v, ok := e.Fields[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	t := parseTime(v)
+	return t, !t.IsZero()
+}
+
+// Duration returns a duration field. logx writes durations as seconds;
+// strings such as "1.5s" are accepted too.
+func (e *Entry) Duration(key string) (time.Duration, bool) {
+	switch v := e.Fields[key].(type) {
+	case json.Number:
+		seconds, err := v.Float64()
+		return time.Duration(seconds * float64(time.Second)), err == nil
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
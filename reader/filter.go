@@ -0,0 +1,69 @@
+package reader
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// Filter selects entries. It returns true for entries to keep.
+type Filter func(e *Entry) bool
+
+// MinLevel keeps entries at or above level.
+func MinLevel(level logx.Level) Filter {
+	return func(e *Entry) bool {
+		return e.Level >= level
+	}
+}
+
+// Since keeps entries written at or after t.
+func Since(t time.Time) Filter {
+	return func(e *Entry) bool {
+		return !e.Time.Before(t)
+	}
+}
+
+// Until keeps entries written before t.
+func Until(t time.Time) Filter {
+	return func(e *Entry) bool {
+		return e.Time.Before(t)
+	}
+}
+
+// Field keeps entries with a field whose string form equals value.
+func Field(key, value string) Filter {
+	return func(e *Entry) bool {
+		v, ok := e.String(key)
+		return ok && v == value
+	}
+}
+
+// HasField keeps entries that have a field with the given key.
+func HasField(key string) Filter {
+	return func(e *Entry) bool {
+		return e.Has(key)
+	}
+}
+
+// Logger keeps entries whose logger name matches a glob pattern (as in
+// path.Match), e.g. "payments.*". An invalid pattern matches nothing.
+func Logger(pattern string) Filter {
+	return func(e *Entry) bool {
+		ok, err := path.Match(pattern, e.Logger)
+		return err == nil && ok
+	}
+}
+
+// Message keeps entries whose message matches a regular expression.
+func Message(expr string) (Filter, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message pattern: %w", err)
+	}
+	return func(e *Entry) bool {
+		return re.MatchString(e.Message)
+	}, nil
+}
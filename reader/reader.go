@@ -0,0 +1,295 @@
+// Package reader reads JSON log files written by logx.
+//
+// It opens plain and gzip-compressed files, including sets of rotated
+// files, and iterates their entries with typed accessors and filters. This
+// enables in-process log analysis tools and compaction jobs without
+// shelling out to jq.
+//
+// Example:
+//
+//	r, err := reader.OpenGlob("/var/log/app/app.log*")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer r.Close()
+//	r.Filter(reader.MinLevel(logx.ErrorLevel), reader.Since(time.Now().Add(-time.Hour)))
+//	for r.Next() {
+//	    e := r.Entry()
+//	    userID, _ := e.String("user_id")
+//	    fmt.Println(e.Time, e.Message, userID)
+//	}
+//	if err := r.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// maxLineSize is the longest line the reader accepts.
+const maxLineSize = 16 * 1024 * 1024
+
+// source is an input of a Reader.
+type source struct {
+	name string // File path, empty for a Reader created by New
+	open func() (io.ReadCloser, error)
+}
+
+// Reader iterates the entries of one or more logx log files in order.
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	sources []source
+	filters []Filter
+	seek    time.Time
+
+	current io.ReadCloser
+	scanner *bufio.Scanner
+	name    string
+	entry   *Entry
+	skipped int
+	err     error
+}
+
+// Open returns a Reader over the given files, read in order. Rotated files
+// should therefore be listed oldest first. Gzip-compressed files are
+// detected and decompressed automatically.
+func Open(paths ...string) (*Reader, error) {
+	sources := make([]source, 0, len(paths))
+	for _, path := range paths {
+		path := path
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source{name: path, open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		}})
+	}
+	return &Reader{sources: sources}, nil
+}
+
+// OpenGlob returns a Reader over the files matching pattern, oldest first
+// by modification time, such as a log file and its rotated backups. Sidecar
+// index files are ignored.
+func OpenGlob(pattern string) (*Reader, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	paths := matches[:0]
+	for _, path := range matches {
+		if !strings.HasSuffix(path, logx.IndexSuffix) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %q", pattern)
+	}
+	modTimes := make(map[string]int64, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime().UnixNano()
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return modTimes[paths[i]] < modTimes[paths[j]]
+	})
+	return Open(paths...)
+}
+
+// New returns a Reader over r. The caller remains responsible for closing r.
+func New(r io.Reader) *Reader {
+	return &Reader{sources: []source{{name: "", open: func() (io.ReadCloser, error) {
+		return io.NopCloser(r), nil
+	}}}}
+}
+
+// Filter adds filters to the reader. Next only returns entries that pass
+// every filter.
+func (r *Reader) Filter(filters ...Filter) *Reader {
+	r.filters = append(r.filters, filters...)
+	return r
+}
+
+// SeekTime skips entries written before t. Files that have a sidecar index
+// (see logx.Config.IndexInterval) are not read from the start but from the
+// indexed offset closest to t, which makes reading a recent time range of a
+// multi-gigabyte file fast. SeekTime must be called before the first call
+// to Next.
+//
+// Example:
+//
+//	r, _ := reader.Open("/var/log/app.log")
+//	r.SeekTime(time.Now().Add(-10 * time.Minute))
+func (r *Reader) SeekTime(t time.Time) *Reader {
+	r.seek = t
+	return r.Filter(Since(t))
+}
+
+// Next advances to the next entry that passes the filters. It returns false
+// at the end of the input or on error; check Err afterwards. Lines that are
+// not JSON objects are skipped and counted in Skipped.
+func (r *Reader) Next() bool {
+	for r.err == nil {
+		if r.scanner == nil && !r.openNext() {
+			return false
+		}
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				r.err = fmt.Errorf("%s: %w", r.name, err)
+			}
+			r.closeCurrent()
+			continue
+		}
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := Parse(line)
+		if err != nil {
+			r.skipped++
+			continue
+		}
+		if r.matches(entry) {
+			r.entry = entry
+			return true
+		}
+	}
+	return false
+}
+
+// openNext opens the next source. It returns false when none remain or
+// opening fails.
+func (r *Reader) openNext() bool {
+	if len(r.sources) == 0 {
+		return false
+	}
+	src := r.sources[0]
+	r.sources = r.sources[1:]
+	rc, err := src.open()
+	if err != nil {
+		r.err = err
+		return false
+	}
+	if !r.seek.IsZero() && src.name != "" {
+		if err := seekIndexed(rc, src.name, r.seek); err != nil {
+			rc.Close()
+			r.err = fmt.Errorf("%s: %w", src.name, err)
+			return false
+		}
+	}
+	buffered := bufio.NewReader(rc)
+	var in io.Reader = buffered
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			rc.Close()
+			r.err = fmt.Errorf("%s: %w", src.name, err)
+			return false
+		}
+		in = gz
+	}
+	r.current = rc
+	r.name = src.name
+	r.scanner = bufio.NewScanner(in)
+	r.scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return true
+}
+
+// seekIndexed moves an uncompressed log file to the indexed offset for t.
+// Files without an index are left at the start.
+func seekIndexed(rc io.ReadCloser, path string, t time.Time) error {
+	file, ok := rc.(*os.File)
+	if !ok {
+		return nil
+	}
+	var magic [2]byte
+	if n, _ := file.ReadAt(magic[:], 0); n == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return nil
+	}
+	offset, err := logx.IndexOffset(path, t)
+	if err != nil || offset == 0 {
+		return err
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// closeCurrent closes the source being read.
+func (r *Reader) closeCurrent() {
+	if r.current != nil {
+		r.current.Close()
+	}
+	r.current = nil
+	r.scanner = nil
+}
+
+// matches reports whether the entry passes every filter.
+func (r *Reader) matches(e *Entry) bool {
+	for _, filter := range r.filters {
+		if !filter(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Entry returns the current entry. It is valid after Next returns true.
+func (r *Reader) Entry() *Entry {
+	return r.entry
+}
+
+// Err returns the first error encountered while reading, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Skipped returns the number of lines skipped because they were not JSON
+// objects.
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+// All reads the remaining entries that pass the filters.
+func (r *Reader) All() ([]*Entry, error) {
+	var entries []*Entry
+	for r.Next() {
+		entries = append(entries, r.Entry())
+	}
+	return entries, r.Err()
+}
+
+// Close closes the file being read. It is safe to call Close more than once.
+func (r *Reader) Close() error {
+	r.closeCurrent()
+	r.sources = nil
+	return nil
+}
+
+// decodeJSON decodes a JSON object keeping numbers as json.Number.
+func decodeJSON(line []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	return doc, nil
+}
@@ -0,0 +1,113 @@
+package logx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// RedactedField describes a field that the current masking policy would
+// mask.
+type RedactedField struct {
+	Key     string // Field key as it appears in the entries
+	Count   int    // Number of entries in which the field would be masked
+	Example string // Masked form of the first value seen
+}
+
+// RedactionReport is the result of a redaction dry run.
+type RedactionReport struct {
+	Entries int             // Entries examined
+	Masked  int             // Entries with at least one field that would be masked
+	Skipped int             // Lines that were not JSON objects
+	Fields  []RedactedField // Fields that would be masked, sorted by key
+}
+
+// redactionTally accumulates a RedactionReport.
+type redactionTally struct {
+	report RedactionReport
+	fields map[string]*RedactedField
+}
+
+// add records the fields of one entry.
+func (t *redactionTally) add(fields map[string]interface{}) {
+	t.report.Entries++
+	masked := false
+	for key, value := range fields {
+		if !isSensitiveKey(key) {
+			continue
+		}
+		masked = true
+		field, ok := t.fields[key]
+		if !ok {
+			field = &RedactedField{Key: key, Example: fmt.Sprint(maskSensitiveData(key, value))}
+			t.fields[key] = field
+		}
+		field.Count++
+	}
+	if masked {
+		t.report.Masked++
+	}
+}
+
+// result returns the report with fields sorted by key.
+func (t *redactionTally) result() RedactionReport {
+	report := t.report
+	report.Fields = make([]RedactedField, 0, len(t.fields))
+	for _, field := range t.fields {
+		report.Fields = append(report.Fields, *field)
+	}
+	sort.Slice(report.Fields, func(i, j int) bool {
+		return report.Fields[i].Key < report.Fields[j].Key
+	})
+	return report
+}
+
+// PreviewRedaction reports which fields of the entries would be masked
+// under the current sensitive key policy, without writing anything. Use it
+// together with AddSensitiveKey and RemoveSensitiveKey to validate a masking
+// change before rolling it out.
+//
+// Example:
+//
+//	logx.AddSensitiveKey("iban")
+//	report := logx.PreviewRedaction(samples)
+//	for _, field := range report.Fields {
+//	    fmt.Printf("%s masked in %d entries, e.g. %s\n", field.Key, field.Count, field.Example)
+//	}
+func PreviewRedaction(entries []Entry) RedactionReport {
+	tally := redactionTally{fields: make(map[string]*RedactedField)}
+	for _, entry := range entries {
+		fields := make(map[string]interface{}, len(entry.Fields))
+		for _, field := range entry.Fields {
+			fields[field.Key] = field.Value
+		}
+		tally.add(fields)
+	}
+	return tally.result()
+}
+
+// PreviewRedactionReader is like PreviewRedaction but reads JSON log lines,
+// such as a captured log file. Only top-level keys are considered, matching
+// how the logger masks fields. Lines that are not JSON objects are counted
+// in Skipped.
+func PreviewRedactionReader(r io.Reader) (RedactionReport, error) {
+	tally := redactionTally{fields: make(map[string]*RedactedField)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]interface{}
+		if line[0] != '{' || json.Unmarshal(line, &fields) != nil {
+			tally.report.Skipped++
+			continue
+		}
+		tally.add(fields)
+	}
+	return tally.result(), scanner.Err()
+}
@@ -0,0 +1,189 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetentionConfig describes the log files a RetentionManager cleans up and
+// how long they are kept.
+type RetentionConfig struct {
+	// Patterns select the files to manage, in filepath.Match syntax, e.g.
+	// "/var/log/app/*.log*". Sidecar indexes are never matched themselves;
+	// they are removed together with their log file.
+	Patterns []string
+
+	// MaxAge removes files last modified more than this long ago. Zero
+	// keeps files regardless of age.
+	// Default: 0
+	MaxAge time.Duration
+
+	// MaxFiles keeps at most this many of the files matched by each
+	// pattern, removing the least recently modified ones. Zero keeps all
+	// files, subject to MaxAge.
+	// Default: 0
+	MaxFiles int
+
+	// CompressAfter gzips kept files that have not been modified for this
+	// long. It must be longer than the time between writes to an active
+	// file, or that file would be compressed while still in use.
+	// Default: 0 (no compression)
+	CompressAfter time.Duration
+
+	// Interval is the time between cleanups.
+	// Default: 1h
+	Interval time.Duration
+
+	// OnError is called when a file cannot be removed or compressed. The
+	// file is retried at the next cleanup.
+	// Default: nil (errors are ignored)
+	OnError func(err error)
+}
+
+// RetentionManager removes and compresses old log files on a background
+// schedule, replacing external cron-based cleanup scripts. Unlike the
+// cleanup done by Rotation, which runs only when a file is rotated, it
+// applies to any files, including those written by other processes, and
+// runs even while no file is rotated.
+//
+// Call Close to stop the schedule.
+type RetentionManager struct {
+	config RetentionConfig
+
+	mu   sync.Mutex // Serializes cleanups
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewRetentionManager validates the patterns, runs a first cleanup in the
+// background and then repeats it every Interval until Close is called.
+//
+// Example:
+//
+//	retention, err := logx.NewRetentionManager(logx.RetentionConfig{
+//	    Patterns:      []string{"/var/log/app/*.log*"},
+//	    MaxAge:        30 * 24 * time.Hour,
+//	    MaxFiles:      50,
+//	    CompressAfter: 24 * time.Hour,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer retention.Close()
+func NewRetentionManager(config RetentionConfig) (*RetentionManager, error) {
+	if len(config.Patterns) == 0 {
+		return nil, fmt.Errorf("retention requires at least one pattern")
+	}
+	for _, pattern := range config.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid retention pattern %q: %w", pattern, err)
+		}
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	m := &RetentionManager{
+		config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go m.run()
+	return m, nil
+}
+
+// run cleans up once and then every Interval until Close is called.
+func (m *RetentionManager) run() {
+	defer close(m.done)
+	m.Cleanup()
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Cleanup()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// retainedFile is a file matched by a retention pattern.
+type retainedFile struct {
+	path    string
+	modTime time.Time
+}
+
+// Cleanup applies the retention policy immediately and returns the errors
+// encountered, joined. It is called by the schedule and can be called
+// directly, e.g. before shutting down.
+func (m *RetentionManager) Cleanup() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for _, pattern := range m.config.Patterns {
+		errs = append(errs, m.cleanPattern(pattern)...)
+	}
+	for _, err := range errs {
+		if m.config.OnError != nil {
+			m.config.OnError(err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanPattern applies the policy to the files matched by pattern.
+func (m *RetentionManager) cleanPattern(pattern string) []error {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return []error{err}
+	}
+	var files []retainedFile
+	for _, match := range matches {
+		if strings.HasSuffix(match, IndexSuffix) {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, retainedFile{path: match, modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	now := time.Now()
+	var errs []error
+	for i, f := range files {
+		age := now.Sub(f.modTime)
+		if (m.config.MaxFiles > 0 && i >= m.config.MaxFiles) || (m.config.MaxAge > 0 && age > m.config.MaxAge) {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to remove log file: %w", err))
+				continue
+			}
+			os.Remove(f.path + IndexSuffix)
+			continue
+		}
+		if m.config.CompressAfter > 0 && age >= m.config.CompressAfter && !strings.HasSuffix(f.path, ".gz") {
+			if err := compressFile(f.path); err != nil {
+				errs = append(errs, fmt.Errorf("failed to compress log file: %w", err))
+				continue
+			}
+			os.Remove(f.path + IndexSuffix)
+		}
+	}
+	return errs
+}
+
+// Close stops the schedule, waiting for a running cleanup to finish. It is
+// safe to call Close more than once.
+func (m *RetentionManager) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	<-m.done
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// compiledRewrite is a RewriteRule with its pattern compiled.
+type compiledRewrite struct {
+	RewriteRule
+	re *regexp.Regexp
+}
+
+// Rewriter is a Processor that applies regular expression find/replace rules
+// to entry messages and fields, normalizing noisy third-party messages before
+// they reach storage. Rules are applied in order, each to the output of the
+// previous one.
+type Rewriter struct {
+	rules []compiledRewrite
+}
+
+// NewRewriter compiles the given rules into a Rewriter.
+// An error is returned if any pattern is not a valid regular expression.
+//
+// Example:
+//
+//	rewriter, err := logx.NewRewriter(
+//	    logx.ScrubMemoryAddresses,
+//	    logx.RewriteRule{Pattern: `took \d+ms`, Replacement: "took Nms"},
+//	    logx.RewriteRule{Pattern: `sess-[a-z0-9]+`, Replacement: "sess-?", Fields: []string{"url"}},
+//	)
+func NewRewriter(rules ...RewriteRule) (*Rewriter, error) {
+	compiled := make([]compiledRewrite, 0, len(rules))
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite rule %d: invalid pattern: %w", i, err)
+		}
+		compiled = append(compiled, compiledRewrite{RewriteRule: rule, re: re})
+	}
+	return &Rewriter{rules: compiled}, nil
+}
+
+// Process rewrites the entry's message and selected fields in place.
+func (r *Rewriter) Process(e *Entry) bool {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.FieldsOnly {
+			e.Message = rule.re.ReplaceAllString(e.Message, rule.Replacement)
+		}
+		for _, key := range rule.Fields {
+			for j := range e.Fields {
+				if e.Fields[j].Key != key {
+					continue
+				}
+				switch v := e.Fields[j].Value.(type) {
+				case string:
+					e.Fields[j].Value = rule.re.ReplaceAllString(v, rule.Replacement)
+				case error:
+					if v != nil {
+						e.Fields[j].Value = rewriteError(v, rule)
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// rewriteError returns err with its message rewritten by rule. The result
+// is still an error, so that its verbose form and the causes it combines are
+// written as for the original, rewritten by the same rule.
+func rewriteError(err error, rule *compiledRewrite) error {
+	rewritten := &rewrittenError{err: err, msg: rule.re.ReplaceAllString(err.Error(), rule.Replacement)}
+	for _, cause := range combinedErrors(err) {
+		if cause != nil {
+			rewritten.causes = append(rewritten.causes, rewriteError(cause, rule))
+		}
+	}
+	if _, ok := err.(fmt.Formatter); ok {
+		return &rewrittenFormatter{rewritten, rule}
+	}
+	return rewritten
+}
+
+// rewrittenError is an error whose message was rewritten by a Rewriter. It
+// does not unwrap to the original, whose messages would not be rewritten.
+type rewrittenError struct {
+	err    error
+	msg    string
+	causes []error
+}
+
+func (e *rewrittenError) Error() string   { return e.msg }
+func (e *rewrittenError) Unwrap() []error { return e.causes }
+func (e *rewrittenError) original() error { return e.err }
+
+// rewrittenFormatter is a rewrittenError of an error implementing
+// fmt.Formatter, such as those of github.com/pkg/errors, whose verbose form
+// is rewritten by the same rule.
+type rewrittenFormatter struct {
+	*rewrittenError
+	rule *compiledRewrite
+}
+
+func (e *rewrittenFormatter) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, e.rule.re.ReplaceAllString(fmt.Sprintf("%+v", e.err), e.rule.Replacement))
+		return
+	}
+	io.WriteString(s, e.msg)
+}
@@ -0,0 +1,473 @@
+package logx
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RotationConfig rotates file outputs by size, by time, or both.
+//
+// Size-based rotation renames a file that would grow past MaxSizeMB to a
+// backup carrying the rotation time, e.g. app-2024-05-01T10-30-00.000.log,
+// together with its sidecar index, and starts a new file. Backups beyond
+// MaxBackups or older than MaxAgeDays are removed, and with Compress they
+// are gzipped, in the background.
+//
+// Time-based rotation, enabled by Interval, starts a new file every hour or
+// day. The output path is then a template containing strftime-style
+// directives, expanded for the start of each period in Config.TimeZone:
+// %Y (year), %m (month), %d (day), %H (hour) and %% (a literal %), e.g.
+// "/var/log/app-%Y%m%d.log". A Go time layout in braces is expanded the same
+// way, e.g. "app-{2006-01-02}.log". Within a period, files are still rotated
+// by size if MaxSizeMB is set.
+//
+// With or without Interval, the placeholders {hostname} and {service}
+// (Service) in the output path are replaced once when the file is opened,
+// e.g. "/shared/app-{service}-{hostname}-{2006-01-02}.log", so that many
+// instances can write to a shared volume. Backups and retention then only
+// apply to the files of this instance.
+type RotationConfig struct {
+	// MaxSizeMB is the size in megabytes at which a file is rotated.
+	// Default: 100, or no size limit when Interval is set
+	MaxSizeMB int
+
+	// MaxBackups is the number of backups kept per file. Zero keeps all
+	// backups, subject to MaxAgeDays.
+	// Default: 0
+	MaxBackups int
+
+	// MaxAgeDays removes backups older than this many days, based on the
+	// rotation time in their name. Zero keeps backups regardless of age.
+	// Default: 0
+	MaxAgeDays int
+
+	// Compress gzips backups. The sidecar index of a compressed backup is
+	// removed, as its offsets no longer apply.
+	// Default: false
+	Compress bool
+
+	// Interval starts a new file every hour or day, named by expanding the
+	// output path template.
+	// Default: RotateNone
+	Interval RotationInterval
+
+	// Retention removes the files of past periods, including their
+	// backups, once they have not been modified for this long. It only
+	// applies with Interval. Zero keeps them.
+	// Default: 0
+	Retention time.Duration
+
+	// Service replaces the {service} placeholder in the output path.
+	// Default: "" (the placeholder is removed)
+	Service string
+}
+
+// RotationInterval is how often time-based rotation starts a new file.
+type RotationInterval int
+
+const (
+	// RotateNone disables time-based rotation. It is the default.
+	RotateNone RotationInterval = iota
+
+	// RotateHourly starts a new file at the start of every hour.
+	RotateHourly
+
+	// RotateDaily starts a new file at midnight.
+	RotateDaily
+)
+
+// periodStart returns the start of the period containing t, in t's
+// location.
+func (i RotationInterval) periodStart(t time.Time) time.Time {
+	if i == RotateHourly {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// periodEnd returns the end of the period starting at start.
+func (i RotationInterval) periodEnd(start time.Time) time.Time {
+	if i == RotateHourly {
+		return start.Add(time.Hour)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// expandPathNames replaces the {hostname} and {service} placeholders of an
+// output path.
+func expandPathNames(path, service string) string {
+	if !strings.Contains(path, "{") {
+		return path
+	}
+	hostname, _ := os.Hostname()
+	return strings.NewReplacer("{hostname}", hostname, "{service}", service).Replace(path)
+}
+
+// expandPathTemplate expands the strftime-style directives and braced time
+// layouts of a time-based rotation template for t. With glob set, they are
+// expanded to "*" instead, matching the files of every period.
+func expandPathTemplate(template string, t time.Time, glob bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] == '{' {
+			end := strings.IndexByte(template[i:], '}')
+			if end < 2 {
+				return "", fmt.Errorf("invalid log path template %q: unclosed or empty {", template)
+			}
+			if glob {
+				b.WriteByte('*')
+			} else {
+				b.WriteString(t.Format(template[i+1 : i+end]))
+			}
+			i += end
+			continue
+		}
+		if template[i] != '%' {
+			b.WriteByte(template[i])
+			continue
+		}
+		if i+1 == len(template) {
+			return "", fmt.Errorf("invalid log path template %q: trailing %%", template)
+		}
+		i++
+		var value string
+		switch template[i] {
+		case 'Y':
+			value = fmt.Sprintf("%04d", t.Year())
+		case 'm':
+			value = fmt.Sprintf("%02d", int(t.Month()))
+		case 'd':
+			value = fmt.Sprintf("%02d", t.Day())
+		case 'H':
+			value = fmt.Sprintf("%02d", t.Hour())
+		case '%':
+			b.WriteByte('%')
+			continue
+		default:
+			return "", fmt.Errorf("invalid log path template %q: unknown directive %%%c", template, template[i])
+		}
+		if glob {
+			value = "*"
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// backupTimeFormat is the rotation time in backup names. It sorts
+// lexically and contains no characters that are invalid in file names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotatingWriter writes to a log file, rotating it once it reaches the
+// configured size or its period ends. Each Write call carries exactly one
+// encoded entry, so entries are never split across files.
+type rotatingWriter struct {
+	mu       sync.Mutex
+	oc       *outputConfig
+	template string // Output path as configured, a template with Interval
+	path     string // Path of the current file
+	config   RotationConfig
+	max      int64     // Size at which the file is rotated, 0 for no limit
+	end      time.Time // End of the current period, zero without Interval
+
+	output zapcore.WriteSyncer // Writer for the current file
+	close  func() error        // Closes the current file
+	size   int64               // Size of the current file
+
+	mill sync.Mutex     // Serializes compression and cleanup of backups
+	busy sync.WaitGroup // Pending compression and cleanup
+}
+
+// newRotatingWriter opens the file at path, or the current file of the path
+// template with time-based rotation.
+func newRotatingWriter(oc *outputConfig, path string, config RotationConfig) (*rotatingWriter, error) {
+	path = expandPathNames(path, config.Service)
+	w := &rotatingWriter{oc: oc, template: path, path: path, config: config}
+	switch {
+	case config.MaxSizeMB > 0:
+		w.max = int64(config.MaxSizeMB) << 20
+	case config.Interval == RotateNone:
+		w.max = 100 << 20
+	}
+	if config.Interval != RotateNone {
+		if _, err := expandPathTemplate(path, time.Time{}, false); err != nil {
+			return nil, err
+		}
+		if glob, _ := expandPathTemplate(path, time.Time{}, true); !strings.Contains(glob, "*") {
+			return nil, fmt.Errorf("time-based rotation requires a %%Y, %%m, %%d, %%H or {layout} directive in %q", path)
+		}
+		w.startPeriod(time.Now())
+	} else if strings.Contains(path, "{") {
+		return nil, fmt.Errorf("time layouts in %q require time-based rotation", path)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	if config.Retention > 0 {
+		w.busy.Add(1)
+		go func() {
+			defer w.busy.Done()
+			w.removeExpired()
+		}()
+	}
+	return w, nil
+}
+
+// startPeriod sets the path and end of the period containing now.
+func (w *rotatingWriter) startPeriod(now time.Time) {
+	start := w.config.Interval.periodStart(now.In(w.oc.location))
+	w.path, _ = expandPathTemplate(w.template, start, false)
+	w.end = w.config.Interval.periodEnd(start)
+}
+
+// open opens the current file.
+func (w *rotatingWriter) open() error {
+	output, closeFile, size, err := w.oc.openFile(w.path)
+	if err != nil {
+		return err
+	}
+	w.output, w.close, w.size = output, closeFile, size
+	return nil
+}
+
+// Write writes one entry, rotating the file first if the entry would take
+// it past the maximum size. If rotation fails, logging continues in the
+// file at the configured path.
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.end.IsZero() {
+		if now := time.Now(); !now.Before(w.end) {
+			w.nextPeriod(now)
+		}
+	}
+	if w.output != nil && w.max > 0 && w.size > 0 && w.size+int64(len(p)) > w.max {
+		w.rotate()
+	}
+	if w.output == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.output.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file and its index to a backup and opens a
+// new file. It requires w.mu.
+func (w *rotatingWriter) rotate() error {
+	w.output.Sync()
+	w.close()
+	w.output = nil
+	backup := w.backupName(time.Now())
+	renameErr := os.Rename(w.path, backup)
+	if renameErr == nil {
+		os.Rename(w.path+IndexSuffix, backup+IndexSuffix)
+	}
+	// Reopen even if the rename failed, so that logging continues.
+	if err := w.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("failed to rotate log file: %w", renameErr)
+	}
+	w.busy.Add(1)
+	go func() {
+		defer w.busy.Done()
+		w.millBackups()
+	}()
+	return nil
+}
+
+// nextPeriod closes the file of the ended period and switches to the
+// file of the period containing now. It requires w.mu.
+func (w *rotatingWriter) nextPeriod(now time.Time) {
+	if w.output != nil {
+		w.output.Sync()
+		w.close()
+		w.output = nil
+	}
+	w.startPeriod(now)
+	if w.config.Retention > 0 {
+		w.busy.Add(1)
+		go func() {
+			defer w.busy.Done()
+			w.removeExpired()
+		}()
+	}
+}
+
+// removeExpired removes the files of past periods, and their backups and
+// indexes, that were last modified more than Retention ago.
+func (w *rotatingWriter) removeExpired() {
+	w.mill.Lock()
+	defer w.mill.Unlock()
+	pattern, err := expandPathTemplate(w.template, time.Time{}, true)
+	if err != nil {
+		return
+	}
+	matches, err := filepath.Glob(pattern + "*")
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	current := w.path
+	w.mu.Unlock()
+	cutoff := time.Now().Add(-w.config.Retention)
+	for _, match := range matches {
+		if match == current || match == current+IndexSuffix {
+			continue
+		}
+		if info, err := os.Stat(match); err == nil && !info.IsDir() && info.ModTime().Before(cutoff) {
+			os.Remove(match)
+		}
+	}
+}
+
+// backupName returns an unused backup name for a rotation at t.
+func (w *rotatingWriter) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	for {
+		name := filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err := os.Stat(name + ".gz"); os.IsNotExist(err) {
+				return name
+			}
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// nameParts splits the path into its directory, the backup name prefix
+// and the extension, e.g. "/var/log", "app-" and ".log".
+func (w *rotatingWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.path)
+	base := filepath.Base(w.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// logBackup is a rotated file.
+type logBackup struct {
+	path string
+	time time.Time
+}
+
+// backups returns the backups of the file, newest first.
+func (w *rotatingWriter) backups() ([]logBackup, error) {
+	dir, prefix, ext := w.nameParts()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []logBackup
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		if !strings.HasSuffix(stamp, ext) && !strings.HasSuffix(stamp, ext+".gz") {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, logBackup{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	return backups, nil
+}
+
+// millBackups removes the backups beyond MaxBackups or MaxAgeDays and
+// compresses the others if configured. Failures are ignored; they are
+// retried at the next rotation.
+func (w *rotatingWriter) millBackups() {
+	w.mill.Lock()
+	defer w.mill.Unlock()
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-time.Duration(w.config.MaxAgeDays) * 24 * time.Hour)
+	for i, b := range backups {
+		expired := w.config.MaxAgeDays > 0 && b.time.Before(cutoff)
+		if (w.config.MaxBackups > 0 && i >= w.config.MaxBackups) || expired {
+			os.Remove(b.path)
+			os.Remove(b.path + IndexSuffix)
+			continue
+		}
+		if w.config.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if compressFile(b.path) == nil {
+				os.Remove(b.path + IndexSuffix)
+			}
+		}
+	}
+}
+
+// compressFile gzips the file at path to path.gz and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// Sync flushes the current file and waits for pending compression and
+// cleanup of backups.
+func (w *rotatingWriter) Sync() error {
+	var err error
+	w.mu.Lock()
+	if w.output != nil {
+		err = w.output.Sync()
+	}
+	w.mu.Unlock()
+	w.busy.Wait()
+	return err
+}
+
+// Close flushes and closes the current file after pending compression and
+// cleanup of backups.
+func (w *rotatingWriter) Close() error {
+	err := w.Sync()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.output != nil {
+		if closeErr := w.close(); err == nil {
+			err = closeErr
+		}
+		w.output = nil
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package logx
+
+import "fmt"
+
+// compiledRoute is a RouteRule with its conditions compiled.
+type compiledRoute struct {
+	entryMatcher
+	sinks []string
+}
+
+// Router is a Processor that selects the sinks an entry is written to.
+// Every matching rule contributes its sinks; entries that match no rule
+// are written to the default sink. Sinks are declared in Config.Sinks.
+type Router struct {
+	rules []compiledRoute
+}
+
+// NewRouter compiles the given rules into a Router.
+// An error is returned if a pattern is invalid or a rule names no sinks.
+//
+// Example:
+//
+//	router, err := logx.NewRouter(
+//	    logx.RouteRule{Fields: map[string]string{"audit": "true"}, Sinks: []string{"audit"}},
+//	    logx.RouteRule{MinLevel: logx.ErrorLevel, Sinks: []string{"default", "alerting"}},
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config := logx.DefaultConfig()
+//	config.Sinks = []logx.SinkConfig{
+//	    {Name: "audit", OutputPath: "/var/log/app/audit.log"},
+//	    {Name: "alerting", OutputPath: "/var/log/app/alerts.log"},
+//	}
+//	config.Processors = []logx.Processor{router}
+func NewRouter(rules ...RouteRule) (*Router, error) {
+	compiled := make([]compiledRoute, 0, len(rules))
+	for i, rule := range rules {
+		if len(rule.Sinks) == 0 {
+			return nil, fmt.Errorf("route rule %d: no sinks", i)
+		}
+		matcher, err := newEntryMatcher(rule.Levels, rule.MinLevel, rule.Logger, rule.Message, rule.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("route rule %d: %w", i, err)
+		}
+		compiled = append(compiled, compiledRoute{entryMatcher: matcher, sinks: rule.Sinks})
+	}
+	return &Router{rules: compiled}, nil
+}
+
+// Process adds the sinks of every matching rule to the entry.
+func (r *Router) Process(e *Entry) bool {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.matches(e) {
+			e.Sinks = append(e.Sinks, rule.sinks...)
+		}
+	}
+	return true
+}
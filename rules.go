@@ -0,0 +1,266 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilterAction specifies what a Filter does with an entry that matches a rule.
+type FilterAction string
+
+const (
+	// FilterDrop discards matching entries.
+	FilterDrop FilterAction = "drop"
+
+	// FilterDowngrade rewrites the level of matching entries to the rule's
+	// DowngradeTo level. If the new level is below the logger's minimum
+	// level, the entry is discarded as usual.
+	FilterDowngrade FilterAction = "downgrade"
+)
+
+// FilterRule describes a set of conditions and the action to take when an
+// entry satisfies all of them. Empty conditions match every entry.
+//
+// Example:
+//
+//	// Suppress access logs for the health check endpoint
+//	rule := logx.FilterRule{
+//	    Fields: map[string]string{"path": "/healthz"},
+//	    Action: logx.FilterDrop,
+//	}
+type FilterRule struct {
+	// Levels restricts the rule to entries at one of the given levels.
+	Levels []Level
+
+	// Message is a regular expression matched against the entry message.
+	Message string
+
+	// Logger is a glob pattern (as in path.Match) matched against the
+	// logger name, e.g. "http" or "http.*".
+	Logger string
+
+	// Fields requires each listed field to be present with a value whose
+	// string form equals the given value.
+	Fields map[string]string
+
+	// Action is the action applied to matching entries.
+	// Default: FilterDrop
+	Action FilterAction
+
+	// DowngradeTo is the level used by the FilterDowngrade action.
+	DowngradeTo Level
+}
+
+// filterRuleSpec is the on-disk representation of a FilterRule, using level
+// names instead of numeric levels.
+type filterRuleSpec struct {
+	Levels      []string          `json:"levels" yaml:"levels"`
+	Message     string            `json:"message" yaml:"message"`
+	Logger      string            `json:"logger" yaml:"logger"`
+	Fields      map[string]string `json:"fields" yaml:"fields"`
+	Action      FilterAction      `json:"action" yaml:"action"`
+	DowngradeTo string            `json:"downgrade_to" yaml:"downgrade_to"`
+}
+
+// rule converts the spec into a FilterRule, resolving level names.
+func (spec filterRuleSpec) rule() (FilterRule, error) {
+	rule := FilterRule{
+		Message: spec.Message,
+		Logger:  spec.Logger,
+		Fields:  spec.Fields,
+		Action:  spec.Action,
+	}
+	for _, name := range spec.Levels {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return FilterRule{}, err
+		}
+		rule.Levels = append(rule.Levels, level)
+	}
+	if spec.DowngradeTo != "" {
+		level, err := ParseLevel(spec.DowngradeTo)
+		if err != nil {
+			return FilterRule{}, err
+		}
+		rule.DowngradeTo = level
+	}
+	return rule, nil
+}
+
+// UnmarshalJSON decodes a rule from JSON, accepting level names such as
+// "debug" or "WARN" for the levels and downgrade_to keys.
+func (r *FilterRule) UnmarshalJSON(data []byte) error {
+	var spec filterRuleSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	rule, err := spec.rule()
+	if err != nil {
+		return err
+	}
+	*r = rule
+	return nil
+}
+
+// UnmarshalYAML decodes a rule from YAML using the same keys as UnmarshalJSON.
+func (r *FilterRule) UnmarshalYAML(node *yaml.Node) error {
+	var spec filterRuleSpec
+	if err := node.Decode(&spec); err != nil {
+		return err
+	}
+	rule, err := spec.rule()
+	if err != nil {
+		return err
+	}
+	*r = rule
+	return nil
+}
+
+// LoadFilterRules reads a JSON array of filter rules from the file at path.
+//
+// Example file:
+//
+//	[
+//	  {"fields": {"path": "/healthz"}, "action": "drop"},
+//	  {"levels": ["warn"], "message": "^cache miss", "action": "downgrade", "downgrade_to": "debug"}
+//	]
+func LoadFilterRules(path string) ([]FilterRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter rules: %w", err)
+	}
+	var rules []FilterRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse filter rules: %w", err)
+	}
+	return rules, nil
+}
+
+// RouteRule sends entries that satisfy all of its conditions to the named
+// sinks. Empty conditions match every entry.
+//
+// Example:
+//
+//	// Audit events go to the audit sink only
+//	logx.RouteRule{Fields: map[string]string{"audit": "true"}, Sinks: []string{"audit"}}
+//	// Errors go to the alerting sink as well as the default output
+//	logx.RouteRule{MinLevel: logx.ErrorLevel, Sinks: []string{"default", "alerting"}}
+type RouteRule struct {
+	// Levels restricts the rule to entries at one of the given levels.
+	Levels []Level
+
+	// MinLevel restricts the rule to entries at or above the given level.
+	MinLevel Level
+
+	// Logger is a glob pattern (as in path.Match) matched against the
+	// logger name, e.g. "payments.*".
+	Logger string
+
+	// Message is a regular expression matched against the entry message.
+	Message string
+
+	// Fields requires each listed field to be present with a value whose
+	// string form equals the given value.
+	Fields map[string]string
+
+	// Sinks names the sinks matching entries are written to. Use
+	// DefaultSinkName to keep writing to the primary output as well.
+	Sinks []string
+}
+
+// routeRuleSpec is the on-disk representation of a RouteRule, using level
+// names instead of numeric levels.
+type routeRuleSpec struct {
+	Levels   []string          `yaml:"levels"`
+	MinLevel string            `yaml:"min_level"`
+	Logger   string            `yaml:"logger"`
+	Message  string            `yaml:"message"`
+	Fields   map[string]string `yaml:"fields"`
+	Sinks    []string          `yaml:"sinks"`
+}
+
+// UnmarshalYAML decodes a rule from YAML, accepting level names such as
+// "error" for the levels and min_level keys.
+func (r *RouteRule) UnmarshalYAML(node *yaml.Node) error {
+	var spec routeRuleSpec
+	if err := node.Decode(&spec); err != nil {
+		return err
+	}
+	rule := RouteRule{
+		Logger:  spec.Logger,
+		Message: spec.Message,
+		Fields:  spec.Fields,
+		Sinks:   spec.Sinks,
+	}
+	for _, name := range spec.Levels {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		rule.Levels = append(rule.Levels, level)
+	}
+	if spec.MinLevel != "" {
+		level, err := ParseLevel(spec.MinLevel)
+		if err != nil {
+			return err
+		}
+		rule.MinLevel = level
+	}
+	*r = rule
+	return nil
+}
+
+// RewriteRule describes a regular expression find/replace applied to the
+// entry message and, optionally, to selected string fields.
+type RewriteRule struct {
+	// Pattern is the regular expression to search for.
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// Replacement replaces every match. It may reference capture groups
+	// using the syntax of regexp.Regexp.ReplaceAllString, e.g. "${1}".
+	Replacement string `json:"replacement" yaml:"replacement"`
+
+	// Fields lists additional field keys whose string or error values are
+	// rewritten. The message is always rewritten unless FieldsOnly is set.
+	Fields []string `json:"fields" yaml:"fields"`
+
+	// FieldsOnly skips the message and rewrites only the listed fields.
+	FieldsOnly bool `json:"fields_only" yaml:"fields_only"`
+}
+
+var (
+	// ScrubMemoryAddresses replaces hexadecimal pointer values such as
+	// "0xc000123450" with "0x?" so otherwise identical messages compare equal.
+	ScrubMemoryAddresses = RewriteRule{
+		Pattern:     `0x[0-9a-fA-F]{6,16}`,
+		Replacement: "0x?",
+	}
+
+	// ScrubFilePaths replaces absolute file paths with "<path>", keeping the
+	// base name, e.g. "/home/build/src/app/db.go:42" becomes "<path>/db.go:42".
+	// URLs are left untouched.
+	ScrubFilePaths = RewriteRule{
+		Pattern:     `(^|[\s"'(=])(?:/[\w.@-]+)+/([\w.@-]+)`,
+		Replacement: "${1}<path>/${2}",
+	}
+)
+
+// SchemaAction specifies what a SchemaValidator does with an entry that does
+// not conform to the schema.
+type SchemaAction string
+
+const (
+	// SchemaDrop discards non-conforming entries.
+	SchemaDrop SchemaAction = "drop"
+
+	// SchemaAnnotate writes non-conforming entries with an added
+	// "schema_errors" field listing the violations.
+	SchemaAnnotate SchemaAction = "annotate"
+
+	// SchemaPanic panics on the first non-conforming entry. Intended for
+	// development and tests, so that schema drift fails loudly.
+	SchemaPanic SchemaAction = "panic"
+)
@@ -0,0 +1,98 @@
+//go:build logx_minimal
+
+package logx
+
+import "fmt"
+
+// The Rewriter and SchemaValidator processors and the query language are
+// compiled out of logx_minimal builds. Their API is kept so that code using
+// it still compiles; the constructors and parsers return an error.
+
+// errNotAvailable returns the error of an API compiled out of logx_minimal
+// builds.
+func errNotAvailable(name string) error {
+	return fmt.Errorf("%s is not available in logx_minimal builds", name)
+}
+
+// Rewriter is a Processor that applies regular expression find/replace rules
+// to entry messages and fields. It is not available in logx_minimal builds.
+type Rewriter struct{}
+
+// NewRewriter returns an error: rewriters are not available in logx_minimal
+// builds.
+func NewRewriter(rules ...RewriteRule) (*Rewriter, error) {
+	return nil, errNotAvailable("Rewriter")
+}
+
+// Process keeps the entry.
+func (r *Rewriter) Process(e *Entry) bool {
+	return true
+}
+
+// SchemaValidator is a Processor that checks entries against a JSON Schema.
+// It is not available in logx_minimal builds.
+type SchemaValidator struct{}
+
+// NewSchemaValidator returns an error: schema validation is not available in
+// logx_minimal builds.
+func NewSchemaValidator(schema []byte, action SchemaAction) (*SchemaValidator, error) {
+	return nil, errNotAvailable("SchemaValidator")
+}
+
+// LoadSchemaValidator returns an error: schema validation is not available
+// in logx_minimal builds.
+func LoadSchemaValidator(path string, action SchemaAction) (*SchemaValidator, error) {
+	return nil, errNotAvailable("SchemaValidator")
+}
+
+// Validate reports no violations.
+func (sv *SchemaValidator) Validate(e *Entry) []string {
+	return nil
+}
+
+// Process keeps the entry.
+func (sv *SchemaValidator) Process(e *Entry) bool {
+	return true
+}
+
+// Query is a compiled entry query. It is not available in logx_minimal
+// builds.
+type Query struct {
+	source string
+}
+
+// ParseQuery returns an error: queries are not available in logx_minimal
+// builds.
+func ParseQuery(expr string) (*Query, error) {
+	return nil, fmt.Errorf("query %q: %w", expr, errNotAvailable("the query language"))
+}
+
+// MustParseQuery panics: queries are not available in logx_minimal builds.
+func MustParseQuery(expr string) *Query {
+	q, err := ParseQuery(expr)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the source expression.
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match reports no match.
+func (q *Query) Match(e *Entry) bool {
+	return false
+}
+
+// Filter returns no entries.
+func (q *Query) Filter(entries []Entry) []Entry {
+	return nil
+}
+
+// Query returns an error: queries are not available in logx_minimal builds.
+func (l *Logger) Query(expr string) ([]Entry, error) {
+	_, err := ParseQuery(expr)
+	return nil, err
+}
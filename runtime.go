@@ -0,0 +1,199 @@
+package logx
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RuntimeMonitorConfig configures the events written by a RuntimeMonitor.
+type RuntimeMonitorConfig struct {
+	// Interval is how often the runtime statistics are sampled. Sampling
+	// briefly stops the world, so keep it in the order of seconds.
+	// Default: 10s
+	Interval time.Duration
+
+	// GCPauseThreshold is the stop-the-world pause above which a GC cycle
+	// is reported.
+	// Default: 100ms
+	GCPauseThreshold time.Duration
+
+	// GoroutineSpikeRatio reports a spike when the number of goroutines
+	// has grown by at least this factor since the previous sample.
+	// Default: 2
+	GoroutineSpikeRatio float64
+
+	// MinGoroutines is the number of goroutines below which spikes are not
+	// reported, so that small absolute changes do not cause noise.
+	// Default: 1000
+	MinGoroutines int
+
+	// MemoryLimitRatio reports when the memory used by the runtime reaches
+	// this fraction of the soft memory limit set by GOMEMLIMIT or
+	// debug.SetMemoryLimit. It is reported again only after usage has
+	// dropped below the ratio. Nothing is reported without a limit.
+	// Default: 0.9
+	MemoryLimitRatio float64
+}
+
+// RuntimeMonitor samples Go runtime statistics in the background and writes
+// Warn entries for significant events: GC cycles pausing longer than the
+// threshold, spikes in the number of goroutines and memory use approaching
+// the soft memory limit. Each entry carries an EventKey field identifying
+// the event, so that occurrences can be queried and alerted on.
+//
+// Call Close to stop sampling.
+type RuntimeMonitor struct {
+	logger *Logger
+	config RuntimeMonitorConfig
+
+	lastGC         uint32 // Number of GC cycles at the previous sample
+	lastGoroutines int    // Number of goroutines at the previous sample
+	nearLimit      bool   // Memory use was at or above the ratio at the previous sample
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartRuntimeMonitor starts sampling runtime statistics and writing
+// runtime events to the logger. Only events after the call are reported.
+//
+// Example:
+//
+//	monitor := logger.StartRuntimeMonitor(logx.RuntimeMonitorConfig{
+//	    GCPauseThreshold: 50 * time.Millisecond,
+//	})
+//	defer monitor.Close()
+//	// {"level":"WARN","message":"Long GC pause","event":"runtime_gc","gc_cycle":812,"pause_ms":63.2,...}
+func (l *Logger) StartRuntimeMonitor(config RuntimeMonitorConfig) *RuntimeMonitor {
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Second
+	}
+	if config.GCPauseThreshold <= 0 {
+		config.GCPauseThreshold = 100 * time.Millisecond
+	}
+	if config.GoroutineSpikeRatio <= 1 {
+		config.GoroutineSpikeRatio = 2
+	}
+	if config.MinGoroutines <= 0 {
+		config.MinGoroutines = 1000
+	}
+	if config.MemoryLimitRatio <= 0 || config.MemoryLimitRatio > 1 {
+		config.MemoryLimitRatio = 0.9
+	}
+	m := &RuntimeMonitor{
+		logger:         l,
+		config:         config,
+		lastGoroutines: runtime.NumGoroutine(),
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	m.lastGC = stats.NumGC
+	go m.run()
+	return m
+}
+
+// run samples the runtime every Interval until Close is called.
+func (m *RuntimeMonitor) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.sample()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// sample reads the runtime statistics and writes the events since the
+// previous sample.
+func (m *RuntimeMonitor) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	m.checkGC(&stats)
+	m.checkGoroutines()
+	m.checkMemoryLimit(&stats)
+}
+
+// checkGC reports the GC cycles since the previous sample whose pause
+// exceeded the threshold. The runtime only retains the last 256 pauses.
+func (m *RuntimeMonitor) checkGC(stats *runtime.MemStats) {
+	first := m.lastGC + 1
+	if stats.NumGC-m.lastGC > 256 {
+		first = stats.NumGC - 255
+	}
+	for cycle := first; cycle <= stats.NumGC; cycle++ {
+		i := (cycle + 255) % 256
+		pause := time.Duration(stats.PauseNs[i])
+		if pause < m.config.GCPauseThreshold {
+			continue
+		}
+		m.write("Long GC pause", []Field{
+			String(EventKey, EventRuntimeGC),
+			Int64("gc_cycle", int64(cycle)),
+			Float64("pause_ms", float64(pause)/float64(time.Millisecond)),
+			Any("gc_time", time.Unix(0, int64(stats.PauseEnd[i]))),
+			Int64("heap_alloc_bytes", int64(stats.HeapAlloc)),
+			Int64("next_gc_bytes", int64(stats.NextGC)),
+		})
+	}
+	m.lastGC = stats.NumGC
+}
+
+// checkGoroutines reports a spike in the number of goroutines.
+func (m *RuntimeMonitor) checkGoroutines() {
+	n := runtime.NumGoroutine()
+	previous := m.lastGoroutines
+	m.lastGoroutines = n
+	if n < m.config.MinGoroutines || float64(n) < float64(previous)*m.config.GoroutineSpikeRatio {
+		return
+	}
+	m.write("Goroutine count spike", []Field{
+		String(EventKey, EventGoroutineSpike),
+		Int("goroutines", n),
+		Int("previous_goroutines", previous),
+	})
+}
+
+// checkMemoryLimit reports memory use reaching the configured fraction of
+// the soft memory limit.
+func (m *RuntimeMonitor) checkMemoryLimit(stats *runtime.MemStats) {
+	limit := debug.SetMemoryLimit(-1)
+	if limit <= 0 || limit == math.MaxInt64 {
+		m.nearLimit = false
+		return
+	}
+	// The memory limit covers all memory mapped by the runtime, except
+	// heap memory returned to the operating system.
+	used := stats.Sys - stats.HeapReleased
+	near := float64(used) >= float64(limit)*m.config.MemoryLimitRatio
+	if near && !m.nearLimit {
+		m.write("Memory limit approached", []Field{
+			String(EventKey, EventMemoryLimit),
+			Int64("memory_used_bytes", int64(used)),
+			Int64("memory_limit_bytes", limit),
+			Float64("memory_limit_ratio", float64(used)/float64(limit)),
+		})
+	}
+	m.nearLimit = near
+}
+
+// write writes a runtime event at WarnLevel.
+func (m *RuntimeMonitor) write(msg string, fields []Field) {
+	m.logger.emit(&Entry{Time: time.Now(), Level: WarnLevel, Message: msg, Fields: fields})
+}
+
+// Close stops sampling. It is safe to call Close more than once.
+func (m *RuntimeMonitor) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	<-m.done
+	return nil
+}
@@ -0,0 +1,200 @@
+package logx
+
+import (
+	"sync"
+	"time"
+)
+
+// Keys of the sampling metadata added to an entry that stands in for
+// entries dropped before it, so that downstream analytics can re-weight
+// counts: the entry represents itself and SuppressedCountKey more entries
+// logged over the SampleWindowKey duration.
+const (
+	SampledKey         = "sampled"
+	SuppressedCountKey = "suppressed_count"
+	SampleWindowKey    = "window"
+)
+
+// samplingFields returns the sampling metadata for an entry standing in for
+// suppressed entries logged over window.
+func samplingFields(suppressed int, window time.Duration) []Field {
+	return []Field{
+		Bool(SampledKey, true),
+		Int(SuppressedCountKey, suppressed),
+		String(SampleWindowKey, window.String()),
+	}
+}
+
+// AdaptiveSamplerConfig configures an AdaptiveSampler.
+type AdaptiveSamplerConfig struct {
+	// BaseRate is the fraction of sampled entries kept during calm
+	// periods, between 0 and 1.
+	// Default: 0.1
+	BaseRate float64
+
+	// BoostRate is the fraction kept right after an error, between 0 and 1.
+	// Default: 1 (keep everything)
+	BoostRate float64
+
+	// Window is how long the boost lasts after the most recent error. The
+	// rate decays linearly from BoostRate back to BaseRate over the window.
+	// Default: 30s
+	Window time.Duration
+
+	// MinLevel is the lowest level that is never sampled and that triggers
+	// the boost.
+	// Default: ErrorLevel
+	MinLevel Level
+
+	// Lookback is the number of most recently dropped entries retained and
+	// written just before an error, so that the context leading up to it
+	// is preserved as well. They are marked with "lookback":true and keep
+	// their original time in "logged_at". Zero disables the lookback.
+	// Default: 0
+	Lookback int
+}
+
+// AdaptiveSampler is a Processor that samples entries below MinLevel at a
+// rate driven by errors: after an entry at MinLevel or above, it keeps
+// BoostRate of entries and decays back to BaseRate over Window, so that the
+// diagnostic context around errors is preserved while average volume is
+// cut during calm periods. Entries at MinLevel or above are never dropped.
+//
+// Sampling is deterministic: at rate r, every 1/r-th entry is kept. A kept
+// entry that follows dropped entries carries the sampling metadata
+// (SampledKey, SuppressedCountKey and SampleWindowKey), counting the entries
+// dropped since the previous kept entry. Lookback entries are included in
+// these counts, so analytics re-weighting counts should skip entries marked
+// with "lookback".
+type AdaptiveSampler struct {
+	config AdaptiveSamplerConfig
+
+	mu        sync.Mutex
+	lastError time.Time
+	credit    float64   // Accumulated fraction of an entry to keep
+	dropped   int       // Entries dropped since the previous kept entry
+	since     time.Time // Time of the previous kept or first dropped entry
+	lookback  []Entry   // Ring of recently dropped entries
+	start     int
+	emit      func(e *Entry)
+}
+
+// NewAdaptiveSampler creates an AdaptiveSampler with the given
+// configuration.
+//
+// Example:
+//
+//	sampler := logx.NewAdaptiveSampler(logx.AdaptiveSamplerConfig{
+//	    BaseRate: 0.05,
+//	    Window:   time.Minute,
+//	    Lookback: 200,
+//	})
+//	config := logx.DefaultConfig()
+//	config.Processors = []logx.Processor{sampler}
+func NewAdaptiveSampler(config AdaptiveSamplerConfig) *AdaptiveSampler {
+	if config.BaseRate <= 0 || config.BaseRate > 1 {
+		config.BaseRate = 0.1
+	}
+	if config.BoostRate <= 0 || config.BoostRate > 1 {
+		config.BoostRate = 1
+	}
+	if config.BoostRate < config.BaseRate {
+		config.BoostRate = config.BaseRate
+	}
+	if config.Window <= 0 {
+		config.Window = 30 * time.Second
+	}
+	if config.MinLevel == TraceLevel {
+		config.MinLevel = ErrorLevel
+	}
+	return &AdaptiveSampler{config: config}
+}
+
+// Bind sets the function used to write the lookback entries.
+func (s *AdaptiveSampler) Bind(emit func(e *Entry)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emit = emit
+}
+
+// Rate returns the fraction of sampled entries currently kept.
+func (s *AdaptiveSampler) Rate() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rateLocked(time.Now())
+}
+
+// rateLocked returns the sampling rate at now. s.mu must be held.
+func (s *AdaptiveSampler) rateLocked(now time.Time) float64 {
+	if s.lastError.IsZero() {
+		return s.config.BaseRate
+	}
+	elapsed := now.Sub(s.lastError)
+	if elapsed >= s.config.Window {
+		return s.config.BaseRate
+	}
+	remaining := 1 - float64(elapsed)/float64(s.config.Window)
+	return s.config.BaseRate + (s.config.BoostRate-s.config.BaseRate)*remaining
+}
+
+// Process keeps entries at MinLevel or above, boosting the rate and
+// writing the lookback, and samples the others at the current rate.
+func (s *AdaptiveSampler) Process(e *Entry) bool {
+	now := e.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Level >= s.config.MinLevel {
+		s.lastError = now
+		s.credit = 1 // Keep the entry right after the error
+		s.flushLookbackLocked()
+		return true
+	}
+	s.credit += s.rateLocked(now)
+	if s.credit >= 1-1e-9 { // Tolerate rounding, e.g. ten steps of 0.1
+		s.credit--
+		if s.dropped > 0 {
+			e.Fields = append(e.Fields, samplingFields(s.dropped, now.Sub(s.since))...)
+		}
+		s.dropped = 0
+		s.since = now
+		return true
+	}
+	if s.since.IsZero() {
+		s.since = now
+	}
+	s.dropped++
+	if s.config.Lookback > 0 {
+		s.rememberLocked(e)
+	}
+	return false
+}
+
+// rememberLocked adds a dropped entry to the lookback ring. s.mu must be
+// held.
+func (s *AdaptiveSampler) rememberLocked(e *Entry) {
+	if len(s.lookback) < s.config.Lookback {
+		s.lookback = append(s.lookback, *e.Clone())
+		return
+	}
+	s.lookback[s.start] = *e.Clone()
+	s.start = (s.start + 1) % len(s.lookback)
+}
+
+// flushLookbackLocked writes the retained entries, oldest first, and
+// empties the ring. s.mu must be held.
+func (s *AdaptiveSampler) flushLookbackLocked() {
+	if s.emit != nil {
+		for i := range s.lookback {
+			entry := s.lookback[(s.start+i)%len(s.lookback)]
+			entry.Fields = append(entry.Fields[:len(entry.Fields):len(entry.Fields)],
+				Bool("lookback", true), Any("logged_at", entry.Time))
+			s.emit(&entry)
+		}
+	}
+	s.lookback = s.lookback[:0]
+	s.start = 0
+}
@@ -0,0 +1,96 @@
+package logx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// needsSanitizing reports whether s contains invalid UTF-8, control
+// characters or Unicode line separators.
+func needsSanitizing(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c < 0x20 || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return true
+		}
+		if (r >= 0x80 && r <= 0x9f) || r == '\u2028' || r == '\u2029' {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// sanitizeString replaces invalid UTF-8 sequences with U+FFFD and escapes
+// control characters and line separators, so that the result always renders
+// on a single line and cannot inject terminal escape sequences.
+//
+// Common whitespace is escaped as \n, \r and \t; other control characters
+// are written as \u00XX.
+//
+// Example:
+//
+//	sanitizeString("line1\nline2")  // `line1\nline2`
+//	sanitizeString("\x1b[31mred")   // `\u001b[31mred`
+//	sanitizeString("bad\xffbyte")   // "bad�byte"
+func sanitizeString(s string) string {
+	if !needsSanitizing(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f) || r == '\u2028' || r == '\u2029':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// sanitizeValue applies sanitizeString to string-like field values. Strings,
+// byte slices, errors and string slices are sanitized; other values are
+// returned unchanged and left to the encoder.
+func sanitizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return sanitizeString(value)
+	case []byte:
+		return sanitizeString(string(value))
+	case error:
+		if value == nil {
+			return value
+		}
+		return sanitizeString(value.Error())
+	case []string:
+		sanitized := make([]string, len(value))
+		for i, s := range value {
+			sanitized[i] = sanitizeString(s)
+		}
+		return sanitized
+	default:
+		return v
+	}
+}
@@ -0,0 +1,352 @@
+package logx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxWindowDuration bounds LevelWindow.Duration, which is checked minute by
+// minute.
+const maxWindowDuration = 31 * 24 * time.Hour
+
+// LevelWindow is a recurring period during which the logger runs at a
+// different level, e.g. Debug during a weekly deployment window.
+type LevelWindow struct {
+	// Name identifies the window in the entries written when it opens and
+	// closes.
+	// Default: the cron expression
+	Name string `yaml:"name"`
+
+	// Cron is a standard five-field cron expression (minute, hour, day of
+	// month, month, day of week) giving the times the window opens, e.g.
+	// "0 2 * * sat". Lists, ranges, steps, month and weekday names and the
+	// @hourly, @daily, @weekly, @monthly and @yearly shorthands are
+	// supported. A "CRON_TZ=Europe/Paris " prefix evaluates it in that time
+	// zone instead of the local one.
+	Cron string `yaml:"cron"`
+
+	// Duration is how long the window stays open, at most 31 days.
+	Duration time.Duration `yaml:"duration"`
+
+	// Level is the level applied while the window is open.
+	Level Level `yaml:"level"`
+}
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64 // Bit sets of the matching values
+	anyDom, anyDow                bool   // Whether the day fields start with "*"
+	location                      *time.Location
+}
+
+// cronField describes the values of a cron field.
+type cronField struct {
+	min, max int
+	names    []string // Names of the values from min, if any
+}
+
+var (
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	cronDow = cronField{min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronShorthands are the expressions of the @ shorthands.
+var cronShorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a five-field cron expression, as described for
+// LevelWindow.Cron.
+//
+// Example:
+//
+//	schedule, err := logx.ParseCron("*/15 9-17 * * mon-fri")
+//	if err == nil && schedule.Matches(time.Now()) {
+//	    // on a quarter hour during office hours
+//	}
+func ParseCron(expr string) (*CronSchedule, error) {
+	s := &CronSchedule{location: time.Local}
+	spec := strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(spec, "CRON_TZ="); ok {
+		name, fields, _ := strings.Cut(rest, " ")
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		s.location = location
+		spec = strings.TrimSpace(fields)
+	}
+	if shorthand, ok := cronShorthands[strings.ToLower(spec)]; ok {
+		spec = shorthand
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	var err error
+	for i, target := range []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow} {
+		field := []cronField{cronMinute, cronHour, cronDom, cronMonth, cronDow}[i]
+		if *target, err = field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too
+	}
+	s.anyDom = strings.HasPrefix(fields[2], "*")
+	s.anyDow = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parse returns the bit set of the values matched by a field.
+func (f cronField) parse(field string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+		lo, hi := f.min, f.max
+		if rangeSpec != "*" {
+			first, last, isRange := strings.Cut(rangeSpec, "-")
+			var err error
+			if lo, err = f.value(first); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(last); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q", rangeSpec)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a number or name of the field.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Matches reports whether the schedule fires in the minute of t. As in
+// cron, when both the day of month and the day of week are restricted, a
+// day matching either is enough.
+func (s *CronSchedule) Matches(t time.Time) bool {
+	t = t.In(s.location)
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// scheduledWindow is a LevelWindow with its parsed schedule.
+type scheduledWindow struct {
+	LevelWindow
+	cron *CronSchedule
+}
+
+// parseLevelWindows validates windows and parses their schedules.
+func parseLevelWindows(windows []LevelWindow) ([]scheduledWindow, error) {
+	parsed := make([]scheduledWindow, 0, len(windows))
+	for _, w := range windows {
+		cron, err := ParseCron(w.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("level window %q: %w", w.Name, err)
+		}
+		if w.Duration < time.Minute || w.Duration > maxWindowDuration {
+			return nil, fmt.Errorf("level window %q: duration %s out of range 1m-744h", w.Name, w.Duration)
+		}
+		if w.Name == "" {
+			w.Name = w.Cron
+		}
+		parsed = append(parsed, scheduledWindow{LevelWindow: w, cron: cron})
+	}
+	return parsed, nil
+}
+
+// openAt reports whether the window is open at t: whether its schedule
+// fired less than Duration before t.
+func (w *scheduledWindow) openAt(t time.Time) bool {
+	t = t.Truncate(time.Minute)
+	for start := t; t.Sub(start) < w.Duration; start = start.Add(-time.Minute) {
+		if w.cron.Matches(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// levelSchedule applies the Config.LevelSchedule windows to a logger,
+// checking them at the start of every minute.
+type levelSchedule struct {
+	control  sync.Mutex // Serializes set and close
+	mu       sync.Mutex // Guards the windows and the open window
+	windows  []scheduledWindow
+	open     int   // Index of the open window, -1 if none
+	previous Level // Level to restore when the open window closes
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newLevelSchedule returns a schedule with no windows.
+func newLevelSchedule() *levelSchedule {
+	return &levelSchedule{open: -1}
+}
+
+// set replaces the windows and applies them to l at once, with the level
+// of l as the level outside windows. The goroutine checking the windows
+// runs while there are any.
+func (s *levelSchedule) set(l *Logger, windows []scheduledWindow) {
+	s.control.Lock()
+	defer s.control.Unlock()
+	s.halt()
+	s.mu.Lock()
+	s.windows = windows
+	s.open = -1
+	s.mu.Unlock()
+	if len(windows) == 0 {
+		return
+	}
+	s.update(l, time.Now())
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(l, s.stop, s.done)
+}
+
+// run updates the level at the start of every minute until stop is
+// closed.
+func (s *levelSchedule) run(l *Logger, stop, done chan struct{}) {
+	defer close(done)
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case now = <-timer.C:
+			s.update(l, now)
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// update opens or closes windows for time now. When windows overlap, the
+// most verbose level applies. Like Boost, closing a window restores the
+// previous level only if the level was not changed while it was open.
+func (s *levelSchedule) update(l *Logger, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	open := -1
+	for i := range s.windows {
+		if s.windows[i].openAt(now) && (open < 0 || s.windows[i].Level < s.windows[open].Level) {
+			open = i
+		}
+	}
+	if open == s.open {
+		return
+	}
+	if s.open >= 0 {
+		closing := s.windows[s.open]
+		restored := l.GetLevel()
+		restore := restored == closing.Level
+		if restore {
+			restored = s.previous
+		}
+		// Write the marker while the more verbose of the two levels applies
+		ended := &Entry{Time: now, Level: windowMarkerLevel(restored, closing.Level), Message: "Scheduled level window ended", Fields: []Field{
+			String("level_window", closing.Name),
+			String("restored_level", restored.String()),
+		}}
+		if !restore || restored >= closing.Level {
+			l.emit(ended)
+		}
+		if restore {
+			l.SetLevel(restored)
+			if restored < closing.Level {
+				l.emit(ended)
+			}
+		}
+	}
+	s.open = open
+	if open >= 0 {
+		opening := s.windows[open]
+		s.previous = l.GetLevel()
+		started := &Entry{Time: now, Level: windowMarkerLevel(s.previous, opening.Level), Message: "Scheduled level window started", Fields: []Field{
+			String("level_window", opening.Name),
+			String("level", opening.Level.String()),
+			String("previous_level", s.previous.String()),
+		}}
+		if opening.Level > s.previous {
+			l.emit(started)
+		}
+		l.SetLevel(opening.Level)
+		if opening.Level <= s.previous {
+			l.emit(started)
+		}
+	}
+}
+
+// windowMarkerLevel returns the level of the entries written when a window
+// changes the level from a to b: Info, or the more verbose of a and b if
+// both are above Info, so that the entries pass the level they are written
+// at.
+func windowMarkerLevel(a, b Level) Level {
+	return max(InfoLevel, min(a, b))
+}
+
+// close stops the goroutine checking the windows, if running. The level
+// is left as it is.
+func (s *levelSchedule) close() {
+	s.control.Lock()
+	defer s.control.Unlock()
+	s.halt()
+}
+
+// halt stops the goroutine, with control held.
+func (s *levelSchedule) halt() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop, s.done = nil, nil
+	}
+}
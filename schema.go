@@ -0,0 +1,261 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// jsonSchema is the supported subset of JSON Schema: type, enum, required,
+// properties, additionalProperties, pattern, minimum, maximum and items.
+type jsonSchema struct {
+	Type                 interface{}            `json:"type"`
+	Enum                 []interface{}          `json:"enum"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	Items                *jsonSchema            `json:"items"`
+
+	types   []string
+	pattern *regexp.Regexp
+}
+
+// compile resolves the type list and compiles patterns recursively.
+func (s *jsonSchema) compile() error {
+	switch t := s.Type.(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("invalid type %v", item)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return fmt.Errorf("invalid type %v", t)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	for name, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	return nil
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value.
+func jsonType(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// validate appends a description of every violation of v to errs.
+func (s *jsonSchema) validate(path string, v interface{}, errs []string) []string {
+	if len(s.types) > 0 {
+		actual := jsonType(v)
+		ok := false
+		for _, t := range s.types {
+			if t == actual || (t == "number" && actual == "integer") {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.types, " or "), actual))
+		}
+	}
+	if len(s.Enum) > 0 {
+		ok := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(allowed, v) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: value %v not in enum", path, v))
+		}
+	}
+
+	switch value := v.(type) {
+	case string:
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			errs = append(errs, fmt.Sprintf("%s: does not match pattern %q", path, s.Pattern))
+		}
+	case float64:
+		if s.Minimum != nil && value < *s.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: %v is less than minimum %v", path, value, *s.Minimum))
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: %v is greater than maximum %v", path, value, *s.Maximum))
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				errs = s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if prop, ok := s.Properties[key]; ok {
+				errs = prop.validate(childPath, value[key], errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, fmt.Sprintf("%s: additional property not allowed", childPath))
+			}
+		}
+	}
+	return errs
+}
+
+// SchemaValidator is a Processor that checks entries against a JSON Schema
+// before they are written, catching schema drift before it breaks downstream
+// dashboards. The entry is validated as the object it is encoded to: the
+// level, message and (for named loggers) logger name under the keys
+// written by the logger, "level", "message" and "logger" by default, plus
+// every field.
+//
+// A practical subset of JSON Schema is supported: type, enum, required,
+// properties, additionalProperties, pattern, minimum, maximum and items.
+type SchemaValidator struct {
+	schema *jsonSchema
+	action SchemaAction
+	keys   atomic.Pointer[entryKeys] // Envelope of the logger, bound by New
+}
+
+// NewSchemaValidator parses a JSON Schema document and returns a validator
+// applying the given action to non-conforming entries.
+//
+// Example:
+//
+//	validator, err := logx.NewSchemaValidator([]byte(`{
+//	    "type": "object",
+//	    "required": ["request_id"],
+//	    "properties": {
+//	        "level":       {"enum": ["INFO", "WARN", "ERROR"]},
+//	        "request_id":  {"type": "string"},
+//	        "duration_ms": {"type": "number", "minimum": 0}
+//	    }
+//	}`), logx.SchemaAnnotate)
+func NewSchemaValidator(schema []byte, action SchemaAction) (*SchemaValidator, error) {
+	switch action {
+	case SchemaDrop, SchemaAnnotate, SchemaPanic:
+	case "":
+		action = SchemaAnnotate
+	default:
+		return nil, fmt.Errorf("unknown schema action %q", action)
+	}
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	sv := &SchemaValidator{schema: &s, action: action}
+	sv.keys.Store(defaultEntryKeys)
+	return sv, nil
+}
+
+// LoadSchemaValidator reads a JSON Schema from the file at path.
+// See NewSchemaValidator.
+func LoadSchemaValidator(path string, action SchemaAction) (*SchemaValidator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return NewSchemaValidator(data, action)
+}
+
+// bindKeys sets the envelope of the logger the validator is given to.
+func (sv *SchemaValidator) bindKeys(keys *entryKeys) {
+	sv.keys.Store(keys)
+}
+
+// Validate checks the entry against the schema and returns the violations
+// found, or nil if the entry conforms.
+func (sv *SchemaValidator) Validate(e *Entry) []string {
+	keys := sv.keys.Load()
+	doc := make(map[string]interface{}, len(e.Fields)+3)
+	doc[keys.level] = keys.levelName(e.Level)
+	doc[keys.message] = e.Message
+	if e.LoggerName != "" {
+		doc[keys.logger] = e.LoggerName
+	}
+	for _, field := range e.Fields {
+		doc[field.Key] = schemaValue(field.Value)
+	}
+	return sv.schema.validate("", doc, nil)
+}
+
+// Process applies the configured action to non-conforming entries.
+func (sv *SchemaValidator) Process(e *Entry) bool {
+	errs := sv.Validate(e)
+	if len(errs) == 0 {
+		return true
+	}
+	switch sv.action {
+	case SchemaDrop:
+		return false
+	case SchemaPanic:
+		panic(fmt.Sprintf("logx: entry %q violates schema: %s", e.Message, strings.Join(errs, "; ")))
+	default:
+		e.Fields = append(e.Fields, Any("schema_errors", errs))
+		return true
+	}
+}
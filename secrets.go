@@ -0,0 +1,414 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SecretSource returns the current values of a set of secrets, e.g. from
+// a secrets manager. NewVaultSource and NewAWSSecretsSource return sources
+// for HashiCorp Vault and AWS Secrets Manager.
+type SecretSource interface {
+	Secrets(ctx context.Context) ([]string, error)
+}
+
+// SecretSourceFunc is an adapter that allows an ordinary function to be
+// used as a SecretSource.
+type SecretSourceFunc func(ctx context.Context) ([]string, error)
+
+// Secrets calls f(ctx).
+func (f SecretSourceFunc) Secrets(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// SecretMaskConfig configures a SecretMasker.
+type SecretMaskConfig struct {
+	// Sources provide the secret values to mask. They are read when the
+	// masker is created and every Interval after that.
+	Sources []SecretSource
+
+	// Interval is the time between two reads of the sources, so that
+	// rotated secrets are masked soon after they change.
+	// Default: 5m
+	Interval time.Duration
+
+	// MinLength ignores secret values shorter than this, which would mask
+	// common words and numbers.
+	// Default: 6
+	MinLength int
+
+	// Replacement replaces every occurrence of a secret value.
+	// Default: "***MASKED***"
+	Replacement string
+
+	// Timeout bounds every read of a source.
+	// Default: 30s
+	Timeout time.Duration
+
+	// OnError is called when a source cannot be read after the masker was
+	// created. The values last read from it are still masked.
+	// Default: nil (errors are ignored)
+	OnError func(err error)
+}
+
+// SecretMasker is a Processor masking the literal values of known secrets
+// wherever they appear: in the message and in every string and error
+// field, whatever its key. Unlike masking by key, it catches secrets
+// accidentally interpolated into messages, such as a connection string
+// logged with its password. The values are read from secret managers and
+// refreshed in the background; call Close to stop refreshing.
+type SecretMasker struct {
+	config   SecretMaskConfig
+	replacer atomic.Pointer[strings.Replacer] // Nil if there is nothing to mask
+
+	mu      sync.Mutex
+	fetched [][]string // Values last read from each source
+	added   []string   // Values given to Add
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewSecretMasker reads the sources and returns a masker for their values.
+// An error is returned if a source cannot be read, since logging without
+// its secrets masked could leak them.
+//
+// Example:
+//
+//	vault, err := logx.NewVaultSource(logx.VaultConfig{
+//	    Address: "https://vault.example.com:8200",
+//	    Token:   func() (string, error) { return os.Getenv("VAULT_TOKEN"), nil },
+//	    Paths:   []string{"secret/data/payments"},
+//	})
+//	masker, err := logx.NewSecretMasker(logx.SecretMaskConfig{Sources: []logx.SecretSource{vault}})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer masker.Close()
+//	config.Processors = []logx.Processor{masker}
+func NewSecretMasker(config SecretMaskConfig) (*SecretMasker, error) {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.MinLength <= 0 {
+		config.MinLength = 6
+	}
+	if config.Replacement == "" {
+		config.Replacement = "***MASKED***"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	m := &SecretMasker{
+		config:  config,
+		fetched: make([][]string, len(config.Sources)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := m.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	go m.run()
+	return m, nil
+}
+
+// run refreshes the values every Interval until Close.
+func (m *SecretMasker) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.Refresh(context.Background()); err != nil && m.config.OnError != nil {
+				m.config.OnError(err)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Refresh reads the sources again and masks their current values. Sources
+// that cannot be read keep their previous values; the first error is
+// returned.
+func (m *SecretMasker) Refresh(ctx context.Context) error {
+	var firstErr error
+	for i, source := range m.config.Sources {
+		readCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+		values, err := source.Secrets(readCtx)
+		cancel()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to read secret source %d: %w", i, err)
+			}
+			continue
+		}
+		m.mu.Lock()
+		m.fetched[i] = values
+		m.mu.Unlock()
+	}
+	m.rebuild()
+	return firstErr
+}
+
+// Add masks values in addition to those of the sources, e.g. secrets
+// generated at runtime.
+func (m *SecretMasker) Add(values ...string) {
+	m.mu.Lock()
+	m.added = append(m.added, values...)
+	m.mu.Unlock()
+	m.rebuild()
+}
+
+// rebuild replaces the replacer with one for the current values. Longer
+// values come first, so that a secret containing another is masked whole.
+func (m *SecretMasker) rebuild() {
+	m.mu.Lock()
+	seen := make(map[string]bool)
+	var values []string
+	for _, set := range append(m.fetched, m.added) {
+		for _, value := range set {
+			if len(value) >= m.config.MinLength && !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if len(values) == 0 {
+		m.replacer.Store(nil)
+		return
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	pairs := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		pairs = append(pairs, value, m.config.Replacement)
+	}
+	m.replacer.Store(strings.NewReplacer(pairs...))
+}
+
+// Process masks the secret values in the entry's message and string and
+// error fields.
+func (m *SecretMasker) Process(e *Entry) bool {
+	replacer := m.replacer.Load()
+	if replacer == nil {
+		return true
+	}
+	e.Message = replacer.Replace(e.Message)
+	for i := range e.Fields {
+		switch v := e.Fields[i].Value.(type) {
+		case string:
+			e.Fields[i].Value = replacer.Replace(v)
+		case error:
+			if v != nil {
+				if masked := replacer.Replace(v.Error()); masked != v.Error() {
+					e.Fields[i].Value = masked
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Close stops refreshing the values. Values already read are still
+// masked.
+func (m *SecretMasker) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+		<-m.done
+	})
+	return nil
+}
+
+// VaultConfig configures a SecretSource reading secrets from HashiCorp
+// Vault.
+type VaultConfig struct {
+	// Address is the URL of the Vault server. Required.
+	Address string
+
+	// Token returns the Vault token requests are made with. It is called
+	// for every read, so that renewed tokens are picked up. Required.
+	Token func() (string, error)
+
+	// Paths are the API paths of the secrets, below /v1/, e.g.
+	// "secret/data/payments" for a KV version 2 engine mounted at
+	// "secret". The values of every key of the secrets are masked.
+	// Required.
+	Paths []string
+
+	// Namespace is the Vault Enterprise namespace of the secrets.
+	// Default: ""
+	Namespace string
+
+	// Client sends the requests.
+	// Default: an http.Client with a 10s timeout
+	Client *http.Client
+}
+
+// NewVaultSource returns a source reading the string values of secrets in
+// Vault key/value engines, versions 1 and 2.
+func NewVaultSource(config VaultConfig) (SecretSource, error) {
+	if config.Address == "" || config.Token == nil || len(config.Paths) == 0 {
+		return nil, fmt.Errorf("vault source requires an address, a token and paths")
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	address := strings.TrimSuffix(config.Address, "/")
+	return SecretSourceFunc(func(ctx context.Context) ([]string, error) {
+		token, err := config.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vault token: %w", err)
+		}
+		var values []string
+		for _, path := range config.Paths {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, address+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("X-Vault-Token", token)
+			if config.Namespace != "" {
+				req.Header.Set("X-Vault-Namespace", config.Namespace)
+			}
+			var secret struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := doSecretRequest(config.Client, req, &secret); err != nil {
+				return nil, fmt.Errorf("failed to read vault secret %s: %w", path, err)
+			}
+			data := secret.Data
+			if nested, ok := data["data"].(map[string]interface{}); ok {
+				if _, ok := data["metadata"]; ok { // KV version 2
+					data = nested
+				}
+			}
+			values = append(values, stringValues(data)...)
+		}
+		return values, nil
+	}), nil
+}
+
+// AWSSecretsConfig configures a SecretSource reading secrets from AWS
+// Secrets Manager.
+type AWSSecretsConfig struct {
+	// Region is the AWS region of the secrets, e.g. "eu-west-1". Required.
+	Region string
+
+	// Credentials returns the credentials requests are signed with. It is
+	// called for every read, so that rotating credentials are picked up.
+	// Required.
+	Credentials func() (AWSCredentials, error)
+
+	// SecretIDs are the names or ARNs of the secrets. A secret whose
+	// string is a JSON object, as for database credentials, contributes
+	// the values of its keys; any other secret string is masked whole.
+	// Required.
+	SecretIDs []string
+
+	// Endpoint is the Secrets Manager API URL.
+	// Default: "https://secretsmanager.<Region>.amazonaws.com"
+	Endpoint string
+
+	// Client sends the requests.
+	// Default: an http.Client with a 10s timeout
+	Client *http.Client
+}
+
+// NewAWSSecretsSource returns a source reading the current versions of
+// secrets in AWS Secrets Manager with GetSecretValue.
+func NewAWSSecretsSource(config AWSSecretsConfig) (SecretSource, error) {
+	if config.Region == "" || config.Credentials == nil || len(config.SecretIDs) == 0 {
+		return nil, fmt.Errorf("aws secrets source requires a region, credentials and secret IDs")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://secretsmanager." + config.Region + ".amazonaws.com"
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return SecretSourceFunc(func(ctx context.Context) ([]string, error) {
+		credentials, err := config.Credentials()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get aws credentials: %w", err)
+		}
+		var values []string
+		for _, id := range config.SecretIDs {
+			req, err := newJSONRequest(strings.TrimSuffix(config.Endpoint, "/"),
+				map[string]string{"SecretId": id},
+				map[string]string{
+					"Content-Type": "application/x-amz-json-1.1",
+					"X-Amz-Target": "secretsmanager.GetSecretValue",
+				})
+			if err != nil {
+				return nil, err
+			}
+			req = req.WithContext(ctx)
+			if err := signAWSv4(req, credentials, config.Region, "secretsmanager", time.Now()); err != nil {
+				return nil, err
+			}
+			var secret struct{ SecretString string }
+			if err := doSecretRequest(config.Client, req, &secret); err != nil {
+				return nil, fmt.Errorf("failed to read aws secret %s: %w", id, err)
+			}
+			var fields map[string]interface{}
+			if json.Unmarshal([]byte(secret.SecretString), &fields) == nil {
+				values = append(values, stringValues(fields)...)
+			} else {
+				values = append(values, secret.SecretString)
+			}
+		}
+		return values, nil
+	}), nil
+}
+
+// doSecretRequest sends req and decodes its JSON response into v.
+func doSecretRequest(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The body is not included, as it could contain secret material
+		return fmt.Errorf("%s responded %s", req.URL.Host, resp.Status)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// stringValues returns the non-empty string values of fields, in key
+// order.
+func stringValues(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var values []string
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,233 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SentryConfig configures a sink forwarding Error and Fatal entries to
+// Sentry as events.
+type SentryConfig struct {
+	// DSN is the Data Source Name of the Sentry project, e.g.
+	// "https://<key>@o123.ingest.sentry.io/456". Required.
+	DSN string
+
+	// Environment tags the events, e.g. "production".
+	// Default: ""
+	Environment string
+
+	// Release tags the events with the version of the application, e.g.
+	// "checkout@1.4.2".
+	// Default: ""
+	Release string
+
+	// ServerName identifies the host in the events.
+	// Default: the hostname
+	ServerName string
+
+	// Tags are added to every event.
+	// Default: nil
+	Tags map[string]string
+
+	// SampleRate is the fraction of entries sent, between 0 and 1.
+	// Default: 1 (every entry)
+	SampleRate float64
+
+	// MinLevel is the lowest level forwarded. Entries below it, and below
+	// Error, are ignored.
+	// Default: ErrorLevel
+	MinLevel Level
+
+	// Batch configures batching. Sentry accepts one event per request, so
+	// MaxEntries is always 1 and every forwarded entry is sent as it is
+	// written; combine with Config.Async to keep logging from waiting on
+	// Sentry.
+	Batch BatchConfig
+}
+
+// sentryLevels maps logx level names to Sentry levels.
+var sentryLevels = map[string]string{"ERROR": "error", "FATAL": "fatal"}
+
+// NewSentrySink returns a sink forwarding Error and Fatal entries to Sentry
+// as events, while other levels only go to the other outputs. The message
+// becomes the event message, the error field the exception, with the
+// stacktrace of the entry when Config.AddStacktrace is set, and the error
+// fingerprint, trace and span IDs group and link the event; the remaining
+// fields become the event's extra data.
+//
+// Example:
+//
+//	sentry, err := logx.NewSentrySink(logx.SentryConfig{
+//	    DSN:         os.Getenv("SENTRY_DSN"),
+//	    Environment: "production",
+//	    Release:     "checkout@" + version,
+//	    SampleRate:  0.5,
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "sentry", Writer: sentry}}
+//	defer sentry.Close()
+func NewSentrySink(config SentryConfig) (*APISink, error) {
+	dsn, err := url.Parse(config.DSN)
+	if err != nil || dsn.User == nil || dsn.User.Username() == "" || dsn.Host == "" {
+		return nil, fmt.Errorf("sentry sink requires a DSN of the form https://<key>@<host>/<project>")
+	}
+	path := strings.TrimSuffix(dsn.Path, "/")
+	slash := strings.LastIndexByte(path, '/')
+	project := path[slash+1:]
+	if project == "" {
+		return nil, fmt.Errorf("sentry DSN %q has no project ID", config.DSN)
+	}
+	target := dsn.Scheme + "://" + dsn.Host + path[:slash] + "/api/" + project + "/envelope/"
+	auth := "Sentry sentry_version=7, sentry_client=go-logx, sentry_key=" + dsn.User.Username()
+	if secret, ok := dsn.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+	if config.ServerName == "" {
+		config.ServerName, _ = os.Hostname()
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+	if config.MinLevel < ErrorLevel {
+		config.MinLevel = ErrorLevel
+	}
+	config.Batch.MaxEntries = 1
+
+	return newAPISink(config.Batch, apiFormat{
+		maxEntries: 1,
+		keep: func(entry apiEntry) bool {
+			level, err := ParseLevel(entry.keys.levelOf(entry.fields))
+			return err == nil && level >= config.MinLevel &&
+				(config.SampleRate == 1 || rand.Float64() < config.SampleRate)
+		},
+		request: func(batch []apiEntry) (*http.Request, error) {
+			event := sentryEvent(batch[0].fields, batch[0].keys, config)
+			data, err := json.Marshal(event)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode event: %w", err)
+			}
+			header, _ := json.Marshal(map[string]interface{}{
+				"event_id": event["event_id"],
+				"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+			})
+			item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(data)})
+			var body bytes.Buffer
+			body.Write(header)
+			body.WriteByte('\n')
+			body.Write(item)
+			body.WriteByte('\n')
+			body.Write(data)
+			body.WriteByte('\n')
+			req, err := http.NewRequest(http.MethodPost, target, &body)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/x-sentry-envelope")
+			req.Header.Set("X-Sentry-Auth", auth)
+			return req, nil
+		},
+	})
+}
+
+// sentryEvent converts the fields of an entry to a Sentry event, moving the
+// fields with an event equivalent out of its extra data.
+func sentryEvent(fields map[string]interface{}, keys *entryKeys, config SentryConfig) map[string]interface{} {
+	event := map[string]interface{}{
+		"event_id":  randomHex(16),
+		"timestamp": keys.takeTime(fields).UTC().Format(time.RFC3339Nano),
+		"platform":  "go",
+		"level":     "error",
+	}
+	if _, ok := fields[keys.level].(string); ok {
+		if mapped, ok := sentryLevels[keys.levelOf(fields)]; ok {
+			event["level"] = mapped
+		}
+		delete(fields, keys.level)
+	}
+	if message, ok := fields[keys.message].(string); ok {
+		event["logentry"] = map[string]interface{}{"formatted": message}
+		delete(fields, keys.message)
+	}
+	for key, name := range map[string]string{keys.logger: "logger", keys.caller: "culprit"} {
+		if value, ok := fields[key].(string); ok {
+			event[name] = value
+			delete(fields, key)
+		}
+	}
+	for key, value := range map[string]string{
+		"environment": config.Environment, "release": config.Release, "server_name": config.ServerName,
+	} {
+		if value != "" {
+			event[key] = value
+		}
+	}
+	if len(config.Tags) > 0 {
+		event["tags"] = config.Tags
+	}
+
+	var frames []map[string]interface{}
+	if stack, ok := fields[keys.stacktrace].(string); ok {
+		frames = sentryFrames(stack)
+		delete(fields, keys.stacktrace)
+	}
+	if message := fieldValue(fields, "error"); message != "" {
+		exception := map[string]interface{}{"type": "error", "value": message}
+		if frames != nil {
+			exception["stacktrace"] = map[string]interface{}{"frames": frames}
+		}
+		event["exception"] = map[string]interface{}{"values": []interface{}{exception}}
+		delete(fields, "error")
+	} else if frames != nil {
+		event["stacktrace"] = map[string]interface{}{"frames": frames}
+	}
+	if fingerprint := fieldValue(fields, FingerprintKey); fingerprint != "" {
+		event["fingerprint"] = []string{fingerprint}
+		delete(fields, FingerprintKey)
+	}
+	traceID, spanID := fieldValue(fields, KeyTraceID), fieldValue(fields, KeySpanID)
+	if isHexID(traceID, 32) && isHexID(spanID, 16) {
+		event["contexts"] = map[string]interface{}{"trace": map[string]interface{}{"trace_id": traceID, "span_id": spanID}}
+		delete(fields, KeyTraceID)
+		delete(fields, KeySpanID)
+	}
+	if len(fields) > 0 {
+		event["extra"] = fields
+	}
+	return event
+}
+
+// sentryFrames parses a stacktrace as written by zap, a function line
+// followed by an indented "file:line" line per frame, innermost first, into
+// Sentry frames, outermost first.
+func sentryFrames(stack string) []map[string]interface{} {
+	lines := strings.Split(stack, "\n")
+	var frames []map[string]interface{}
+	for i := len(lines)/2*2 - 2; i >= 0; i -= 2 {
+		frame := map[string]interface{}{"function": lines[i]}
+		location := strings.TrimSpace(lines[i+1])
+		if colon := strings.LastIndexByte(location, ':'); colon > 0 {
+			if line, err := strconv.Atoi(location[colon+1:]); err == nil {
+				frame["abs_path"] = location[:colon]
+				frame["lineno"] = line
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// isHexID reports whether id is a hex-encoded ID of n digits.
+func isHexID(id string, n int) bool {
+	_, err := hex.DecodeString(id)
+	return len(id) == n && err == nil
+}
@@ -0,0 +1,29 @@
+package logx
+
+import "sync"
+
+// SequenceKey is the field key of the sequence number added when
+// Config.StrictOrdering is enabled.
+const SequenceKey = "seq"
+
+// sequencer serializes writes so that entries reach every output, the
+// asynchronous queue, subscribers and the history in a single global order,
+// and numbers them in that order.
+type sequencer struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// newSequencer returns a sequencer if enabled, otherwise nil.
+func newSequencer(enabled bool) *sequencer {
+	if !enabled {
+		return nil
+	}
+	return &sequencer{}
+}
+
+// assign returns the next sequence number. The caller must hold mu.
+func (s *sequencer) assign() uint64 {
+	s.next++
+	return s.next
+}
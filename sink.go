@@ -0,0 +1,98 @@
+package logx
+
+import (
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultSinkName is the name of the primary output configured by
+// Config.OutputPath and Config.Development.
+const DefaultSinkName = "default"
+
+// SinkConfig describes an additional named output. Entries are written to a
+// sink when a processor such as Router adds its name to Entry.Sinks.
+type SinkConfig struct {
+	// Name identifies the sink in routing rules. It must be unique and
+	// must not be "default".
+	Name string
+
+	// OutputPath specifies the file path the sink writes to.
+	// If empty and Writer is nil, the sink writes to stdout.
+	OutputPath string
+
+	// Writer, if set, receives the sink's output instead of OutputPath.
+	// If it implements Sync() error, Sync is called when the logger syncs.
+	Writer io.Writer
+
+	// Development selects the human-readable console encoding instead of JSON.
+	Development bool
+
+	// Level, if set, is the minimum level written to this sink, independent
+	// of the logger's level and of SetLevel. This lets a local console or
+	// debug file receive Trace and Debug entries while production outputs
+	// stay capped at Info. Trace entries are treated as Debug, as in the
+	// primary output. If nil, the sink follows the logger's level.
+	// Default: nil
+	Level *Level
+}
+
+// newSinkCores creates a core for each configured sink, sharing the output
+// configuration of the primary output.
+func newSinkCores(configs []SinkConfig, outputs *outputConfig) (map[string]zapcore.Core, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	sinks := make(map[string]zapcore.Core, len(configs))
+	for _, sc := range configs {
+		if sc.Name == "" || sc.Name == DefaultSinkName {
+			return nil, fmt.Errorf("invalid sink name %q", sc.Name)
+		}
+		if _, exists := sinks[sc.Name]; exists {
+			return nil, fmt.Errorf("duplicate sink name %q", sc.Name)
+		}
+		sinkOutputs := outputs
+		if sc.Level != nil {
+			own := *outputs
+			own.level = toZapLevel(*sc.Level)
+			sinkOutputs = &own
+		}
+		core, err := sinkOutputs.newCore(sc.Name, sc.Development, sc.OutputPath, sc.Writer)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", sc.Name, err)
+		}
+		sinks[sc.Name] = core
+	}
+	return sinks, nil
+}
+
+// sinkLevelFloor returns the lowest level enabled by a sink with its own
+// Level, or nil if no sink sets one. Entries below the logger's level are
+// still processed when a sink's level admits them.
+func sinkLevelFloor(configs []SinkConfig) *zapcore.Level {
+	var floor *zapcore.Level
+	for _, sc := range configs {
+		if sc.Level == nil {
+			continue
+		}
+		level := toZapLevel(*sc.Level)
+		if floor == nil || level < *floor {
+			floor = &level
+		}
+	}
+	return floor
+}
+
+// namedSinks returns a copy of sinks with name appended to each logger name.
+func namedSinks(sinks map[string]*zap.Logger, name string) map[string]*zap.Logger {
+	if len(sinks) == 0 {
+		return sinks
+	}
+	named := make(map[string]*zap.Logger, len(sinks))
+	for sinkName, zl := range sinks {
+		named[sinkName] = zl.Named(name)
+	}
+	return named
+}
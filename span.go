@@ -0,0 +1,106 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SpanEvent is a Warn, Error or Fatal entry to be added as an event to the
+// span active in the context it was logged with.
+type SpanEvent struct {
+	Name  string    // The entry message
+	Time  time.Time // The time the entry was logged
+	Level Level     // The entry level
+
+	// Attributes are the entry's fields, masked and encrypted as in the log
+	// output, with values converted to strings, booleans, int64 or float64,
+	// plus the "log.severity" and, for named loggers, "log.logger"
+	// attributes.
+	Attributes map[string]interface{}
+}
+
+// SpanEventFunc adds an event to the span active in ctx. It must do nothing
+// if ctx carries no recording span. logx has no tracing dependency; the
+// function adapts to the tracer in use, e.g. for OpenTelemetry:
+//
+//	config.SpanEvents = func(ctx context.Context, ev logx.SpanEvent) {
+//	    span := trace.SpanFromContext(ctx)
+//	    if !span.IsRecording() {
+//	        return
+//	    }
+//	    attrs := make([]attribute.KeyValue, 0, len(ev.Attributes))
+//	    for k, v := range ev.Attributes {
+//	        attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+//	    }
+//	    span.AddEvent(ev.Name, trace.WithTimestamp(ev.Time), trace.WithAttributes(attrs...))
+//	}
+type SpanEventFunc func(ctx context.Context, event SpanEvent)
+
+// WithContext returns a child logger bound to ctx. When Config.SpanEvents
+// is set, its Warn, Error and Fatal entries are also added as events to the
+// span active in ctx, so that traces show the log context inline without
+// instrumenting the same failures twice.
+//
+// Example:
+//
+//	func (s *Server) Charge(ctx context.Context, req *ChargeRequest) error {
+//	    log := s.logger.WithContext(ctx)
+//	    if err := s.gateway.Charge(ctx, req); err != nil {
+//	        log.Error("Charge failed", logx.ErrorField(err)) // also a span event
+//	        return err
+//	    }
+//	    return nil
+//	}
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	child := l.child(append([]Field(nil), l.fields...))
+	child.ctx = ctx
+	return child
+}
+
+// addSpanEvent passes the entry to Config.SpanEvents with the logger's
+// context.
+func (l *Logger) addSpanEvent(level Level, msg string, fields []Field) {
+	var masked map[string]bool
+	if component := l.shared.components.lookup(l.name); component != nil {
+		masked = component.masked
+	}
+	attributes := make(map[string]interface{}, len(fields)+2)
+	attributes["log.severity"] = level.String()
+	if l.name != "" {
+		attributes["log.logger"] = l.name
+	}
+	for _, field := range fields {
+		attributes[field.Key] = spanAttributeValue(l.protectValue(field, masked))
+	}
+	l.shared.spanEvents(l.ctx, SpanEvent{Name: msg, Time: time.Now(), Level: level, Attributes: attributes})
+}
+
+// spanAttributeValue converts a field value to a type every tracer accepts
+// as an attribute.
+func spanAttributeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string, bool, int64, float64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
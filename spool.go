@@ -0,0 +1,177 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// CheckpointSuffix is appended to BatchConfig.SpoolPath to name the file
+// recording how far the spool has been shipped.
+const CheckpointSuffix = ".checkpoint"
+
+// ShippingCheckpoint records how far an APISink has shipped its spool: the
+// entries up to Offset, the last of which had the event ID LastID, have
+// been acknowledged by the API.
+type ShippingCheckpoint struct {
+	Offset    int64     `json:"offset"`            // Spool offset following the last acknowledged entry
+	LastID    string    `json:"last_id,omitempty"` // EventIDKey of the last acknowledged entry, if set
+	UpdatedAt time.Time `json:"updated_at"`        // When the checkpoint was written
+}
+
+// spool is the on-disk journal of an APISink. Entries are appended before
+// they are sent and the checkpoint advances as batches are acknowledged;
+// once every entry is acknowledged, the journal is truncated.
+type spool struct {
+	path       string
+	file       *os.File
+	size       int64
+	checkpoint ShippingCheckpoint
+	unsent     int // Entries after the checkpoint
+}
+
+// openSpool opens or creates the spool at path and positions it after the
+// last acknowledged entry, found by its ID if the checkpoint has one.
+func openSpool(path string) (*spool, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool: %w", err)
+	}
+	sp := &spool{path: path, file: file}
+	if data, err := os.ReadFile(path + CheckpointSuffix); err == nil {
+		if err := json.Unmarshal(data, &sp.checkpoint); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("invalid spool checkpoint %s: %w", path+CheckpointSuffix, err)
+		}
+	}
+
+	// Scan the journal to count pending entries and, if the checkpoint
+	// names the last acknowledged entry, to resume right after it.
+	reader := bufio.NewReader(io.NewSectionReader(file, 0, 1<<62))
+	var offset int64
+	resumeAt := int64(-1)
+	var offsets []int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			offset += int64(len(line))
+			offsets = append(offsets, offset)
+			if sp.checkpoint.LastID != "" && resumeAt < 0 && spoolEntryID(line) == sp.checkpoint.LastID {
+				resumeAt = offset
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	// A torn entry at the end of the journal is dropped
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to repair spool: %w", err)
+	}
+	sp.size = offset
+	switch {
+	case resumeAt >= 0:
+		sp.checkpoint.Offset = resumeAt
+	case sp.checkpoint.Offset > sp.size:
+		// The journal was truncated after being fully acknowledged
+		sp.checkpoint.Offset = 0
+	}
+	for _, end := range offsets {
+		if end > sp.checkpoint.Offset {
+			sp.unsent++
+		}
+	}
+	return sp, nil
+}
+
+// spoolEntryID returns the event ID of an encoded entry, or "".
+func spoolEntryID(line []byte) string {
+	var entry struct {
+		ID string `json:"event_id"`
+	}
+	if json.Unmarshal(line, &entry) != nil {
+		return ""
+	}
+	return entry.ID
+}
+
+// append journals encoded entries, one per line.
+func (sp *spool) append(entries []apiEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		buf.Write(entry.raw)
+		buf.WriteByte('\n')
+	}
+	n, err := sp.file.Write(buf.Bytes())
+	sp.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+	sp.unsent += len(entries)
+	return nil
+}
+
+// read returns the entries between start and end, at most maxEntries of
+// them and maxBytes if positive, the offset following the last one and
+// the number of journaled lines consumed, including undecodable ones.
+func (sp *spool) read(start, end int64, maxEntries, maxBytes int) ([]apiEntry, int64, int) {
+	reader := bufio.NewReader(io.NewSectionReader(sp.file, start, end-start))
+	var batch []apiEntry
+	size, lines := 0, 0
+	offset := start
+	for lines < maxEntries {
+		line, _ := reader.ReadBytes('\n')
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			break
+		}
+		raw := line[:len(line)-1]
+		if maxBytes > 0 && len(batch) > 0 && size+len(raw) > maxBytes {
+			break
+		}
+		var fields map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if decoder.Decode(&fields) == nil {
+			batch = append(batch, apiEntry{fields: fields, raw: raw})
+			size += len(raw)
+		}
+		offset += int64(len(line))
+		lines++
+	}
+	return batch, offset, lines
+}
+
+// ack advances the checkpoint past count journaled lines ending at offset, the
+// last of which had the event ID lastID. Once every entry is acknowledged
+// the journal is truncated.
+func (sp *spool) ack(offset int64, count int, lastID string) error {
+	sp.unsent -= count
+	if offset >= sp.size {
+		// Truncate before resetting the checkpoint; a crash in between
+		// leaves a checkpoint beyond the end, which openSpool resets.
+		if err := sp.file.Truncate(0); err != nil {
+			return fmt.Errorf("failed to truncate spool: %w", err)
+		}
+		sp.size, offset, sp.unsent = 0, 0, 0
+	}
+	sp.checkpoint = ShippingCheckpoint{Offset: offset, LastID: lastID, UpdatedAt: time.Now()}
+	data, err := json.Marshal(sp.checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := sp.path + CheckpointSuffix + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write spool checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, sp.path+CheckpointSuffix); err != nil {
+		return fmt.Errorf("failed to write spool checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package logx
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// StatsConfig configures the usage statistics returned by Logger.Stats.
+type StatsConfig struct {
+	// TopMessages is the number of most frequent message templates
+	// reported by Logger.Stats.
+	// Default: 10
+	TopMessages int
+
+	// MaxTemplates bounds the number of distinct message templates counted,
+	// and so the memory used. Once it is reached, a new template replaces
+	// the least frequent one and inherits its count, so that frequent
+	// templates are always reported; the counts of templates that replaced
+	// another are upper bounds, exceeding the true count by at most their
+	// Overcount.
+	// Default: 1000
+	MaxTemplates int
+}
+
+// Stats is a snapshot of what a logger and every logger sharing its state
+// have written since it was created.
+type Stats struct {
+	Since             time.Time        `json:"since"`               // Creation of the logger
+	Entries           uint64           `json:"entries"`             // Entries written
+	Levels            map[Level]uint64 `json:"levels"`              // Entries written by level
+	Bytes             uint64           `json:"bytes"`               // Bytes written to the outputs, including sinks
+	AverageEntryBytes float64          `json:"average_entry_bytes"` // Bytes per entry, across its outputs
+	TopMessages       []MessageStats   `json:"top_messages"`        // Most frequent message templates, most frequent first
+}
+
+// MessageStats counts the entries written with a message template: a
+// message with its numbers, quoted values, UUIDs and hexadecimal values
+// replaced with "?", so that "Retry 3 of 5" and "Retry 4 of 5" are counted
+// together as "Retry ? of ?".
+type MessageStats struct {
+	Template  string `json:"template"`
+	Level     Level  `json:"level"`
+	Count     uint64 `json:"count"`
+	Overcount uint64 `json:"overcount,omitempty"` // Maximum error of Count, see StatsConfig.MaxTemplates
+}
+
+// templateKey identifies a counted message template.
+type templateKey struct {
+	template string
+	level    Level
+}
+
+// templateCount is the count of a message template.
+type templateCount struct {
+	count     uint64
+	overcount uint64
+}
+
+// logStats accumulates the statistics of Logger.Stats.
+type logStats struct {
+	since        time.Time
+	topMessages  int
+	maxTemplates int
+	levels       [FatalLevel - TraceLevel + 1]atomic.Uint64
+	bytes        atomic.Uint64
+
+	mu        sync.Mutex
+	templates map[templateKey]*templateCount
+}
+
+// newLogStats creates the statistics for config, or returns nil if
+// disabled.
+func newLogStats(config *StatsConfig) *logStats {
+	if config == nil {
+		return nil
+	}
+	s := &logStats{
+		since:        time.Now(),
+		topMessages:  config.TopMessages,
+		maxTemplates: config.MaxTemplates,
+		templates:    make(map[templateKey]*templateCount),
+	}
+	if s.topMessages <= 0 {
+		s.topMessages = 10
+	}
+	if s.maxTemplates <= 0 {
+		s.maxTemplates = 1000
+	}
+	if s.maxTemplates < s.topMessages {
+		s.maxTemplates = s.topMessages
+	}
+	return s
+}
+
+// record counts a written entry. Templates are counted with the
+// Space-Saving algorithm, which keeps the table at maxTemplates.
+func (s *logStats) record(level Level, msg string) {
+	if level >= TraceLevel && level <= FatalLevel {
+		s.levels[level-TraceLevel].Add(1)
+	}
+	key := templateKey{normalizeMessage(msg), level}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.templates[key]; ok {
+		c.count++
+		return
+	}
+	if len(s.templates) < s.maxTemplates {
+		s.templates[key] = &templateCount{count: 1}
+		return
+	}
+	var minKey templateKey
+	var min *templateCount
+	for k, c := range s.templates {
+		if min == nil || c.count < min.count {
+			minKey, min = k, c
+		}
+	}
+	delete(s.templates, minKey)
+	s.templates[key] = &templateCount{count: min.count + 1, overcount: min.count}
+}
+
+// snapshot returns the statistics so far.
+func (s *logStats) snapshot() Stats {
+	stats := Stats{
+		Since:  s.since,
+		Levels: make(map[Level]uint64),
+		Bytes:  s.bytes.Load(),
+	}
+	for i := range s.levels {
+		if n := s.levels[i].Load(); n > 0 {
+			stats.Levels[TraceLevel+Level(i)] = n
+			stats.Entries += n
+		}
+	}
+	if stats.Entries > 0 {
+		stats.AverageEntryBytes = float64(stats.Bytes) / float64(stats.Entries)
+	}
+
+	s.mu.Lock()
+	top := make([]MessageStats, 0, len(s.templates))
+	for k, c := range s.templates {
+		top = append(top, MessageStats{Template: k.template, Level: k.level, Count: c.count, Overcount: c.overcount})
+	}
+	s.mu.Unlock()
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Template < top[j].Template
+	})
+	if len(top) > s.topMessages {
+		top = top[:s.topMessages]
+	}
+	stats.TopMessages = top
+	return stats
+}
+
+// statsWriteSyncer counts the bytes written to an output.
+type statsWriteSyncer struct {
+	zapcore.WriteSyncer
+	stats *logStats
+}
+
+func (w *statsWriteSyncer) Write(p []byte) (int, error) {
+	n, err := w.WriteSyncer.Write(p)
+	w.stats.bytes.Add(uint64(n))
+	return n, err
+}
+
+// Stats returns the number of entries written by the logger and every
+// logger sharing its state since it was created, by level and by message
+// template, and the bytes written to its outputs, as configured by
+// Config.Stats, so that a service can report what it logs most without
+// external analytics. Entries dropped by processors, samplers or levels
+// are not counted. If the statistics are disabled, the zero Stats is
+// returned.
+//
+// Example:
+//
+//	config := logx.DefaultConfig()
+//	config.Stats = &logx.StatsConfig{TopMessages: 20}
+//	logger, _ := logx.New(config)
+//	...
+//	http.HandleFunc("/debug/logstats", func(w http.ResponseWriter, r *http.Request) {
+//	    json.NewEncoder(w).Encode(logger.Stats())
+//	})
+func (l *Logger) Stats() Stats {
+	if l.shared.stats == nil {
+		return Stats{}
+	}
+	return l.shared.stats.snapshot()
+}
@@ -0,0 +1,61 @@
+package logx
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// StreamLabelsKey is the key of the object grouping the fields designated
+// by Config.StreamLabels.
+const StreamLabelsKey = "labels"
+
+// streamLabel is a field written as a stream label.
+type streamLabel struct {
+	key   string
+	value string
+}
+
+// streamLabels is the labels of an entry, written as one object with keys
+// in sorted order.
+type streamLabels []streamLabel
+
+// newStreamLabelKeys returns the set of label keys, or nil if there are
+// none.
+func newStreamLabelKeys(keys []string) map[string]bool {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// add adds a label in key order, replacing an earlier value with the same
+// key.
+func (s streamLabels) add(key string, value interface{}) streamLabels {
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	i := sort.Search(len(s), func(i int) bool { return s[i].key >= key })
+	if i < len(s) && s[i].key == key {
+		s[i].value = text
+		return s
+	}
+	s = append(s, streamLabel{})
+	copy(s[i+1:], s[i:])
+	s[i] = streamLabel{key: key, value: text}
+	return s
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (s streamLabels) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, label := range s {
+		enc.AddString(label.key, label.value)
+	}
+	return nil
+}
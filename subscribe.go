@@ -0,0 +1,160 @@
+package logx
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// subscriptionBuffer is the number of entries buffered per subscription.
+// When a subscriber falls further behind, new entries are dropped for that
+// subscriber rather than blocking the logging goroutine.
+const subscriptionBuffer = 1024
+
+// Subscription delivers a live stream of entries written by a logger and all
+// loggers derived from it. Entries are delivered after processing and with
+// sensitive fields already masked or encrypted, as in the output.
+//
+// Delivery never blocks logging: if the subscriber does not keep up and its
+// buffer fills, further entries are dropped for that subscriber and counted
+// in Dropped. Entries share their Fields slice between subscribers and must
+// be treated as read-only.
+type Subscription struct {
+	// C receives the entries. It is closed when the subscription is closed.
+	C <-chan Entry
+
+	ch      chan Entry
+	filter  func(e *Entry) bool
+	dropped atomic.Uint64
+	hub     *subscriptionHub
+	once    sync.Once
+	mu      sync.Mutex // Guards closed against concurrent delivery
+	closed  bool
+}
+
+// Dropped returns the number of entries that were discarded because the
+// subscriber's buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops delivery and closes C. It is safe to call Close more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.remove(s)
+	})
+}
+
+// deliver sends the entry without blocking, counting it as dropped if the
+// buffer is full. Entries arriving after Close are discarded.
+func (s *Subscription) deliver(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- e:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// subscriptionHub fans entries out to the subscriptions of a logger tree.
+type subscriptionHub struct {
+	mu    sync.RWMutex
+	subs  map[*Subscription]struct{}
+	count atomic.Int32 // Fast-path check that avoids locking when unused
+}
+
+// newSubscriptionHub creates an empty hub.
+func newSubscriptionHub() *subscriptionHub {
+	return &subscriptionHub{subs: make(map[*Subscription]struct{})}
+}
+
+// active reports whether any subscriptions exist. A nil hub has none.
+func (h *subscriptionHub) active() bool {
+	return h != nil && h.count.Load() > 0
+}
+
+// add registers a new subscription with the given filter.
+func (h *subscriptionHub) add(filter func(e *Entry) bool) *Subscription {
+	ch := make(chan Entry, subscriptionBuffer)
+	s := &Subscription{C: ch, ch: ch, filter: filter, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[s] = struct{}{}
+	h.count.Add(1)
+	return s
+}
+
+// remove unregisters the subscription and closes its channel.
+func (h *subscriptionHub) remove(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		h.count.Add(-1)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	}
+}
+
+// maskedCopy returns a copy of the entry with fields, its fields protected
+// by protectFields as they are written, suitable for handing out of the
+// logger.
+func maskedCopy(e *Entry, fields []Field) Entry {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	return Entry{
+		Time:       e.Time,
+		Level:      e.Level,
+		LoggerName: e.LoggerName,
+		Message:    e.Message,
+		Caller:     e.Caller,
+		Fields:     fields,
+	}
+}
+
+// publish delivers masked, a masked copy of an entry, to every matching
+// subscription. The filters run without the hub's lock held, so that a
+// filter may close its subscription.
+func (h *subscriptionHub) publish(masked Entry) {
+	h.mu.RLock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.mu.RUnlock()
+
+	for _, s := range subs {
+		if s.filter != nil && !s.filter(&masked) {
+			continue
+		}
+		s.deliver(masked)
+	}
+}
+
+// Subscribe returns a subscription that receives every entry written by this
+// logger and any logger derived from it (or its parent) that satisfies the
+// filter. A nil filter receives all entries.
+//
+// The filter is called with a shared copy of the entry and must not modify it.
+// Always call Close when done to release the subscription.
+//
+// Example:
+//
+//	sub := logger.Subscribe(func(e *logx.Entry) bool {
+//	    return e.Level >= logx.WarnLevel
+//	})
+//	defer sub.Close()
+//	for entry := range sub.C {
+//	    fmt.Println(entry.Level, entry.Message)
+//	}
+func (l *Logger) Subscribe(filter func(e *Entry) bool) *Subscription {
+	return l.shared.hub.add(filter)
+}
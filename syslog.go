@@ -0,0 +1,323 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat selects the syslog message format.
+type SyslogFormat int
+
+// Syslog message formats.
+const (
+	SyslogRFC5424 SyslogFormat = iota // "<PRI>1 TIMESTAMP HOST APP PID - - MSG"
+	SyslogRFC3164                     // "<PRI>Mmm dd hh:mm:ss HOST TAG[PID]: MSG"
+)
+
+// SyslogFacility is a syslog facility.
+type SyslogFacility int
+
+// Syslog facilities.
+const (
+	FacilityUser   SyslogFacility = 1
+	FacilityDaemon SyslogFacility = 3
+	FacilityAuth   SyslogFacility = 4
+	FacilitySyslog SyslogFacility = 5
+	FacilityLocal0 SyslogFacility = 16
+	FacilityLocal1 SyslogFacility = 17
+	FacilityLocal2 SyslogFacility = 18
+	FacilityLocal3 SyslogFacility = 19
+	FacilityLocal4 SyslogFacility = 20
+	FacilityLocal5 SyslogFacility = 21
+	FacilityLocal6 SyslogFacility = 22
+	FacilityLocal7 SyslogFacility = 23
+)
+
+// SyslogConfig configures a sink delivering entries to a syslog daemon.
+type SyslogConfig struct {
+	// Network is "unixgram" or "unix" for a local socket, "udp" or "tcp".
+	// With "tcp" and TLS set, messages are sent over TLS (RFC 5425).
+	// Default: "" (the local daemon at /dev/log, /var/run/syslog or
+	// /var/run/log)
+	Network string
+
+	// Address is the socket path or host:port of the daemon. Required
+	// unless Network is empty.
+	Address string
+
+	// TLS, if set, secures "tcp" connections.
+	// Default: nil
+	TLS *tls.Config
+
+	// Format selects the message format.
+	// Default: SyslogRFC5424
+	Format SyslogFormat
+
+	// Facility is the facility of the messages.
+	// Default: FacilityUser
+	Facility SyslogFacility
+
+	// Tag is the APP-NAME (RFC 5424) or TAG (RFC 3164) of the messages.
+	// Default: the base name of the executable
+	Tag string
+
+	// Hostname is the HOSTNAME of the messages.
+	// Default: os.Hostname()
+	Hostname string
+
+	// Timeout bounds dialing and every stream write.
+	// Default: 5s
+	Timeout time.Duration
+
+	// OnError is called when an entry cannot be converted or sent. It must
+	// not log to the same sink.
+	// Default: nil (errors are only returned by Write)
+	OnError func(err error)
+}
+
+// syslogSeverities maps logx level names to syslog severities.
+var syslogSeverities = map[string]int{
+	"TRACE": 7, "DEBUG": 7, "INFO": 6, "WARN": 4, "ERROR": 3, "FATAL": 2,
+}
+
+// syslogLocalSockets are the paths of the local daemon's socket.
+var syslogLocalSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogSink delivers the JSON entries of a sink to syslog. Every entry is
+// sent as one message whose severity follows the entry's level and whose
+// MSG is the JSON entry itself, so that structured fields survive. Stream
+// transports frame messages with octet counting for RFC 5424 (RFC 6587)
+// and with a trailing newline for RFC 3164; the connection is
+// re-established once when a write fails.
+type SyslogSink struct {
+	config SyslogConfig
+	pid    string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink returns a sink delivering entries to syslog.
+//
+// Example:
+//
+//	syslog, err := logx.NewSyslogSink(logx.SyslogConfig{
+//	    Network:  "tcp",
+//	    Address:  "logs.example.com:6514",
+//	    TLS:      &tls.Config{ServerName: "logs.example.com"},
+//	    Facility: logx.FacilityLocal0,
+//	    Tag:      "checkout",
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "syslog", Writer: syslog}}
+//	defer syslog.Close()
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	switch config.Network {
+	case "":
+	case "unix", "unixgram", "udp", "tcp":
+		if config.Address == "" {
+			return nil, fmt.Errorf("syslog sink requires an address for network %q", config.Network)
+		}
+	default:
+		return nil, fmt.Errorf("syslog sink: unsupported network %q", config.Network)
+	}
+	if config.TLS != nil && config.Network != "tcp" {
+		return nil, fmt.Errorf("syslog sink: TLS requires the tcp network")
+	}
+	if config.Facility == 0 {
+		config.Facility = FacilityUser
+	}
+	if config.Tag == "" {
+		config.Tag = filepath.Base(os.Args[0])
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	s := &SyslogSink{config: config, pid: strconv.Itoa(os.Getpid())}
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// dial connects to the daemon.
+func (s *SyslogSink) dial() error {
+	if s.config.Network == "" {
+		for _, network := range []string{"unixgram", "unix"} {
+			for _, path := range syslogLocalSockets {
+				if conn, err := net.DialTimeout(network, path, s.config.Timeout); err == nil {
+					s.conn = conn
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("failed to connect to the local syslog daemon")
+	}
+	var conn net.Conn
+	var err error
+	if s.config.TLS != nil {
+		dialer := &net.Dialer{Timeout: s.config.Timeout}
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.config.Address, s.config.TLS)
+	} else {
+		conn, err = net.DialTimeout(s.config.Network, s.config.Address, s.config.Timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog at %s: %w", s.config.Address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// stream reports whether the connection is a byte stream that needs
+// framing.
+func (s *SyslogSink) stream() bool {
+	switch s.conn.(type) {
+	case *net.UDPConn:
+		return false
+	case *net.UnixConn:
+		return s.conn.RemoteAddr().Network() == "unix"
+	}
+	return true
+}
+
+// Write converts the encoded entries in p to syslog messages and sends
+// them.
+func (s *SyslogSink) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		message, err := s.message(line)
+		if err == nil {
+			err = s.send(message)
+		}
+		if err != nil {
+			if s.config.OnError != nil {
+				s.config.OnError(err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(p), nil
+}
+
+// message formats a JSON entry as a syslog message.
+func (s *SyslogSink) message(line []byte) ([]byte, error) {
+	var envelope struct {
+		Level     string `json:"level"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode entry: %w", err)
+	}
+	severity, ok := syslogSeverities[envelope.Level]
+	if !ok {
+		severity = syslogSeverities["INFO"]
+	}
+	t, err := time.Parse(time.RFC3339Nano, envelope.Timestamp)
+	if err != nil {
+		t = time.Now()
+	}
+	pri := int(s.config.Facility)*8 + severity
+
+	var buf bytes.Buffer
+	if s.config.Format == SyslogRFC3164 {
+		fmt.Fprintf(&buf, "<%d>%s %s %s[%s]: ", pri, t.Format(time.Stamp), s.config.Hostname, s.config.Tag, s.pid)
+	} else {
+		fmt.Fprintf(&buf, "<%d>1 %s %s %s %s - - ", pri, t.Format("2006-01-02T15:04:05.000000Z07:00"),
+			syslogHeaderValue(s.config.Hostname, 255), syslogHeaderValue(s.config.Tag, 48), s.pid)
+	}
+	buf.Write(line)
+	return buf.Bytes(), nil
+}
+
+// syslogHeaderValue returns value as an RFC 5424 header field: printable
+// ASCII without spaces, at most max characters, or "-" if empty.
+func syslogHeaderValue(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+
+// send writes one message, framing it on stream connections.
+func (s *SyslogSink) send(message []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return err
+		}
+	}
+	frame := message
+	if s.stream() {
+		if s.config.Format == SyslogRFC3164 {
+			frame = append(message, '\n')
+		} else {
+			frame = append([]byte(strconv.Itoa(len(message))+" "), message...)
+		}
+	}
+	if err := s.write(frame); err != nil {
+		s.conn.Close()
+		if err := s.dial(); err != nil {
+			s.conn = nil
+			return err
+		}
+		return s.write(frame)
+	}
+	return nil
+}
+
+// write writes a frame to the connection.
+func (s *SyslogSink) write(frame []byte) error {
+	s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout))
+	if _, err := s.conn.Write(frame); err != nil {
+		return fmt.Errorf("failed to send syslog message: %w", err)
+	}
+	return nil
+}
+
+// Sync does nothing; messages are sent by Write.
+func (s *SyslogSink) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the daemon.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -7,6 +7,7 @@ require github.com/seasbee/go-logx v0.0.0
 require (
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/seasbee/go-logx => ../../
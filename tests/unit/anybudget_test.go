@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+type budgetOrder struct {
+	ID    string   `json:"id"`
+	Items []string `json:"items"`
+}
+
+func TestAnyBudget(t *testing.T) {
+	stored := make(map[string][]byte)
+	var store func(ref string, data []byte) error
+	config := logx.DefaultConfig()
+	config.AnyBudget = &logx.AnyBudget{MaxBytes: 64, Store: func(ref string, data []byte) error {
+		if store == nil {
+			return errors.New("no store")
+		}
+		return store(ref, data)
+	}}
+	logger, read := newCaptureLogger(t, config)
+
+	items := make([]string, 20)
+	for i := range items {
+		items[i] = "sku-123456"
+	}
+	logger.Info("Small", logx.Any("order", &budgetOrder{ID: "o-1", Items: items[:2]}))
+	logger.Info("Large", logx.Any("order", &budgetOrder{ID: "o-2", Items: items}), logx.Any("items", items), logx.String("note", strings.Repeat("x", 100)))
+
+	store = func(ref string, data []byte) error {
+		stored[ref] = data
+		return nil
+	}
+	logger.Info("Stored", logx.Any("items", items))
+	store = nil
+	logger.Info("Store failed", logx.Any("items", items))
+
+	entries := read()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	if order, ok := entries[0]["order"].(map[string]interface{}); !ok || order["id"] != "o-1" {
+		t.Errorf("Expected the small value in full, got %v", entries[0]["order"])
+	}
+	order, _ := entries[1]["order"].(map[string]interface{})
+	if order["type"] != "*unit.budgetOrder" || order["bytes"] == nil || len(order["sha256"].(string)) != 64 || order["length"] != nil {
+		t.Errorf("Expected a summary of the large struct, got %v", entries[1]["order"])
+	}
+	summary, _ := entries[1]["items"].(map[string]interface{})
+	if summary["type"] != "[]string" || summary["length"] != float64(20) || summary["ref"] != nil {
+		t.Errorf("Expected a summary with the slice length, got %v", entries[1]["items"])
+	}
+	if entries[1]["note"] != strings.Repeat("x", 100) {
+		t.Errorf("Expected strings to be written in full, got %v", entries[1]["note"])
+	}
+
+	ref, _ := entries[2]["items"].(map[string]interface{})["ref"].(string)
+	if data, ok := stored[ref]; !ok || !strings.HasPrefix(string(data), `["sku-123456"`) {
+		t.Errorf("Expected the value stored under the summary's ref, got %q in %v", ref, stored)
+	}
+	if failed := entries[3]["items"].(map[string]interface{}); failed["ref"] != nil || failed["sha256"] == nil {
+		t.Errorf("Expected a summary without ref when storing fails, got %v", failed)
+	}
+}
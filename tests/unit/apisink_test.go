@@ -0,0 +1,698 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// apiRecorder is a test server recording the requests sent by an APISink.
+type apiRecorder struct {
+	mu      sync.Mutex
+	headers []http.Header
+	paths   []string
+	bodies  []interface{}
+}
+
+func newAPIRecorder(t *testing.T) (*apiRecorder, *httptest.Server) {
+	rec := &apiRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid request body: %v", err)
+		}
+		rec.mu.Lock()
+		rec.headers = append(rec.headers, r.Header)
+		rec.paths = append(rec.paths, r.URL.Path)
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+// newAPISinkLogger returns a logger writing every entry to sink only.
+func newAPISinkLogger(t *testing.T, sink *logx.APISink) *logx.Logger {
+	config := logx.DefaultConfig()
+	config.AddCaller = false
+	return newAPISinkLoggerWith(t, sink, config)
+}
+
+// newAPISinkLoggerWith returns a logger created with config writing every
+// entry to sink only.
+func newAPISinkLoggerWith(t *testing.T, sink *logx.APISink, config *logx.Config) *logx.Logger {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"api"}})
+	config.OutputPath = t.TempDir() + "/app.log"
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "api", Writer: sink}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestHoneycombSink(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewHoneycombSink(logx.HoneycombConfig{
+		APIKey:     "hc-key",
+		Dataset:    "checkout api",
+		Endpoint:   server.URL,
+		Attributes: map[string]string{"message": "name"},
+		Batch:      logx.BatchConfig{MaxEntries: 2},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Info("first", logx.Int("n", 1))
+	logger.Info("second", logx.Int("n", 2)) // Fills the batch
+	logger.Info("third", logx.Int("n", 3))
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if len(rec.bodies) != 2 {
+		t.Fatalf("Expected a full batch and a synced batch, got %d requests", len(rec.bodies))
+	}
+	if rec.paths[0] != "/1/batch/checkout api" || rec.headers[0].Get("X-Honeycomb-Team") != "hc-key" {
+		t.Errorf("Unexpected request: %s %v", rec.paths[0], rec.headers[0])
+	}
+	events := rec.bodies[0].([]interface{})
+	event := events[1].(map[string]interface{})
+	data := event["data"].(map[string]interface{})
+	if event["time"] == nil || data["name"] != "second" || data["n"] != float64(2) || data["level"] != "INFO" {
+		t.Errorf("Unexpected event: %v", event)
+	}
+}
+
+func TestNewRelicSink(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewNewRelicSink(logx.NewRelicConfig{
+		APIKey:           "nr-key",
+		Endpoint:         server.URL + "/log/v1",
+		Attributes:       map[string]string{"trace_id": "trace.id"},
+		CommonAttributes: map[string]interface{}{"service.name": "checkout"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Warn("slow request", logx.TraceID("abc"))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(rec.bodies) != 1 || rec.headers[0].Get("Api-Key") != "nr-key" {
+		t.Fatalf("Expected one authenticated request, got %v", rec.headers)
+	}
+	payload := rec.bodies[0].([]interface{})[0].(map[string]interface{})
+	common := payload["common"].(map[string]interface{})["attributes"].(map[string]interface{})
+	log := payload["logs"].([]interface{})[0].(map[string]interface{})
+	attributes := log["attributes"].(map[string]interface{})
+	if common["service.name"] != "checkout" || log["message"] != "slow request" || attributes["trace.id"] != "abc" || log["timestamp"] == nil {
+		t.Errorf("Unexpected payload: %v", payload)
+	}
+}
+
+func TestAPISinkTimeLayouts(t *testing.T) {
+	for _, layout := range []string{"", time.RFC3339, logx.TimeLayoutEpoch, logx.TimeLayoutEpochMillis, logx.TimeLayoutEpochNanos, "02 Jan 2006 15:04:05.000 -0700"} {
+		rec, server := newAPIRecorder(t)
+		sink, err := logx.NewNewRelicSink(logx.NewRelicConfig{APIKey: "nr-key", Endpoint: server.URL})
+		if err != nil {
+			t.Fatalf("Failed to create sink: %v", err)
+		}
+		config := logx.DefaultConfig()
+		config.TimeLayout = layout
+		logger := newAPISinkLoggerWith(t, sink, config)
+
+		before := time.Now().Truncate(time.Second)
+		logger.Info("timed")
+		after := time.Now()
+		time.Sleep(20 * time.Millisecond) // Sent after the entry
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		log := rec.bodies[0].([]interface{})[0].(map[string]interface{})["logs"].([]interface{})[0].(map[string]interface{})
+		sent := time.UnixMilli(int64(log["timestamp"].(float64)))
+		if sent.Before(before) || sent.After(after) {
+			t.Errorf("Layout %q: expected the entry time between %v and %v, got %v", layout, before, after, sent)
+		}
+	}
+}
+
+func TestAPISinkReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	var reported []error
+	sink, _ := logx.NewNewRelicSink(logx.NewRelicConfig{
+		APIKey:   "bad",
+		Endpoint: server.URL,
+		Batch:    logx.BatchConfig{OnError: func(err error) { reported = append(reported, err) }},
+	})
+	defer sink.Close()
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Info("lost")
+	if err := sink.Sync(); err == nil || len(reported) != 1 {
+		t.Errorf("Expected the failed send to be reported, got %v and %v", err, reported)
+	}
+}
+
+func TestPubSubSink(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewPubSubSink(logx.PubSubConfig{
+		Project:          "proj",
+		Topic:            "logs",
+		Token:            func() (string, error) { return "tok", nil },
+		OrderingKeyField: logx.KeyRequestID,
+		Endpoint:         server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Named("api").Error("failed", logx.RequestID("r1"))
+	logger.Info("no key")
+	sink.Close()
+
+	if len(rec.bodies) != 1 || rec.paths[0] != "/v1/projects/proj/topics/logs:publish" || rec.headers[0].Get("Authorization") != "Bearer tok" {
+		t.Fatalf("Unexpected requests: %v %v", rec.paths, rec.headers)
+	}
+	messages := rec.bodies[0].(map[string]interface{})["messages"].([]interface{})
+	first := messages[0].(map[string]interface{})
+	attributes := first["attributes"].(map[string]interface{})
+	data, _ := base64.StdEncoding.DecodeString(first["data"].(string))
+	var entry map[string]interface{}
+	json.Unmarshal(data, &entry)
+	if first["orderingKey"] != "r1" || attributes["level"] != "ERROR" || attributes["logger"] != "api" || entry["message"] != "failed" {
+		t.Errorf("Unexpected message: %v (data %s)", first, data)
+	}
+	if _, ok := messages[1].(map[string]interface{})["orderingKey"]; ok {
+		t.Errorf("Expected no ordering key without the field, got %v", messages[1])
+	}
+}
+
+func TestKinesisSink(t *testing.T) {
+	var target, authorization string
+	var body struct {
+		StreamName string
+		Records    []struct {
+			Data         []byte
+			PartitionKey string
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, authorization = r.Header.Get("X-Amz-Target"), r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"FailedRecordCount":1}`))
+	}))
+	defer server.Close()
+	var reported []error
+	sink, err := logx.NewKinesisSink(logx.KinesisConfig{
+		Stream: "logs",
+		Region: "eu-west-1",
+		Credentials: func() (logx.AWSCredentials, error) {
+			return logx.AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		},
+		PartitionKeyField: logx.KeyTenant,
+		Endpoint:          server.URL,
+		Batch:             logx.BatchConfig{MaxEntries: 1000, OnError: func(err error) { reported = append(reported, err) }},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Info("ordered", logx.Tenant("acme"))
+	logger.Info("spread")
+	sink.Close()
+
+	if target != "Kinesis_20131202.PutRecords" || !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(authorization, "/eu-west-1/kinesis/aws4_request") {
+		t.Errorf("Unexpected request headers: %s %s", target, authorization)
+	}
+	if body.StreamName != "logs" || len(body.Records) != 2 || body.Records[0].PartitionKey != "acme" || body.Records[1].PartitionKey == "" {
+		t.Fatalf("Unexpected records: %+v", body)
+	}
+	var entry map[string]interface{}
+	if json.Unmarshal(body.Records[0].Data, &entry); entry["message"] != "ordered" {
+		t.Errorf("Unexpected record data: %s", body.Records[0].Data)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "1 records rejected") {
+		t.Errorf("Expected the rejected record to be reported, got %v", reported)
+	}
+}
+
+func TestAPISinkSpoolResumesAfterCheckpoint(t *testing.T) {
+	var mu sync.Mutex
+	failing := true
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, log := range body[0]["logs"].([]interface{}) {
+			received = append(received, log.(map[string]interface{})["message"].(string))
+		}
+	}))
+	defer server.Close()
+	spoolPath := t.TempDir() + "/newrelic.spool"
+	newSink := func() *logx.APISink {
+		sink, err := logx.NewNewRelicSink(logx.NewRelicConfig{
+			APIKey:   "key",
+			Endpoint: server.URL,
+			Batch:    logx.BatchConfig{MaxEntries: 2, SpoolPath: spoolPath},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create sink: %v", err)
+		}
+		return sink
+	}
+	newLogger := func(sink *logx.APISink) *logx.Logger {
+		router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"api"}})
+		config := logx.DefaultConfig()
+		config.OutputPath = t.TempDir() + "/app.log"
+		config.EventID = true
+		config.Processors = []logx.Processor{router}
+		config.Sinks = []logx.SinkConfig{{Name: "api", Writer: sink}}
+		logger, err := logx.New(config)
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		return logger
+	}
+
+	// While the API is down, entries stay in the spool
+	sink := newSink()
+	logger := newLogger(sink)
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+	if err := sink.Sync(); err == nil {
+		t.Fatal("Expected the send to fail")
+	}
+
+	// After a restart, everything is shipped in order, once
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	sink = newSink()
+	logger = newLogger(sink)
+	logger.Info("four") // Ships one, two and three, four
+	logger.Info("five")
+	if checkpoint := sink.Checkpoint(); checkpoint.LastID == "" {
+		t.Errorf("Expected a checkpoint after the acknowledged batches, got %+v", checkpoint)
+	}
+
+	// After another restart, only the unacknowledged entry is sent
+	sink = newSink()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	want := []string{"one", "two", "three", "four", "five"}
+	if strings.Join(received, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v to be shipped exactly once, got %v", want, received)
+	}
+	if checkpoint := sink.Checkpoint(); checkpoint.Offset != 0 {
+		t.Errorf("Expected the spool to be truncated once shipped, got %+v", checkpoint)
+	}
+}
+
+func TestAPISinkSpoolResumesMidJournal(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, log := range body[0]["logs"].([]interface{}) {
+			received = append(received, log.(map[string]interface{})["message"].(string))
+		}
+	}))
+	defer server.Close()
+	spoolPath := t.TempDir() + "/newrelic.spool"
+	newSink := func() *logx.APISink {
+		sink, err := logx.NewNewRelicSink(logx.NewRelicConfig{
+			APIKey:   "key",
+			Endpoint: server.URL,
+			Batch:    logx.BatchConfig{MaxEntries: 2, SpoolPath: spoolPath},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create sink: %v", err)
+		}
+		return sink
+	}
+
+	sink := newSink()
+	// Ships one and two, then journals three
+	sink.Write([]byte(`{"message":"one","event_id":"1"}` + "\n" +
+		`{"message":"two","event_id":"2"}` + "\n" +
+		`{"message":"three","event_id":"3"}` + "\n"))
+	if err := sink.Sync(); err == nil {
+		t.Fatal("Expected the second send to fail")
+	}
+	if checkpoint := sink.Checkpoint(); checkpoint.Offset == 0 || checkpoint.LastID != "2" {
+		t.Errorf("Expected a checkpoint after the second entry, got %+v", checkpoint)
+	}
+
+	sink = newSink()
+	sink.Close()
+	if strings.Join(received, ",") != "one,two,three" {
+		t.Errorf("Expected each entry to be shipped once, got %v", received)
+	}
+}
+
+func TestCloudLoggingSink(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewCloudLoggingSink(logx.CloudLoggingConfig{
+		Project:        "proj",
+		LogID:          "checkout",
+		Token:          func() (string, error) { return "tok", nil },
+		ResourceType:   "k8s_container",
+		ResourceLabels: map[string]string{"cluster_name": "prod"},
+		Labels:         map[string]string{"env": "prod"},
+		Endpoint:       server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Warn("slow charge", logx.String("trace", "4bf92f3577b34da6"), logx.SpanID("00f067aa"), logx.Int("ms", 900))
+	logger.Info("no trace", logx.TraceID("projects/other/traces/abc"))
+	sink.Close()
+
+	if len(rec.bodies) != 1 || rec.paths[0] != "/v2/entries:write" || rec.headers[0].Get("Authorization") != "Bearer tok" {
+		t.Fatalf("Unexpected requests: %v %v", rec.paths, rec.headers)
+	}
+	body := rec.bodies[0].(map[string]interface{})
+	resource := body["resource"].(map[string]interface{})
+	if body["logName"] != "projects/proj/logs/checkout" || resource["type"] != "k8s_container" ||
+		body["labels"].(map[string]interface{})["env"] != "prod" {
+		t.Errorf("Unexpected request envelope: %v", body)
+	}
+	entries := body["entries"].([]interface{})
+	first := entries[0].(map[string]interface{})
+	payload := first["jsonPayload"].(map[string]interface{})
+	if first["severity"] != "WARNING" || first["trace"] != "projects/proj/traces/4bf92f3577b34da6" || first["spanId"] != "00f067aa" {
+		t.Errorf("Unexpected entry: %v", first)
+	}
+	if payload["message"] != "slow charge" || payload["ms"] != float64(900) || payload["trace"] != nil || payload["level"] != nil {
+		t.Errorf("Unexpected payload: %v", payload)
+	}
+	if _, ok := first["timestamp"].(string); !ok {
+		t.Errorf("Expected a timestamp, got %v", first)
+	}
+	second := entries[1].(map[string]interface{})
+	if second["severity"] != "INFO" || second["trace"] != "projects/other/traces/abc" {
+		t.Errorf("Expected the trace_id fallback, got %v", second)
+	}
+	if _, err := logx.NewCloudLoggingSink(logx.CloudLoggingConfig{Project: "proj"}); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+func TestOTLPSinkHTTPJSON(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewOTLPSink(logx.OTLPConfig{
+		Endpoint: server.URL,
+		Protocol: logx.OTLPHTTPJSON,
+		Headers:  map[string]string{"X-Api-Key": "key"},
+		Resource: map[string]string{"service.name": "checkout"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Error("Payment failed",
+		logx.TraceID("4bf92f3577b34da6a3ce929d0e0e4736"), logx.SpanID("00f067aa0ba902b7"),
+		logx.Int("attempt", 3), logx.Float64("amount", 12.5), logx.Any("card", map[string]interface{}{"brand": "visa"}))
+	logger.Info("Untraced", logx.TraceID("not-hex"))
+	sink.Close()
+
+	if len(rec.bodies) != 1 || rec.paths[0] != "/v1/logs" || rec.headers[0].Get("X-Api-Key") != "key" {
+		t.Fatalf("Unexpected requests: %v %v", rec.paths, rec.headers)
+	}
+	resourceLogs := rec.bodies[0].(map[string]interface{})["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	resource := resourceLogs["resource"].(map[string]interface{})["attributes"].([]interface{})[0].(map[string]interface{})
+	if resource["key"] != "service.name" || resource["value"].(map[string]interface{})["stringValue"] != "checkout" {
+		t.Errorf("Unexpected resource: %v", resource)
+	}
+	scopeLogs := resourceLogs["scopeLogs"].([]interface{})[0].(map[string]interface{})
+	records := scopeLogs["logRecords"].([]interface{})
+	first := records[0].(map[string]interface{})
+	if first["severityNumber"] != float64(17) || first["severityText"] != "ERROR" ||
+		first["body"].(map[string]interface{})["stringValue"] != "Payment failed" ||
+		first["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || first["spanId"] != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected record: %v", first)
+	}
+	if _, ok := first["timeUnixNano"].(string); !ok {
+		t.Errorf("Expected the time as a string, got %v", first["timeUnixNano"])
+	}
+	attributes := make(map[string]interface{})
+	for _, a := range first["attributes"].([]interface{}) {
+		kv := a.(map[string]interface{})
+		attributes[kv["key"].(string)] = kv["value"]
+	}
+	if attributes["attempt"].(map[string]interface{})["intValue"] != "3" ||
+		attributes["amount"].(map[string]interface{})["doubleValue"] != 12.5 ||
+		attributes["card"].(map[string]interface{})["kvlistValue"] == nil || attributes["message"] != nil {
+		t.Errorf("Unexpected attributes: %v", attributes)
+	}
+	second := records[1].(map[string]interface{})
+	if second["traceId"] != nil || second["severityNumber"] != float64(9) {
+		t.Errorf("Expected an invalid trace ID kept as an attribute, got %v", second)
+	}
+	if _, err := logx.NewOTLPSink(logx.OTLPConfig{Protocol: "thrift"}); err == nil {
+		t.Error("Expected an error for an unknown protocol")
+	}
+}
+
+func TestAPISinkRenamedKeys(t *testing.T) {
+	rec, server := newAPIRecorder(t)
+	sink, err := logx.NewOTLPSink(logx.OTLPConfig{Endpoint: server.URL, Protocol: logx.OTLPHTTPJSON})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	config := logx.DefaultConfig()
+	config.FieldProfile = logx.ProfileGCP
+	config.Keys = logx.EncoderKeys{Timestamp: "ts", Message: "msg"}
+	logger := newAPISinkLoggerWith(t, sink, config)
+
+	before := time.Now()
+	logger.Warn("Disk almost full")
+	after := time.Now()
+	time.Sleep(20 * time.Millisecond) // Sent after the entry
+	sink.Close()
+
+	resourceLogs := rec.bodies[0].(map[string]interface{})["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	record := resourceLogs["scopeLogs"].([]interface{})[0].(map[string]interface{})["logRecords"].([]interface{})[0].(map[string]interface{})
+	if record["severityNumber"] != float64(13) || record["severityText"] != "WARNING" ||
+		record["body"].(map[string]interface{})["stringValue"] != "Disk almost full" {
+		t.Errorf("Expected the level and message under the renamed keys, got %v", record)
+	}
+	nanos, _ := strconv.ParseInt(record["timeUnixNano"].(string), 10, 64)
+	if sent := time.Unix(0, nanos); sent.Before(before) || sent.After(after) {
+		t.Errorf("Expected the entry time between %v and %v, got %v", before, after, sent)
+	}
+	for _, a := range record["attributes"].([]interface{}) {
+		if key := a.(map[string]interface{})["key"]; key == "ts" || key == "msg" || key == "severity" {
+			t.Errorf("Expected envelope key %v not to be an attribute", key)
+		}
+	}
+}
+
+func TestOTLPSinkGRPC(t *testing.T) {
+	var mu sync.Mutex
+	var messages [][]byte
+	status := "0"
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || r.URL.Path != "/opentelemetry.proto.collector.logs.v1.LogsService/Export" ||
+			r.Header.Get("Content-Type") != "application/grpc" {
+			t.Errorf("Unexpected request: %s %s %v", r.Proto, r.URL.Path, r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if len(body) < 5 || body[0] != 0 || int(binary.BigEndian.Uint32(body[1:5])) != len(body)-5 {
+			t.Errorf("Invalid gRPC frame: %x", body)
+		}
+		mu.Lock()
+		messages = append(messages, body[5:])
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.Header().Set("Content-Type", "application/grpc")
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Grpc-Status", status)
+		w.Header().Set("Grpc-Message", "collector%20unavailable")
+		mu.Unlock()
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	sink, err := logx.NewOTLPSink(logx.OTLPConfig{Endpoint: server.URL, Protocol: logx.OTLPGRPC})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Warn("Slow charge", logx.TraceID("4bf92f3577b34da6a3ce929d0e0e4736"))
+	if err := sink.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	mu.Lock()
+	status = "14"
+	mu.Unlock()
+	logger.Warn("Retried charge")
+	if err := sink.Sync(); err == nil || !strings.Contains(err.Error(), "collector unavailable") {
+		t.Errorf("Expected the gRPC status error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(messages))
+	}
+	traceID, _ := hex.DecodeString("4bf92f3577b34da6a3ce929d0e0e4736")
+	for _, want := range [][]byte{[]byte("Slow charge"), []byte("WARN"), []byte("service.name"), []byte("github.com/seasbee/go-logx"), traceID} {
+		if !bytes.Contains(messages[0], want) {
+			t.Errorf("Expected %q in the exported message", want)
+		}
+	}
+}
+
+func TestSentrySink(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var envelopes [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, r)
+		envelopes = append(envelopes, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://pubkey@", 1) + "/456"
+	sink, err := logx.NewSentrySink(logx.SentryConfig{
+		DSN:         dsn,
+		Environment: "production",
+		Release:     "checkout@1.4.2",
+		Tags:        map[string]string{"region": "eu"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Warn("Not forwarded")
+	logger.Error("Charge failed", logx.ErrorField(errors.New("card declined")), logx.String("order", "o-1"),
+		logx.TraceID("4bf92f3577b34da6a3ce929d0e0e4736"), logx.SpanID("00f067aa0ba902b7"))
+	sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(envelopes) != 1 || requests[0].URL.Path != "/api/456/envelope/" ||
+		!strings.Contains(requests[0].Header.Get("X-Sentry-Auth"), "sentry_key=pubkey") {
+		t.Fatalf("Unexpected requests: %d", len(envelopes))
+	}
+	lines := envelopes[0]
+	var header, item, event map[string]interface{}
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[0]), &header) != nil || json.Unmarshal([]byte(lines[1]), &item) != nil ||
+		json.Unmarshal([]byte(lines[2]), &event) != nil {
+		t.Fatalf("Invalid envelope: %q", lines)
+	}
+	if item["type"] != "event" || item["length"] != float64(len(lines[2])) || header["event_id"] != event["event_id"] {
+		t.Errorf("Unexpected envelope headers: %v %v", header, item)
+	}
+	if event["level"] != "error" || event["logentry"].(map[string]interface{})["formatted"] != "Charge failed" ||
+		event["environment"] != "production" || event["release"] != "checkout@1.4.2" ||
+		event["tags"].(map[string]interface{})["region"] != "eu" || event["extra"].(map[string]interface{})["order"] != "o-1" {
+		t.Errorf("Unexpected event: %v", event)
+	}
+	exception := event["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	frames, _ := exception["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	if exception["value"] != "card declined" || len(frames) == 0 ||
+		!strings.HasSuffix(frames[len(frames)-1].(map[string]interface{})["function"].(string), "TestSentrySink") {
+		t.Errorf("Unexpected exception: %v", exception)
+	}
+	if trace := event["contexts"].(map[string]interface{})["trace"].(map[string]interface{}); trace["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Unexpected trace context: %v", trace)
+	}
+
+	sampled, _ := logx.NewSentrySink(logx.SentryConfig{DSN: dsn, SampleRate: 1e-9})
+	sampledLogger := newAPISinkLogger(t, sampled)
+	for i := 0; i < 10; i++ {
+		sampledLogger.Error("Sampled out")
+	}
+	sampled.Close()
+	if len(envelopes) != 1 {
+		t.Errorf("Expected sampled out events not to be sent, got %d requests", len(envelopes))
+	}
+	if _, err := logx.NewSentrySink(logx.SentryConfig{DSN: "https://sentry.io/456"}); err == nil {
+		t.Error("Expected an error for a DSN without a key")
+	}
+}
+
+func TestAPISinkDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	deadLetterPath := t.TempDir() + "/honeycomb.dead"
+	sink, err := logx.NewHoneycombSink(logx.HoneycombConfig{
+		APIKey:   "key",
+		Dataset:  "app",
+		Endpoint: server.URL,
+		Batch:    logx.BatchConfig{DeadLetterPath: deadLetterPath},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newAPISinkLogger(t, sink)
+
+	logger.Info("Lost otherwise", logx.String("order", "o-1"))
+	if err := sink.Close(); err == nil {
+		t.Error("Expected Close to report the failed batch")
+	}
+	entries := readJSONFile(t, deadLetterPath)
+	if len(entries) != 1 || entries[0]["message"] != "Lost otherwise" || entries[0]["order"] != "o-1" {
+		t.Errorf("Unexpected dead letters: %v", entries)
+	}
+}
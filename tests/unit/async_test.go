@@ -0,0 +1,166 @@
+package unit
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// gatedWriter blocks every write until the gate is opened.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Split(strings.TrimSpace(w.buf.String()), "\n")
+}
+
+func TestAsyncWatermarks(t *testing.T) {
+	writer := &gatedWriter{gate: make(chan struct{})}
+	var high, low atomic.Int32
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"slow"}})
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "slow", Writer: writer}}
+	config.Async = &logx.AsyncConfig{
+		QueueSize:       10,
+		HighWatermark:   0.8,
+		LowWatermark:    0.2,
+		OnHighWatermark: func(depth, capacity int) { high.Add(1) },
+		OnLowWatermark:  func(depth, capacity int) { low.Add(1) },
+	}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	// The writer goroutine takes one entry and blocks on it; 10 more fill
+	// the queue and the rest are dropped.
+	for i := 0; i < 20; i++ {
+		logger.Info("entry")
+	}
+	if high.Load() != 1 {
+		t.Errorf("Expected one high watermark call, got %d", high.Load())
+	}
+	if bp := logger.Backpressure(); bp < 0.8 {
+		t.Errorf("Backpressure = %v, want at least 0.8", bp)
+	}
+
+	close(writer.gate)
+	logger.Sync()
+	if low.Load() != 1 {
+		t.Errorf("Expected one low watermark call, got %d", low.Load())
+	}
+	if bp := logger.Backpressure(); bp != 0 {
+		t.Errorf("Backpressure after Sync = %v, want 0", bp)
+	}
+	if n := len(writer.lines()); n < 10 || n > 11 {
+		t.Errorf("Expected 10 or 11 written entries, got %d", n)
+	}
+}
+
+func TestAsyncPreservesOrder(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Async = &logx.AsyncConfig{}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 100; i++ {
+		logger.Info("entry", logx.Int("n", i))
+	}
+	logger.Close()
+
+	entries := read()
+	if len(entries) != 100 {
+		t.Fatalf("Expected 100 entries, got %d", len(entries))
+	}
+	for i := 0; i < 100; i++ {
+		if entries[i]["n"] != float64(i) {
+			t.Fatalf("Entry %d out of order: %v", i, entries[i]["n"])
+		}
+	}
+}
+
+func TestBackpressureSynchronous(t *testing.T) {
+	logger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	if bp := logger.Backpressure(); bp != 0 {
+		t.Errorf("Backpressure = %v, want 0 for a synchronous logger", bp)
+	}
+}
+
+func TestAsyncWALRecoversPendingEntries(t *testing.T) {
+	walPath := filepath.Join(t.TempDir(), "queue.wal")
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"slow"}})
+
+	// The first logger accepts entries that never reach its blocked writer,
+	// standing in for a process that crashes with a backlog.
+	blocked := &gatedWriter{gate: make(chan struct{})}
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "slow", Writer: blocked}}
+	config.Async = &logx.AsyncConfig{WALPath: walPath}
+	crashed, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		crashed.Info("pending", logx.Int("n", i))
+	}
+
+	// The next logger with the same write-ahead file writes them on startup
+	var recovered bytes.Buffer
+	restarted := logx.DefaultConfig()
+	restarted.Processors = []logx.Processor{router}
+	restarted.Sinks = []logx.SinkConfig{{Name: "slow", Writer: &recovered}}
+	restarted.Async = &logx.AsyncConfig{WALPath: walPath}
+	logger, read := newCaptureLogger(t, restarted)
+	defer logger.Close()
+
+	entries := decodeLines(t, &recovered)
+	if len(entries) != 3 || entries[0]["n"] != float64(0) || entries[2]["n"] != float64(2) {
+		t.Errorf("Expected the 3 pending entries in order, got %v", entries)
+	}
+	notice := read()
+	if len(notice) != 1 || notice[0]["recovered_count"] != float64(3) {
+		t.Errorf("Expected a recovery notice, got %v", notice)
+	}
+
+	close(blocked.gate)
+	crashed.Close()
+}
+
+func TestAsyncWALCleanRestart(t *testing.T) {
+	walPath := filepath.Join(t.TempDir(), "queue.wal")
+	config := logx.DefaultConfig()
+	config.Async = &logx.AsyncConfig{WALPath: walPath, WALSize: 4096}
+	logger, _ := newCaptureLogger(t, config)
+	// More data than the file holds, so that its space must be reused
+	for i := 0; i < 200; i++ {
+		logger.Info("written", logx.Int("n", i))
+	}
+	logger.Close()
+
+	config = logx.DefaultConfig()
+	config.Async = &logx.AsyncConfig{WALPath: walPath, WALSize: 4096}
+	logger, read := newCaptureLogger(t, config)
+	defer logger.Close()
+	if entries := read(); len(entries) != 0 {
+		t.Errorf("Expected nothing to recover after a clean shutdown, got %v", entries)
+	}
+}
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestAuditExporter(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Now().UTC().Format("2006-01-02")
+	manifestPath := filepath.Join(dir, "audit-"+date+logx.AuditManifestSuffix)
+
+	newAuditLogger := func() (*logx.Logger, *logx.AuditExporter) {
+		exporter, err := logx.NewAuditExporter(logx.AuditExporterConfig{Dir: dir})
+		if err != nil {
+			t.Fatalf("Failed to create audit exporter: %v", err)
+		}
+		router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"audit"}})
+		config := logx.DefaultConfig()
+		config.OutputPath = filepath.Join(dir, "app.log")
+		config.EventID = true
+		config.Processors = []logx.Processor{router}
+		config.Sinks = []logx.SinkConfig{{Name: "audit", Writer: exporter}}
+		logger, err := logx.New(config)
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		return logger, exporter
+	}
+
+	logger, exporter := newAuditLogger()
+	logger.Info("login", logx.UserID("u1"))
+	logger.Info("logout", logx.UserID("u1"))
+	logger.Sync()
+	first, err := logx.VerifyAuditManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("Expected a valid manifest: %v", err)
+	}
+	if first.Entries != 2 || first.FirstID == "" || first.FirstID == first.LastID {
+		t.Errorf("Unexpected manifest: %+v", first)
+	}
+	exporter.Close()
+
+	// A new exporter continues the day's file and manifest
+	logger, exporter = newAuditLogger()
+	logger.Info("login", logx.UserID("u2"))
+	exporter.Close()
+	manifest, err := logx.VerifyAuditManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("Expected a valid manifest: %v", err)
+	}
+	if manifest.Entries != 3 || manifest.FirstID != first.FirstID || manifest.LastID == first.LastID {
+		t.Errorf("Unexpected manifest after reopening: %+v", manifest)
+	}
+
+	// Tampering with the file is detected
+	file, _ := os.OpenFile(filepath.Join(dir, manifest.File), os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString(`{"message":"forged"}` + "\n")
+	file.Close()
+	if _, err := logx.VerifyAuditManifest(manifestPath); err == nil {
+		t.Error("Expected verification to fail after the file was modified")
+	}
+}
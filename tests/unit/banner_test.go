@@ -0,0 +1,35 @@
+package unit
+
+import (
+	"os"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStartupBanner(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Level = logx.ErrorLevel
+	config.Banner = &logx.BannerConfig{
+		Version: "v1.2.3",
+		Fields:  []logx.Field{logx.String(logx.KeyService, "checkout")},
+	}
+	_, read := newCaptureLogger(t, config)
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single banner entry despite the Error level, got %v", entries)
+	}
+	banner := entries[0]
+	if banner[logx.EventKey] != logx.EventStartup || banner["version"] != "v1.2.3" || banner[logx.KeyService] != "checkout" {
+		t.Errorf("Unexpected banner: %v", banner)
+	}
+	summary := banner["config"].(map[string]interface{})
+	if summary["level"] != "ERROR" || summary["output"] != config.OutputPath {
+		t.Errorf("Unexpected config summary: %v", summary)
+	}
+	host := banner["host"].(map[string]interface{})
+	if host["pid"] != float64(os.Getpid()) {
+		t.Errorf("Unexpected host summary: %v", host)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestBodyJSONMasked(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	bodies := logx.BodyConfig{Paths: []string{"card.number"}}
+	body := []byte(`{"amount":1200,"card":{"number":"4111111111111111"},"password":"hunter22"}`)
+
+	logger.Info("Charge", bodies.Field("request_body", body, "application/json; charset=utf-8"))
+
+	value := read()[0]["request_body"].(map[string]interface{})
+	if value["content_type"] != "application/json" || value["size"] != float64(len(body)) {
+		t.Errorf("Expected the media type and size, got %v", value)
+	}
+	decoded := value["body"].(map[string]interface{})
+	if decoded["amount"] != float64(1200) || decoded["password"] != "hu***22" {
+		t.Errorf("Expected sensitive keys masked, got %v", decoded)
+	}
+	if decoded["card"].(map[string]interface{})["number"] != "***MASKED***" {
+		t.Errorf("Expected masked path, got %v", decoded)
+	}
+	if _, ok := value["sha256"]; ok {
+		t.Errorf("Expected no hash for a logged body, got %v", value)
+	}
+}
+
+func TestBodyOmitted(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	large := []byte(`{"data":"` + strings.Repeat("x", 100) + `"}`)
+	binary := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0, 0}
+	invalid := []byte(`{"password":"hunter22"`)
+
+	logger.Info("Bodies",
+		logx.BodyConfig{MaxBytes: 64}.Field("large", large, "application/json"),
+		logx.Body("binary", binary, ""),
+		logx.Body("invalid", invalid, "application/json"),
+	)
+
+	entry := read()[0]
+	for key, want := range map[string]struct {
+		reason string
+		body   []byte
+	}{
+		"large":   {logx.BodyOmittedSize, large},
+		"binary":  {logx.BodyOmittedBinary, binary},
+		"invalid": {logx.BodyOmittedInvalid, invalid},
+	} {
+		value := entry[key].(map[string]interface{})
+		sum := sha256.Sum256(want.body)
+		if value["omitted"] != want.reason || value["sha256"] != hex.EncodeToString(sum[:]) {
+			t.Errorf("%s: expected omitted %q with hash, got %v", key, want.reason, value)
+		}
+		if _, ok := value["body"]; ok {
+			t.Errorf("%s: expected no body, got %v", key, value)
+		}
+	}
+	if entry["binary"].(map[string]interface{})["content_type"] != "image/png" {
+		t.Errorf("Expected a detected content type, got %v", entry["binary"])
+	}
+}
+
+func TestBodyTextAndForm(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	bodies := logx.BodyConfig{TextTypes: []string{"application/vnd.custom"}}
+
+	logger.Info("Bodies",
+		logx.Body("text", []byte("hello"), "text/plain"),
+		logx.Body("form", []byte("user=ann&token=abcdef&tag=a&tag=b"), "application/x-www-form-urlencoded"),
+		bodies.Field("custom", []byte("custom payload"), "application/vnd.custom"),
+		logx.Body("empty", nil, "application/json"),
+	)
+
+	entry := read()[0]
+	if entry["text"].(map[string]interface{})["body"] != "hello" {
+		t.Errorf("Expected the text body, got %v", entry["text"])
+	}
+	form := entry["form"].(map[string]interface{})["body"].(map[string]interface{})
+	if form["user"] != "ann" || form["token"] != "ab***ef" || len(form["tag"].([]interface{})) != 2 {
+		t.Errorf("Expected decoded form values with sensitive keys masked, got %v", form)
+	}
+	if entry["custom"].(map[string]interface{})["body"] != "custom payload" {
+		t.Errorf("Expected a configured text type to be logged, got %v", entry["custom"])
+	}
+	empty := entry["empty"].(map[string]interface{})
+	if empty["size"] != float64(0) || empty["omitted"] != nil {
+		t.Errorf("Expected an empty body without content, got %v", empty)
+	}
+}
+
+func TestBodyMessage(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	req := &chargeRequest{OrderID: "o-1", Card: &card{Number: "4111111111111111"}, Token: "abcdef"}
+
+	logger.Info("RPC", logx.BodyConfig{Paths: []string{"card.number"}}.Message("request", req))
+
+	body := read()[0]["request"].(map[string]interface{})["body"].(map[string]interface{})
+	if body["order_id"] != "o-1" || body["token"] != "ab***ef" || body["card"].(map[string]interface{})["number"] != "***MASKED***" {
+		t.Errorf("Expected the masked message, got %v", body)
+	}
+}
@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestVerbosityBoost(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Level = logx.WarnLevel
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Debug("before")
+	logger.Boost(time.Hour)
+	logger.Debug("during")
+	logger.EndBoost()
+	logger.Debug("after")
+	logger.EndBoost()
+
+	if logger.GetLevel() != logx.WarnLevel {
+		t.Errorf("Expected the level to be restored, got %v", logger.GetLevel())
+	}
+	var messages []string
+	for _, entry := range read() {
+		messages = append(messages, entry["message"].(string))
+	}
+	want := []string{"Verbosity boost started", "during", "Verbosity boost ended"}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, messages)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, messages)
+		}
+	}
+}
+
+func TestVerbosityBoostExpires(t *testing.T) {
+	config := logx.DefaultConfig()
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Boost(20 * time.Millisecond)
+	if logger.GetLevel() != logx.DebugLevel {
+		t.Fatalf("Expected Debug during the boost, got %v", logger.GetLevel())
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for logger.GetLevel() != logx.InfoLevel && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if logger.GetLevel() != logx.InfoLevel {
+		t.Fatalf("Expected the boost to expire")
+	}
+
+	entries := read()
+	if len(entries) != 2 || entries[0]["previous_level"] != "INFO" || entries[1]["restored_level"] != "INFO" {
+		t.Errorf("Unexpected boost markers: %v", entries)
+	}
+}
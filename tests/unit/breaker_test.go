@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestCircuitBreakerTripsAndRestores(t *testing.T) {
+	breaker := logx.NewCircuitBreaker(logx.CircuitBreakerConfig{
+		MaxRate:  100, // 1 entry per 10ms interval
+		Sustain:  20 * time.Millisecond,
+		Cooldown: 30 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+	})
+	defer breaker.Close()
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{breaker}
+	logger, read := newCaptureLogger(t, config)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !breaker.Open() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected breaker to open under sustained load")
+		}
+		logger.Info("flood")
+		time.Sleep(100 * time.Microsecond)
+	}
+
+	logger.Info("suppressed")
+	logger.Error("kept")
+
+	deadline = time.Now().Add(2 * time.Second)
+	for breaker.Open() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected breaker to close once load stops")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	logger.Info("restored")
+
+	var opened, closed, summary, kept bool
+	for _, entry := range read() {
+		switch entry["message"] {
+		case "suppressed":
+			t.Error("Expected Info entry to be suppressed while open")
+		case "kept":
+			kept = true
+		case "Log circuit breaker opened, writing errors only":
+			opened = true
+		case "Log entries suppressed by circuit breaker":
+			summary = entry["suppressed_info"] != nil
+		case "Log circuit breaker closed, normal logging restored":
+			closed = entry["suppressed_count"].(float64) >= 1
+		}
+	}
+	if !opened || !closed || !summary || !kept {
+		t.Errorf("Expected open, summary, kept error and close entries; got opened=%v summary=%v kept=%v closed=%v",
+			opened, summary, kept, closed)
+	}
+}
+
+func TestCircuitBreakerToleratesBursts(t *testing.T) {
+	breaker := logx.NewCircuitBreaker(logx.CircuitBreakerConfig{
+		MaxRate:  100,
+		Sustain:  time.Hour,
+		Interval: 10 * time.Millisecond,
+	})
+	defer breaker.Close()
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{breaker}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 1000; i++ {
+		logger.Info("burst")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if breaker.Open() {
+		t.Fatal("Expected a short burst not to trip the breaker")
+	}
+	if entries := read(); len(entries) != 1000 {
+		t.Errorf("Expected all 1000 entries, got %d", len(entries))
+	}
+}
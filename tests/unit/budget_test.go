@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestLogBudget(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	reqLogger := logger.WithBudget(logx.Budget{MaxEntries: 5}).With(logx.RequestID("r1"))
+	for i := 0; i < 20; i++ {
+		reqLogger.Debug("noisy")
+		reqLogger.Named("db").Info("query")
+	}
+	reqLogger.Error("still written")
+	reqLogger.EndBudget()
+	reqLogger.EndBudget()
+	logger.Info("other request unaffected")
+
+	entries := read()
+	var info, notices, errors int
+	var summary map[string]interface{}
+	for _, entry := range entries {
+		switch entry["message"] {
+		case "query":
+			info++
+		case "Log budget exceeded, suppressing entries below Warn":
+			notices++
+		case "Log budget summary":
+			summary = entry
+		case "still written":
+			errors++
+		}
+	}
+	if info != 5 || notices != 1 || errors != 1 {
+		t.Errorf("Expected 5 info entries, 1 notice and the error; got %d, %d, %d", info, notices, errors)
+	}
+	if summary == nil || summary["request_id"] != "r1" || summary["suppressed_count"] != float64(15) {
+		t.Errorf("Unexpected budget summary: %v", summary)
+	}
+	if last := entries[len(entries)-1]; last["message"] != "other request unaffected" {
+		t.Errorf("Expected the parent logger to be unaffected, got %v", last)
+	}
+}
@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestCallerComponent(t *testing.T) {
+	tests := []struct {
+		name   string
+		config logx.CallerComponentConfig
+		want   string
+	}{
+		{"Module root", logx.CallerComponentConfig{TrimPrefix: "tests/unit"}, "unit"},
+		{"Trimmed prefix", logx.CallerComponentConfig{TrimPrefix: "tests/"}, "unit"},
+		{"Depth", logx.CallerComponentConfig{TrimPrefix: "example.com/other", Depth: 1}, "tests"},
+		{"Whole path", logx.CallerComponentConfig{TrimPrefix: "example.com/other"}, "tests/unit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := logx.DefaultConfig()
+			config.CallerComponent = &tt.config
+			logger, read := newCaptureLogger(t, config)
+
+			logger.Info("First")
+			logger.Info("Second")
+			logger.Component("db").Info("Named")
+
+			entries := read()
+			if len(entries) != 3 {
+				t.Fatalf("Expected 3 entries, got %d", len(entries))
+			}
+			for _, entry := range entries[:2] {
+				if entry[logx.KeyComponent] != tt.want {
+					t.Errorf("Expected component %q, got %v", tt.want, entry[logx.KeyComponent])
+				}
+			}
+			if entries[2][logx.KeyComponent] != "db" {
+				t.Errorf("Expected the component of the logger to be kept, got %v", entries[2][logx.KeyComponent])
+			}
+		})
+	}
+}
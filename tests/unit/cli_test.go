@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// fakeProgress records the calls made to a progress bar.
+type fakeProgress struct {
+	out   *bytes.Buffer
+	calls []string
+}
+
+func (p *fakeProgress) Clear() error {
+	p.calls = append(p.calls, "clear")
+	return nil
+}
+
+func (p *fakeProgress) RenderBlank() error {
+	p.calls = append(p.calls, "render")
+	p.out.WriteString("[bar]\n")
+	return nil
+}
+
+func TestCLIProgressLines(t *testing.T) {
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Level = logx.DebugLevel
+	config.CLI = &logx.CLIConfig{Output: &buf}
+	config.OutputPath = filepath.Join(t.TempDir(), "cli.log")
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Debug("Resolving mirrors")
+	logger.Info("Downloaded archive", logx.Int("size", 1048576), logx.String("path", "/tmp/my file.tgz"))
+	logger.With(logx.String("step", "verify")).Warn("Checksum file missing")
+	logger.Error("Extraction failed", logx.ErrorField(errors.New("unexpected EOF")), logx.Any("files", []string{"a", "b"}))
+	logger.Sync()
+
+	want := strings.Join([]string{
+		`Downloaded archive size=1048576 path="/tmp/my file.tgz"`,
+		`warning: Checksum file missing step=verify`,
+		`error: Extraction failed error="unexpected EOF" files=["a","b"]`,
+		``,
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("Expected progress lines\n%s\ngot\n%s", want, buf.String())
+	}
+
+	entries := readJSONFile(t, config.OutputPath)
+	if len(entries) != 4 || entries[0]["message"] != "Resolving mirrors" || entries[1]["size"] != float64(1048576) {
+		t.Errorf("Expected every entry as JSON in the log file, got %v", entries)
+	}
+}
+
+func TestCLIVerboseAndProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progress := &fakeProgress{out: &buf}
+	config := logx.DefaultConfig()
+	config.Level = logx.DebugLevel
+	config.CLI = &logx.CLIConfig{Output: &buf, Verbose: true, Progress: progress}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Debug("Resolving mirrors")
+	logger.Info("Done")
+
+	if want := "debug: Resolving mirrors\n[bar]\nDone\n[bar]\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+	if strings.Join(progress.calls, ",") != "clear,render,clear,render" {
+		t.Errorf("Expected the bar cleared and redrawn around each line, got %v", progress.calls)
+	}
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// colorOutput logs an Error entry through a development logger writing to
+// a buffer with theme and returns the output.
+func colorOutput(t *testing.T, theme logx.ColorTheme) string {
+	t.Helper()
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.ColorTheme = theme
+	config.OutputWriter = &buf
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Error("failed")
+	return buf.String()
+}
+
+func TestColorThemeNotTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	if out := colorOutput(t, logx.ThemeDark); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected no colors when not writing to a terminal, got %q", out)
+	}
+}
+
+func TestColorThemeForceColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+	themes := map[logx.ColorTheme]string{
+		logx.ThemeDark:         "\x1b[31mERROR\x1b[0m",
+		logx.ThemeLight:        "\x1b[31mERROR\x1b[0m",
+		logx.ThemeHighContrast: "\x1b[1;97;41mERROR\x1b[0m",
+	}
+	for theme, want := range themes {
+		if out := colorOutput(t, theme); !strings.Contains(out, want) {
+			t.Errorf("Expected %q with the %s theme, got %q", want, theme, out)
+		}
+	}
+	if out := colorOutput(t, logx.ThemeNone); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected no colors without a theme, got %q", out)
+	}
+}
+
+func TestColorThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("FORCE_COLOR", "1")
+	if out := colorOutput(t, logx.ThemeDark); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected NO_COLOR to win over FORCE_COLOR, got %q", out)
+	}
+}
+
+func TestEnableColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.EnableColor = true
+	config.OutputWriter = &buf
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Error("failed")
+
+	out := buf.String()
+	if !strings.Contains(out, "\x1b[31mERROR\x1b[0m") {
+		t.Errorf("Expected the dark theme's level colors, got %q", out)
+	}
+	if !strings.Contains(out, "\x1b[2munit/color_test.go:") {
+		t.Errorf("Expected a dimmed caller, got %q", out)
+	}
+}
+
+func TestEnableColorNotTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.EnableColor = true
+	config.OutputWriter = &buf
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Info("plain")
+	if out := buf.String(); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected no colors when not writing to a terminal, got %q", out)
+	}
+}
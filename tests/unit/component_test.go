@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestComponentHierarchy(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	client := logger.Component("http").Component("client")
+	client.Info("Request sent")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %v", entries)
+	}
+	if entries[0]["logger"] != "http.client" || entries[0]["component"] != "http.client" {
+		t.Errorf("Expected logger and component http.client, got %v", entries[0])
+	}
+}
+
+func TestComponentLevelInherited(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	client := logger.Component("http").Component("client")
+	db := logger.Component("db")
+
+	debug, errorLevel := logx.DebugLevel, logx.ErrorLevel
+	logger.ConfigureComponent("http", logx.ComponentConfig{Level: &debug})
+	logger.ConfigureComponent("db", logx.ComponentConfig{Level: &errorLevel})
+
+	client.Debug("client debug")
+	logger.Debug("root debug")
+	db.Warn("db warn")
+	db.Named("pool").Error("pool error")
+	logger.Info("root info")
+
+	if got := logger.ComponentLevel("http.client.tls"); got != logx.DebugLevel {
+		t.Errorf("Expected inherited Debug level, got %v", got)
+	}
+	if got := logger.ComponentLevel("cache"); got != logx.InfoLevel {
+		t.Errorf("Expected the logger's level for an unconfigured component, got %v", got)
+	}
+
+	entries := read()
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e["message"].(string))
+	}
+	want := []string{"client debug", "pool error", "root info"}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, messages)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, messages)
+		}
+	}
+
+	logger.ResetComponent("http")
+	client.Debug("hidden")
+	if entries := read(); len(entries) != 3 {
+		t.Errorf("Expected the reset component to follow the logger's level, got %v", entries)
+	}
+}
+
+func TestComponentLevelFollowsSetLevel(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	warn := logx.WarnLevel
+	logger.ConfigureComponent("http", logx.ComponentConfig{Level: &warn})
+
+	logger.SetLevel(logx.DebugLevel)
+	logger.Debug("root debug")
+	logger.Named("http").Info("http info")
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["message"] != "root debug" {
+		t.Errorf("Expected only the root entry, got %v", entries)
+	}
+}
+
+func TestComponentSampler(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	dropAll := logx.ProcessorFunc(func(e *logx.Entry) bool { return false })
+	keepAll := logx.ProcessorFunc(func(e *logx.Entry) bool { return true })
+	logger.ConfigureComponent("http", logx.ComponentConfig{Sampler: dropAll})
+	logger.ConfigureComponent("http.admin", logx.ComponentConfig{Sampler: keepAll})
+
+	logger.Component("http").Component("client").Info("dropped")
+	logger.Component("http").Component("admin").Info("kept")
+	logger.Info("root")
+
+	entries := read()
+	if len(entries) != 2 || entries[0]["message"] != "kept" || entries[1]["message"] != "root" {
+		t.Errorf("Expected the descendant's sampler to replace the inherited one, got %v", entries)
+	}
+}
+
+func TestComponentMasking(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	logger.ConfigureComponent("http", logx.ComponentConfig{SensitiveKeys: []string{"Cookie"}})
+	logger.ConfigureComponent("http.client", logx.ComponentConfig{SensitiveKeys: []string{"user_agent"}})
+
+	logger.Named("http").Named("client").Info("request",
+		logx.String("cookie", "session=abcdef"),
+		logx.String("user_agent", "curl/8.0"),
+		logx.String("path", "/"))
+	logger.Info("root", logx.String("cookie", "session=abcdef"))
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	if entries[0]["cookie"] != "se***ef" || entries[0]["user_agent"] != "cu***.0" || entries[0]["path"] != "/" {
+		t.Errorf("Expected inherited and own keys masked, got %v", entries[0])
+	}
+	if entries[1]["cookie"] != "session=abcdef" {
+		t.Errorf("Expected the root logger unaffected, got %v", entries[1])
+	}
+}
@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write logger config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	dir := t.TempDir()
+	auditPath := filepath.Join(dir, "audit.log")
+	path := writeConfigFile(t, "logging.yaml", `
+level: debug
+output_path: `+filepath.Join(dir, "app.log")+`
+add_caller: false
+keys:
+  message: msg
+sinks:
+  - name: audit
+    output_path: `+auditPath+`
+    level: warn
+masking:
+  sensitive_keys: [config_test_secret]
+processors:
+  - type: route
+    routes:
+      - sinks: [default, audit]
+`)
+	defer logx.RemoveSensitiveKey("config_test_secret")
+
+	config, err := logx.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	defer logx.Pipeline(config.Processors).Close()
+	if config.Level != logx.DebugLevel || config.AddCaller || !config.AddStacktrace {
+		t.Errorf("Unexpected config: level %v, caller %v, stacktrace %v", config.Level, config.AddCaller, config.AddStacktrace)
+	}
+	if config.Keys.Message != "msg" {
+		t.Errorf("Expected the message key from the file, got %+v", config.Keys)
+	}
+	if len(config.Sinks) != 1 || config.Sinks[0].Level == nil || *config.Sinks[0].Level != logx.WarnLevel {
+		t.Fatalf("Expected the audit sink at WARN, got %+v", config.Sinks)
+	}
+
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Debug("debug")
+	logger.Warn("warn", logx.String("config_test_secret", "hunter22"))
+	logger.Sync()
+
+	if entries := readJSONFile(t, config.OutputPath); len(entries) != 2 {
+		t.Errorf("Expected 2 entries in the default output, got %v", entries)
+	}
+	audit := readJSONFile(t, auditPath)
+	if len(audit) != 1 || audit[0]["config_test_secret"] != "hu***22" {
+		t.Errorf("Expected one masked entry in the audit sink, got %v", audit)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeConfigFile(t, "logging.json", `{
+  "level": "ERROR",
+  "time_zone": "UTC",
+  "rotation": {"interval": "daily", "retention": "72h", "service": "api"},
+  "level_schedule": [{"name": "deploy", "cron": "0 2 * * sat", "duration": "2h", "level": "debug"}]
+}`)
+	config, err := logx.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Level != logx.ErrorLevel || config.TimeZone != "UTC" {
+		t.Errorf("Unexpected config: level %v, time zone %q", config.Level, config.TimeZone)
+	}
+	if r := config.Rotation; r == nil || r.Interval != logx.RotateDaily || r.Retention.Hours() != 72 || r.Service != "api" {
+		t.Errorf("Unexpected rotation: %+v", config.Rotation)
+	}
+	if w := config.LevelSchedule; len(w) != 1 || w[0].Duration != 2*time.Hour || w[0].Level != logx.DebugLevel {
+		t.Errorf("Unexpected level schedule: %+v", config.LevelSchedule)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for _, content := range []string{
+		"level: loud\n",
+		"levle: debug\n",
+		"rotation: {interval: weekly}\n",
+		"processors:\n  - type: unknown\n",
+	} {
+		if _, err := logx.LoadConfig(writeConfigFile(t, "logging.yaml", content)); err == nil {
+			t.Errorf("Expected an error for %q", content)
+		}
+	}
+	if _, err := logx.LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build js && wasm
+
+package unit
+
+import (
+	"syscall/js"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestBrowserConsoleOutput(t *testing.T) {
+	type call struct {
+		method  string
+		message string
+		fields  js.Value
+	}
+	var calls []call
+	console := js.Global().Get("Object").New()
+	for _, method := range []string{"debug", "log", "warn", "error"} {
+		method := method
+		fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			calls = append(calls, call{method, args[0].String(), args[1]})
+			return nil
+		})
+		defer fn.Release()
+		console.Set(method, fn)
+	}
+	original := js.Global().Get("console")
+	js.Global().Set("console", console)
+	defer js.Global().Set("console", original)
+
+	config := logx.DefaultConfig()
+	config.Level = logx.DebugLevel
+	config.Development = true
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Debug("Cache miss", logx.String("path", "/users/1"))
+	logger.Info("Started", logx.Int("port", 8080))
+	logger.Warn("Slow request")
+	logger.Error("Request failed", logx.Any("tags", map[string]interface{}{"retry": true}))
+	logger.Sync()
+
+	want := []string{"debug", "log", "warn", "error"}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected %d console calls, got %d", len(want), len(calls))
+	}
+	for i, c := range calls {
+		if c.method != want[i] || c.fields.Type() != js.TypeObject {
+			t.Errorf("Call %d: got console.%s with %s", i, c.method, c.fields.Type())
+		}
+	}
+	if calls[0].fields.Get("path").String() != "/users/1" || calls[1].message != "Started" ||
+		calls[1].fields.Get("port").Int() != 8080 || !calls[3].fields.Get("tags").Get("retry").Bool() {
+		t.Errorf("Unexpected console arguments")
+	}
+}
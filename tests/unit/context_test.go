@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func contextField(t *testing.T, ctx context.Context) map[string]interface{} {
+	t.Helper()
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	logger.Info("Call", logx.CtxInfo(ctx))
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	info, ok := entries[0]["context"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected context object, got %v", entries[0]["context"])
+	}
+	return info
+}
+
+func TestCtxInfoActive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	info := contextField(t, ctx)
+	if info["done"] != false {
+		t.Errorf("done = %v, want false", info["done"])
+	}
+	if _, ok := info["deadline"]; !ok {
+		t.Error("Expected deadline")
+	}
+	if remaining, _ := info["remaining"].(string); !strings.HasPrefix(remaining, "59m") {
+		t.Errorf("remaining = %q", remaining)
+	}
+	if _, ok := info["error"]; ok {
+		t.Error("Expected no error for an active context")
+	}
+}
+
+func TestCtxInfoDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	info := contextField(t, ctx)
+	if info["done"] != true {
+		t.Errorf("done = %v, want true", info["done"])
+	}
+	if info["error"] != context.DeadlineExceeded.Error() {
+		t.Errorf("error = %v", info["error"])
+	}
+	if remaining, _ := info["remaining"].(string); !strings.HasPrefix(remaining, "-") {
+		t.Errorf("Expected negative remaining, got %q", remaining)
+	}
+}
+
+func TestCtxInfoCause(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("client disconnected"))
+
+	info := contextField(t, ctx)
+	if info["error"] != context.Canceled.Error() {
+		t.Errorf("error = %v", info["error"])
+	}
+	if info["cause"] != "client disconnected" {
+		t.Errorf("cause = %v", info["cause"])
+	}
+	if _, ok := info["deadline"]; ok {
+		t.Error("Expected no deadline")
+	}
+}
@@ -0,0 +1,17 @@
+package unit
+
+import logx "github.com/seasbee/go-logx"
+
+// logCoverageFixture contains the log statements of TestCoverage.
+func logCoverageFixture(logger *logx.Logger, fail bool) {
+	logger.Debug("Cache warmed")
+	for i := 0; i < 2; i++ {
+		logger.Infof("Processed batch %d", i)
+	}
+	if fail {
+		logger.Error("Batch failed",
+			logx.String("reason", "never"))
+	}
+	msg := "Computed message"
+	logger.Warn(msg)
+}
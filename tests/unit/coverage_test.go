@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestCoverage(t *testing.T) {
+	coverage := logx.NewCoverage()
+	config := logx.DefaultConfig()
+	config.Coverage = coverage
+	logger, _ := newCaptureLogger(t, config)
+
+	logCoverageFixture(logger, false)
+
+	report, err := coverage.Report("coverage_fixture_test.go")
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, site := range report.Statements {
+		counts[site.Message] = site.Count
+	}
+	want := map[string]int{"Cache warmed": 1, "Processed batch %d": 2, "Batch failed": 0, "Computed message": 1}
+	for msg, count := range want {
+		if got, ok := counts[msg]; !ok || got != count {
+			t.Errorf("Expected %q exercised %d times, got %d (found %v)", msg, count, got, ok)
+		}
+	}
+
+	uncovered := report.Uncovered()
+	if len(uncovered) != 1 || uncovered[0].Level != logx.ErrorLevel {
+		t.Fatalf("Expected only the Error statement uncovered, got %+v", uncovered)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if !strings.Contains(buf.String(), `coverage_fixture_test.go:12 `) || !strings.Contains(buf.String(), `ERROR "Batch failed"`) {
+		t.Errorf("Expected the uncovered statement in the report, got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestDeduplicatorCollapsesRepeats(t *testing.T) {
+	dedup := logx.NewDeduplicator(time.Hour, "target")
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{dedup}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("retrying connection", logx.String("target", "db-1"), logx.Int("attempt", i))
+	}
+	logger.Warn("retrying connection", logx.String("target", "db-2"))
+
+	if entries := read(); len(entries) != 2 {
+		t.Fatalf("Expected 2 entries before flush, got %d", len(entries))
+	}
+
+	dedup.Close()
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after flush, got %d: %v", len(entries), entries)
+	}
+	summary := entries[2]
+	if summary["target"] != "db-1" || summary["repeat_count"] != float64(4) {
+		t.Errorf("Unexpected summary entry: %v", summary)
+	}
+	if summary[logx.SampledKey] != true || summary[logx.SuppressedCountKey] != float64(3) || summary[logx.SampleWindowKey] != "1h0m0s" {
+		t.Errorf("Expected sampling metadata on the summary: %v", summary)
+	}
+	if _, ok := summary["caller"]; ok {
+		t.Errorf("Summary entry should not carry caller information: %v", summary)
+	}
+}
+
+func TestDeduplicatorWindowExpiry(t *testing.T) {
+	dedup := logx.NewDeduplicator(20 * time.Millisecond)
+	defer dedup.Close()
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{dedup}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	time.Sleep(100 * time.Millisecond)
+	logger.Info("tick")
+
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected first entry, summary and new window entry, got %d: %v", len(entries), entries)
+	}
+	if entries[1]["repeat_count"] != float64(1) {
+		t.Errorf("Expected repeat_count 1 in summary, got %v", entries[1])
+	}
+}
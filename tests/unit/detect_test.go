@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"os"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// setArgs replaces the command line arguments for the duration of a test.
+func setArgs(t *testing.T, args ...string) {
+	t.Helper()
+	saved := os.Args
+	os.Args = append([]string{saved[0]}, args...)
+	t.Cleanup(func() { os.Args = saved })
+}
+
+// clearLevelEnv unsets the variables inspected by DetectLevel.
+func clearLevelEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"LOG_LEVEL", "AWS_LAMBDA_LOG_LEVEL", "DEBUG"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want logx.Level
+		ok   bool
+	}{
+		{name: "nothing requested", ok: false, want: logx.InfoLevel},
+		{name: "flag with equals", args: []string{"serve", "--log-level=warn"}, env: map[string]string{"LOG_LEVEL": "debug"}, want: logx.WarnLevel, ok: true},
+		{name: "flag with value", args: []string{"-log-level", "error"}, want: logx.ErrorLevel, ok: true},
+		{name: "debug flag", args: []string{"--debug"}, env: map[string]string{"LOG_LEVEL": "error"}, want: logx.DebugLevel, ok: true},
+		{name: "debug flag false", args: []string{"--debug=false"}, want: logx.InfoLevel, ok: false},
+		{name: "flags after terminator", args: []string{"--", "--log-level=trace"}, want: logx.InfoLevel, ok: false},
+		{name: "LOG_LEVEL", env: map[string]string{"LOG_LEVEL": "warning", "AWS_LAMBDA_LOG_LEVEL": "DEBUG"}, want: logx.WarnLevel, ok: true},
+		{name: "Lambda", env: map[string]string{"AWS_LAMBDA_LOG_LEVEL": "TRACE", "DEBUG": "1"}, want: logx.TraceLevel, ok: true},
+		{name: "DEBUG pattern", env: map[string]string{"DEBUG": "app:*"}, want: logx.DebugLevel, ok: true},
+		{name: "DEBUG false", env: map[string]string{"DEBUG": "false"}, want: logx.InfoLevel, ok: false},
+		{name: "invalid skipped", env: map[string]string{"LOG_LEVEL": "loud", "DEBUG": "true"}, want: logx.DebugLevel, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setArgs(t, tt.args...)
+			clearLevelEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			level, ok := logx.DetectLevel()
+			if ok != tt.ok || (ok && level != tt.want) {
+				t.Errorf("Expected %v, %v, got %v, %v", tt.want, tt.ok, level, ok)
+			}
+		})
+	}
+}
+
+func TestConfigDetectLevel(t *testing.T) {
+	setArgs(t)
+	clearLevelEnv(t)
+	t.Setenv("LOG_LEVEL", "debug")
+
+	config := logx.DefaultConfig()
+	config.Level = logx.WarnLevel
+	config.DetectLevel = true
+	logger, read := newCaptureLogger(t, config)
+	if logger.GetLevel() != logx.DebugLevel {
+		t.Errorf("Expected the detected level, got %v", logger.GetLevel())
+	}
+
+	t.Setenv("LOG_LEVEL", "")
+	if err := logger.Reload(config); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if logger.GetLevel() != logx.WarnLevel {
+		t.Errorf("Expected the fallback level after reloading, got %v", logger.GetLevel())
+	}
+	if entries := read(); len(entries) != 0 {
+		t.Errorf("Expected no warnings, got %v", entries)
+	}
+}
+
+func TestConfigDetectLevelInvalid(t *testing.T) {
+	setArgs(t)
+	clearLevelEnv(t)
+	t.Setenv("LOG_LEVEL", "loud")
+
+	config := logx.DefaultConfig()
+	config.DetectLevel = true
+	logger, read := newCaptureLogger(t, config)
+
+	entries := read()
+	if logger.GetLevel() != logx.InfoLevel || len(entries) != 1 || entries[0]["message"] != "Ignored invalid log level" {
+		t.Fatalf("Expected the fallback level and a warning, got %v, %v", logger.GetLevel(), entries)
+	}
+	if entries[0]["level"] != "WARN" {
+		t.Errorf("Expected a Warn entry, got %v", entries[0])
+	}
+}
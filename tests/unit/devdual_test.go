@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestDevDualMirrorsJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.json")
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.DevDual = true
+	config.OutputPath = path
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("Server started", logx.Int("port", 8080))
+	logger.Error("Request failed")
+	logger.Sync()
+
+	entries := readJSONFile(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 JSON entries, got %d", len(entries))
+	}
+	if entries[0]["message"] != "Server started" || entries[0]["port"] != float64(8080) {
+		t.Errorf("Unexpected entry %v", entries[0])
+	}
+	if _, ok := entries[1]["stacktrace"].([]interface{}); !ok {
+		t.Errorf("Expected strict JSON stacktrace array, got %v", entries[1]["stacktrace"])
+	}
+}
+
+func TestDevDualRequiresOutputPath(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.DevDual = true
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected error without OutputPath")
+	}
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestFieldEncryption(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	publicKey, err := logx.ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("Failed to parse key: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Encryption = &logx.EncryptionConfig{PublicKey: publicKey, KeyID: "2024-q3", Keys: []string{"Email", "account"}}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("Login", logx.String("email", "user@example.com"), logx.Any("account", map[string]int{"id": 7}), logx.String("password", "hunter22"))
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["password"] != "hu***22" {
+		t.Errorf("Expected other sensitive keys to stay masked, got %v", entry["password"])
+	}
+	for key, want := range map[string]string{"email": `"user@example.com"`, "account": `{"id":7}`} {
+		value, _ := entry[key].(string)
+		if !strings.HasPrefix(value, logx.EncryptedPrefix+"2024-q3:") || strings.Contains(value, "example") {
+			t.Fatalf("Expected %s to be encrypted, got %v", key, entry[key])
+		}
+		plaintext, keyID, err := logx.DecryptValue(value, privateKey)
+		if err != nil || keyID != "2024-q3" || string(plaintext) != want {
+			t.Errorf("Expected %s to decrypt to %s, got %s, %q, %v", key, want, plaintext, keyID, err)
+		}
+	}
+
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, _, err := logx.DecryptValue(entry["email"].(string), otherKey); err == nil {
+		t.Error("Expected decryption with another key to fail")
+	}
+}
+
+func TestFieldEncryptionRequiresKey(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Encryption = &logx.EncryptionConfig{Keys: []string{"email"}}
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error without a public key")
+	}
+}
+
+func TestFieldEncryptionSubscribeAndSnapshot(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Encryption = &logx.EncryptionConfig{PublicKey: &privateKey.PublicKey, Keys: []string{"customer_ref"}}
+	config.History = logx.HistoryConfig{MaxEntries: 10}
+	logger, read := newCaptureLogger(t, config)
+	sub := logger.Subscribe(nil)
+
+	logger.Info("Order placed", logx.String("customer_ref", "alice@example.com"))
+	written := read()
+	if len(written) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(written))
+	}
+	sub.Close()
+
+	var got []logx.Entry
+	for entry := range sub.C {
+		got = append(got, entry)
+	}
+	got = append(got, logger.Snapshot(0)...)
+	if len(got) != 2 {
+		t.Fatalf("Expected the entry from the subscription and the snapshot, got %+v", got)
+	}
+	for _, entry := range got {
+		if len(entry.Fields) != 1 {
+			t.Fatalf("Expected 1 field, got %+v", entry.Fields)
+		}
+		value, _ := entry.Fields[0].Value.(string)
+		if !strings.HasPrefix(value, logx.EncryptedPrefix) || strings.Contains(value, "alice") {
+			t.Errorf("Expected customer_ref to be encrypted, got %v", entry.Fields[0].Value)
+		}
+		if plaintext, _, err := logx.DecryptValue(value, privateKey); err != nil || string(plaintext) != `"alice@example.com"` {
+			t.Errorf("Expected customer_ref to decrypt, got %s, %v", plaintext, err)
+		}
+		if value != written[0]["customer_ref"] {
+			t.Errorf("Expected the ciphertext written to the output, got %v and %v", value, written[0]["customer_ref"])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestEntryCloneAndCaller(t *testing.T) {
+	var kept []*logx.Entry
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{logx.ProcessorFunc(func(e *logx.Entry) bool {
+		kept = append(kept, e.Clone())
+		return true
+	})}
+	logger, _ := newCaptureLogger(t, config)
+
+	logger.Info("first", logx.String("k", "v1"))
+	logger.Info("second", logx.String("k", "v2"))
+
+	if len(kept) != 2 || kept[0].Message != "first" || kept[1].Message != "second" {
+		t.Fatalf("Unexpected retained entries: %v", kept)
+	}
+	if !strings.HasPrefix(kept[0].Caller, "unit/entry_test.go:") {
+		t.Errorf("Expected the caller to point at the logging call, got %q", kept[0].Caller)
+	}
+
+	clone := kept[0].Clone()
+	clone.Fields[0].Value = "changed"
+	if kept[0].Fields[0].Value != "v1" {
+		t.Errorf("Expected Clone to copy the fields, got %v", kept[0].Fields)
+	}
+}
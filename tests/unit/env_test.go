@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestEnvMasksSensitiveNames(t *testing.T) {
+	t.Setenv("LOGXTEST_MODE", "prod")
+	t.Setenv("LOGXTEST_DB_PASSWORD", "s3cretword")
+	t.Setenv("LOGXTEST_API_KEY", "abcdef")
+	t.Setenv("DB_HOST", "db1")
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	logger.Info("Starting", logx.Env("LOGXTEST_*", "DB_HOST", "LOGXTEST_UNSET"))
+
+	env := read()[0][logx.EnvKey].(map[string]interface{})
+	expected := map[string]interface{}{
+		"LOGXTEST_MODE":        "prod",
+		"LOGXTEST_DB_PASSWORD": "s3***rd",
+		"LOGXTEST_API_KEY":     "ab***ef",
+		"DB_HOST":              "db1",
+	}
+	if len(env) != len(expected) {
+		t.Errorf("Expected %d variables, got %v", len(expected), env)
+	}
+	for name, want := range expected {
+		if env[name] != want {
+			t.Errorf("Expected %s=%v, got %v", name, want, env[name])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// multiError combines errors like hashicorp/go-multierror.
+type multiError struct{ errs []error }
+
+func (m *multiError) Error() string          { return fmt.Sprintf("%d errors occurred", len(m.errs)) }
+func (m *multiError) WrappedErrors() []error { return m.errs }
+
+func TestJoinedErrorCauses(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	inner := errors.Join(errors.New("disk full"), os.ErrPermission)
+	err := fmt.Errorf("save failed: %w", errors.Join(inner, &multiError{errs: []error{errors.New("timeout")}}))
+	logger.Error("Operation failed", logx.ErrorField(err))
+
+	entry := read()[0]
+	if entry["error"] != err.Error() {
+		t.Errorf("Expected the error message to be kept, got %v", entry["error"])
+	}
+	causes, ok := entry["error"+logx.ErrorCausesSuffix].([]interface{})
+	if !ok || len(causes) != 2 {
+		t.Fatalf("Expected 2 causes, got %v", entry["error_causes"])
+	}
+	first := causes[0].(map[string]interface{})
+	nested := first["causes"].([]interface{})
+	if len(nested) != 2 || nested[0].(map[string]interface{})["message"] != "disk full" {
+		t.Errorf("Unexpected nested causes: %v", nested)
+	}
+	if nested[1].(map[string]interface{})["type"] != "*errors.errorString" {
+		t.Errorf("Expected the cause type, got %v", nested[1])
+	}
+	second := causes[1].(map[string]interface{})
+	if second["type"] != "*unit.multiError" || len(second["causes"].([]interface{})) != 1 {
+		t.Errorf("Expected the multierror's wrapped errors, got %v", second)
+	}
+}
+
+func TestPlainErrorHasNoCauses(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	logger.Error("Operation failed", logx.ErrorField(fmt.Errorf("wrapped: %w", os.ErrNotExist)))
+	if entry := read()[0]; entry["error_causes"] != nil {
+		t.Errorf("Expected no causes for a single error, got %v", entry["error_causes"])
+	}
+}
@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestNewEventIDIsSortable(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = logx.NewEventID()
+		if len(ids[i]) != 26 {
+			t.Fatalf("Expected 26-character ULID, got %q", ids[i])
+		}
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("Expected event IDs to sort in creation order")
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate event ID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestEventIDStampedAcrossSinks(t *testing.T) {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{logx.DefaultSinkName, "copy"}})
+	var copySink bytes.Buffer
+	config := logx.DefaultConfig()
+	config.EventID = true
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "copy", Writer: &copySink}}
+	logger, read := newCaptureLogger(t, config)
+	sub := logger.Subscribe(nil)
+	defer sub.Close()
+
+	logger.Info("stamped")
+
+	main := read()
+	copies := decodeLines(t, &copySink)
+	if len(main) != 1 || len(copies) != 1 {
+		t.Fatalf("Expected one entry per sink, got %d and %d", len(main), len(copies))
+	}
+	id, _ := main[0][logx.EventIDKey].(string)
+	if len(id) != 26 || copies[0][logx.EventIDKey] != id {
+		t.Errorf("Expected the same event ID in both sinks, got %v and %v", main[0], copies[0])
+	}
+	entry := <-sub.C
+	last := entry.Fields[len(entry.Fields)-1]
+	if last.Key != logx.EventIDKey || last.Value != id {
+		t.Errorf("Expected subscribers to see the event ID, got %+v", entry.Fields)
+	}
+}
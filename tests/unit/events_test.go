@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestDeprecationWarnReportedOnce(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	for i := 0; i < 5; i++ {
+		logger.DeprecationWarn("client.GetUserV1", "v3.0.0")
+	}
+	logger.With(logx.String("request_id", "r1")).DeprecationWarn("client.GetUserV1", "v3.0.0")
+	logger.DeprecationWarn("client.ListV1", "2025-01-01")
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected one entry per feature, got %d: %v", len(entries), entries)
+	}
+	first := entries[0]
+	if first["level"] != "WARN" || first[logx.EventKey] != logx.EventDeprecation ||
+		first["feature"] != "client.GetUserV1" || first["removal"] != "v3.0.0" {
+		t.Errorf("Unexpected deprecation entry: %v", first)
+	}
+}
+
+func TestFeatureFlagReportsChanges(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	for _, enabled := range []bool{true, true, false, false, true} {
+		logger.FeatureFlag("new-checkout", enabled)
+	}
+
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected the initial state and two changes, got %d: %v", len(entries), entries)
+	}
+	for i, want := range []bool{true, false, true} {
+		if entries[i][logx.EventKey] != logx.EventFeatureFlag || entries[i]["flag"] != "new-checkout" || entries[i]["enabled"] != want {
+			t.Errorf("Unexpected feature flag entry %d: %v", i, entries[i])
+		}
+	}
+}
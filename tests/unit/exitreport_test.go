@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// exitSink is a sink writer reporting dropped entries, whose Sync blocks
+// when hang is set.
+type exitSink struct {
+	hang bool
+}
+
+func (s *exitSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *exitSink) Dropped() uint64 { return 3 }
+
+func (s *exitSink) Sync() error {
+	if s.hang {
+		select {}
+	}
+	return nil
+}
+
+// TestExitReportHelperProcess logs two errors and a Fatal entry in a child
+// process started by runExitReportHelper.
+func TestExitReportHelperProcess(t *testing.T) {
+	dir := os.Getenv("LOGX_EXIT_HELPER_DIR")
+	if dir == "" {
+		return
+	}
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "app.log")
+	config.History = logx.HistoryConfig{MaxEntries: 100}
+	config.Sinks = []logx.SinkConfig{{Name: "remote", Writer: &exitSink{hang: os.Getenv("LOGX_EXIT_HELPER_HANG") != ""}}}
+	config.ExitReport = &logx.ExitReportConfig{
+		SnapshotPath: filepath.Join(dir, "snapshot.log"),
+		FlushTimeout: 200 * time.Millisecond,
+	}
+	logger, err := logx.New(config)
+	if err != nil {
+		os.Exit(2)
+	}
+	logger.Info("Starting")
+	logger.Error("Upstream unavailable")
+	logger.Error("Upstream unavailable")
+	logger.Fatal("Giving up")
+}
+
+func runExitReportHelper(t *testing.T, hang bool) (string, time.Duration) {
+	t.Helper()
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExitReportHelperProcess$")
+	cmd.Env = append(os.Environ(), "LOGX_EXIT_HELPER_DIR="+dir)
+	if hang {
+		cmd.Env = append(cmd.Env, "LOGX_EXIT_HELPER_HANG=1")
+	}
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit code 1, got %v", err)
+	}
+	return dir, elapsed
+}
+
+func TestExitReport(t *testing.T) {
+	dir, _ := runExitReportHelper(t, false)
+
+	entries := readJSONFile(t, filepath.Join(dir, "app.log"))
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[3]["message"] != "Giving up" {
+		t.Errorf("Expected the Fatal entry before the report, got %v", entries[3])
+	}
+	report := entries[4]
+	if report["message"] != logx.ExitReportMessage || report["level"] != "FATAL" {
+		t.Fatalf("Expected the exit report last, got %v", report)
+	}
+	if report["error_count"] != float64(3) || report["recent_error_count"] != float64(3) {
+		t.Errorf("Expected 3 errors counting the Fatal entry, got %v", report)
+	}
+	if report["error_window_ms"] != float64(5*time.Minute/time.Millisecond) {
+		t.Errorf("Expected the default error window, got %v", report["error_window_ms"])
+	}
+	if report["dropped_entries"] != float64(3) {
+		t.Errorf("Expected the entries dropped by the sink, got %v", report["dropped_entries"])
+	}
+	if _, ok := report["uptime_ms"].(float64); !ok {
+		t.Errorf("Expected uptime_ms, got %v", report)
+	}
+	snapshotPath := filepath.Join(dir, "snapshot.log")
+	if report["snapshot_path"] != snapshotPath {
+		t.Errorf("Expected snapshot_path %q, got %v", snapshotPath, report["snapshot_path"])
+	}
+
+	snapshot := readJSONFile(t, snapshotPath)
+	if len(snapshot) != 4 || snapshot[3]["message"] != "Giving up" {
+		t.Errorf("Expected the 4 entries written before exiting in the snapshot, got %v", snapshot)
+	}
+}
+
+func TestExitReportFlushTimeout(t *testing.T) {
+	dir, elapsed := runExitReportHelper(t, true)
+	if elapsed > 10*time.Second {
+		t.Errorf("Expected the flush timeout to bound the exit, took %v", elapsed)
+	}
+	entries := readJSONFile(t, filepath.Join(dir, "app.log"))
+	if len(entries) == 0 || entries[len(entries)-1]["message"] != logx.ExitReportMessage {
+		t.Errorf("Expected the exit report despite the hung sink, got %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// flakyWriter fails every write while down.
+type flakyWriter struct {
+	mu    sync.Mutex
+	down  bool
+	calls int
+	buf   bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+	if w.down {
+		return 0, errors.New("connection refused")
+	}
+	return w.buf.Write(p)
+}
+
+func TestFailoverWriter(t *testing.T) {
+	primary := &flakyWriter{down: true}
+	var fallback bytes.Buffer
+	var events []logx.FailoverEvent
+	output := logx.NewFailoverWriter(logx.FailoverConfig{
+		Writers:       []io.Writer{primary, &fallback},
+		RetryInterval: 50 * time.Millisecond,
+		OnFailover:    func(event logx.FailoverEvent) { events = append(events, event) },
+	})
+	config := logx.DefaultConfig()
+	config.OutputWriter = output
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("First")
+	logger.Info("Second")
+	if primary.calls != 1 || strings.Count(fallback.String(), "\n") != 2 {
+		t.Errorf("Expected the primary to be tried once and skipped after, got %d calls and %q", primary.calls, fallback.String())
+	}
+	if len(events) != 1 || events[0].Writer != 0 || events[0].Err == nil {
+		t.Errorf("Unexpected failover events: %v", events)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	primary.mu.Lock()
+	primary.down = false
+	primary.mu.Unlock()
+	logger.Info("Third")
+	if !strings.Contains(primary.buf.String(), "Third") || strings.Contains(fallback.String(), "Third") {
+		t.Errorf("Expected the primary to be used again once it recovers")
+	}
+	stats := output.Stats()
+	if stats.Writes[0] != 1 || stats.Failures[0] != 1 || stats.Writes[1] != 2 || stats.Failures[1] != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	broken := logx.NewFailoverWriter(logx.FailoverConfig{Writers: []io.Writer{&flakyWriter{down: true}, &flakyWriter{down: true}}})
+	if _, err := broken.Write([]byte("entry\n")); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected the errors of every writer, got %v", err)
+	}
+}
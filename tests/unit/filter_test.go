@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestFilterRules(t *testing.T) {
+	filter, err := logx.NewFilter(
+		logx.FilterRule{
+			Fields: map[string]string{"path": "/healthz"},
+		},
+		logx.FilterRule{
+			Levels:      []logx.Level{logx.WarnLevel},
+			Message:     "^cache miss",
+			Action:      logx.FilterDowngrade,
+			DowngradeTo: logx.DebugLevel,
+		},
+		logx.FilterRule{
+			Logger: "noisy.*",
+			Action: logx.FilterDrop,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create filter: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{filter}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("request", logx.String("path", "/healthz"))
+	logger.Info("request", logx.String("path", "/users"))
+	logger.Warn("cache miss for key")
+	logger.Named("noisy").Named("client").Info("chatter")
+	logger.Named("quiet").Info("kept")
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0]["path"] != "/users" {
+		t.Errorf("Expected /users entry first, got %v", entries[0])
+	}
+	if entries[1]["logger"] != "quiet" {
+		t.Errorf("Expected entry from quiet logger, got %v", entries[1])
+	}
+}
+
+func TestFilterDowngradeKeepsEnabledLevel(t *testing.T) {
+	filter, err := logx.NewFilter(logx.FilterRule{
+		Message:     "downgrade me",
+		Action:      logx.FilterDowngrade,
+		DowngradeTo: logx.WarnLevel,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create filter: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{filter}
+	logger, read := newCaptureLogger(t, config)
+	logger.Error("downgrade me")
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["level"] != "WARN" {
+		t.Fatalf("Expected a single WARN entry, got %v", entries)
+	}
+}
+
+func TestNewFilterInvalidRules(t *testing.T) {
+	if _, err := logx.NewFilter(logx.FilterRule{Message: "("}); err == nil {
+		t.Error("Expected error for invalid message pattern")
+	}
+	if _, err := logx.NewFilter(logx.FilterRule{Action: "explode"}); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+func TestLoadFilterRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	data := `[
+		{"fields": {"path": "/healthz"}},
+		{"levels": ["warn"], "message": "^cache miss", "action": "downgrade", "downgrade_to": "debug"}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write rules: %v", err)
+	}
+
+	rules, err := logx.LoadFilterRules(path)
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[1].Levels[0] != logx.WarnLevel || rules[1].DowngradeTo != logx.DebugLevel {
+		t.Errorf("Level names not decoded: %+v", rules[1])
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"levels": ["loud"]}]`), 0644); err != nil {
+		t.Fatalf("Failed to write rules: %v", err)
+	}
+	if _, err := logx.LoadFilterRules(path); err == nil || !strings.Contains(err.Error(), "loud") {
+		t.Errorf("Expected unknown level error, got %v", err)
+	}
+}
+
+func TestCallerPointsAtCallSite(t *testing.T) {
+	config := logx.DefaultConfig()
+	logger, read := newCaptureLogger(t, config)
+	logger.Info("where am I")
+	logger.Infof("where am %s", "I")
+
+	for _, entry := range read() {
+		caller, _ := entry["caller"].(string)
+		if !strings.HasPrefix(caller, "unit/filter_test.go") {
+			t.Errorf("Expected caller in filter_test.go, got %q", caller)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func logQueryFailure(logger *logx.Logger, id int) {
+	logger.Error("Query failed", logx.ErrorField(fmt.Errorf("query %d: %w", id, os.ErrDeadlineExceeded)))
+}
+
+func logOtherFailure(logger *logx.Logger, id int) {
+	logger.Error("Query failed", logx.ErrorField(fmt.Errorf("query %d: %w", id, os.ErrDeadlineExceeded)))
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.ErrorFingerprint = true
+	logger, read := newCaptureLogger(t, config)
+
+	logQueryFailure(logger, 17)
+	logQueryFailure(logger, 4242)
+	logOtherFailure(logger, 17)
+	logger.Error("Query failed", logx.ErrorField(fmt.Errorf("query %d: %w", 17, os.ErrClosed)))
+	logger.Info("Query succeeded")
+
+	entries := read()
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+	first, _ := entries[0]["error_fingerprint"].(string)
+	if len(first) != 16 {
+		t.Fatalf("Expected 16 character fingerprint, got %q", first)
+	}
+	if entries[1]["error_fingerprint"] != first {
+		t.Errorf("Expected same fingerprint for same failure with different IDs, got %v and %v", first, entries[1]["error_fingerprint"])
+	}
+	if entries[2]["error_fingerprint"] == first {
+		t.Error("Expected different fingerprint for a different call site")
+	}
+	if entries[3]["error_fingerprint"] == first {
+		t.Error("Expected different fingerprint for a different error")
+	}
+	if _, ok := entries[4]["error_fingerprint"]; ok {
+		t.Error("Expected no fingerprint on Info entries")
+	}
+}
@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestMemFileSystemOutputs(t *testing.T) {
+	memfs := logx.NewMemFileSystem()
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{logx.DefaultSinkName, "audit"}})
+	config := logx.DefaultConfig()
+	config.OutputPath = "app.log"
+	config.IndexInterval = 1
+	config.FileSystem = memfs
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "audit", OutputPath: "audit.log"}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Info("in memory")
+	logger.Sync()
+
+	for _, name := range []string{"app.log", "audit.log"} {
+		data, err := memfs.ReadFile(name)
+		if err != nil {
+			t.Fatalf("Expected %s in memory: %v", name, err)
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal(bytes.TrimSpace(data), &entry); err != nil || entry["message"] != "in memory" {
+			t.Errorf("Unexpected %s content %q", name, data)
+		}
+	}
+	if index, err := memfs.ReadFile("app.log" + logx.IndexSuffix); err != nil || len(index) != 8+16 {
+		t.Errorf("Expected the sidecar index in memory, got %d bytes (%v)", len(index), err)
+	}
+	if _, err := os.Stat(filepath.Join(".", "app.log")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written to disk")
+	}
+}
@@ -0,0 +1,169 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// newGELFLogger returns a logger writing every entry to sink only.
+func newGELFLogger(t *testing.T, sink *logx.GELFSink) *logx.Logger {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"graylog"}})
+	config := logx.DefaultConfig()
+	config.OutputPath = t.TempDir() + "/app.log"
+	config.AddCaller = false
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "graylog", Writer: sink}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestGELFMessages(t *testing.T) {
+	var buf bytes.Buffer
+	sink, err := logx.NewGELFSink(logx.GELFConfig{Writer: &buf, Host: "web-1"})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	logger := newGELFLogger(t, sink)
+
+	logger.Named("checkout").Error("Charge failed",
+		logx.ErrorField(errors.New("declined")),
+		logx.Int("attempt", 2),
+		logx.Bool("retry", true),
+		logx.Any("cart", map[string]int{"items": 3}),
+		logx.String("id", "c-1"),
+		logx.String("user name", "bob"))
+
+	var message map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &message); err != nil {
+		t.Fatalf("Expected one GELF message per line, got %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"version": "1.1", "host": "web-1", "short_message": "Charge failed", "level": float64(3),
+		"_logger": "checkout", "_error": "declined", "_attempt": float64(2), "_retry": "true",
+		"_cart": `{"items":3}`, "_id_": "c-1", "_user_name": "bob",
+	}
+	for key, value := range want {
+		if message[key] != value {
+			t.Errorf("%s = %v, want %v", key, message[key], value)
+		}
+	}
+	if ts, ok := message["timestamp"].(float64); !ok || time.Since(time.Unix(int64(ts), 0)) > time.Minute {
+		t.Errorf("Expected a Unix timestamp, got %v", message["timestamp"])
+	}
+	if full, _ := message["full_message"].(string); !strings.Contains(full, "TestGELFMessages") {
+		t.Errorf("Expected the stacktrace as full message, got %q", full)
+	}
+}
+
+func TestGELFSinkUDPChunks(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+	sink, err := logx.NewGELFSink(logx.GELFConfig{Address: conn.LocalAddr().String(), ChunkSize: 64, Compress: true})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newGELFLogger(t, sink)
+
+	payload := strings.Repeat("0123456789abcdef", 40)
+	logger.Info("Large", logx.String("payload", payload))
+
+	chunks := make(map[byte][]byte)
+	var id []byte
+	count := -1
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for count < 0 || len(chunks) < count {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		chunk := buf[:n]
+		if n > 64 || chunk[0] != 0x1e || chunk[1] != 0x0f {
+			t.Fatalf("Expected chunks of at most 64 bytes, got %d bytes", n)
+		}
+		if id == nil {
+			id = append([]byte(nil), chunk[2:10]...)
+		} else if !bytes.Equal(id, chunk[2:10]) {
+			t.Fatalf("Expected one message ID across chunks")
+		}
+		count = int(chunk[11])
+		chunks[chunk[10]] = append([]byte(nil), chunk[12:]...)
+	}
+	var compressed []byte
+	for i := 0; i < count; i++ {
+		compressed = append(compressed, chunks[byte(i)]...)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("Expected a gzipped message: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	var message map[string]interface{}
+	if err := json.Unmarshal(data, &message); err != nil || message["_payload"] != payload {
+		t.Errorf("Expected the reassembled message, got %s (%v)", data, err)
+	}
+}
+
+func TestGELFSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			message, err := reader.ReadString(0)
+			if err != nil {
+				return
+			}
+			received <- strings.TrimSuffix(message, "\x00")
+		}
+	}()
+
+	sink, err := logx.NewGELFSink(logx.GELFConfig{Network: "tcp", Address: listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newGELFLogger(t, sink)
+	logger.Warn("first")
+	logger.Info("second")
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case raw := <-received:
+			var message map[string]interface{}
+			if err := json.Unmarshal([]byte(raw), &message); err != nil || message["short_message"] != want {
+				t.Errorf("Expected %q as a null-terminated message, got %q", want, raw)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %q", want)
+		}
+	}
+}
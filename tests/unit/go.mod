@@ -2,11 +2,12 @@ module tests/unit
 
 go 1.24.5
 
-require github.com/seasbee/go-logx v0.0.0
-
 require (
-	go.uber.org/multierr v1.10.0 // indirect
-	go.uber.org/zap v1.26.0 // indirect
+	github.com/seasbee/go-logx v0.0.0
+	go.uber.org/zap v1.26.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
+require go.uber.org/multierr v1.10.0 // indirect
+
 replace github.com/seasbee/go-logx => ../../
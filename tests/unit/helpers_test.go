@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// newCaptureLogger creates a JSON logger writing to a temporary file and
+// returns it together with a function that reads back the decoded entries.
+func newCaptureLogger(t *testing.T, config *logx.Config) (*logx.Logger, func() []map[string]interface{}) {
+	t.Helper()
+	config.OutputPath = filepath.Join(t.TempDir(), "test.log")
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	read := func() []map[string]interface{} {
+		t.Helper()
+		logger.Sync()
+		return readJSONFile(t, config.OutputPath)
+	}
+	return logger, read
+}
+
+// readJSONFile decodes every line of a JSON log file.
+func readJSONFile(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid JSON log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// decodeLines decodes the JSON entries written to buf, one per line.
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestSnapshot(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 3}
+	logger, _ := newCaptureLogger(t, config)
+
+	child := logger.Named("db")
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		child.Info(msg, logx.String("password", "secret"))
+	}
+	logger.Debug("not written")
+
+	entries := logger.Snapshot(time.Minute)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "two" || entries[2].Message != "four" {
+		t.Errorf("Expected oldest first, got %q .. %q", entries[0].Message, entries[2].Message)
+	}
+	if entries[0].LoggerName != "db" {
+		t.Errorf("LoggerName = %q, want db", entries[0].LoggerName)
+	}
+	if v := entries[0].Fields[0].Value; v == "secret" {
+		t.Error("Expected sensitive field to be masked")
+	}
+}
+
+func TestSnapshotWindow(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 10}
+	logger, _ := newCaptureLogger(t, config)
+
+	logger.Info("old")
+	time.Sleep(50 * time.Millisecond)
+	logger.Info("recent")
+
+	entries := logger.Snapshot(25 * time.Millisecond)
+	if len(entries) != 1 || entries[0].Message != "recent" {
+		t.Errorf("Expected only the recent entry, got %v", entries)
+	}
+	if all := logger.Snapshot(0); len(all) != 2 {
+		t.Errorf("Expected all entries for a zero duration, got %d", len(all))
+	}
+}
+
+func TestWriteSnapshot(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 10, MaxAge: time.Hour}
+	logger, _ := newCaptureLogger(t, config)
+
+	logger.Warn("Disk low", logx.Int("free_mb", 12))
+	logger.Error("Disk full")
+
+	var buf bytes.Buffer
+	if err := logger.WriteSnapshot(&buf, time.Minute); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Failed to decode line: %v", err)
+	}
+	if entry["level"] != "WARN" || entry["message"] != "Disk low" || entry["free_mb"] != float64(12) {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+}
+
+func TestWriteSnapshotRenamedKeys(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 10}
+	config.Keys = logx.EncoderKeys{Timestamp: "ts", Level: "lvl", Message: "msg"}
+	config.TimeLayout = logx.TimeLayoutEpochMillis
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Named("disk").Warn("Disk low")
+
+	var buf bytes.Buffer
+	if err := logger.WriteSnapshot(&buf, 0); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode snapshot: %v", err)
+	}
+	written := read()[0]
+	for _, key := range []string{"ts", "lvl", "msg", "logger"} {
+		if entry[key] == nil || entry[key] != written[key] {
+			t.Errorf("Expected %s to match the output, got %v and %v", key, entry[key], written[key])
+		}
+	}
+	for _, key := range []string{"timestamp", "level", "message"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Expected no %s key in the snapshot, got %v", key, entry)
+		}
+	}
+}
+
+func TestSnapshotDisabled(t *testing.T) {
+	logger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	logger.Info("message")
+	if entries := logger.Snapshot(time.Minute); entries != nil {
+		t.Errorf("Expected nil without history, got %v", entries)
+	}
+}
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+	"github.com/seasbee/go-logx/reader"
+)
+
+func TestSidecarIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	config.IndexInterval = 10
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		logger.Info("early", logx.Int("n", i))
+	}
+	time.Sleep(20 * time.Millisecond)
+	mark := time.Now()
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < 50; i++ {
+		logger.Info("late", logx.Int("n", i))
+	}
+	logger.Sync()
+
+	records, err := logx.ReadIndex(path)
+	if err != nil {
+		t.Fatalf("ReadIndex failed: %v", err)
+	}
+	if len(records) != 10 {
+		t.Fatalf("Expected 10 index records, got %d", len(records))
+	}
+	if records[0].Offset != 0 || records[1].Offset <= 0 {
+		t.Errorf("Unexpected offsets %v", records[:2])
+	}
+
+	offset, err := logx.IndexOffset(path, mark)
+	if err != nil {
+		t.Fatalf("IndexOffset failed: %v", err)
+	}
+	if offset != records[4].Offset {
+		t.Errorf("Expected offset of the last record before the mark %d, got %d", records[4].Offset, offset)
+	}
+
+	r, err := reader.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+	entries, err := r.SeekTime(mark).All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(entries) != 50 || entries[0].Message != "late" {
+		t.Errorf("Expected the 50 late entries, got %d starting with %q", len(entries), entries[0].Message)
+	}
+}
+
+func TestIndexOffsetWithoutIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("{}\n"), 0644)
+	if offset, err := logx.IndexOffset(path, time.Now()); err != nil || offset != 0 {
+		t.Errorf("IndexOffset = %d, %v; want 0, nil", offset, err)
+	}
+}
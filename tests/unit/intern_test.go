@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestInternedString(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.InternKeys = []string{"route"}
+	logger, read := newCaptureLogger(t, config)
+
+	route := []byte("/users/{id}")
+	logger.Info("Request served", logger.InternedString("route", route))
+
+	// Storing the string in Field.Value costs one allocation either way;
+	// interning saves the conversion.
+	interned := testing.AllocsPerRun(100, func() {
+		logger.InternedString("route", route)
+	})
+	converted := testing.AllocsPerRun(100, func() {
+		logger.InternedString("other", route)
+	})
+	if interned >= converted {
+		t.Errorf("Expected fewer allocations for an interned value, got %v vs %v", interned, converted)
+	}
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["route"] != "/users/{id}" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+}
+
+func TestInternMaxValues(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.InternKeys = []string{"id"}
+	config.InternMaxValues = 1
+	logger, _ := newCaptureLogger(t, config)
+
+	first := []byte("first value")
+	second := []byte("second value")
+	logger.InternedString("id", first)
+	logger.InternedString("id", second)
+
+	interned := testing.AllocsPerRun(100, func() {
+		logger.InternedString("id", first)
+	})
+	rejected := testing.AllocsPerRun(100, func() {
+		logger.InternedString("id", second)
+	})
+	if interned >= rejected {
+		t.Errorf("Expected only the first value to be interned, got %v vs %v allocations", interned, rejected)
+	}
+}
+
+func TestInternFieldsWritten(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.InternKeys = []string{"service"}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.With(logx.String("service", "billing")).Info("one", logx.String("service", "api"))
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["service"] != "api" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+}
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestKeyPolicy(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.KeyPolicy = &logx.KeyPolicy{}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("checkout",
+		logx.String("message", "user supplied"),
+		logx.String("http.method", "GET"),
+		logx.String("user name", "ada"),
+		logx.String("order_id", "o-1"),
+	)
+
+	entry := read()[0]
+	want := map[string]interface{}{
+		"message":       "checkout",
+		"field_message": "user supplied",
+		"http_method":   "GET",
+		"user_name":     "ada",
+		"order_id":      "o-1",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry)
+		}
+	}
+	if logger.FixedKeys() != 3 {
+		t.Errorf("Expected 3 fixed keys, got %d", logger.FixedKeys())
+	}
+}
+
+func TestKeyPolicyAllowDots(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.KeyPolicy = &logx.KeyPolicy{AllowDots: true, Reserved: []string{"level", "tenant"}, ReservedPrefix: "x_"}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.With(logx.Tenant("acme")).Info("ok", logx.String("http.method", "GET"))
+
+	entry := read()[0]
+	if entry["http.method"] != "GET" || entry["x_tenant"] != "acme" {
+		t.Errorf("Unexpected keys: %v", entry)
+	}
+}
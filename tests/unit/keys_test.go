@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStandardFieldBuilders(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	logger.With(logx.RequestID("req-1"), logx.UserID("42")).Info("Request completed",
+		logx.HTTPRequest("GET", "/orders", 200, 1500*time.Millisecond)...)
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	expected := map[string]interface{}{
+		logx.KeyRequestID:  "req-1",
+		logx.KeyUserID:     "42",
+		logx.KeyMethod:     "GET",
+		logx.KeyPath:       "/orders",
+		logx.KeyStatusCode: float64(200),
+		logx.KeyDurationMS: float64(1500),
+	}
+	for key, want := range expected {
+		if got := entries[0][key]; got != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestWithPprofLabels(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.PprofLabels = []string{"worker", "tenant"}
+	logger, read := newCaptureLogger(t, config)
+
+	pprof.Do(context.Background(), pprof.Labels("worker", "billing", "tenant", "acme", "shard", "3"), func(ctx context.Context) {
+		logger.WithPprofLabels(ctx).Info("Batch started")
+	})
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0]["worker"] != "billing" || entries[0]["tenant"] != "acme" {
+		t.Errorf("Expected selected labels, got %v", entries[0])
+	}
+	if _, ok := entries[0]["shard"]; ok {
+		t.Error("Expected unselected label to be omitted")
+	}
+}
+
+func TestWithPprofLabelsAll(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("worker", "billing", "shard", "3"))
+	logger.WithPprofLabels(ctx).Info("Batch started")
+	logger.WithPprofLabels(context.Background()).Info("Unlabeled")
+
+	entries := read()
+	if entries[0]["worker"] != "billing" || entries[0]["shard"] != "3" {
+		t.Errorf("Expected all labels, got %v", entries[0])
+	}
+	if _, ok := entries[1]["worker"]; ok {
+		t.Errorf("Expected no labels, got %v", entries[1])
+	}
+}
@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetLevelPropagatesToDerivedLoggers(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	child := logger.With(logx.String("request_id", "r1"))
+	named := logger.Named("db").With(logx.String("table", "users"))
+
+	child.Debug("before")
+	logger.SetLevel(logx.DebugLevel)
+	child.Debug("child debug")
+	named.Debug("named debug")
+
+	if got := child.GetLevel(); got != logx.DebugLevel {
+		t.Errorf("child.GetLevel() = %v, want DEBUG", got)
+	}
+
+	logger.SetLevel(logx.ErrorLevel)
+	named.Warn("suppressed")
+	named.Error("named error")
+
+	entries := read()
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e["message"].(string))
+	}
+	want := []string{"child debug", "named debug", "named error"}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected messages %v, got %v", want, messages)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("message %d = %q, want %q", i, messages[i], want[i])
+		}
+	}
+}
+
+func TestSetLevelAppliesToSinks(t *testing.T) {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"audit"}})
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{router}
+	auditPath := t.TempDir() + "/audit.log"
+	config.Sinks = []logx.SinkConfig{{Name: "audit", OutputPath: auditPath}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Debug("hidden")
+	logger.SetLevel(logx.DebugLevel)
+	logger.Debug("visible")
+	logger.Sync()
+
+	entries := readJSONFile(t, auditPath)
+	if len(entries) != 1 || entries[0]["message"] != "visible" {
+		t.Errorf("Expected only the entry logged after SetLevel, got %v", entries)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]logx.Level{
+		"trace":   logx.TraceLevel,
+		"DEBUG":   logx.DebugLevel,
+		" Info ":  logx.InfoLevel,
+		"warning": logx.WarnLevel,
+		"error":   logx.ErrorLevel,
+		"FATAL":   logx.FatalLevel,
+	}
+	for name, want := range cases {
+		got, err := logx.ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := logx.ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level name")
+	}
+}
+
+func TestLevelTextMarshaling(t *testing.T) {
+	var settings struct {
+		Level logx.Level `json:"level" yaml:"level"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"warn"}`), &settings); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if settings.Level != logx.WarnLevel {
+		t.Errorf("JSON level = %v, want WARN", settings.Level)
+	}
+	if err := yaml.Unmarshal([]byte("level: debug\n"), &settings); err != nil {
+		t.Fatalf("Failed to unmarshal YAML: %v", err)
+	}
+	if settings.Level != logx.DebugLevel {
+		t.Errorf("YAML level = %v, want DEBUG", settings.Level)
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+	if string(data) != `{"level":"DEBUG"}` {
+		t.Errorf("Marshaled level = %s, want {\"level\":\"DEBUG\"}", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level":"loud"}`), &settings); err == nil {
+		t.Error("Expected an error for an unknown level name")
+	}
+	if _, err := logx.Level(42).MarshalText(); err == nil {
+		t.Error("Expected an error marshaling an out-of-range level")
+	}
+}
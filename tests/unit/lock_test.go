@@ -0,0 +1,193 @@
+package unit
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// TestFileLockHelperProcess holds a logger open in a separate process for
+// the file locking tests. It does nothing when run directly.
+func TestFileLockHelperProcess(t *testing.T) {
+	path := os.Getenv("LOGX_LOCK_HELPER_PATH")
+	if path == "" {
+		return
+	}
+	mode, _ := strconv.Atoi(os.Getenv("LOGX_LOCK_HELPER_MODE"))
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	config.FileLock = logx.FileLockMode(mode)
+	logger, err := logx.New(config)
+	if err != nil {
+		os.Stdout.WriteString("error: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+	os.Stdout.WriteString("ready\n")
+	// Wait until the parent closes stdin
+	bufio.NewReader(os.Stdin).ReadString('\n')
+	logger.Info("from helper")
+	logger.Sync()
+	os.Exit(0)
+}
+
+// startLockHelper starts a process holding a logger on path with mode and
+// returns a function that stops it.
+func startLockHelper(t *testing.T, path string, mode logx.FileLockMode) func() {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFileLockHelperProcess$")
+	cmd.Env = append(os.Environ(), "LOGX_LOCK_HELPER_PATH="+path, "LOGX_LOCK_HELPER_MODE="+strconv.Itoa(int(mode)))
+	stdin, _ := cmd.StdinPipe()
+	stdout, _ := cmd.StdoutPipe()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start helper: %v", err)
+	}
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	if line != "ready\n" {
+		cmd.Process.Kill()
+		t.Fatalf("Helper failed: %q", line)
+	}
+	return func() {
+		stdin.Close()
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("Helper exited with %v", err)
+		}
+	}
+}
+
+// lockHelperClaims reports whether a separate process can create a logger
+// on path with mode.
+func lockHelperClaims(t *testing.T, path string, mode logx.FileLockMode) bool {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFileLockHelperProcess$")
+	cmd.Env = append(os.Environ(), "LOGX_LOCK_HELPER_PATH="+path, "LOGX_LOCK_HELPER_MODE="+strconv.Itoa(int(mode)))
+	cmd.Stdin = strings.NewReader("\n")
+	output, _ := cmd.Output()
+	return strings.HasPrefix(string(output), "ready\n")
+}
+
+func TestFileLockDetectsSharing(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process-level locking tested on linux and darwin")
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	stop := startLockHelper(t, path, logx.FileLockExclusive)
+	defer stop()
+
+	for _, mode := range []logx.FileLockMode{logx.FileLockExclusive, logx.FileLockShared} {
+		config := logx.DefaultConfig()
+		config.OutputPath = path
+		config.FileLock = mode
+		if _, err := logx.New(config); err == nil {
+			t.Errorf("Expected mode %d to fail while another process claims the file", mode)
+		}
+	}
+}
+
+func TestFileLockShared(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process-level locking tested on linux and darwin")
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	stop := startLockHelper(t, path, logx.FileLockShared)
+
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	config.FileLock = logx.FileLockExclusive
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an exclusive claim to fail while another process shares the file")
+	}
+
+	config.FileLock = logx.FileLockShared
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Expected sharing to succeed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		logger.Info("from parent", logx.Int("n", i))
+	}
+	logger.Sync()
+	stop()
+
+	entries := readJSONFile(t, path)
+	if len(entries) != 101 {
+		t.Errorf("Expected 101 intact entries from both processes, got %d", len(entries))
+	}
+}
+
+func TestFileLockConfigValidation(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(t.TempDir(), "app.log")
+	config.FileLock = logx.FileLockShared
+	config.IndexInterval = 10
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected shared locking with a sidecar index to be rejected")
+	}
+
+	config = logx.DefaultConfig()
+	config.OutputPath = "app.log"
+	config.FileSystem = logx.NewMemFileSystem()
+	config.FileLock = logx.FileLockExclusive
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected locking on a filesystem without descriptors to be rejected")
+	}
+}
+
+func TestFileLockSurvivesReload(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process-level locking tested on linux and darwin")
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	config.FileLock = logx.FileLockExclusive
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if lockHelperClaims(t, path, logx.FileLockExclusive) {
+		t.Fatal("Expected another process to fail to claim the file")
+	}
+
+	if err := logger.Reload(config); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if lockHelperClaims(t, path, logx.FileLockExclusive) {
+		t.Error("Expected the claim to survive reloading onto the same file")
+	}
+
+	failing := *config
+	failing.Sinks = []logx.SinkConfig{{Name: "audit", OutputPath: filepath.Join(t.TempDir(), "audit.log")}}
+	if err := logger.Reload(&failing); err == nil {
+		t.Fatal("Expected adding a sink to fail")
+	}
+	if lockHelperClaims(t, path, logx.FileLockExclusive) {
+		t.Error("Expected the claim to survive a failed reload")
+	}
+}
+
+func TestFileLockReleasedByClose(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process-level locking tested on linux and darwin")
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	config.FileLock = logx.FileLockExclusive
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if !lockHelperClaims(t, path, logx.FileLockExclusive) {
+		t.Error("Expected another process to claim the file after Close")
+	}
+}
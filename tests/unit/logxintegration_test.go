@@ -0,0 +1,67 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+	"github.com/seasbee/go-logx/logxintegration"
+)
+
+func TestHTTPBackendDelivery(t *testing.T) {
+	backend := logxintegration.NewHTTPBackend(t)
+	backend.Fail(1, http.StatusServiceUnavailable)
+
+	sink, err := logx.NewWebhookSink(logx.WebhookConfig{
+		URL:           backend.Endpoint(),
+		Compress:      true,
+		FlushInterval: 10 * time.Millisecond,
+		Backoff:       time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newWebhookLogger(t, sink)
+
+	logger.Info("Order placed", logx.String("order_id", "o-1"), logx.Int("items", 3))
+	logger.Warn("Stock low", logx.String("sku", "s-9"))
+
+	logxintegration.AssertDelivered(t, backend, 5*time.Second,
+		map[string]interface{}{"message": "Order placed", "order_id": "o-1", "items": 3},
+		map[string]interface{}{"level": "WARN", "sku": "s-9"},
+	)
+	entries := logxintegration.WaitForEntries(t, backend, 2, time.Second)
+	if len(entries) != 2 {
+		t.Errorf("Expected each entry once, got %v", entries)
+	}
+	if headers := backend.Headers(); len(headers) < 2 || headers[1].Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected a rejected request and a gzipped retry, got %v", headers)
+	}
+}
+
+func TestLokiBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping container backend in short mode")
+	}
+	backend := logxintegration.StartLoki(t, logxintegration.ContainerOptions{})
+
+	line, _ := json.Marshal(map[string]interface{}{"message": "Order placed", "order_id": "o-1"})
+	push, _ := json.Marshal(map[string]interface{}{"streams": []interface{}{map[string]interface{}{
+		"stream": map[string]string{"job": "checkout"},
+		"values": [][]string{{strconv.FormatInt(time.Now().UnixNano(), 10), string(line)}},
+	}}})
+	resp, err := http.Post(backend.PushURL(), "application/json", bytes.NewReader(push))
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	resp.Body.Close()
+
+	logxintegration.AssertDelivered(t, backend, 30*time.Second, map[string]interface{}{"order_id": "o-1"})
+}
@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestManagerQuotas(t *testing.T) {
+	dir := t.TempDir()
+	manager := logx.NewManager(logx.ManagerConfig{
+		TenantConfig: func(tenant string) *logx.Config {
+			config := logx.DefaultConfig()
+			config.OutputPath = filepath.Join(dir, tenant+".log")
+			return config
+		},
+		Quota: logx.TenantQuota{MaxEntries: 3, Window: time.Hour},
+	})
+
+	acme, err := manager.Logger("acme")
+	if err != nil {
+		t.Fatalf("Failed to create tenant logger: %v", err)
+	}
+	again, _ := manager.Logger("acme")
+	if acme != again {
+		t.Error("Expected tenant logger to be cached")
+	}
+	globex, _ := manager.Logger("globex")
+
+	for i := 0; i < 10; i++ {
+		acme.Info("busy tenant", logx.Int("i", i))
+	}
+	acme.Error("errors are always written")
+	globex.Info("quiet tenant")
+	manager.Sync()
+
+	usage, ok := manager.Usage("acme")
+	if !ok {
+		t.Fatal("Expected usage for acme")
+	}
+	if usage.Entries != 4 || usage.DroppedEntries != 7 || usage.Bytes <= 0 {
+		t.Errorf("Unexpected acme usage: %+v", usage)
+	}
+	if usage, _ := manager.Usage("globex"); usage.Entries != 1 || usage.DroppedEntries != 0 {
+		t.Errorf("Quota of one tenant should not affect another: %+v", usage)
+	}
+	if _, ok := manager.Usage("initech"); ok {
+		t.Error("Expected no usage for unknown tenant")
+	}
+	if len(manager.Tenants()) != 2 {
+		t.Errorf("Expected 2 tenants, got %v", manager.Tenants())
+	}
+
+	entries := readJSONFile(t, filepath.Join(dir, "acme.log"))
+	// 3 info entries, the quota notice and the error
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries for acme, got %d: %v", len(entries), entries)
+	}
+	if entries[3]["message"] != "Tenant log quota exceeded" || entries[3]["tenant"] != "acme" {
+		t.Errorf("Expected quota notice, got %v", entries[3])
+	}
+	for _, entry := range entries {
+		if entry["tenant"] != "acme" {
+			t.Errorf("Expected tenant field on every entry, got %v", entry)
+		}
+	}
+}
+
+func TestManagerByteQuota(t *testing.T) {
+	manager := logx.NewManager(logx.ManagerConfig{
+		Base:  &logx.Config{Level: logx.InfoLevel, OutputPath: filepath.Join(t.TempDir(), "all.log")},
+		Quota: logx.TenantQuota{MaxBytes: 1024},
+	})
+	logger, err := manager.Logger("acme")
+	if err != nil {
+		t.Fatalf("Failed to create tenant logger: %v", err)
+	}
+	payload := string(make([]byte, 400))
+	for i := 0; i < 5; i++ {
+		logger.Info("payload", logx.String("data", payload))
+	}
+	usage, _ := manager.Usage("acme")
+	if usage.WindowBytes > 1024 || usage.DroppedEntries == 0 {
+		t.Errorf("Expected byte quota to be enforced, got %+v", usage)
+	}
+}
@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type marshalerCredentials struct {
+	User     string
+	Password string
+	PIN      int
+}
+
+func (c marshalerCredentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("user", c.User)
+	enc.AddString("password", c.Password)
+	enc.AddInt("pin", c.PIN)
+	return nil
+}
+
+type marshalerOrder struct {
+	ID    string
+	Items []string
+	Auth  marshalerCredentials
+}
+
+func (o *marshalerOrder) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("order_id", o.ID)
+	enc.AddInt("item_count", len(o.Items))
+	return enc.AddObject("login", o.Auth)
+}
+
+func TestAnyObjectMarshaler(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.AnyBudget = &logx.AnyBudget{MaxBytes: 64}
+	logger, read := newCaptureLogger(t, config)
+	logger.ConfigureComponent("billing", logx.ComponentConfig{SensitiveKeys: []string{"user"}})
+
+	items := make([]string, 50)
+	for i := range items {
+		items[i] = "sku-123456"
+	}
+	order := &marshalerOrder{ID: "o-1", Items: items, Auth: marshalerCredentials{User: "alice", Password: "hunter2secret", PIN: 1234}}
+	logger.Info("Order", logx.Any("order", order))
+	logger.Named("billing").Info("Charge", logx.Any("order", order))
+	logger.Info("Field", logx.Any("login", zap.Object("ignored", order.Auth)), logx.Any("attempts", zap.Int("ignored", 3)))
+	logger.Info("Sensitive", logx.Any("token", order))
+
+	entries := read()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	written, ok := entries[0]["order"].(map[string]interface{})
+	if !ok || written["order_id"] != "o-1" || written["item_count"] != float64(50) {
+		t.Fatalf("Expected the order written by MarshalLogObject in full, got %v", entries[0]["order"])
+	}
+	auth := written["login"].(map[string]interface{})
+	if auth["user"] != "alice" || auth["password"] != "hu***et" || auth["pin"] != "***MASKED***" {
+		t.Errorf("Expected the nested sensitive keys masked, got %v", auth)
+	}
+	auth = entries[1]["order"].(map[string]interface{})["login"].(map[string]interface{})
+	if auth["user"] != "al***ce" {
+		t.Errorf("Expected the component's masked key masked, got %v", auth)
+	}
+	auth, _ = entries[2]["login"].(map[string]interface{})
+	if auth["password"] != "hu***et" || entries[2]["attempts"] != float64(3) || entries[2]["ignored"] != nil {
+		t.Errorf("Expected zap fields written under the logx key, got %v", entries[2])
+	}
+	if entries[3]["token"] != "***MASKED***" {
+		t.Errorf("Expected a marshaler under a sensitive key masked whole, got %v", entries[3]["token"])
+	}
+}
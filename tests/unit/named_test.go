@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestParseNamedLevels(t *testing.T) {
+	levels, err := logx.ParseNamedLevels(" http=debug, db = WARN,,http.client=trace ")
+	if err != nil {
+		t.Fatalf("Failed to parse named levels: %v", err)
+	}
+	want := map[string]logx.Level{"http": logx.DebugLevel, "db": logx.WarnLevel, "http.client": logx.TraceLevel}
+	if len(levels) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, levels)
+	}
+	for name, level := range want {
+		if levels[name] != level {
+			t.Errorf("Expected %s=%v, got %v", name, level, levels[name])
+		}
+	}
+
+	if levels, err := logx.ParseNamedLevels(""); err != nil || len(levels) != 0 {
+		t.Errorf("Expected no levels for an empty spec, got %v, %v", levels, err)
+	}
+	for _, spec := range []string{"http", "=debug", "http=verbose"} {
+		if _, err := logx.ParseNamedLevels(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestConfigNamedLevels(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.NamedLevels = map[string]logx.Level{"http": logx.DebugLevel, "db": logx.WarnLevel}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Named("http").Named("client").Debug("http debug")
+	logger.Named("db").Info("db info")
+	logger.Named("db").Warn("db warn")
+	logger.Named("cache").Debug("cache debug")
+	logger.Named("cache").Info("cache info")
+
+	entries := read()
+	want := []string{"http debug", "db warn", "cache info"}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, entries)
+	}
+	for i, msg := range want {
+		if entries[i]["message"] != msg {
+			t.Errorf("Entry %d: expected %q, got %v", i, msg, entries[i])
+		}
+	}
+}
+
+func TestSetNamedLevels(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	db := logger.Named("db")
+
+	logger.SetNamedLevel("db", logx.DebugLevel)
+	db.Debug("first")
+	logger.SetNamedLevels(map[string]logx.Level{"http": logx.ErrorLevel})
+	db.Debug("hidden")
+	logger.Named("http").Warn("hidden")
+	db.Info("second")
+
+	entries := read()
+	if len(entries) != 2 || entries[0]["message"] != "first" || entries[1]["message"] != "second" {
+		t.Errorf("Expected SetNamedLevels to replace the named levels, got %v", entries)
+	}
+	if got := logger.ComponentLevel("db"); got != logx.InfoLevel {
+		t.Errorf("Expected db to follow the logger's level, got %v", got)
+	}
+}
+
+func TestReloadNamedLevels(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.NamedLevels = map[string]logx.Level{"db": logx.DebugLevel}
+	logger, read := newCaptureLogger(t, config)
+
+	reloaded := *config
+	reloaded.NamedLevels = map[string]logx.Level{"http": logx.DebugLevel}
+	if err := logger.Reload(&reloaded); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	logger.Named("db").Debug("hidden")
+	logger.Named("http").Debug("written")
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["message"] != "written" {
+		t.Errorf("Expected the reloaded named levels, got %v", entries)
+	}
+}
+
+func TestLoadConfigNamedLevels(t *testing.T) {
+	path := writeConfigFile(t, "logging.yaml", `
+output_path: `+filepath.Join(t.TempDir(), "app.log")+`
+named_levels:
+  http: debug
+  db: warn
+`)
+	config, err := logx.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.NamedLevels["http"] != logx.DebugLevel || config.NamedLevels["db"] != logx.WarnLevel {
+		t.Errorf("Expected named levels from the file, got %v", config.NamedLevels)
+	}
+}
+
+func TestPackageNamed(t *testing.T) {
+	logx.InitDefault()
+
+	http := logx.Named("http")
+	if http == nil || http != logx.Named("http") {
+		t.Fatal("Expected Named to return the same logger for a name")
+	}
+	if logx.Named("db") == http {
+		t.Error("Expected different loggers for different names")
+	}
+	logx.SetNamedLevel("http", logx.DebugLevel)
+	defer http.ResetComponent("http")
+	if got := http.ComponentLevel("http"); got != logx.DebugLevel {
+		t.Errorf("Expected the named level, got %v", got)
+	}
+}
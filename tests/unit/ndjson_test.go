@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStrictNDJSONStacktrace(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.StrictNDJSON = true
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Error("Request failed", logx.String("body", "line1\nline2"))
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	frames, ok := entries[0]["stacktrace"].([]interface{})
+	if !ok || len(frames) == 0 {
+		t.Fatalf("Expected stacktrace array, got %v", entries[0]["stacktrace"])
+	}
+	if frame, _ := frames[0].(string); !strings.Contains(frame, "TestStrictNDJSONStacktrace") || !strings.Contains(frame, "ndjson_test.go:") {
+		t.Errorf("Expected first frame to name the test and its location, got %q", frame)
+	}
+	if entries[0]["body"] != "line1\nline2" {
+		t.Errorf("body = %q", entries[0]["body"])
+	}
+}
+
+func TestStrictNDJSONConsole(t *testing.T) {
+	var buf bytes.Buffer
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"console"}})
+	config := logx.DefaultConfig()
+	config.StrictNDJSON = true
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "console", Writer: &buf, Development: true}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Error("first\nsecond", logx.String("input", "a\r\nb"))
+	logger.Info("next")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `first\nsecond`) {
+		t.Errorf("Expected escaped message, got %q", lines[0])
+	}
+	if !strings.Contains(lines[0], `"stacktrace":`) {
+		t.Errorf("Expected inline stacktrace, got %q", lines[0])
+	}
+}
+
+func TestStrictNDJSONDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"json"}})
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "json", Writer: &buf}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Error("failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if _, ok := entry["stacktrace"].(string); !ok {
+		t.Errorf("Expected string stacktrace without StrictNDJSON, got %v", entry["stacktrace"])
+	}
+}
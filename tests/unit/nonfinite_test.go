@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestNonFinitePolicies(t *testing.T) {
+	tests := []struct {
+		policy  logx.NonFinitePolicy
+		ratio   interface{}
+		samples interface{}
+		dropped []interface{}
+	}{
+		{logx.NonFiniteString, "NaN", []interface{}{1.5, "+Inf", "-Inf"}, nil},
+		{logx.NonFiniteNull, nil, []interface{}{1.5, nil, nil}, nil},
+		{logx.NonFiniteDrop, nil, nil, []interface{}{"ratio", "samples"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			config := logx.DefaultConfig()
+			config.NonFinite = tt.policy
+			logger, read := newCaptureLogger(t, config)
+
+			logger.Info("Stats",
+				logx.Float64("ratio", math.NaN()),
+				logx.Any("samples", []float64{1.5, math.Inf(1), math.Inf(-1)}),
+				logx.Any("meta", map[string]interface{}{"mean": 2.0}),
+				logx.Float64("total", 3))
+
+			entries := read()
+			if len(entries) != 1 {
+				t.Fatalf("Expected one valid JSON entry, got %d", len(entries))
+			}
+			entry := entries[0]
+			if entry["total"] != float64(3) || entry["meta"].(map[string]interface{})["mean"] != float64(2) {
+				t.Errorf("Expected finite values unchanged, got %v", entry)
+			}
+			if tt.dropped != nil {
+				if _, ok := entry["ratio"]; ok {
+					t.Errorf("Expected ratio to be dropped, got %v", entry)
+				}
+				got, _ := entry[logx.NonFiniteKey].([]interface{})
+				if len(got) != len(tt.dropped) || got[0] != tt.dropped[0] || got[1] != tt.dropped[1] {
+					t.Errorf("Expected dropped keys %v, got %v", tt.dropped, entry[logx.NonFiniteKey])
+				}
+				return
+			}
+			if ratio, ok := entry["ratio"]; !ok || ratio != tt.ratio {
+				t.Errorf("Expected ratio %v, got %v", tt.ratio, entry["ratio"])
+			}
+			samples, _ := entry["samples"].([]interface{})
+			for i, want := range tt.samples.([]interface{}) {
+				if len(samples) != 3 || samples[i] != want {
+					t.Fatalf("Expected samples %v, got %v", tt.samples, entry["samples"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStrictOrdering(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.StrictOrdering = true
+	config.Async = &logx.AsyncConfig{QueueSize: 10000}
+	logger, read := newCaptureLogger(t, config)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			child := logger.With(logx.Int("goroutine", g))
+			for i := 0; i < 200; i++ {
+				child.Info("event", logx.Int("i", i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	logger.Close()
+
+	entries := read()
+	if len(entries) != 1600 {
+		t.Fatalf("Expected 1600 entries, got %d", len(entries))
+	}
+	var last time.Time
+	for i, entry := range entries {
+		if entry["seq"] != float64(i+1) {
+			t.Fatalf("Entry %d has seq %v, want %d", i, entry["seq"], i+1)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, entry["timestamp"].(string))
+		if err != nil {
+			t.Fatalf("Failed to parse timestamp: %v", err)
+		}
+		if ts.Before(last) {
+			t.Fatalf("Entry %d timestamp %v before previous %v", i, ts, last)
+		}
+		last = ts
+	}
+}
@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+type panicCode int
+
+func TestPanicValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		wantMsg  string
+		wantType string
+	}{
+		{"error", errors.New("boom"), "boom", "*errors.errorString"},
+		{"string", "bad state", "bad state", "string"},
+		{"value", panicCode(7), "7", "unit.panicCode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, read := newCaptureLogger(t, logx.DefaultConfig())
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						logger.Error("Recovered from panic", logx.PanicValue(r))
+					}
+				}()
+				panic(tt.value)
+			}()
+
+			entries := read()
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(entries))
+			}
+			p, ok := entries[0]["panic"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected panic object, got %v", entries[0]["panic"])
+			}
+			if p["message"] != tt.wantMsg {
+				t.Errorf("message = %v, want %q", p["message"], tt.wantMsg)
+			}
+			if p["type"] != tt.wantType {
+				t.Errorf("type = %v, want %q", p["type"], tt.wantType)
+			}
+			if stack, _ := p["stack"].(string); !strings.Contains(stack, "TestPanicValue") {
+				t.Errorf("Expected stack to contain the panicking test, got %q", stack)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+type card struct {
+	Number string `json:"number"`
+	Expiry string `json:"expiry"`
+}
+
+type contact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+type chargeRequest struct {
+	OrderID  string    `json:"order_id"`
+	Card     *card     `json:"card"`
+	Contacts []contact `json:"contacts"`
+	Token    string    `json:"token"`
+}
+
+func TestPayloadFieldMask(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	req := &chargeRequest{
+		OrderID:  "o-1",
+		Card:     &card{Number: "4111111111111111", Expiry: "12/30"},
+		Contacts: []contact{{Name: "Ann", Phone: "555-0100"}, {Name: "Bob", Phone: "555-0101"}},
+		Token:    "abcdef",
+	}
+
+	logger.Info("RPC completed", logx.Payload("request", req, "card.number", "contacts.name", "missing.path"))
+
+	request := read()[0]["request"].(map[string]interface{})
+	if request["order_id"] != "o-1" {
+		t.Errorf("Expected unmasked fields to be kept, got %v", request)
+	}
+	if c := request["card"].(map[string]interface{}); c["number"] != "***MASKED***" || c["expiry"] != "12/30" {
+		t.Errorf("Expected only card.number to be masked, got %v", c)
+	}
+	for _, item := range request["contacts"].([]interface{}) {
+		c := item.(map[string]interface{})
+		if c["name"] != "***MASKED***" || c["phone"] == "555-0100" || c["phone"] == "555-0101" {
+			t.Errorf("Expected repeated field mask and sensitive key masking, got %v", c)
+		}
+	}
+	if request["token"] != "ab***ef" {
+		t.Errorf("Expected nested sensitive key to be masked, got %v", request["token"])
+	}
+	if req.Card.Number != "4111111111111111" {
+		t.Error("Expected the original message to be left untouched")
+	}
+}
@@ -0,0 +1,62 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestLoadPipeline(t *testing.T) {
+	path := writePipelineFile(t, `
+processors:
+  - type: filter
+    rules:
+      - fields: {path: /healthz}
+      - levels: [warn]
+        message: "^cache miss"
+        action: downgrade
+        downgrade_to: info
+  - type: rewrite
+    rewrites:
+      - pattern: "0x[0-9a-f]+"
+        replacement: "0x?"
+  - type: dedup
+    window: 1h
+  - type: ratelimit
+    limit: 100
+    interval: 1s
+    key_field: tenant
+  - type: enrich
+    fields: {region: eu-west-1, service: api}
+`)
+	pipeline, err := logx.LoadPipeline(path)
+	if err != nil {
+		t.Fatalf("Failed to load pipeline: %v", err)
+	}
+	defer pipeline.Close()
+	if len(pipeline) != 5 {
+		t.Fatalf("Expected 5 processors, got %d", len(pipeline))
+	}
+
+	config := logx.DefaultConfig()
+	config.Processors = pipeline
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("request", logx.String("path", "/healthz"))
+	logger.Warn("cache miss at 0xdeadbeef")
+	logger.Warn("cache miss at 0xcafebabe")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	entry := entries[0]
+	if entry["level"] != "INFO" || entry["message"] != "cache miss at 0x?" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+	if entry["region"] != "eu-west-1" || entry["service"] != "api" {
+		t.Errorf("Expected enrichment fields, got %v", entry)
+	}
+}
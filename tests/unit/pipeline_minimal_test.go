@@ -0,0 +1,71 @@
+//go:build logx_minimal
+
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestLoadPipelineMinimal(t *testing.T) {
+	path := writePipelineFile(t, `
+processors:
+  - type: filter
+    rules:
+      - fields: {path: /healthz}
+  - type: route
+    routes:
+      - min_level: error
+        sinks: [default]
+  - type: enrich
+    fields: {region: eu-west-1}
+`)
+	pipeline, err := logx.LoadPipeline(path)
+	if err != nil {
+		t.Fatalf("Failed to load pipeline: %v", err)
+	}
+	defer pipeline.Close()
+
+	config := logx.DefaultConfig()
+	config.Processors = pipeline
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("request", logx.String("path", "/healthz"))
+	logger.Info("request", logx.String("path", "/users"))
+
+	entries := read()
+	if len(entries) != 1 || entries[0]["path"] != "/users" || entries[0]["region"] != "eu-west-1" {
+		t.Errorf("Unexpected entries: %v", entries)
+	}
+}
+
+func TestMinimalCompiledOut(t *testing.T) {
+	for _, spec := range []string{
+		"processors:\n  - type: rewrite\n    rewrites:\n      - pattern: x\n",
+		"processors:\n  - type: schema\n    schema: schema.json\n",
+	} {
+		if _, err := logx.LoadPipeline(writePipelineFile(t, spec)); err == nil ||
+			!strings.Contains(err.Error(), "not available in logx_minimal builds") {
+			t.Errorf("Expected a logx_minimal error, got %v", err)
+		}
+	}
+
+	if _, err := logx.NewRewriter(logx.ScrubFilePaths); err == nil {
+		t.Error("Expected NewRewriter to fail")
+	}
+	if _, err := logx.NewSchemaValidator([]byte(`{}`), logx.SchemaAnnotate); err == nil {
+		t.Error("Expected NewSchemaValidator to fail")
+	}
+	if _, err := logx.ParseQuery("level>=error"); err == nil {
+		t.Error("Expected ParseQuery to fail")
+	}
+
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 10}
+	logger, _ := newCaptureLogger(t, config)
+	if _, err := logger.Query("level>=error"); err == nil {
+		t.Error("Expected Logger.Query to fail")
+	}
+}
@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func writePipelineFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write pipeline config: %v", err)
+	}
+	return path
+}
+
+func TestLoadPipelineErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown type":   "processors:\n  - type: teleport\n",
+		"bad regex":      "processors:\n  - type: rewrite\n    rewrites:\n      - pattern: \"[\"\n",
+		"bad level":      "processors:\n  - type: filter\n    rules:\n      - levels: [loud]\n",
+		"missing limit":  "processors:\n  - type: ratelimit\n",
+		"malformed yaml": "processors: [\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := logx.LoadPipeline(writePipelineFile(t, content)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if _, err := logx.LoadPipeline(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "read") {
+		t.Errorf("Expected read error, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestFieldProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *logx.FieldProfile
+		want    map[string]interface{}
+	}{
+		{"otel", logx.ProfileOTel, map[string]interface{}{
+			"severity_text": "WARN", "body": "Disk low", "otel.scope.name": "storage", "exception.message": "full",
+		}},
+		{"ecs", logx.ProfileECS, map[string]interface{}{
+			"log.level": "WARN", "message": "Disk low", "log.logger": "storage", "error.message": "full",
+			"ecs.version": logx.ECSVersion,
+		}},
+		{"gcp", logx.ProfileGCP, map[string]interface{}{
+			"severity": "WARNING", "message": "Disk low", "logger": "storage", "error": "full",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := logx.DefaultConfig()
+			config.FieldProfile = tt.profile
+			logger, read := newCaptureLogger(t, config)
+
+			logger.Named("storage").Warn("Disk low", logx.ErrorField(errors.New("full")))
+
+			entries := read()
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(entries))
+			}
+			for key, want := range tt.want {
+				if entries[0][key] != want {
+					t.Errorf("%s = %v, want %v (entry %v)", key, entries[0][key], want, entries[0])
+				}
+			}
+		})
+	}
+}
+
+func TestFieldProfileProcessorsSeeLogxKeys(t *testing.T) {
+	var seen []string
+	config := logx.DefaultConfig()
+	config.FieldProfile = logx.ProfileECS
+	config.Processors = []logx.Processor{logx.ProcessorFunc(func(e *logx.Entry) bool {
+		for _, f := range e.Fields {
+			seen = append(seen, f.Key)
+		}
+		return true
+	})}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Error("Failed", logx.ErrorField(errors.New("boom")))
+
+	if len(seen) != 1 || seen[0] != "error" {
+		t.Errorf("Expected processor to see the error key, got %v", seen)
+	}
+	if entries := read(); entries[0]["error.message"] != "boom" {
+		t.Errorf("Expected renamed error field, got %v", entries[0])
+	}
+}
+
+func TestLoadFieldProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	data := "name: acme\nkeys:\n  message: msg\n  user_id: uid\nlevels:\n  INFO: information\nfields:\n  schema: acme/2\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write profile: %v", err)
+	}
+	profile, err := logx.LoadFieldProfile(path)
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.FieldProfile = profile
+	logger, read := newCaptureLogger(t, config)
+	logger.Info("Signed in", logx.String("user_id", "42"))
+
+	entry := read()[0]
+	if entry["msg"] != "Signed in" || entry["uid"] != "42" || entry["level"] != "information" || entry["schema"] != "acme/2" {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	os.WriteFile(bad, []byte("levels:\n  LOUD: x\n"), 0644)
+	if _, err := logx.LoadFieldProfile(bad); err == nil {
+		t.Error("Expected error for unknown level name")
+	}
+}
+
+func TestEncoderKeys(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.FieldProfile = logx.ProfileECS
+	config.Keys = logx.EncoderKeys{Timestamp: "ts", Level: "lvl", Message: "msg"}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Named("storage").Warn("Disk low")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["lvl"] != "WARN" || entry["msg"] != "Disk low" || entry["ts"] == nil || entry["log.logger"] != "storage" {
+		t.Errorf("Expected the overridden keys over the profile's, got %v", entry)
+	}
+	for _, key := range []string{"timestamp", "@timestamp", "log.level", "message"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Unexpected key %q in %v", key, entry)
+		}
+	}
+}
+
+func TestEncoderKeysDuplicate(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Keys = logx.EncoderKeys{Message: "level"}
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error for a message key equal to the level key")
+	}
+}
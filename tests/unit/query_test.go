@@ -0,0 +1,78 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestQueryMatch(t *testing.T) {
+	entries := []logx.Entry{
+		{Level: logx.ErrorLevel, Message: "upstream timeout", LoggerName: "http", Fields: []logx.Field{logx.String("user_id", "42"), logx.Int("status", 504)}},
+		{Level: logx.ErrorLevel, Message: "disk full", LoggerName: "db", Fields: []logx.Field{logx.String("user_id", "42")}},
+		{Level: logx.InfoLevel, Message: "request timeout retried", LoggerName: "http", Fields: []logx.Field{logx.String("user_id", "7"), logx.Int("status", 200)}},
+	}
+
+	cases := map[string][]string{
+		`level>=error AND fields.user_id="42" AND msg~"timeout"`: {"upstream timeout"},
+		`level<error`:                                      {"request timeout retried"},
+		`logger=http AND fields.status>=500`:               {"upstream timeout"},
+		`NOT (msg~timeout) OR fields.user_id=7`:            {"disk full", "request timeout retried"},
+		`fields.status!=200`:                               {"upstream timeout", "disk full"},
+		`level=ERROR and (logger=db or fields.status<300)`: {"disk full"},
+	}
+	for expr, want := range cases {
+		q, err := logx.ParseQuery(expr)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", expr, err)
+			continue
+		}
+		matched := q.Filter(entries)
+		if len(matched) != len(want) {
+			t.Errorf("%s: expected %v, got %d entries", expr, want, len(matched))
+			continue
+		}
+		for i, e := range matched {
+			if e.Message != want[i] {
+				t.Errorf("%s: expected %q at %d, got %q", expr, want[i], i, e.Message)
+			}
+		}
+	}
+}
+
+func TestQueryParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`level>=loud`,
+		`msg~"("`,
+		`unknown=1`,
+		`level>=error AND`,
+		`(msg=a`,
+		`msg="unterminated`,
+		`level~error`,
+	} {
+		if _, err := logx.ParseQuery(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+func TestLoggerQueryHistory(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 100}
+	logger, _ := newCaptureLogger(t, config)
+
+	logger.Info("connected", logx.String("user_id", "42"))
+	logger.Error("query timeout", logx.String("user_id", "42"))
+	logger.Error("query timeout", logx.String("user_id", "7"))
+
+	matched, err := logger.Query(`level>=error AND fields.user_id="42"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matched) != 1 || matched[0].Message != "query timeout" {
+		t.Errorf("Unexpected query result: %v", matched)
+	}
+}
@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestRateLimiterPerKey(t *testing.T) {
+	limiter := logx.NewRateLimiter(2, time.Hour, "tenant")
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{limiter}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("noisy", logx.String("tenant", "a"))
+	}
+	logger.Info("quiet", logx.String("tenant", "b"))
+	logger.Error("always kept", logx.String("tenant", "a"))
+
+	if entries := read(); len(entries) != 4 {
+		t.Fatalf("Expected 4 entries before flush, got %d", len(entries))
+	}
+
+	limiter.Close()
+	entries := read()
+	if len(entries) != 5 {
+		t.Fatalf("Expected a suppression summary after close, got %d entries", len(entries))
+	}
+	summary := entries[4]
+	if summary["tenant"] != "a" || summary["suppressed_count"] != float64(8) || summary["level"] != "WARN" {
+		t.Errorf("Unexpected summary: %v", summary)
+	}
+}
+
+func TestRateLimiterWindowReset(t *testing.T) {
+	limiter := logx.NewRateLimiter(1, 20*time.Millisecond, "")
+	defer limiter.Close()
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{limiter}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("first")
+	logger.Info("suppressed")
+	time.Sleep(100 * time.Millisecond)
+	logger.Info("next window")
+
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected entry, summary and next window entry, got %d: %v", len(entries), entries)
+	}
+	if entries[2]["message"] != "next window" {
+		t.Errorf("Expected budget reset after window, got %v", entries[2])
+	}
+}
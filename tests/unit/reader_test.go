@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+	"github.com/seasbee/go-logx/reader"
+)
+
+func writeLogFile(t *testing.T, path string, write func(logger *logx.Logger)) {
+	t.Helper()
+	config := logx.DefaultConfig()
+	config.OutputPath = path
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	write(logger)
+	logger.Sync()
+}
+
+func TestReaderTypedAccessors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLogFile(t, path, func(logger *logx.Logger) {
+		logger.Named("http").Info("Request served",
+			logx.String("path", "/users"),
+			logx.Int("status", 200),
+			logx.Float64("ratio", 0.25),
+			logx.Bool("cached", true),
+			logx.Any("latency", 1500*time.Millisecond),
+		)
+		logger.Error("Request failed", logx.ErrorField(errors.New("timeout")))
+	})
+
+	r, err := reader.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+	entries, err := r.All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != logx.InfoLevel || e.Message != "Request served" || e.Logger != "http" {
+		t.Errorf("Unexpected envelope %+v", e)
+	}
+	if time.Since(e.Time) > time.Minute {
+		t.Errorf("Unexpected time %v", e.Time)
+	}
+	if v, _ := e.String("path"); v != "/users" {
+		t.Errorf("path = %q", v)
+	}
+	if v, _ := e.Int64("status"); v != 200 {
+		t.Errorf("status = %d", v)
+	}
+	if v, _ := e.Float64("ratio"); v != 0.25 {
+		t.Errorf("ratio = %v", v)
+	}
+	if v, ok := e.Bool("cached"); !ok || !v {
+		t.Errorf("cached = %v, %v", v, ok)
+	}
+	if v, _ := e.Duration("latency"); v != 1500*time.Millisecond {
+		t.Errorf("latency = %v", v)
+	}
+	if _, ok := e.Int64("missing"); ok {
+		t.Error("Expected missing field to report false")
+	}
+	if entries[1].Stacktrace == "" || entries[1].Caller == "" {
+		t.Errorf("Expected caller and stacktrace on error entry, got %+v", entries[1])
+	}
+}
+
+func TestReaderRotatedFilesAndFilters(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app.log.1.gz")
+	current := filepath.Join(dir, "app.log")
+
+	plain := filepath.Join(dir, "plain")
+	writeLogFile(t, plain, func(logger *logx.Logger) {
+		logger.Info("old info")
+		logger.Warn("old warn", logx.String("user_id", "42"))
+	})
+	data, _ := os.ReadFile(plain)
+	file, _ := os.Create(old)
+	gz := gzip.NewWriter(file)
+	gz.Write(data)
+	gz.Close()
+	file.Close()
+	os.Remove(plain)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(old, past, past)
+
+	writeLogFile(t, current, func(logger *logx.Logger) {
+		logger.Warn("new warn", logx.String("user_id", "42"))
+		logger.Error("new error", logx.String("user_id", "7"))
+	})
+	f, _ := os.OpenFile(current, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("not json\n")
+	f.Close()
+
+	r, err := reader.OpenGlob(filepath.Join(dir, "app.log*"))
+	if err != nil {
+		t.Fatalf("OpenGlob failed: %v", err)
+	}
+	defer r.Close()
+	r.Filter(reader.MinLevel(logx.WarnLevel), reader.Field("user_id", "42"))
+
+	var messages []string
+	for r.Next() {
+		messages = append(messages, r.Entry().Message)
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(messages, ","); got != "old warn,new warn" {
+		t.Errorf("Messages = %q, want oldest first", got)
+	}
+	if r.Skipped() != 1 {
+		t.Errorf("Skipped = %d, want 1", r.Skipped())
+	}
+}
+
+func TestReaderMessageFilter(t *testing.T) {
+	r := reader.New(strings.NewReader(
+		`{"level":"INFO","timestamp":"2024-05-01T10:00:00Z","message":"cache hit"}` + "\n" +
+			`{"level":"INFO","timestamp":"2024-05-01T11:00:00Z","message":"cache miss"}` + "\n"))
+	miss, err := reader.Message("miss$")
+	if err != nil {
+		t.Fatalf("Message failed: %v", err)
+	}
+	r.Filter(miss, reader.Since(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)))
+	entries, _ := r.All()
+	if len(entries) != 1 || entries[0].Message != "cache miss" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+	if _, err := reader.Message("("); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,51 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestPreviewRedaction(t *testing.T) {
+	report := logx.PreviewRedaction([]logx.Entry{
+		{Message: "login", Fields: []logx.Field{logx.String("password", "hunter22"), logx.String("user", "bob")}},
+		{Message: "login", Fields: []logx.Field{logx.String("password", "letmein")}},
+		{Message: "plain", Fields: []logx.Field{logx.String("user", "alice")}},
+	})
+
+	if report.Entries != 3 || report.Masked != 2 {
+		t.Fatalf("Expected 3 entries with 2 masked, got %+v", report)
+	}
+	if len(report.Fields) != 1 {
+		t.Fatalf("Expected only password to be reported, got %+v", report.Fields)
+	}
+	field := report.Fields[0]
+	if field.Key != "password" || field.Count != 2 || field.Example != "hu***22" {
+		t.Errorf("Unexpected field report: %+v", field)
+	}
+}
+
+func TestPreviewRedactionReaderPolicyChange(t *testing.T) {
+	input := `{"message":"a","iban":"DE123456"}
+not json
+{"message":"b","iban":"FR987654"}
+`
+	report, err := logx.PreviewRedactionReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Masked != 0 || report.Skipped != 1 {
+		t.Fatalf("Expected nothing masked before the change, got %+v", report)
+	}
+
+	logx.AddSensitiveKey("iban")
+	defer logx.RemoveSensitiveKey("iban")
+	report, err = logx.PreviewRedactionReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Masked != 2 || len(report.Fields) != 1 || report.Fields[0].Example != "DE***56" {
+		t.Errorf("Expected iban to be masked after the change, got %+v", report)
+	}
+}
@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestReloadSwapsOutputsAndLevel(t *testing.T) {
+	dir := t.TempDir()
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "before.log")
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	child := logger.Named("db").With(logx.String("table", "users"))
+	child.Debug("hidden")
+	child.Info("before reload")
+
+	reloaded := logx.DefaultConfig()
+	reloaded.Level = logx.DebugLevel
+	reloaded.OutputPath = filepath.Join(dir, "after.log")
+	if err := logger.Reload(reloaded); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	child.Debug("after reload")
+	logger.Sync()
+
+	if entries := readJSONFile(t, config.OutputPath); len(entries) != 1 || entries[0]["message"] != "before reload" {
+		t.Errorf("Expected only the entry logged before the reload in the old file, got %v", entries)
+	}
+	after := readJSONFile(t, reloaded.OutputPath)
+	if len(after) != 1 || after[0]["message"] != "after reload" || after[0]["logger"] != "db" || after[0]["table"] != "users" {
+		t.Errorf("Expected the child's debug entry in the new file, got %v", after)
+	}
+	if logger.GetLevel() != logx.DebugLevel {
+		t.Errorf("Expected the reloaded level, got %v", logger.GetLevel())
+	}
+}
+
+func TestReloadSinks(t *testing.T) {
+	dir := t.TempDir()
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"audit"}})
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "app.log")
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "audit", OutputPath: filepath.Join(dir, "audit-1.log")}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	reloaded := *config
+	reloaded.Sinks = []logx.SinkConfig{{Name: "audit", OutputPath: filepath.Join(dir, "audit-2.log")}}
+	if err := logger.Reload(&reloaded); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	logger.Info("audited")
+	logger.Sync()
+	if entries := readJSONFile(t, reloaded.Sinks[0].OutputPath); len(entries) != 1 {
+		t.Errorf("Expected the entry in the reloaded sink, got %v", entries)
+	}
+
+	added := reloaded
+	added.Sinks = append(added.Sinks, logx.SinkConfig{Name: "debug", OutputPath: filepath.Join(dir, "debug.log")})
+	if err := logger.Reload(&added); err == nil {
+		t.Error("Expected an error when adding a sink")
+	}
+}
+
+func TestReloadKeepsConfigurationOnError(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	invalid := logx.DefaultConfig()
+	invalid.TimeZone = "Nowhere/Invalid"
+	if err := logger.Reload(invalid); err == nil {
+		t.Fatal("Expected an error for an invalid time zone")
+	}
+	logger.Info("still here")
+	if entries := read(); len(entries) != 1 {
+		t.Errorf("Expected the logger to keep its output, got %v", entries)
+	}
+}
@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestRetentionManager(t *testing.T) {
+	dir := t.TempDir()
+	ages := map[string]time.Duration{
+		"app-1.log":    time.Minute,
+		"app-2.log":    36 * time.Hour,
+		"app-3.log":    48 * time.Hour,
+		"app-4.log":    72 * time.Hour,
+		"app-5.log.gz": 30 * 24 * time.Hour,
+		"other.txt":    30 * 24 * time.Hour,
+	}
+	for name, age := range ages {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte("{}\n"), 0644)
+		os.Chtimes(path, time.Now().Add(-age), time.Now().Add(-age))
+	}
+	os.WriteFile(filepath.Join(dir, "app-4.log"+logx.IndexSuffix), nil, 0644)
+
+	retention, err := logx.NewRetentionManager(logx.RetentionConfig{
+		Patterns:      []string{filepath.Join(dir, "app-*.log*")},
+		MaxAge:        7 * 24 * time.Hour,
+		MaxFiles:      3,
+		CompressAfter: 24 * time.Hour,
+		Interval:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create retention manager: %v", err)
+	}
+	defer retention.Close()
+	if err := retention.Cleanup(); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+
+	for _, name := range []string{"app-1.log", "app-2.log.gz", "app-3.log.gz", "other.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to exist: %v", name, err)
+		}
+	}
+	for _, name := range []string{"app-2.log", "app-3.log", "app-4.log", "app-4.log" + logx.IndexSuffix, "app-5.log.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", name)
+		}
+	}
+}
+
+func TestRetentionManagerRunsOnSchedule(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "old.log")
+	os.WriteFile(path, []byte("{}\n"), 0644)
+	os.Chtimes(path, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+
+	retention, err := logx.NewRetentionManager(logx.RetentionConfig{
+		Patterns: []string{filepath.Join(dir, "*.log")},
+		MaxAge:   time.Minute,
+		Interval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create retention manager: %v", err)
+	}
+	defer retention.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the old file to be removed by the scheduled cleanup")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRetentionManagerInvalidPattern(t *testing.T) {
+	if _, err := logx.NewRetentionManager(logx.RetentionConfig{Patterns: []string{"["}}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+	if _, err := logx.NewRetentionManager(logx.RetentionConfig{}); err == nil {
+		t.Error("Expected an error without patterns")
+	}
+}
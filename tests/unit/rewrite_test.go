@@ -0,0 +1,122 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestRewriter(t *testing.T) {
+	rewriter, err := logx.NewRewriter(
+		logx.ScrubMemoryAddresses,
+		logx.ScrubFilePaths,
+		logx.RewriteRule{Pattern: `took \d+ms`, Replacement: "took Nms"},
+		logx.RewriteRule{Pattern: `sess-[a-z0-9]+`, Replacement: "sess-?", Fields: []string{"url", "error"}, FieldsOnly: true},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create rewriter: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{rewriter}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("object 0xc000123450 at /home/build/src/app/db.go:42 took 153ms",
+		logx.String("url", "https://example.com/sess-abc123/view"),
+		logx.ErrorField(errors.New("expired sess-xyz9")),
+		logx.String("other", "sess-keep"),
+	)
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if want := "object 0x? at <path>/db.go:42 took Nms"; entry["message"] != want {
+		t.Errorf("Message = %q, want %q", entry["message"], want)
+	}
+	if entry["url"] != "https://example.com/sess-?/view" {
+		t.Errorf("Unexpected url: %v", entry["url"])
+	}
+	if entry["error"] != "expired sess-?" {
+		t.Errorf("Unexpected error: %v", entry["error"])
+	}
+	if entry["other"] != "sess-keep" {
+		t.Errorf("Unlisted field should not be rewritten: %v", entry["other"])
+	}
+}
+
+func TestNewRewriterInvalidPattern(t *testing.T) {
+	if _, err := logx.NewRewriter(logx.RewriteRule{Pattern: "[a-"}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+// verboseError formats with a stack trace under %+v, like the errors of
+// github.com/pkg/errors.
+type verboseError struct{ msg string }
+
+func (e verboseError) Error() string { return e.msg }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	io.WriteString(s, e.msg)
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, "\nmain.run\n\t/home/build/src/app/main.go:12")
+	}
+}
+
+func TestRewriterKeepsErrors(t *testing.T) {
+	paths := logx.ScrubFilePaths
+	paths.Fields = []string{"error"}
+	rewriter, err := logx.NewRewriter(paths)
+	if err != nil {
+		t.Fatalf("Failed to create rewriter: %v", err)
+	}
+
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{rewriter}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Error("joined", logx.ErrorField(errors.Join(
+		errors.New("open /home/build/a.txt"),
+		errors.New("open /home/build/b.txt"),
+	)))
+	logger.Error("verbose", logx.ErrorField(verboseError{"read /home/build/c.txt"}))
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if want := "open <path>/a.txt\nopen <path>/b.txt"; entries[0]["error"] != want {
+		t.Errorf("error = %q, want %q", entries[0]["error"], want)
+	}
+	causes, ok := entries[0]["error"+logx.ErrorCausesSuffix].([]interface{})
+	if !ok || len(causes) != 2 {
+		t.Fatalf("Expected 2 error causes, got %v", entries[0]["error"+logx.ErrorCausesSuffix])
+	}
+	cause := causes[1].(map[string]interface{})
+	if cause["message"] != "open <path>/b.txt" {
+		t.Errorf("Cause message not rewritten: %v", cause["message"])
+	}
+	if cause["type"] != "*errors.errorString" {
+		t.Errorf("Cause type = %v, want the original type", cause["type"])
+	}
+
+	if entries[1]["error"] != "read <path>/c.txt" {
+		t.Errorf("Unexpected error: %v", entries[1]["error"])
+	}
+	verbose, _ := entries[1]["errorVerbose"].(string)
+	if verbose == "" {
+		t.Fatal("Expected errorVerbose to be kept")
+	}
+	if strings.Contains(verbose, "/home/build") || !strings.Contains(verbose, "<path>/main.go:12") {
+		t.Errorf("errorVerbose not rewritten: %q", verbose)
+	}
+}
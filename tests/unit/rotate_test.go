@@ -0,0 +1,179 @@
+package unit
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// logOverOneMB writes entries adding up to a little more than 1 MB.
+func logOverOneMB(logger *logx.Logger) {
+	payload := strings.Repeat("x", 1000)
+	for i := 0; i < 1100; i++ {
+		logger.Info("filler", logx.String("payload", payload), logx.Int("i", i))
+	}
+}
+
+func TestSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "app.log")
+	config.IndexInterval = 100
+	config.Rotation = &logx.RotationConfig{MaxSizeMB: 1, MaxBackups: 2}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		logOverOneMB(logger)
+	}
+	logger.Sync()
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups to be kept, got %v", backups)
+	}
+	for _, backup := range backups {
+		info, _ := os.Stat(backup)
+		if info.Size() > 1<<20 {
+			t.Errorf("Expected %s to stay within 1 MB, got %d bytes", backup, info.Size())
+		}
+		if _, err := os.Stat(backup + logx.IndexSuffix); err != nil {
+			t.Errorf("Expected the index to be rotated with %s: %v", backup, err)
+		}
+		// Every backup starts with a complete entry
+		readJSONFile(t, backup)
+	}
+	records, err := logx.ReadIndex(config.OutputPath)
+	if err != nil || len(records) == 0 || records[0].Offset != 0 {
+		t.Errorf("Expected a fresh index for the current file, got %v, %v", records, err)
+	}
+}
+
+func TestSizeRotationCompress(t *testing.T) {
+	dir := t.TempDir()
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "app.log")
+	config.Rotation = &logx.RotationConfig{MaxSizeMB: 1, Compress: true}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logOverOneMB(logger)
+	logger.Sync()
+
+	compressed, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	plain, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(compressed) != 1 || len(plain) != 0 {
+		t.Fatalf("Expected one compressed backup, got %v and %v", compressed, plain)
+	}
+	file, _ := os.Open(compressed[0])
+	defer file.Close()
+	if _, err := gzip.NewReader(file); err != nil {
+		t.Errorf("Expected a valid gzip backup: %v", err)
+	}
+}
+
+func TestSizeRotationRequiresOSFileSystem(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.OutputPath = "app.log"
+	config.FileSystem = logx.NewMemFileSystem()
+	config.Rotation = &logx.RotationConfig{}
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error for rotation on a custom filesystem")
+	}
+}
+
+func TestTimeRotation(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app-2020010100.log")
+	oldBackup := filepath.Join(dir, "app-2020010100-2020-01-01T00-30-00.000.log.gz")
+	unrelated := filepath.Join(dir, "other.log")
+	for _, path := range []string{old, oldBackup, unrelated} {
+		os.WriteFile(path, []byte("{}\n"), 0644)
+		os.Chtimes(path, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour))
+	}
+
+	config := logx.DefaultConfig()
+	config.TimeZone = "UTC"
+	config.OutputPath = filepath.Join(dir, "app-%Y%m%d%H.log")
+	config.Rotation = &logx.RotationConfig{Interval: logx.RotateHourly, Retention: 24 * time.Hour}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Info("hello")
+	logger.Sync()
+
+	current := filepath.Join(dir, "app-"+time.Now().UTC().Format("2006010215")+".log")
+	if entries := readJSONFile(t, current); len(entries) != 1 {
+		t.Errorf("Expected the entry in the current hour's file, got %v", entries)
+	}
+	for _, path := range []string{old, oldBackup} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed by retention", path)
+		}
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("Expected unrelated files to be kept: %v", err)
+	}
+}
+
+func TestTimeRotationRequiresTemplate(t *testing.T) {
+	for _, path := range []string{"app.log", "app-%Q.log", "app-{2006.log", "app-{}.log"} {
+		config := logx.DefaultConfig()
+		config.OutputPath = filepath.Join(t.TempDir(), path)
+		config.Rotation = &logx.RotationConfig{Interval: logx.RotateDaily}
+		if _, err := logx.New(config); err == nil {
+			t.Errorf("Expected an error for the path %q", path)
+		}
+	}
+}
+
+func TestRotationPathPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	config := logx.DefaultConfig()
+	config.TimeZone = "UTC"
+	config.OutputPath = filepath.Join(dir, "app-{service}-{hostname}-{2006-01-02}.log")
+	config.Rotation = &logx.RotationConfig{Interval: logx.RotateDaily, Service: "billing"}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Info("hello")
+	logger.Sync()
+
+	hostname, _ := os.Hostname()
+	current := filepath.Join(dir, "app-billing-"+hostname+"-"+time.Now().UTC().Format("2006-01-02")+".log")
+	if entries := readJSONFile(t, current); len(entries) != 1 {
+		t.Errorf("Expected the entry in %s, got %v", current, entries)
+	}
+}
+
+func TestRotationPathPlaceholdersWithoutInterval(t *testing.T) {
+	dir := t.TempDir()
+	config := logx.DefaultConfig()
+	config.OutputPath = filepath.Join(dir, "{service}.log")
+	config.Rotation = &logx.RotationConfig{Service: "api"}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Info("hello")
+	logger.Sync()
+	if entries := readJSONFile(t, filepath.Join(dir, "api.log")); len(entries) != 1 {
+		t.Errorf("Expected the entry in api.log, got %v", entries)
+	}
+
+	config.OutputPath = filepath.Join(dir, "app-{2006}.log")
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error for a time layout without Interval")
+	}
+}
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestRouterSendsEntriesToSinks(t *testing.T) {
+	router, err := logx.NewRouter(
+		logx.RouteRule{Fields: map[string]string{"audit": "true"}, Sinks: []string{"audit"}},
+		logx.RouteRule{MinLevel: logx.ErrorLevel, Sinks: []string{logx.DefaultSinkName, "alerting", "missing"}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	var audit, alerting bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{
+		{Name: "audit", Writer: &audit},
+		{Name: "alerting", Writer: &alerting},
+	}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("user deleted", logx.Bool("audit", true))
+	logger.Named("db").Error("connection lost")
+	logger.Info("plain entry")
+
+	main := read()
+	if len(main) != 2 || main[0]["message"] != "connection lost" || main[1]["message"] != "plain entry" {
+		t.Errorf("Unexpected default sink entries: %v", main)
+	}
+	auditEntries := decodeLines(t, &audit)
+	if len(auditEntries) != 1 || auditEntries[0]["message"] != "user deleted" {
+		t.Errorf("Unexpected audit sink entries: %v", auditEntries)
+	}
+	alertEntries := decodeLines(t, &alerting)
+	if len(alertEntries) != 1 || alertEntries[0]["logger"] != "db" {
+		t.Errorf("Unexpected alerting sink entries: %v", alertEntries)
+	}
+	if caller, _ := alertEntries[0]["caller"].(string); !strings.HasPrefix(caller, "unit/router_test.go") {
+		t.Errorf("Expected caller in router_test.go, got %q", caller)
+	}
+}
+
+func TestSinkConfigValidation(t *testing.T) {
+	for _, sinks := range [][]logx.SinkConfig{
+		{{Name: ""}},
+		{{Name: logx.DefaultSinkName}},
+		{{Name: "a", Writer: &bytes.Buffer{}}, {Name: "a", Writer: &bytes.Buffer{}}},
+	} {
+		config := logx.DefaultConfig()
+		config.Sinks = sinks
+		if _, err := logx.New(config); err == nil {
+			t.Errorf("Expected error for sinks %+v", sinks)
+		}
+	}
+
+	if _, err := logx.NewRouter(logx.RouteRule{MinLevel: logx.ErrorLevel}); err == nil {
+		t.Error("Expected error for route rule without sinks")
+	}
+}
+
+func TestSinkLevelIndependentOfLoggerLevel(t *testing.T) {
+	router, err := logx.NewRouter(logx.RouteRule{Sinks: []string{logx.DefaultSinkName, "debug", "errors"}})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	var debug, errs bytes.Buffer
+	debugLevel, errorLevel := logx.DebugLevel, logx.ErrorLevel
+	config := logx.DefaultConfig()
+	config.Level = logx.InfoLevel
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{
+		{Name: "debug", Writer: &debug, Level: &debugLevel},
+		{Name: "errors", Writer: &errs, Level: &errorLevel},
+	}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Trace("trace entry")
+	logger.Debug("debug entry")
+	logger.Info("info entry")
+	logger.Error("error entry")
+
+	if main := read(); len(main) != 2 || main[0]["message"] != "info entry" {
+		t.Errorf("Expected default sink capped at Info, got %v", main)
+	}
+	if entries := decodeLines(t, &debug); len(entries) != 4 {
+		t.Errorf("Expected debug sink to receive all 4 entries, got %v", entries)
+	}
+	if entries := decodeLines(t, &errs); len(entries) != 1 || entries[0]["message"] != "error entry" {
+		t.Errorf("Expected errors sink to receive only the error, got %v", entries)
+	}
+
+	// SetLevel changes the logger's level but not a sink's own level
+	logger.SetLevel(logx.ErrorLevel)
+	debug.Reset()
+	logger.Debug("still visible")
+	if entries := decodeLines(t, &debug); len(entries) != 1 {
+		t.Errorf("Expected sink level to be unaffected by SetLevel, got %v", entries)
+	}
+}
@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// waitForEvent polls the log until an entry with the given event appears.
+func waitForEvent(t *testing.T, read func() []map[string]interface{}, event string) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, entry := range read() {
+			if entry[logx.EventKey] == event {
+				return entry
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected a %q event", event)
+	return nil
+}
+
+func TestRuntimeMonitorReportsGCPauses(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	monitor := logger.StartRuntimeMonitor(logx.RuntimeMonitorConfig{
+		Interval:         10 * time.Millisecond,
+		GCPauseThreshold: time.Nanosecond,
+	})
+	defer monitor.Close()
+	runtime.GC()
+
+	entry := waitForEvent(t, read, logx.EventRuntimeGC)
+	if entry["level"] != "WARN" || entry["pause_ms"] == nil || entry["gc_cycle"] == nil {
+		t.Errorf("Unexpected GC event: %v", entry)
+	}
+}
+
+func TestRuntimeMonitorReportsGoroutineSpikes(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	monitor := logger.StartRuntimeMonitor(logx.RuntimeMonitorConfig{
+		Interval:      10 * time.Millisecond,
+		MinGoroutines: 10,
+	})
+	defer monitor.Close()
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	spawn := 4*runtime.NumGoroutine() + 10
+	for i := 0; i < spawn; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+		}()
+	}
+	entry := waitForEvent(t, read, logx.EventGoroutineSpike)
+	close(release)
+	wg.Wait()
+	if entry["goroutines"].(float64) <= entry["previous_goroutines"].(float64) {
+		t.Errorf("Unexpected goroutine spike event: %v", entry)
+	}
+}
+
+func TestRuntimeMonitorReportsMemoryLimit(t *testing.T) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	previous := debug.SetMemoryLimit(int64(stats.Sys))
+	defer debug.SetMemoryLimit(previous)
+
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+	monitor := logger.StartRuntimeMonitor(logx.RuntimeMonitorConfig{
+		Interval:         10 * time.Millisecond,
+		MemoryLimitRatio: 0.1,
+	})
+	defer monitor.Close()
+
+	entry := waitForEvent(t, read, logx.EventMemoryLimit)
+	if entry["memory_limit_bytes"] != float64(stats.Sys) {
+		t.Errorf("Unexpected memory limit event: %v", entry)
+	}
+}
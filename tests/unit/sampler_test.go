@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestAdaptiveSamplerBoostsAroundErrors(t *testing.T) {
+	sampler := logx.NewAdaptiveSampler(logx.AdaptiveSamplerConfig{
+		BaseRate: 0.1,
+		Window:   time.Hour,
+		Lookback: 3,
+	})
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{sampler}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 100; i++ {
+		logger.Info("calm", logx.Int("n", i))
+	}
+	if entries := read(); len(entries) != 10 {
+		t.Fatalf("Expected 10%% of calm entries, got %d", len(entries))
+	}
+
+	logger.Error("failure")
+	for i := 0; i < 10; i++ {
+		logger.Info("after error")
+	}
+
+	entries := read()[10:]
+	if len(entries) != 3+1+10 {
+		t.Fatalf("Expected lookback, error and boosted entries, got %d: %v", len(entries), entries)
+	}
+	for i, n := range []float64{96, 97, 98} {
+		if entries[i]["n"] != n || entries[i]["lookback"] != true || entries[i]["logged_at"] == nil {
+			t.Errorf("Expected lookback entry n=%v, got %v", n, entries[i])
+		}
+	}
+	if entries[3]["message"] != "failure" {
+		t.Errorf("Expected the error after its lookback, got %v", entries[3])
+	}
+	if rate := sampler.Rate(); rate < 0.99 {
+		t.Errorf("Expected a boosted rate right after the error, got %v", rate)
+	}
+}
+
+func TestAdaptiveSamplerDecays(t *testing.T) {
+	sampler := logx.NewAdaptiveSampler(logx.AdaptiveSamplerConfig{
+		BaseRate: 0.2,
+		Window:   50 * time.Millisecond,
+	})
+	sampler.Process(&logx.Entry{Level: logx.ErrorLevel, Time: time.Now()})
+	if rate := sampler.Rate(); rate <= 0.2 {
+		t.Errorf("Expected a boosted rate, got %v", rate)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if rate := sampler.Rate(); rate != 0.2 {
+		t.Errorf("Expected the base rate after the window, got %v", rate)
+	}
+}
+
+func TestAdaptiveSamplerAnnotatesKeptEntries(t *testing.T) {
+	sampler := logx.NewAdaptiveSampler(logx.AdaptiveSamplerConfig{BaseRate: 0.25, Window: time.Hour})
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{sampler}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 8; i++ {
+		logger.Info("calm", logx.Int("n", i))
+	}
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 kept entries, got %v", entries)
+	}
+	for _, entry := range entries {
+		if entry[logx.SampledKey] != true || entry[logx.SuppressedCountKey] != float64(3) || entry[logx.SampleWindowKey] == nil {
+			t.Errorf("Expected sampling metadata for 3 suppressed entries, got %v", entry)
+		}
+	}
+}
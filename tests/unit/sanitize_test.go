@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestSanitizeStrings(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.SanitizeStrings = true
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("login\nfailed\x1b[31m",
+		logx.String("user_input", "bad\xffbyte\r\nINFO forged entry"),
+		logx.ErrorField(errors.New("line1\nline2")),
+		logx.Any("tags", []string{"a\tb"}),
+		logx.String("key\nwith newline", "v"),
+	)
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if want := `login\nfailed\u001b[31m`; entry["message"] != want {
+		t.Errorf("Message = %q, want %q", entry["message"], want)
+	}
+	if want := "bad\uFFFDbyte\\r\\nINFO forged entry"; entry["user_input"] != want {
+		t.Errorf("user_input = %q, want %q", entry["user_input"], want)
+	}
+	if entry["error"] != `line1\nline2` {
+		t.Errorf("error = %q", entry["error"])
+	}
+	if tags, _ := entry["tags"].([]interface{}); len(tags) != 1 || tags[0] != `a\tb` {
+		t.Errorf("tags = %v", entry["tags"])
+	}
+	if _, ok := entry[`key\nwith newline`]; !ok {
+		t.Errorf("Expected sanitized key, got %v", entry)
+	}
+}
+
+func TestSanitizeStringsConsole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.log")
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"console"}})
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	config := logx.DefaultConfig()
+	config.SanitizeStrings = true
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "console", Writer: file, Development: true}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("multi\nline\nmessage", logx.String("input", "a\nb"))
+	logger.Sync()
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("Expected exactly one line of console output, got %d: %q", lines, data)
+	}
+}
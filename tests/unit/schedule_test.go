@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestParseCron(t *testing.T) {
+	// 2024-06-01 is a Saturday
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"0 2 * * sat", at(6, 1, 2, 0), true},
+		{"0 2 * * sat", at(6, 2, 2, 0), false},
+		{"0 2 * * 6", at(6, 1, 2, 1), false},
+		{"*/15 9-17 * * mon-fri", at(6, 3, 9, 45), true},
+		{"*/15 9-17 * * mon-fri", at(6, 3, 18, 0), false},
+		{"*/15 9-17 * * mon-fri", at(6, 3, 9, 50), false},
+		{"30 4 1,15 * *", at(6, 15, 4, 30), true},
+		{"0 0 * * 7", at(6, 2, 0, 0), true},
+		{"0 0 13 * fri", at(9, 13, 0, 0), true}, // Day of month or weekday
+		{"0 0 13 * fri", at(6, 7, 0, 0), true},
+		{"0 0 * jan,jun *", at(6, 9, 0, 0), true},
+		{"@daily", at(6, 9, 0, 0), true},
+		{"@hourly", at(6, 9, 5, 1), false},
+		{"CRON_TZ=Asia/Tokyo 0 9 * * *", at(6, 9, 0, 0), true},
+	}
+	for _, tt := range tests {
+		schedule, err := logx.ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Matches(tt.t); got != tt.want {
+			t.Errorf("%q matches %v = %v, want %v", tt.expr, tt.t, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *", "CRON_TZ=Nowhere * * * * *"} {
+		if _, err := logx.ParseCron(expr); err == nil {
+			t.Errorf("Expected ParseCron(%q) to fail", expr)
+		}
+	}
+}
+
+func TestLevelSchedule(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.LevelSchedule = []logx.LevelWindow{
+		{Name: "never", Cron: "0 0 30 2 *", Duration: time.Hour, Level: logx.TraceLevel},
+		{Name: "deploy", Cron: "* * * * *", Duration: time.Hour, Level: logx.DebugLevel},
+	}
+	logger, read := newCaptureLogger(t, config)
+	defer logger.Close()
+
+	if logger.GetLevel() != logx.DebugLevel {
+		t.Fatalf("Expected Debug during the window, got %v", logger.GetLevel())
+	}
+	logger.Debug("during")
+
+	// Reloading without windows restores Level
+	reload := logx.DefaultConfig()
+	reload.OutputPath = config.OutputPath
+	if err := logger.Reload(reload); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	logger.Debug("after")
+	if logger.GetLevel() != logx.InfoLevel {
+		t.Errorf("Expected Info after removing the window, got %v", logger.GetLevel())
+	}
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected the window marker and one Debug entry, got %v", entries)
+	}
+	started := entries[0]
+	if started["message"] != "Scheduled level window started" || started["level_window"] != "deploy" ||
+		started["level"] != "DEBUG" || started["previous_level"] != "INFO" {
+		t.Errorf("Unexpected window marker: %v", started)
+	}
+	if entries[1]["message"] != "during" {
+		t.Errorf("Expected the Debug entry, got %v", entries[1])
+	}
+}
+
+func TestLevelScheduleQuietingWindow(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.LevelSchedule = []logx.LevelWindow{
+		{Name: "batch", Cron: "* * * * *", Duration: time.Hour, Level: logx.WarnLevel},
+	}
+	logger, read := newCaptureLogger(t, config)
+	defer logger.Close()
+
+	if logger.GetLevel() != logx.WarnLevel {
+		t.Fatalf("Expected Warn during the window, got %v", logger.GetLevel())
+	}
+	logger.Info("during")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected only the window marker, got %v", entries)
+	}
+	started := entries[0]
+	if started["message"] != "Scheduled level window started" || started["level_window"] != "batch" ||
+		started["level"] != "WARN" || started["previous_level"] != "INFO" {
+		t.Errorf("Unexpected window marker: %v", started)
+	}
+}
+
+func TestLevelScheduleWindowAboveInfo(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Level = logx.WarnLevel
+	config.LevelSchedule = []logx.LevelWindow{
+		{Name: "incident", Cron: "* * * * *", Duration: time.Hour, Level: logx.ErrorLevel},
+	}
+	logger, read := newCaptureLogger(t, config)
+	defer logger.Close()
+
+	if logger.GetLevel() != logx.ErrorLevel {
+		t.Fatalf("Expected Error during the window, got %v", logger.GetLevel())
+	}
+	logger.Warn("during")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected only the window marker, got %v", entries)
+	}
+	if started := entries[0]; started["message"] != "Scheduled level window started" ||
+		started["level_window"] != "incident" || started["previous_level"] != "WARN" {
+		t.Errorf("Unexpected window marker: %v", started)
+	}
+}
+
+func TestLevelScheduleInvalid(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.OutputPath = t.TempDir() + "/app.log"
+	config.LevelSchedule = []logx.LevelWindow{{Cron: "0 2 * * sat", Level: logx.DebugLevel}}
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error for a window without a duration")
+	}
+	config.LevelSchedule[0] = logx.LevelWindow{Cron: "0 2 * *", Duration: time.Hour}
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected an error for an invalid cron expression")
+	}
+}
@@ -0,0 +1,137 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["request_id"],
+	"properties": {
+		"level":       {"enum": ["INFO", "WARN", "ERROR"]},
+		"request_id":  {"type": "string", "pattern": "^req-"},
+		"duration_ms": {"type": "number", "minimum": 0},
+		"attempt":     {"type": "integer"},
+		"tags":        {"type": "array", "items": {"type": "string"}},
+		"error":       {"type": "string"},
+		"elapsed":     {"type": "number"}
+	}
+}`
+
+func TestSchemaValidatorValidate(t *testing.T) {
+	validator, err := logx.NewSchemaValidator([]byte(testSchema), logx.SchemaDrop)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	valid := &logx.Entry{Level: logx.InfoLevel, Message: "ok", Fields: []logx.Field{
+		logx.String("request_id", "req-1"),
+		logx.Float64("duration_ms", 12.5),
+		logx.Int("attempt", 2),
+		logx.Any("tags", []string{"a", "b"}),
+		logx.ErrorField(errors.New("boom")),
+		logx.Any("elapsed", time.Second),
+	}}
+	if errs := validator.Validate(valid); len(errs) != 0 {
+		t.Errorf("Expected valid entry, got %v", errs)
+	}
+
+	invalid := &logx.Entry{Level: logx.DebugLevel, Message: "bad", Fields: []logx.Field{
+		logx.Int("duration_ms", -1),
+		logx.Float64("attempt", 1.5),
+		logx.Any("tags", []int{1}),
+	}}
+	errs := validator.Validate(invalid)
+	for _, want := range []string{"level", "request_id", "duration_ms", "attempt", "tags[0]"} {
+		found := false
+		for _, e := range errs {
+			if strings.Contains(e, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected violation mentioning %q in %v", want, errs)
+		}
+	}
+}
+
+func TestSchemaValidatorActions(t *testing.T) {
+	annotate, err := logx.NewSchemaValidator([]byte(testSchema), logx.SchemaAnnotate)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{annotate}
+	logger, read := newCaptureLogger(t, config)
+	logger.Info("missing request id")
+	logger.Info("complete", logx.String("request_id", "req-2"))
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if _, ok := entries[0]["schema_errors"]; !ok {
+		t.Errorf("Expected schema_errors annotation, got %v", entries[0])
+	}
+	if _, ok := entries[1]["schema_errors"]; ok {
+		t.Errorf("Unexpected schema_errors on valid entry: %v", entries[1])
+	}
+
+	strict, err := logx.NewSchemaValidator([]byte(testSchema), logx.SchemaPanic)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for non-conforming entry")
+		}
+	}()
+	strict.Process(&logx.Entry{Level: logx.InfoLevel, Message: "no id"})
+}
+
+func TestSchemaValidatorRenamedKeys(t *testing.T) {
+	validator, err := logx.NewSchemaValidator([]byte(`{
+		"type": "object",
+		"required": ["severity", "msg"],
+		"properties": {"severity": {"enum": ["INFO", "WARNING"]}}
+	}`), logx.SchemaAnnotate)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	config := logx.DefaultConfig()
+	config.FieldProfile = logx.ProfileGCP
+	config.Keys = logx.EncoderKeys{Message: "msg"}
+	config.Processors = []logx.Processor{validator}
+	logger, read := newCaptureLogger(t, config)
+	logger.Warn("disk almost full")
+	logger.Error("disk full")
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if errs, ok := entries[0]["schema_errors"]; ok {
+		t.Errorf("Expected the entry under the renamed keys to conform, got %v", errs)
+	}
+	if _, ok := entries[1]["schema_errors"]; !ok {
+		t.Errorf("Expected the ERROR severity to violate the schema, got %v", entries[1])
+	}
+}
+
+func TestNewSchemaValidatorErrors(t *testing.T) {
+	for _, schema := range []string{`{`, `{"type": 5}`, `{"properties": {"a": {"pattern": "("}}}`} {
+		if _, err := logx.NewSchemaValidator([]byte(schema), logx.SchemaDrop); err == nil {
+			t.Errorf("Expected error for schema %s", schema)
+		}
+	}
+	if _, err := logx.NewSchemaValidator([]byte(`{}`), "explode"); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
@@ -0,0 +1,144 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestSecretMasker(t *testing.T) {
+	var password atomic.Value
+	password.Store("hunter2-db-password")
+	source := logx.SecretSourceFunc(func(ctx context.Context) ([]string, error) {
+		return []string{password.Load().(string), "short"}, nil
+	})
+	masker, err := logx.NewSecretMasker(logx.SecretMaskConfig{Sources: []logx.SecretSource{source}})
+	if err != nil {
+		t.Fatalf("Failed to create masker: %v", err)
+	}
+	defer masker.Close()
+
+	config := logx.DefaultConfig()
+	config.Processors = []logx.Processor{masker}
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("Connecting to postgres://app:hunter2-db-password@db/app as short")
+	logger.Error("Query failed",
+		logx.String("dsn", "app:hunter2-db-password@db"),
+		logx.ErrorField(errors.New("auth failed for hunter2-db-password")))
+	password.Store("rotated-password-2")
+	if err := masker.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	masker.Add("runtime-generated-key")
+	logger.Info("New password rotated-password-2, key runtime-generated-key")
+
+	entries := read()
+	if entries[0]["message"] != "Connecting to postgres://app:***MASKED***@db/app as short" {
+		t.Errorf("Expected the password masked in the message, got %q", entries[0]["message"])
+	}
+	if entries[1]["dsn"] != "app:***MASKED***@db" || entries[1]["error"] != "auth failed for ***MASKED***" {
+		t.Errorf("Expected the password masked in the fields, got %v", entries[1])
+	}
+	if entries[2]["message"] != "New password ***MASKED***, key ***MASKED***" {
+		t.Errorf("Expected refreshed and added values masked, got %q", entries[2]["message"])
+	}
+}
+
+func TestSecretMaskerSourceFailure(t *testing.T) {
+	failing := logx.SecretSourceFunc(func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("vault sealed")
+	})
+	if _, err := logx.NewSecretMasker(logx.SecretMaskConfig{Sources: []logx.SecretSource{failing}}); err == nil {
+		t.Error("Expected an error when a source cannot be read")
+	}
+}
+
+func TestVaultSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/payments": // KV version 2
+			w.Write([]byte(`{"data":{"data":{"api_key":"sk_live_abcdef","port":5432},"metadata":{"version":3}}}`))
+		case "/v1/kv/legacy": // KV version 1
+			w.Write([]byte(`{"data":{"password":"legacy-password"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := logx.NewVaultSource(logx.VaultConfig{
+		Address: server.URL,
+		Token:   func() (string, error) { return "s.token", nil },
+		Paths:   []string{"secret/data/payments", "kv/legacy"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	values, err := source.Secrets(context.Background())
+	if err != nil {
+		t.Fatalf("Secrets failed: %v", err)
+	}
+	if strings.Join(values, ",") != "sk_live_abcdef,legacy-password" {
+		t.Errorf("Unexpected secret values: %v", values)
+	}
+
+	missing, _ := logx.NewVaultSource(logx.VaultConfig{
+		Address: server.URL,
+		Token:   func() (string, error) { return "s.token", nil },
+		Paths:   []string{"secret/data/missing"},
+	})
+	if _, err := missing.Secrets(context.Background()); err == nil {
+		t.Error("Expected an error for a missing secret")
+	}
+}
+
+func TestAWSSecretsSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("Unexpected request headers: %v", r.Header)
+		}
+		var request struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&request)
+		switch request.SecretId {
+		case "prod/db":
+			json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"username":"app","password":"db-secret-value"}`})
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"SecretString": "plain-api-token"})
+		}
+	}))
+	defer server.Close()
+
+	source, err := logx.NewAWSSecretsSource(logx.AWSSecretsConfig{
+		Region: "eu-west-1",
+		Credentials: func() (logx.AWSCredentials, error) {
+			return logx.AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		},
+		SecretIDs: []string{"prod/db", "prod/token"},
+		Endpoint:  server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	values, err := source.Secrets(context.Background())
+	if err != nil {
+		t.Fatalf("Secrets failed: %v", err)
+	}
+	if strings.Join(values, ",") != "db-secret-value,app,plain-api-token" {
+		t.Errorf("Unexpected secret values: %v", values)
+	}
+}
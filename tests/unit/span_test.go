@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// spanKey marks contexts carrying a fake span.
+type spanKey struct{}
+
+// spanRecorder collects the events added to fake spans.
+type spanRecorder struct {
+	mu     sync.Mutex
+	events map[string][]logx.SpanEvent
+}
+
+func (r *spanRecorder) add(ctx context.Context, ev logx.SpanEvent) {
+	span, ok := ctx.Value(spanKey{}).(string)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[span] = append(r.events[span], ev)
+}
+
+func TestSpanEvents(t *testing.T) {
+	recorder := &spanRecorder{events: make(map[string][]logx.SpanEvent)}
+	config := logx.DefaultConfig()
+	config.SpanEvents = recorder.add
+	logger, read := newCaptureLogger(t, config)
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "charge")
+	log := logger.Named("billing").WithContext(ctx).With(logx.String("order_id", "o-1"))
+	log.Info("Charging")
+	log.Warn("Retrying", logx.Int("attempt", 2))
+	log.Error("Charge failed", logx.ErrorField(errors.New("card declined")), logx.String("token", "abcdef"))
+	logger.Error("Unbound")
+	logger.WithContext(context.Background()).Error("No span")
+
+	if entries := read(); len(entries) != 5 {
+		t.Fatalf("Expected every entry to be written, got %v", entries)
+	}
+	events := recorder.events["charge"]
+	if len(recorder.events) != 1 || len(events) != 2 {
+		t.Fatalf("Expected the Warn and Error entries as events, got %v", recorder.events)
+	}
+	if events[0].Name != "Retrying" || events[0].Level != logx.WarnLevel || events[0].Attributes["attempt"] != int64(2) {
+		t.Errorf("Unexpected Warn event %+v", events[0])
+	}
+	attrs := events[1].Attributes
+	if attrs["error"] != "card declined" || attrs["token"] != "ab***ef" || attrs["order_id"] != "o-1" {
+		t.Errorf("Expected masked fields as attributes, got %v", attrs)
+	}
+	if attrs["log.severity"] != "ERROR" || attrs["log.logger"] != "billing" {
+		t.Errorf("Expected severity and logger attributes, got %v", attrs)
+	}
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStats(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Stats = &logx.StatsConfig{TopMessages: 3, MaxTemplates: 3}
+	logger, read := newCaptureLogger(t, config)
+
+	for i := 0; i < 5; i++ {
+		logger.Infof("Cache miss for key %d", i)
+	}
+	logger.Warn("Retry 1 of 3")
+	logger.Warn("Retry 2 of 3")
+	logger.Debug("Disabled")
+	logger.Error("Rare a")
+	logger.Error("Rare b")
+
+	stats := logger.Stats()
+	entries := read()
+	if stats.Entries != uint64(len(entries)) || stats.Entries != 9 {
+		t.Errorf("Expected 9 entries counted, got %d (%d written)", stats.Entries, len(entries))
+	}
+	if stats.Levels[logx.InfoLevel] != 5 || stats.Levels[logx.WarnLevel] != 2 || stats.Levels[logx.ErrorLevel] != 2 ||
+		stats.Levels[logx.DebugLevel] != 0 {
+		t.Errorf("Unexpected level counts: %v", stats.Levels)
+	}
+	if stats.Bytes == 0 || stats.AverageEntryBytes != float64(stats.Bytes)/9 {
+		t.Errorf("Unexpected byte counts: %d, %f", stats.Bytes, stats.AverageEntryBytes)
+	}
+	if stats.Since.IsZero() {
+		t.Error("Expected the start time")
+	}
+	top := stats.TopMessages
+	if len(top) != 3 || top[0].Template != "Cache miss for key ?" || top[0].Count != 5 || top[0].Overcount != 0 {
+		t.Fatalf("Unexpected top messages: %+v", top)
+	}
+	// "Rare b" replaced "Rare a" in the full table, inheriting its count.
+	if top[1].Template != "Rare b" || top[1].Count != 2 || top[1].Overcount != 1 ||
+		top[2].Template != "Retry ? of ?" || top[2].Level != logx.WarnLevel || top[2].Count != 2 || top[2].Overcount != 0 {
+		t.Errorf("Unexpected top messages: %+v", top)
+	}
+
+	disabled, _ := newCaptureLogger(t, logx.DefaultConfig())
+	disabled.Info("Not counted")
+	if stats := disabled.Stats(); stats.Entries != 0 || stats.TopMessages != nil {
+		t.Errorf("Expected zero stats when disabled, got %+v", stats)
+	}
+}
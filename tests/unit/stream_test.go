@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestStreamLabels(t *testing.T) {
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.OutputWriter = &buf
+	config.AddCaller = false
+	config.StreamLabels = []string{"service", "env", "shard", "token"}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.With(logx.String("service", "checkout"), logx.String("order_id", "o-1")).
+		Info("Charged", logx.String("env", "prod"), logx.Int("shard", 4), logx.String("token", "abcdef"))
+	logger.Info("No labels", logx.String("order_id", "o-2"))
+	logger.Sync()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %q", buf.String())
+	}
+	want := `"message":"Charged","labels":{"env":"prod","service":"checkout","shard":"4","token":"ab***ef"},"order_id":"o-1"}`
+	if !strings.HasSuffix(lines[0], want) {
+		t.Errorf("Expected sorted, masked labels before the other fields, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], `"labels"`) {
+		t.Errorf("Expected no labels object without labels, got %s", lines[1])
+	}
+}
@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestSubscribe(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Level = logx.DebugLevel
+	logger, _ := newCaptureLogger(t, config)
+
+	sub := logger.Subscribe(func(e *logx.Entry) bool {
+		return e.Level >= logx.WarnLevel
+	})
+	all := logger.Subscribe(nil)
+
+	child := logger.With(logx.String("request_id", "r-1")).Named("http")
+	child.Debug("ignored by filter")
+	child.Warn("slow request", logx.String("password", "hunter22"))
+
+	sub.Close()
+	sub.Close()
+	logger.Warn("after close")
+
+	var got []logx.Entry
+	for entry := range sub.C {
+		got = append(got, entry)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(got), got)
+	}
+	entry := got[0]
+	if entry.Message != "slow request" || entry.LoggerName != "http" || entry.Time.IsZero() {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if len(entry.Fields) != 2 || entry.Fields[0].Value != "r-1" {
+		t.Errorf("Expected context and call fields, got %+v", entry.Fields)
+	}
+	if entry.Fields[1].Value == "hunter22" {
+		t.Error("Expected sensitive field to be masked for subscribers")
+	}
+
+	if n := len(all.C); n != 3 {
+		t.Errorf("Expected 3 buffered entries for unfiltered subscriber, got %d", n)
+	}
+	all.Close()
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	logger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	sub := logger.Subscribe(nil)
+	defer sub.Close()
+
+	total := 5000
+	for i := 0; i < total; i++ {
+		logger.Info("flood", logx.Int("i", i))
+	}
+	if sub.Dropped() == 0 {
+		t.Fatal("Expected entries to be dropped for a slow subscriber")
+	}
+	if got := uint64(len(sub.C)) + sub.Dropped(); got != uint64(total) {
+		t.Errorf("Expected delivered+dropped == %d, got %d", total, got)
+	}
+}
+
+func TestSubscribeMasksComponentKeys(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.History = logx.HistoryConfig{MaxEntries: 10}
+	logger, _ := newCaptureLogger(t, config)
+	logger.ConfigureComponent("http", logx.ComponentConfig{SensitiveKeys: []string{"Cookie"}})
+	sub := logger.Subscribe(nil)
+
+	logger.Named("http").Info("request", logx.String("cookie", "session=abcdef"))
+	sub.Close()
+
+	var got []logx.Entry
+	for entry := range sub.C {
+		got = append(got, entry)
+	}
+	got = append(got, logger.Snapshot(0)...)
+	if len(got) != 2 {
+		t.Fatalf("Expected the entry from the subscription and the snapshot, got %+v", got)
+	}
+	for _, entry := range got {
+		if len(entry.Fields) != 1 || entry.Fields[0].Value != "se***ef" {
+			t.Errorf("Expected the component's sensitive key to be masked, got %+v", entry.Fields)
+		}
+	}
+}
+
+func TestSubscribeFilterCloses(t *testing.T) {
+	logger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	var sub *logx.Subscription
+	sub = logger.Subscribe(func(e *logx.Entry) bool {
+		sub.Close()
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		logger.Info("closes the subscription")
+		logger.Info("after close")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a filter closing its subscription not to deadlock")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Error("Expected no entries after the filter closed the subscription")
+	}
+}
@@ -0,0 +1,163 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// newSyslogLogger returns a logger writing every entry to sink only.
+func newSyslogLogger(t *testing.T, sink *logx.SyslogSink) *logx.Logger {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"syslog"}})
+	config := logx.DefaultConfig()
+	config.OutputPath = t.TempDir() + "/app.log"
+	config.AddCaller = false
+	config.AddStacktrace = false
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "syslog", Writer: sink}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestSyslogSinkRFC5424UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+	sink, err := logx.NewSyslogSink(logx.SyslogConfig{
+		Network:  "udp",
+		Address:  conn.LocalAddr().String(),
+		Facility: logx.FacilityLocal0,
+		Tag:      "checkout api",
+		Hostname: "web-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newSyslogLogger(t, sink)
+
+	logger.Error("Charge failed", logx.String("order_id", "o-1"))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	// local0 (16) * 8 + error (3) = 131
+	pattern := `^<131>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) web-1 checkout_api ` + strconv.Itoa(os.Getpid()) + ` - - \{.*"message":"Charge failed".*"order_id":"o-1".*\}$`
+	if !regexp.MustCompile(pattern).Match(buf[:n]) {
+		t.Errorf("Unexpected message %q", buf[:n])
+	}
+}
+
+func TestSyslogSinkRFC3164Unixgram(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("Unix sockets not available: %v", err)
+	}
+	defer conn.Close()
+	sink, err := logx.NewSyslogSink(logx.SyslogConfig{
+		Network:  "unixgram",
+		Address:  path,
+		Format:   logx.SyslogRFC3164,
+		Tag:      "worker",
+		Hostname: "web-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newSyslogLogger(t, sink)
+
+	logger.Warn("Queue backlog")
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	// user (1) * 8 + warning (4) = 12
+	pattern := `^<12>[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d web-1 worker\[\d+\]: \{.*"message":"Queue backlog".*\}$`
+	if !regexp.MustCompile(pattern).Match(buf[:n]) {
+		t.Errorf("Unexpected message %q", buf[:n])
+	}
+}
+
+func TestSyslogSinkTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server.TLS)
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			frame := make([]byte, n)
+			if _, err := io.ReadFull(reader, frame); err != nil {
+				return
+			}
+			received <- string(frame)
+		}
+	}()
+
+	clientTLS := server.Client().Transport.(*http.Transport).TLSClientConfig
+	sink, err := logx.NewSyslogSink(logx.SyslogConfig{Network: "tcp", Address: listener.Addr().String(), TLS: clientTLS})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newSyslogLogger(t, sink)
+	logger.Info("first")
+	logger.Info("second")
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case frame := <-received:
+			if !strings.HasPrefix(frame, "<14>1 ") || !strings.Contains(frame, `"message":"`+want+`"`) {
+				t.Errorf("Expected an octet-counted frame for %q, got %q", want, frame)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %q", want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestTimeZone(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.TimeZone = "Asia/Tokyo"
+	logger, read := newCaptureLogger(t, config)
+
+	event := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	logger.Info("Scheduled", logx.Any("run_at", event))
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if ts, _ := entries[0]["timestamp"].(string); !strings.HasSuffix(ts, "+09:00") {
+		t.Errorf("Expected timestamp in Asia/Tokyo, got %q", ts)
+	}
+	if want := "2024-05-01T09:00:00+09:00"; entries[0]["run_at"] != want {
+		t.Errorf("run_at = %v, want %q", entries[0]["run_at"], want)
+	}
+}
+
+func TestTimeZoneUTC(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.TimeZone = "UTC"
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("Tick")
+
+	entries := read()
+	if ts, _ := entries[0]["timestamp"].(string); !strings.HasSuffix(ts, "Z") {
+		t.Errorf("Expected UTC timestamp, got %q", ts)
+	}
+}
+
+func TestTimeZoneInvalid(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.TimeZone = "Mars/Olympus_Mons"
+	if _, err := logx.New(config); err == nil {
+		t.Error("Expected error for unknown time zone")
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.TimeZone = "UTC"
+	config.TimeLayout = time.RFC3339
+	logger, read := newCaptureLogger(t, config)
+
+	logger.Info("Scheduled", logx.Any("run_at", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+
+	entries := read()
+	ts, _ := entries[0]["timestamp"].(string)
+	if _, err := time.Parse(time.RFC3339, ts); err != nil || strings.Contains(ts, ".") {
+		t.Errorf("Expected an RFC3339 timestamp without fractional seconds, got %q", ts)
+	}
+	if entries[0]["run_at"] != "2024-05-01T00:00:00Z" {
+		t.Errorf("Expected Time fields in the layout, got %v", entries[0]["run_at"])
+	}
+}
+
+func TestTimeLayoutEpoch(t *testing.T) {
+	for layout, unit := range map[string]time.Duration{
+		logx.TimeLayoutEpoch:       time.Second,
+		logx.TimeLayoutEpochMillis: time.Millisecond,
+		logx.TimeLayoutEpochNanos:  time.Nanosecond,
+	} {
+		config := logx.DefaultConfig()
+		config.TimeLayout = layout
+		logger, read := newCaptureLogger(t, config)
+
+		before := time.Now()
+		logger.Info("Tick")
+		after := time.Now()
+
+		ts, ok := read()[0]["timestamp"].(float64)
+		if !ok {
+			t.Fatalf("%s: expected a numeric timestamp", layout)
+		}
+		got := time.Unix(0, int64(ts*float64(unit)))
+		// Allow for the float64 precision of large nanosecond values.
+		if got.Before(before.Add(-time.Millisecond)) || got.After(after.Add(time.Millisecond)) {
+			t.Errorf("%s: timestamp %v outside [%v, %v]", layout, got, before, after)
+		}
+	}
+}
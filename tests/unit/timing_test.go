@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestSpan(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	checkout := logger.Span(context.Background(), "checkout", logx.String("cart_id", "c1"))
+	charge := logger.Span(checkout.Context(), "charge")
+	charge.Logger().Info("Calling gateway")
+	charge.End(errors.New("card declined"))
+	charge.End(nil) // Ignored
+	checkout.End(nil)
+
+	if logx.SpanFromContext(charge.Context()) != charge || logx.SpanFromContext(context.Background()) != nil {
+		t.Error("Expected the span in its context only")
+	}
+	entries := read()
+	if len(entries) != 5 {
+		t.Fatalf("Expected 2 entries per span and 1 inside, got %d: %v", len(entries), entries)
+	}
+	started, inside, chargeEnded, checkoutEnded := entries[0], entries[2], entries[3], entries[4]
+	if started["message"] != "Span started" || started["operation"] != "checkout" || started["cart_id"] != "c1" {
+		t.Errorf("Unexpected start entry: %v", started)
+	}
+	if inside["span_id"] != charge.SpanID() || inside["parent_span_id"] != checkout.SpanID() ||
+		inside["trace_id"] != checkout.TraceID() || charge.TraceID() != checkout.TraceID() {
+		t.Errorf("Expected the child span nested in the trace, got %v", inside)
+	}
+	if chargeEnded["level"] != "ERROR" || chargeEnded["outcome"] != "error" || chargeEnded["error"] != "card declined" {
+		t.Errorf("Unexpected failed span end: %v", chargeEnded)
+	}
+	if _, ok := chargeEnded["duration_ms"].(float64); !ok {
+		t.Errorf("Expected a duration, got %v", chargeEnded)
+	}
+	if checkoutEnded["message"] != "Span ended" || checkoutEnded["level"] != "INFO" || checkoutEnded["outcome"] != "ok" ||
+		checkoutEnded["parent_span_id"] != nil {
+		t.Errorf("Unexpected root span end: %v", checkoutEnded)
+	}
+}
@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	txn := logger.Begin(logx.String("order_id", "o-1"))
+	txn.Info("Reserving stock")
+	logger.Info("Unrelated")
+	txn.Debug("Below the level")
+	func() {
+		txn.Error("Charge declined", logx.String("password", "hunter22"))
+	}()
+	if entries := read(); len(entries) != 1 {
+		t.Fatalf("Expected only the unrelated entry before Commit, got %d", len(entries))
+	}
+	txn.Commit()
+	txn.Commit()
+
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	first, second := entries[1], entries[2]
+	if first["message"] != "Reserving stock" || second["message"] != "Charge declined" ||
+		first["order_id"] != "o-1" || second["password"] == "hunter22" {
+		t.Errorf("Unexpected committed entries: %v %v", first, second)
+	}
+	if first["timestamp"].(string) > entries[0]["timestamp"].(string) {
+		t.Errorf("Expected committed entries to keep the time they were logged")
+	}
+	if caller, _ := first["caller"].(string); !strings.HasPrefix(caller, "unit/transaction_test.go:") {
+		t.Errorf("Expected the caller of the logging call, got %q", caller)
+	}
+	if stack, _ := second["stacktrace"].(string); !strings.Contains(stack, "TestTransactionCommit.func1") {
+		t.Errorf("Expected the stacktrace of the logging call, got %q", stack)
+	}
+
+	txn.Warn("Discarded")
+	txn.Discard()
+	txn.Commit()
+	if entries := read(); len(entries) != 3 {
+		t.Errorf("Expected discarded entries not to be written, got %d entries", len(entries))
+	}
+}
+
+func TestTransactionCommitIsContiguous(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				txn := logger.Begin(logx.String("txn", fmt.Sprintf("%d-%d", g, i)))
+				for step := 0; step < 4; step++ {
+					txn.Info("Step", logx.Int("step", step))
+				}
+				txn.Commit()
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				logger.Info("Noise")
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries := read()
+	if len(entries) != 8*20*4+8*50 {
+		t.Fatalf("Unexpected number of entries: %d", len(entries))
+	}
+	for i := 0; i < len(entries); i++ {
+		id, ok := entries[i]["txn"]
+		if !ok {
+			continue
+		}
+		for step := 0; step < 4; step++ {
+			e := entries[i+step]
+			if e["txn"] != id || e["step"] != float64(step) {
+				t.Fatalf("Transaction %v interleaved at entry %d: %v", id, i+step, e)
+			}
+		}
+		i += 3
+	}
+}
+
+func TestTransactionCommitEntry(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	txn := logger.Begin(logx.String("order_id", "o-1"))
+	txn.Info("Reserving stock", logx.Int("items", 3))
+	txn.Warn("Card retried", logx.String("token", "tok_abcdef"))
+	txn.CommitEntry("Order placed", logx.String("status", "ok"))
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single combined entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["level"] != "WARN" || entry["message"] != "Order placed" || entry["order_id"] != "o-1" || entry["status"] != "ok" {
+		t.Errorf("Unexpected combined entry: %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "unit/transaction_test.go:") {
+		t.Errorf("Expected the caller of CommitEntry, got %q", caller)
+	}
+	combined, _ := entry[logx.TransactionEntriesKey].([]interface{})
+	if len(combined) != 2 {
+		t.Fatalf("Expected 2 combined entries, got %v", entry[logx.TransactionEntriesKey])
+	}
+	first, second := combined[0].(map[string]interface{}), combined[1].(map[string]interface{})
+	if first["message"] != "Reserving stock" || first["level"] != "INFO" || first["items"] != float64(3) || first["timestamp"] == nil {
+		t.Errorf("Unexpected first entry: %v", first)
+	}
+	if _, ok := first["order_id"]; ok {
+		t.Errorf("Expected transaction fields only on the combined entry: %v", first)
+	}
+	if second["level"] != "WARN" || second["token"] != "to***ef" {
+		t.Errorf("Expected the sensitive field to be masked once: %v", second)
+	}
+}
+
+func TestTransactionCommitEntryRenamedKeys(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.FieldProfile = logx.ProfileECS
+	config.Keys = logx.EncoderKeys{Message: "msg"}
+	logger, read := newCaptureLogger(t, config)
+
+	txn := logger.Begin()
+	txn.Info("Reserving stock")
+	txn.CommitEntry("Order placed")
+
+	entries := read()
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single combined entry, got %d", len(entries))
+	}
+	combined, _ := entries[0][logx.TransactionEntriesKey].([]interface{})
+	if len(combined) != 1 {
+		t.Fatalf("Expected 1 combined entry, got %v", entries[0][logx.TransactionEntriesKey])
+	}
+	first := combined[0].(map[string]interface{})
+	if first["msg"] != "Reserving stock" || first["log.level"] != "INFO" || first["@timestamp"] == nil || first["log.origin.file.name"] == nil {
+		t.Errorf("Expected the envelope keys of the output, got %v", first)
+	}
+	for _, key := range []string{"timestamp", "level", "message", "caller"} {
+		if _, ok := first[key]; ok {
+			t.Errorf("Expected no %s key in the combined entry, got %v", key, first)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestVerbosity(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Level = logx.TraceLevel
+	config.Verbosity = 2
+	logger, read := newCaptureLogger(t, config)
+
+	logger.V(0).Info("v0")
+	logger.V(2).Info("v2", logx.String("cache_key", "k"))
+	logger.V(3).Info("v3")
+	if logger.V(3).Enabled() {
+		t.Error("Expected V(3) to be disabled at verbosity 2")
+	}
+	logger.SetVerbosity(5)
+	logger.Named("cache").V(5).Infof("v%d", 5)
+
+	entries := read()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %v", entries)
+	}
+	for i, want := range []float64{0, 2, 5} {
+		if entries[i]["v"] != want {
+			t.Errorf("Entry %d: expected v=%v, got %v", i, want, entries[i])
+		}
+	}
+	if entries[1]["cache_key"] != "k" || !strings.HasSuffix(entries[1]["caller"].(string), "verbosity_test.go:17") {
+		t.Errorf("Expected fields and the caller of V(2).Info, got %v", entries[1])
+	}
+}
+
+func TestVerbosityRequiresTrace(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.Verbosity = 10
+	logger, read := newCaptureLogger(t, config)
+
+	logger.V(1).Info("hidden")
+	if logger.V(0).Enabled() {
+		t.Error("Expected V(0) to be disabled at Info level")
+	}
+	if entries := read(); len(entries) != 0 {
+		t.Errorf("Expected no entries, got %v", entries)
+	}
+}
@@ -0,0 +1,214 @@
+//go:build !logx_minimal
+
+package unit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+// newWebhookLogger returns a logger writing every entry to sink only.
+func newWebhookLogger(t *testing.T, sink *logx.WebhookSink) *logx.Logger {
+	router, _ := logx.NewRouter(logx.RouteRule{Sinks: []string{"webhook"}})
+	config := logx.DefaultConfig()
+	config.OutputPath = t.TempDir() + "/app.log"
+	config.AddCaller = false
+	config.Processors = []logx.Processor{router}
+	config.Sinks = []logx.SinkConfig{{Name: "webhook", Writer: sink}}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestWebhookSinkRetriesAndCompresses(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var batches [][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "gzip" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("Expected a gzipped body: %v", err)
+		}
+		var batch []map[string]interface{}
+		if err := json.NewDecoder(zr).Decode(&batch); err != nil {
+			t.Errorf("Expected a JSON array: %v", err)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	sink, err := logx.NewWebhookSink(logx.WebhookConfig{
+		URL:        server.URL,
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+		Compress:   true,
+		MaxEntries: 2,
+		Backoff:    time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newWebhookLogger(t, sink)
+
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatalf("Expected batches of 2 and 1 entries after a retry, got %v", batches)
+	}
+	if batches[0][0]["message"] != "first" || batches[1][0]["message"] != "third" {
+		t.Errorf("Expected the entries in order, got %v", batches)
+	}
+	if sink.Dropped() != 0 {
+		t.Errorf("Expected no dropped entries, got %d", sink.Dropped())
+	}
+}
+
+func TestWebhookSinkDropsWhenDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var errs []error
+	sink, err := logx.NewWebhookSink(logx.WebhookConfig{
+		URL:           server.URL,
+		MaxEntries:    10,
+		BufferSize:    10,
+		FlushInterval: time.Hour,
+		DropPolicy:    logx.DropNewest,
+		OnError:       func(err error) { errs = append(errs, err) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+
+	sink.Write([]byte(strings.Repeat(`{"message":"entry"}`+"\n", 12)))
+	if err := sink.Sync(); err == nil {
+		t.Error("Expected Sync to report the rejected batch")
+	}
+	if sink.Dropped() != 12 {
+		t.Errorf("Expected 2 entries dropped from the buffer and 10 rejected, got %d", sink.Dropped())
+	}
+	if len(errs) == 0 {
+		t.Error("Expected OnError to be called")
+	}
+	if _, err := logx.NewWebhookSink(logx.WebhookConfig{}); err == nil {
+		t.Error("Expected an error without a URL")
+	}
+}
+
+func TestWebhookSinkDeadLetters(t *testing.T) {
+	var mu sync.Mutex
+	failing := true
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var batch []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&batch)
+		for _, entry := range batch {
+			received = append(received, entry["message"].(string))
+		}
+	}))
+	defer server.Close()
+
+	deadLetterPath := t.TempDir() + "/webhook.dead"
+	sink, err := logx.NewWebhookSink(logx.WebhookConfig{
+		URL:            server.URL,
+		FlushInterval:  time.Hour,
+		DeadLetterPath: deadLetterPath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	defer sink.Close()
+	logger := newWebhookLogger(t, sink)
+
+	logger.Info("First")
+	logger.Info("Second")
+	if err := sink.Sync(); err == nil {
+		t.Error("Expected Sync to report the rejected batch")
+	}
+	data, err := os.ReadFile(deadLetterPath)
+	if err != nil || strings.Count(string(data), "\n") != 2 || sink.Dropped() != 0 {
+		t.Fatalf("Expected the rejected entries in the dead-letter file, got %q (%v), %d dropped", data, err, sink.Dropped())
+	}
+
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	n, err := logx.ReplayDeadLetters(deadLetterPath, sink)
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 entries replayed, got %d: %v", n, err)
+	}
+	if err := sink.Sync(); err != nil {
+		t.Fatalf("Failed to send the replayed entries: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(received, ",") != "First,Second" {
+		t.Errorf("Unexpected replayed entries: %v", received)
+	}
+	if _, err := os.Stat(deadLetterPath + ".replay"); !os.IsNotExist(err) {
+		t.Errorf("Expected the replayed file to be removed")
+	}
+	if n, err := logx.ReplayDeadLetters(deadLetterPath, sink); n != 0 || err != nil {
+		t.Errorf("Expected nothing to replay, got %d: %v", n, err)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("endpoint down")
+}
+
+func TestReplayDeadLettersKeepsUnsentEntries(t *testing.T) {
+	deadLetterPath := t.TempDir() + "/api.dead"
+	os.WriteFile(deadLetterPath, []byte("{\"message\":\"a\"}\n{\"message\":\"b\"}\n"), 0644)
+
+	if n, err := logx.ReplayDeadLetters(deadLetterPath, failingWriter{}); n != 0 || err == nil {
+		t.Fatalf("Expected the replay to fail, got %d: %v", n, err)
+	}
+	var buf bytes.Buffer
+	n, err := logx.ReplayDeadLetters(deadLetterPath, &buf)
+	if err != nil || n != 2 || buf.String() != "{\"message\":\"a\"}\n{\"message\":\"b\"}\n" {
+		t.Errorf("Expected the kept entries to be replayed, got %d %q: %v", n, buf.String(), err)
+	}
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestWithOverridesInheritedField(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	anonymous := logger.With(logx.String("user_id", "anon"), logx.String("request_id", "r1"))
+	authenticated := anonymous.With(logx.String("user_id", "42"))
+	authenticated.Info("after auth")
+	anonymous.Info("before auth")
+
+	entries := read()
+	if entries[0]["user_id"] != "42" || entries[0]["request_id"] != "r1" {
+		t.Errorf("Expected user_id to be replaced, got %v", entries[0])
+	}
+	if entries[1]["user_id"] != "anon" {
+		t.Errorf("Expected the parent to be unchanged, got %v", entries[1])
+	}
+}
+
+func TestWithout(t *testing.T) {
+	logger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	parent := logger.With(logx.String("session_id", "s1"), logx.String("user_id", "42"), logx.String("request_id", "r1"))
+	parent.Without("session_id", "user_id").Info("logged out")
+	parent.Info("still logged in")
+
+	entries := read()
+	if _, ok := entries[0]["session_id"]; ok || entries[0]["user_id"] != nil || entries[0]["request_id"] != "r1" {
+		t.Errorf("Expected session_id and user_id to be removed, got %v", entries[0])
+	}
+	if entries[1]["session_id"] != "s1" {
+		t.Errorf("Expected the parent to be unchanged, got %v", entries[1])
+	}
+}
+
+func TestFieldsAndWithFieldsFrom(t *testing.T) {
+	reqLogger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	poolLogger, read := newCaptureLogger(t, logx.DefaultConfig())
+
+	req := reqLogger.With(logx.String("request_id", "r1"), logx.String("user_id", "42"))
+	fields := req.Fields()
+	if len(fields) != 2 || fields[0].Key != "request_id" || fields[1].Value != "42" {
+		t.Fatalf("Expected the context fields in order, got %v", fields)
+	}
+	fields[0].Value = "changed"
+	if req.Fields()[0].Value != "r1" {
+		t.Error("Expected Fields to return a copy")
+	}
+
+	worker := poolLogger.Named("pool").With(logx.String("user_id", "system"), logx.Int("worker", 3))
+	worker.WithFieldsFrom(req).Info("Job done")
+	worker.WithFieldsFrom(nil).Info("No request")
+
+	entries := read()
+	if len(entries) != 2 {
+		t.Fatalf("Expected both entries in the pool's output, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["request_id"] != "r1" || entry["user_id"] != "42" || entry["worker"] != float64(3) || entry["logger"] != "pool" {
+		t.Errorf("Expected the request fields on the pool logger, got %v", entry)
+	}
+	if entries[1]["user_id"] != "system" {
+		t.Errorf("Expected the pool logger unchanged, got %v", entries[1])
+	}
+}
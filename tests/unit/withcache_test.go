@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestWithCache(t *testing.T) {
+	config := logx.DefaultConfig()
+	config.WithCacheSize = 2
+	logger, read := newCaptureLogger(t, config)
+
+	a := logger.With(logx.String("route", "/users"), logx.Int("shard", 1))
+	if b := logger.With(logx.String("route", "/users"), logx.Int("shard", 1)); a != b {
+		t.Error("Expected identical fields to return the cached child")
+	}
+	if c := logger.With(logx.String("route", "/users"), logx.Int64("shard", 1)); c == a {
+		t.Error("Expected fields with different value types to create a new child")
+	}
+	if d := logger.Named("api").With(logx.String("route", "/users"), logx.Int("shard", 1)); d == a {
+		t.Error("Expected a different parent to create a new child")
+	}
+	if e := logger.With(logx.Any("tags", []string{"a"})); e == logger.With(logx.Any("tags", []string{"a"})) {
+		t.Error("Expected non-scalar fields to bypass the cache")
+	}
+
+	// a was evicted by the two newer entries.
+	if logger.With(logx.String("route", "/users"), logx.Int("shard", 1)) == a {
+		t.Error("Expected least recently used child to be evicted")
+	}
+
+	a.Info("served")
+	entries := read()
+	if len(entries) != 1 || entries[0]["route"] != "/users" || entries[0]["shard"] != float64(1) {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+}
+
+func TestWithCacheDisabled(t *testing.T) {
+	logger, _ := newCaptureLogger(t, logx.DefaultConfig())
+	if logger.With(logx.String("k", "v")) == logger.With(logx.String("k", "v")) {
+		t.Error("Expected a new child on every call without a cache")
+	}
+}
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logx "github.com/seasbee/go-logx"
+)
+
+func TestOutputWriter(t *testing.T) {
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.OutputWriter = &buf
+	config.Banner = &logx.BannerConfig{}
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("to the buffer", logx.Int("n", 1))
+
+	entries := decodeLines(t, &buf)
+	if len(entries) != 2 || entries[1]["message"] != "to the buffer" {
+		t.Fatalf("Expected the banner and the entry in the buffer, got %v", entries)
+	}
+	if summary := entries[0]["config"].(map[string]interface{}); summary["output"] != "writer" {
+		t.Errorf("Expected the banner to report the writer output, got %v", summary)
+	}
+}
+
+func TestOutputWriterDevelopment(t *testing.T) {
+	var buf bytes.Buffer
+	config := logx.DefaultConfig()
+	config.Development = true
+	config.OutputWriter = &buf
+	logger, err := logx.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("console line")
+	if out := buf.String(); !strings.Contains(out, "console line") || strings.HasPrefix(out, "{") {
+		t.Errorf("Expected console output in the writer, got %q", out)
+	}
+}
+
+func TestSplitStdStreams(t *testing.T) {
+	dir := t.TempDir()
+	stdout, _ := os.Create(filepath.Join(dir, "stdout"))
+	stderr, _ := os.Create(filepath.Join(dir, "stderr"))
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	config := logx.DefaultConfig()
+	config.Level = logx.DebugLevel
+	config.SplitStdStreams = true
+	logger, err := logx.New(config)
+	os.Stdout, os.Stderr = origStdout, origStderr
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Debug("debug")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.SetLevel(logx.WarnLevel)
+	logger.Info("filtered")
+	stdout.Close()
+	stderr.Close()
+
+	var messages [2][]string
+	for i, path := range []string{stdout.Name(), stderr.Name()} {
+		for _, entry := range readJSONFile(t, path) {
+			messages[i] = append(messages[i], entry["message"].(string))
+		}
+	}
+	if strings.Join(messages[0], ",") != "debug,warn" || strings.Join(messages[1], ",") != "error" {
+		t.Errorf("Expected debug,warn on stdout and error on stderr, got %v", messages)
+	}
+}
@@ -0,0 +1,148 @@
+package logx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// Keys of the fields written by Span, in addition to KeyOperation,
+// KeyTraceID, KeySpanID, KeyDurationMS and KeyError.
+const (
+	KeyParentSpanID = "parent_span_id" // Span ID of the enclosing span
+	KeyOutcome      = "outcome"        // "ok" or "error"
+)
+
+// Span outcomes.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// spanContextKey is the context key of the current Span.
+type spanContextKey struct{}
+
+// Span is a timed unit of work started by Logger.Span. It writes a "Span
+// started" entry when it starts and a "Span ended" entry, with its
+// duration and outcome, when End is called. Spans nest through their
+// context, sharing a trace ID, which gives teams without a tracer request
+// timings and call trees from their logs alone.
+type Span struct {
+	logger   *Logger // The logger of the span, with its identifying fields
+	ctx      context.Context
+	name     string
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+	ended    atomic.Bool
+}
+
+// Span starts a span named name, a child of the span in ctx if there is
+// one, and writes a "Span started" Info entry. The entries of the span and
+// of Span.Logger carry the operation name, trace ID and span ID, plus the
+// given fields. Pass Span.Context to the work inside the span so that
+// spans started there nest under it, and call End when the work is done.
+//
+// Example:
+//
+//	func (s *Service) Checkout(ctx context.Context, cart *Cart) (err error) {
+//	    span := s.logger.Span(ctx, "checkout", logx.Int("items", len(cart.Items)))
+//	    defer func() { span.End(err) }()
+//	    return s.charge(span.Context(), cart) // may start a "charge" child span
+//	}
+//	// {"level":"INFO","message":"Span ended","operation":"checkout","trace_id":"...","span_id":"...","duration_ms":182,"outcome":"ok",...}
+func (l *Logger) Span(ctx context.Context, name string, fields ...Field) *Span {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s := &Span{name: name, spanID: randomHex(8), start: time.Now()}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		s.traceID = randomHex(16)
+	}
+	s.ctx = context.WithValue(ctx, spanContextKey{}, s)
+
+	spanFields := []Field{Operation(name), TraceID(s.traceID), SpanID(s.spanID)}
+	if s.parentID != "" {
+		spanFields = append(spanFields, String(KeyParentSpanID, s.parentID))
+	}
+	s.logger = l.WithContext(s.ctx).With(append(spanFields, fields...)...)
+	s.logger.emitSpan(InfoLevel, "Span started", nil)
+	return s
+}
+
+// SpanFromContext returns the span started by Logger.Span that ctx belongs
+// to, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanContextKey{}).(*Span)
+	return s
+}
+
+// Context returns a context carrying the span, for the work done inside
+// it.
+func (s *Span) Context() context.Context {
+	return s.ctx
+}
+
+// Logger returns a logger whose entries carry the span's fields.
+func (s *Span) Logger() *Logger {
+	return s.logger
+}
+
+// TraceID returns the trace ID shared by the span and the spans around it.
+func (s *Span) TraceID() string {
+	return s.traceID
+}
+
+// SpanID returns the ID of the span.
+func (s *Span) SpanID() string {
+	return s.spanID
+}
+
+// End writes a "Span ended" entry with the span's duration and outcome: an
+// Info entry with outcome "ok" if err is nil, an Error entry with outcome
+// "error" and the error otherwise. It returns the duration. Calls after
+// the first do nothing but return the duration so far.
+func (s *Span) End(err error, fields ...Field) time.Duration {
+	elapsed := time.Since(s.start)
+	if !s.ended.CompareAndSwap(false, true) {
+		return elapsed
+	}
+	level, outcome := InfoLevel, OutcomeOK
+	if err != nil {
+		level, outcome = ErrorLevel, OutcomeError
+	}
+	fields = append([]Field{DurationMS(elapsed), String(KeyOutcome, outcome)}, fields...)
+	if err != nil {
+		fields = append(fields, ErrorField(err))
+	}
+	s.logger.emitSpan(level, "Span ended", fields)
+	return elapsed
+}
+
+// emitSpan writes a span entry with the logger's fields, if level is
+// enabled. The entry has no caller, as it would point into Span.
+func (l *Logger) emitSpan(level Level, msg string, fields []Field) {
+	if !l.enabled(level) {
+		return
+	}
+	l.mu.RLock()
+	all := append(append([]Field(nil), l.fields...), fields...)
+	l.mu.RUnlock()
+	l.emit(&Entry{Time: time.Now(), Level: level, LoggerName: l.name, Message: msg, Fields: all})
+}
+
+// randomHex returns n random bytes, hex-encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,250 @@
+package logx
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TransactionEntriesKey is the field key of the entries combined by
+// Transaction.CommitEntry.
+const TransactionEntriesKey = "entries"
+
+// Transaction is a logger holding its entries until Commit writes them
+// together, so that the lines of a multi-step operation are not
+// interleaved with the entries written concurrently by other goroutines.
+// It is created by Logger.Begin and embeds a Logger: every logging method,
+// and the loggers derived from it through With or Named, add to the
+// transaction. Entries keep the time, caller and stacktrace of the call
+// that logged them; levels, processors and samplers apply when they are
+// logged, and entries that would not be written are not held.
+//
+// A Fatal entry commits the transaction before it is written. A
+// transaction is safe for concurrent use and can be committed more than
+// once: each commit writes the entries logged since the previous one.
+type Transaction struct {
+	*Logger
+	mu      sync.Mutex
+	records []transactionRecord
+}
+
+// transactionRecord is an entry held by a transaction, with what the write
+// path needs to write it later.
+type transactionRecord struct {
+	targets []*zap.Logger
+	level   Level
+	msg     string
+	fields  []Field
+	entry   *Entry
+	name    string
+	checked zapcore.Entry // Time, caller and stacktrace recorded when logged
+}
+
+// Begin starts a transaction: a logger with the given fields added, as
+// With does, whose entries are held until Commit writes them as one
+// contiguous block, or CommitEntry as a single entry.
+//
+// Example:
+//
+//	txn := logger.Begin(logx.String("order_id", id))
+//	txn.Info("Reserving stock")
+//	txn.Info("Charging card", logx.String("amount", amount))
+//	txn.Info("Order placed")
+//	txn.Commit() // the three lines are written back to back
+func (l *Logger) Begin(fields ...Field) *Transaction {
+	t := &Transaction{}
+	t.Logger = l.with(fields)
+	t.Logger.txn = t
+	return t
+}
+
+// add holds an entry logged on the transaction, unless no target would
+// write it.
+func (t *Transaction) add(targets []*zap.Logger, level Level, msg string, fields []Field, entry *Entry, name string) {
+	checked, ok := t.check(targets, level, msg)
+	if !ok {
+		return
+	}
+	if entry != nil {
+		entry = entry.Clone()
+	} else {
+		entry = &Entry{Time: checked.Time, Level: level, LoggerName: name, Message: msg, Fields: fields}
+		if checked.Caller.Defined {
+			entry.Caller = checked.Caller.TrimmedPath()
+		}
+	}
+	t.mu.Lock()
+	t.records = append(t.records, transactionRecord{
+		targets: targets,
+		level:   level,
+		msg:     msg,
+		fields:  entry.Fields,
+		entry:   entry,
+		name:    name,
+		checked: checked,
+	})
+	t.mu.Unlock()
+}
+
+// check returns the zap entry of the first target accepting an entry,
+// with the time, caller and stacktrace that writing it now would record.
+// Like Logger.writeLocked, it is called three frames below the public
+// logging method.
+func (t *Transaction) check(targets []*zap.Logger, level Level, msg string) (zapcore.Entry, bool) {
+	for _, zl := range targets {
+		if ce := zl.Check(toZapLevel(level), msg); ce != nil {
+			return ce.Entry, true
+		}
+	}
+	return zapcore.Entry{}, false
+}
+
+// take removes and returns the entries held.
+func (t *Transaction) take() []transactionRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	records := t.records
+	t.records = nil
+	return records
+}
+
+// Commit writes the entries held, in the order they were logged, with no
+// entry of another logger sharing the outputs in between.
+func (t *Transaction) Commit() {
+	records := t.take()
+	if len(records) == 0 {
+		return
+	}
+	l := t.Logger
+	// Hold off every other write, and Reload, until the block is written.
+	l.shared.reload.Lock()
+	defer l.shared.reload.Unlock()
+	for i := range records {
+		r := &records[i]
+		l.writeLocked(r.targets, r.level, r.msg, r.fields, r.entry, r.name, &r.checked)
+	}
+}
+
+// CommitEntry writes the entries held as a single entry with the given
+// message and fields, at the highest level among them, to every output
+// one of them was routed to. The entries are written in order under
+// TransactionEntriesKey, each with its timestamp, level, message, caller
+// and fields other than those of the transaction, which the combined
+// entry carries once. Entries are not run through the processors again.
+//
+// Example:
+//
+//	txn.CommitEntry("Order placed", logx.DurationMS(time.Since(start)))
+//	// {"level":"INFO","message":"Order placed","order_id":"o-1","duration_ms":1200,"entries":[
+//	//   {"timestamp":"...","level":"INFO","message":"Reserving stock"}, ...]}
+func (t *Transaction) CommitEntry(msg string, fields ...Field) {
+	records := t.take()
+	if len(records) == 0 {
+		return
+	}
+	l := t.Logger
+	base := l.Fields()
+	inherited := make(map[string]bool, len(base))
+	for _, field := range base {
+		inherited[field.Key] = true
+	}
+
+	level := records[0].level
+	keys := l.shared.keys.Load()
+	var targets []*zap.Logger
+	entries := make(transactionEntries, len(records))
+	for i, r := range records {
+		if r.level > level {
+			level = r.level
+		}
+		targets = appendTargets(targets, r.targets)
+		own := make([]Field, 0, len(r.fields))
+		for _, field := range r.fields {
+			if !inherited[field.Key] {
+				own = append(own, field)
+			}
+		}
+		entries[i] = transactionEntry{checked: r.checked, level: r.level, msg: r.msg, fields: l.convertFields(l.protectFields(own, r.name), r.name), keys: keys}
+		if l.shared.sanitize {
+			entries[i].msg = sanitizeString(r.msg)
+		}
+	}
+
+	allFields := append(base, fields...)
+	allFields = append(allFields, Field{Key: TransactionEntriesKey, Value: entries})
+	checked := zapcore.Entry{Time: time.Now()}
+	if l.shared.addCaller {
+		checked.Caller = zapcore.NewEntryCaller(runtime.Caller(1))
+	}
+	entry := &Entry{Time: checked.Time, Level: level, LoggerName: l.name, Message: msg, Fields: allFields}
+	if checked.Caller.Defined {
+		entry.Caller = checked.Caller.TrimmedPath()
+	}
+	l.shared.reload.RLock()
+	defer l.shared.reload.RUnlock()
+	l.writeLocked(targets, level, msg, allFields, entry, l.name, &checked)
+}
+
+// Discard drops the entries held without writing them.
+func (t *Transaction) Discard() {
+	t.take()
+}
+
+// appendTargets appends the targets not already in list.
+func appendTargets(list, targets []*zap.Logger) []*zap.Logger {
+	for _, target := range targets {
+		found := false
+		for _, t := range list {
+			if t == target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, target)
+		}
+	}
+	return list
+}
+
+// transactionEntries are the entries combined by CommitEntry. Their fields
+// are converted when committed, so marshalerValue does not mask them again.
+type transactionEntries []transactionEntry
+
+// transactionEntry is an entry combined by CommitEntry, written with the
+// envelope keys of the logger.
+type transactionEntry struct {
+	checked zapcore.Entry
+	level   Level
+	msg     string
+	fields  []zap.Field
+	keys    *entryKeys
+}
+
+func (e transactionEntries) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, entry := range e {
+		if err := enc.AppendObject(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e transactionEntry) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddTime(e.keys.time, e.checked.Time)
+	enc.AddString(e.keys.level, e.keys.levelName(e.level))
+	enc.AddString(e.keys.message, e.msg)
+	if e.checked.Caller.Defined {
+		enc.AddString(e.keys.caller, e.checked.Caller.TrimmedPath())
+	}
+	if e.checked.Stack != "" {
+		enc.AddString(e.keys.stacktrace, e.checked.Stack)
+	}
+	for _, field := range e.fields {
+		field.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package logx
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// EventIDKey is the field key used for event IDs when Config.EventID is set.
+const EventIDKey = "event_id"
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGenerator produces monotonic ULIDs: IDs generated within the same
+// millisecond increment the random component, so they sort in creation order.
+type ulidGenerator struct {
+	mu      sync.Mutex
+	lastMS  uint64
+	entropy [10]byte
+}
+
+// eventIDs is the process-wide ULID generator.
+var eventIDs ulidGenerator
+
+// next returns a new ULID for the given time.
+func (g *ulidGenerator) next(t time.Time) string {
+	ms := uint64(t.UnixMilli())
+
+	g.mu.Lock()
+	if ms > g.lastMS {
+		g.lastMS = ms
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			// crypto/rand never fails on supported platforms; fall back
+			// to the previous entropy rather than panicking in a logger.
+			g.increment()
+		}
+	} else {
+		// Same millisecond (or clock went backwards): stay monotonic.
+		ms = g.lastMS
+		g.increment()
+	}
+	var id [16]byte
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	copy(id[6:], g.entropy[:])
+	g.mu.Unlock()
+
+	return encodeULID(id)
+}
+
+// increment adds one to the 80-bit random component. g.mu must be held.
+func (g *ulidGenerator) increment() {
+	for i := len(g.entropy) - 1; i >= 0; i-- {
+		g.entropy[i]++
+		if g.entropy[i] != 0 {
+			return
+		}
+	}
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters.
+func encodeULID(id [16]byte) string {
+	var out [26]byte
+	// 130 bits of output for 128 bits of input: the first character holds
+	// the top 3 bits, every following character holds 5 bits.
+	out[0] = crockfordAlphabet[id[0]>>5]
+	acc := uint32(id[0]) & 0x1f
+	bits := uint(5)
+	pos := 1
+	for i := 1; i < len(id); i++ {
+		acc = acc<<8 | uint32(id[i])
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockfordAlphabet[(acc>>bits)&0x1f]
+			pos++
+		}
+	}
+	return string(out[:])
+}
+
+// NewEventID returns a new ULID: a 26-character, time-sortable, unique
+// identifier. It is the same generator used to stamp entries when
+// Config.EventID is enabled, and can be used to correlate other records
+// with log entries.
+func NewEventID() string {
+	return eventIDs.next(time.Now())
+}
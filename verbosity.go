@@ -0,0 +1,83 @@
+package logx
+
+import "fmt"
+
+// VerbosityKey is the field key recording the verbosity of entries written
+// through V.
+const VerbosityKey = "v"
+
+// Verbose writes Trace entries at a verbosity level, in the style of klog
+// and logr, so that code ported from them keeps its graduated verbosity
+// beneath Trace. It is returned by Logger.V and V.
+type Verbose struct {
+	logger  *Logger
+	level   int
+	enabled bool
+}
+
+// V returns a Verbose for the given verbosity. Its entries are written at
+// TraceLevel with a "v" field holding the verbosity, when Trace entries are
+// enabled and level does not exceed the logger's verbosity (see
+// Config.Verbosity and SetVerbosity). V(0) is therefore equivalent to
+// Trace. Check Enabled before building expensive fields.
+//
+// Example:
+//
+//	logger.V(2).Info("Cache lookup", logx.String("key", key))
+//	if v := logger.V(4); v.Enabled() {
+//	    v.Info("Cache contents", logx.Any("entries", cache.Dump()))
+//	}
+func (l *Logger) V(level int) Verbose {
+	enabled := level <= int(l.shared.verbosity.Load()) && l.enabled(TraceLevel)
+	return Verbose{logger: l, level: level, enabled: enabled}
+}
+
+// Enabled reports whether entries at this verbosity are written.
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+// Info writes a Trace entry at this verbosity if it is enabled.
+func (v Verbose) Info(msg string, fields ...Field) {
+	if !v.enabled {
+		return
+	}
+	v.logger.log(TraceLevel, msg, append(fields[:len(fields):len(fields)], Int(VerbosityKey, v.level)))
+}
+
+// Infof writes a formatted Trace entry at this verbosity if it is enabled.
+// The arguments are not formatted when it is disabled.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	v.logger.log(TraceLevel, fmt.Sprintf(format, args...), []Field{Int(VerbosityKey, v.level)})
+}
+
+// SetVerbosity changes the highest verbosity written through V at runtime,
+// for the logger and every logger sharing its state.
+func (l *Logger) SetVerbosity(verbosity int) {
+	l.shared.verbosity.Store(int32(verbosity))
+}
+
+// GetVerbosity returns the highest verbosity written through V.
+func (l *Logger) GetVerbosity() int {
+	return int(l.shared.verbosity.Load())
+}
+
+// V returns a Verbose for the default logger; see Logger.V. If the default
+// logger is not initialized, the Verbose is disabled.
+func V(level int) Verbose {
+	if defaultLogger == nil {
+		return Verbose{}
+	}
+	return defaultLogger.V(level)
+}
+
+// SetVerbosity changes the highest verbosity written through V by the
+// default logger.
+func SetVerbosity(verbosity int) {
+	if defaultLogger != nil {
+		defaultLogger.SetVerbosity(verbosity)
+	}
+}
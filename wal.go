@@ -0,0 +1,214 @@
+package logx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// walMagic identifies a write-ahead log file.
+const walMagic = "LGXWAL1\n"
+
+// Layout of a write-ahead log record. Records follow the magic back to back
+// and the first record with a zero length ends the log:
+//
+//	length  uint32  Length of the payload, written last to commit the record
+//	state   uint8   walPending or walDone
+//	keyLen  uint8   Length of the output key
+//	        [2]byte Reserved
+//	key     [keyLen]byte
+//	data    [length-keyLen]byte
+const (
+	walHeaderSize = 8
+	walPending    = 1
+	walDone       = 2
+)
+
+// walStore is the storage backing a write-ahead log: a byte slice mirroring
+// the file. Changes to the slice are persisted with persist, which is a
+// no-op when the slice is a shared memory mapping.
+type walStore interface {
+	bytes() []byte
+	persist(off, n int) error
+	close() error
+}
+
+// walRecord is a pending entry recovered from a write-ahead log.
+type walRecord struct {
+	key  string
+	data []byte
+}
+
+// wal journals queued asynchronous writes so that entries accepted before a
+// crash can be written on the next startup. Space is reused once every
+// journaled entry has been written; entries that do not fit are queued
+// without being journaled.
+type wal struct {
+	mu      sync.Mutex
+	store   walStore
+	buf     []byte
+	end     int // Offset of the terminating zero length
+	pending int // Journaled entries not yet written
+}
+
+// openWAL opens or creates the write-ahead log at path with at least size
+// bytes, and returns it with the entries left pending by a previous process.
+// Those entries stay in the file until discard is called, so that they are
+// not lost if the process crashes again before writing them.
+func openWAL(path string, size int) (*wal, []walRecord, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	if info.Size() > int64(size) {
+		// Never shrink an existing log, it may hold pending entries
+		size = int(info.Size())
+	} else if err := file.Truncate(int64(size)); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to size write-ahead log: %w", err)
+	}
+	store, err := newWALStore(file, size)
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to map write-ahead log: %w", err)
+	}
+
+	w := &wal{store: store, buf: store.bytes()}
+	magic := w.buf[:len(walMagic)]
+	switch {
+	case string(magic) == walMagic:
+	case isZero(magic):
+		copy(magic, walMagic)
+		if err := store.persist(0, len(walMagic)); err != nil {
+			store.close()
+			return nil, nil, err
+		}
+	default:
+		store.close()
+		return nil, nil, fmt.Errorf("%s is not a write-ahead log", path)
+	}
+
+	records, end := w.pendingRecords()
+	w.end = end
+	return w, records, nil
+}
+
+// isZero reports whether b contains only zero bytes.
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingRecords returns the records that were journaled but not written,
+// in order, and the offset at which the log ends. A torn record at the end
+// of the log is ignored.
+func (w *wal) pendingRecords() ([]walRecord, int) {
+	var records []walRecord
+	off := len(walMagic)
+	for off+walHeaderSize <= len(w.buf) {
+		length := int(binary.BigEndian.Uint32(w.buf[off:]))
+		if length == 0 || off+walHeaderSize+length > len(w.buf) {
+			break
+		}
+		state, keyLen := w.buf[off+4], int(w.buf[off+5])
+		payload := w.buf[off+walHeaderSize : off+walHeaderSize+length]
+		if state == walPending && keyLen <= length {
+			records = append(records, walRecord{
+				key:  string(payload[:keyLen]),
+				data: append([]byte(nil), payload[keyLen:]...),
+			})
+		}
+		off += walHeaderSize + length
+	}
+	return records, off
+}
+
+// discard drops the records left by a previous process once they have been
+// written, unless entries journaled since are still pending.
+func (w *wal) discard() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == 0 {
+		w.reset()
+	}
+}
+
+// reset discards every record. It is only called when none are pending.
+func (w *wal) reset() {
+	w.end = len(walMagic)
+	w.pending = 0
+	w.writeTerminator()
+}
+
+// writeTerminator writes the zero length that ends the log at w.end.
+func (w *wal) writeTerminator() {
+	if w.end+4 > len(w.buf) {
+		return
+	}
+	binary.BigEndian.PutUint32(w.buf[w.end:], 0)
+	w.store.persist(w.end, 4)
+}
+
+// append journals data for the output identified by key and returns the
+// record offset, or -1 if the record does not fit.
+func (w *wal) append(key string, data []byte) int {
+	if len(key) > 255 {
+		return -1
+	}
+	length := len(key) + len(data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	off := w.end
+	next := off + walHeaderSize + length
+	if next+4 > len(w.buf) {
+		return -1
+	}
+
+	// Write the payload and the new terminator before committing the
+	// record with its length, so that a crash never exposes a torn record.
+	record := w.buf[off:next]
+	record[4], record[5], record[6], record[7] = walPending, byte(len(key)), 0, 0
+	copy(record[walHeaderSize:], key)
+	copy(record[walHeaderSize+len(key):], data)
+	w.end = next
+	w.writeTerminator()
+	w.store.persist(off+4, len(record)-4)
+	binary.BigEndian.PutUint32(record, uint32(length))
+	w.store.persist(off, 4)
+	w.pending++
+	return off
+}
+
+// done marks the record at off as written. Once no records are pending,
+// the space is reused.
+func (w *wal) done(off int) {
+	if off < 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf[off+4] = walDone
+	w.store.persist(off+4, 1)
+	w.pending--
+	if w.pending == 0 {
+		w.reset()
+	}
+}
+
+// close releases the log. Pending records remain in the file.
+func (w *wal) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.store.close()
+}
@@ -0,0 +1,33 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logx
+
+import (
+	"io"
+	"os"
+)
+
+// fileStore keeps the write-ahead log in memory and writes every change
+// through to the file, on platforms without a memory mapping.
+type fileStore struct {
+	file *os.File
+	data []byte
+}
+
+// newWALStore reads size bytes of file.
+func newWALStore(file *os.File, size int) (walStore, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(io.NewSectionReader(file, 0, int64(size)), data); err != nil {
+		return nil, err
+	}
+	return &fileStore{file: file, data: data}, nil
+}
+
+func (s *fileStore) bytes() []byte { return s.data }
+
+func (s *fileStore) persist(off, n int) error {
+	_, err := s.file.WriteAt(s.data[off:off+n], int64(off))
+	return err
+}
+
+func (s *fileStore) close() error { return s.file.Close() }
@@ -0,0 +1,407 @@
+//go:build !logx_minimal
+
+package logx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WebhookDropPolicy selects which entries a WebhookSink drops when its
+// buffer is full.
+type WebhookDropPolicy int
+
+// Webhook drop policies.
+const (
+	DropOldest WebhookDropPolicy = iota // Drop the oldest buffered entries
+	DropNewest                          // Drop the entries being written
+)
+
+// WebhookConfig configures a sink posting batches of entries to an HTTP
+// endpoint.
+type WebhookConfig struct {
+	// URL receives the batches. Required.
+	URL string
+
+	// Headers are added to every request, e.g. for authentication.
+	// Default: nil
+	Headers map[string]string
+
+	// Compress gzips the request bodies.
+	// Default: false
+	Compress bool
+
+	// MaxEntries is the number of entries that triggers a send and the
+	// largest batch sent.
+	// Default: 100
+	MaxEntries int
+
+	// FlushInterval is how often buffered entries are sent when fewer than
+	// MaxEntries are buffered.
+	// Default: 5s
+	FlushInterval time.Duration
+
+	// BufferSize bounds the entries buffered while the endpoint is slow or
+	// down. Beyond it, entries are dropped following DropPolicy, so that
+	// logging never blocks on the endpoint.
+	// Default: 10000
+	BufferSize int
+
+	// DropPolicy selects the entries dropped when the buffer is full.
+	// Default: DropOldest
+	DropPolicy WebhookDropPolicy
+
+	// MaxRetries is the number of times a failed batch is retried, with
+	// exponential backoff, before it is dropped. Network errors, 429 and
+	// 5xx responses are retried; other responses are not.
+	// Default: 5
+	MaxRetries int
+
+	// Backoff is the delay before the first retry. It doubles with every
+	// retry, with jitter, up to MaxBackoff; a Retry-After header takes
+	// precedence.
+	// Default: 500ms
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between retries.
+	// Default: 30s
+	MaxBackoff time.Duration
+
+	// Client sends the requests.
+	// Default: an http.Client with a 10s timeout
+	Client *http.Client
+
+	// OnError is called when a batch is dropped after failing or entries
+	// are dropped from a full buffer. It must not log to the same sink.
+	// Default: nil
+	OnError func(err error)
+
+	// DeadLetterPath, if set, is a file the entries of batches that failed
+	// every retry are appended to, one JSON line each, instead of being
+	// dropped, so that they can be sent later with ReplayDeadLetters.
+	// Entries dropped from a full buffer are not written to it.
+	// Default: "" (failed batches are dropped)
+	DeadLetterPath string
+}
+
+// WebhookSink posts the entries of a JSON sink to an HTTP endpoint as JSON
+// arrays, from a background goroutine. Writes only append to a bounded
+// buffer, so a slow or unavailable endpoint never blocks logging; entries
+// that do not fit are dropped and counted.
+type WebhookSink struct {
+	config      WebhookConfig
+	dropped     atomic.Uint64
+	deadLetters *deadLetters // Entries of failed batches, nil if disabled
+
+	mu      sync.Mutex
+	buffer  [][]byte
+	syncs   []chan error // Waiting Sync calls, answered once the buffer is sent
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	lastErr error
+}
+
+// NewWebhookSink starts a sink posting batches of entries to config.URL.
+//
+// Example:
+//
+//	webhook, err := logx.NewWebhookSink(logx.WebhookConfig{
+//	    URL:      "https://logs.example.com/ingest",
+//	    Headers:  map[string]string{"Authorization": "Bearer " + token},
+//	    Compress: true,
+//	})
+//	config.Sinks = []logx.SinkConfig{{Name: "webhook", Writer: webhook}}
+//	defer webhook.Close()
+func NewWebhookSink(config WebhookConfig) (*WebhookSink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook sink requires a URL")
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 10000
+	}
+	if config.BufferSize < config.MaxEntries {
+		config.BufferSize = config.MaxEntries
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = 5
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 500 * time.Millisecond
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	s := &WebhookSink{
+		config:      config,
+		deadLetters: newDeadLetters(config.DeadLetterPath),
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write buffers the encoded entries in p, dropping entries following the
+// drop policy if the buffer is full. It only fails if an entry is not
+// valid JSON.
+func (s *WebhookSink) Write(p []byte) (int, error) {
+	var entries [][]byte
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			err := fmt.Errorf("webhook sink: invalid entry %.64q", line)
+			s.report(err)
+			return 0, err
+		}
+		entries = append(entries, append([]byte(nil), line...))
+	}
+
+	s.mu.Lock()
+	s.buffer = append(s.buffer, entries...)
+	overflow := len(s.buffer) - s.config.BufferSize
+	if overflow > 0 {
+		if s.config.DropPolicy == DropNewest {
+			s.buffer = s.buffer[:s.config.BufferSize]
+		} else {
+			s.buffer = append(s.buffer[:0], s.buffer[overflow:]...)
+		}
+	}
+	full := len(s.buffer) >= s.config.MaxEntries
+	s.mu.Unlock()
+
+	if overflow > 0 {
+		s.dropped.Add(uint64(overflow))
+		s.report(fmt.Errorf("webhook sink: buffer full, dropped %d entries", overflow))
+	}
+	if full {
+		s.signal()
+	}
+	return len(p), nil
+}
+
+// signal wakes the sending goroutine.
+func (s *WebhookSink) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run sends batches when the buffer fills, every FlushInterval and on
+// Sync, until Close.
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.wake:
+		case <-ticker.C:
+		case <-s.stop:
+			s.drain()
+			return
+		}
+		s.drain()
+	}
+}
+
+// drain sends the buffered entries in batches, then answers the waiting
+// Sync calls with the last error, which is then cleared.
+func (s *WebhookSink) drain() {
+	for {
+		s.mu.Lock()
+		n := len(s.buffer)
+		if n > s.config.MaxEntries {
+			n = s.config.MaxEntries
+		}
+		batch := s.buffer[:n:n]
+		s.buffer = s.buffer[n:]
+		if n == 0 {
+			syncs, err := s.syncs, s.lastErr
+			if len(syncs) > 0 {
+				s.syncs, s.lastErr = nil, nil
+			}
+			s.mu.Unlock()
+			for _, done := range syncs {
+				done <- err
+			}
+			return
+		}
+		s.mu.Unlock()
+
+		if err := s.send(batch); err != nil {
+			s.report(err)
+			if s.deadLetters == nil {
+				s.dropped.Add(uint64(len(batch)))
+			} else if err := s.deadLetters.append(batch); err != nil {
+				s.dropped.Add(uint64(len(batch)))
+				s.report(err)
+			}
+			s.mu.Lock()
+			s.lastErr = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// send posts a batch, retrying with backoff. Retries stop early when the
+// sink is closed.
+func (s *WebhookSink) send(batch [][]byte) error {
+	body, err := s.encode(batch)
+	if err != nil {
+		return err
+	}
+	delay := s.config.Backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt >= s.config.MaxRetries {
+			return fmt.Errorf("webhook sink: dropped %d entries: %w", len(batch), err)
+		}
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-s.stop:
+			if attempt > 0 {
+				return fmt.Errorf("webhook sink: dropped %d entries on close: %w", len(batch), err)
+			}
+		}
+		if delay *= 2; delay > s.config.MaxBackoff {
+			delay = s.config.MaxBackoff
+		}
+	}
+}
+
+// encode returns the request body of a batch: a JSON array, gzipped if
+// Compress is set.
+func (s *WebhookSink) encode(batch [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if s.config.Compress {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	w.Write([]byte{'['})
+	for i, entry := range batch {
+		if i > 0 {
+			w.Write([]byte{','})
+		}
+		w.Write(entry)
+	}
+	w.Write([]byte{']'})
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends one request. It returns a negative retryAfter if the failure
+// must not be retried, and the delay requested by a Retry-After header.
+func (s *WebhookSink) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return 0, nil
+	}
+	err = fmt.Errorf("%s responded %s", req.URL.Host, resp.Status)
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, err
+	}
+	return 0, err
+}
+
+// report passes err to OnError, if set.
+func (s *WebhookSink) report(err error) {
+	if s.config.OnError != nil {
+		s.config.OnError(err)
+	}
+}
+
+// Dropped returns the number of entries dropped so far, from a full buffer
+// or after their batch failed and could not be written to the dead-letter
+// file.
+func (s *WebhookSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Sync sends the buffered entries and returns the error of the last batch
+// that failed, if any. It waits for retries.
+func (s *WebhookSink) Sync() error {
+	done := make(chan error, 1)
+	s.mu.Lock()
+	s.syncs = append(s.syncs, done)
+	s.mu.Unlock()
+	s.signal()
+	select {
+	case err := <-done:
+		return err
+	case <-s.done:
+		return nil
+	}
+}
+
+// Close stops the sink after sending the buffered entries and returns the
+// error of the last batch that failed since the last Sync. Pending retries
+// are abandoned after one more attempt. Entries written after Close are
+// not sent.
+func (s *WebhookSink) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
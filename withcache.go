@@ -0,0 +1,113 @@
+package logx
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// withCacheKey identifies a child logger by its parent and the fields
+// passed to With.
+type withCacheKey struct {
+	parent *Logger
+	fields string
+}
+
+// withCacheEntry is an element of the LRU list.
+type withCacheEntry struct {
+	key    withCacheKey
+	logger *Logger
+}
+
+// withCache is a bounded LRU cache of child loggers created by With, so
+// that middleware calling With with the same fields on every request reuses
+// one prepared child. Loggers are immutable once created, so sharing them
+// is safe.
+type withCache struct {
+	mu    sync.Mutex
+	max   int
+	order *list.List // Most recently used at the front
+	items map[withCacheKey]*list.Element
+}
+
+// newWithCache creates a cache holding up to max loggers, or returns nil if
+// max is not positive.
+func newWithCache(max int) *withCache {
+	if max <= 0 {
+		return nil
+	}
+	return &withCache{max: max, order: list.New(), items: make(map[withCacheKey]*list.Element)}
+}
+
+// get returns the cached logger for key, if any.
+func (c *withCache) get(key withCacheKey) (*Logger, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*withCacheEntry).logger, true
+	}
+	return nil, false
+}
+
+// add caches logger under key, evicting the least recently used logger
+// when the cache is full. If another goroutine cached a logger for the same
+// key first, that logger is returned instead.
+func (c *withCache) add(key withCacheKey, logger *Logger) *Logger {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*withCacheEntry).logger
+	}
+	c.items[key] = c.order.PushFront(&withCacheEntry{key: key, logger: logger})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*withCacheEntry).key)
+	}
+	return logger
+}
+
+// fieldsCacheKey encodes fields as a cache key. Only fields with scalar
+// values can be cached; for any other value the second result is false.
+func fieldsCacheKey(fields []Field) (string, bool) {
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(strconv.Quote(field.Key))
+		switch v := field.Value.(type) {
+		case string:
+			b.WriteString("s")
+			b.WriteString(strconv.Quote(v))
+		case int:
+			b.WriteString("i")
+			b.WriteString(strconv.Itoa(v))
+		case int64:
+			b.WriteString("I")
+			b.WriteString(strconv.FormatInt(v, 10))
+		case int32:
+			b.WriteString("j")
+			b.WriteString(strconv.FormatInt(int64(v), 10))
+		case uint:
+			b.WriteString("u")
+			b.WriteString(strconv.FormatUint(uint64(v), 10))
+		case uint64:
+			b.WriteString("U")
+			b.WriteString(strconv.FormatUint(v, 10))
+		case float64:
+			b.WriteString("f")
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		case bool:
+			b.WriteString("b")
+			b.WriteString(strconv.FormatBool(v))
+		case time.Duration:
+			b.WriteString("d")
+			b.WriteString(strconv.FormatInt(int64(v), 10))
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}